@@ -1,10 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
-	"strings"
 
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/ai"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/applier"
@@ -13,15 +13,17 @@ import (
 )
 
 var (
-	applyAll          bool
-	applyFile         string
-	applyShowResolved bool
-	applyDebug        bool
-	applyAIAuto       bool
-	applyAIProvider   string
-	applyAIModel      string
-	applyAITemplate   string
-	applyAIToken      string
+	applyAll           bool
+	applyFile          string
+	applyShowResolved  bool
+	applyDebug         bool
+	applyAIAuto        bool
+	applyAIProvider    string
+	applyAIModel       string
+	applyAITemplate    string
+	applyAIToken       string
+	applyMinConfidence float64
+	applyYes           bool
 )
 
 var applyCmd = &cobra.Command{
@@ -39,13 +41,19 @@ func init() {
 
 	// AI flags
 	applyCmd.Flags().BoolVar(&applyAIAuto, "ai-auto", false, "Automatically apply all suggestions using AI")
-	applyCmd.Flags().StringVar(&applyAIProvider, "ai-provider", "", "AI provider to use (gemini) - defaults to env or 'gemini'")
+	applyCmd.Flags().StringVar(&applyAIProvider, "ai-provider", "", "AI provider to use (gemini, openai) - defaults to GH_RC_AI_PROVIDER or the first configured provider")
 	applyCmd.Flags().StringVar(&applyAIModel, "ai-model", "", "AI model to use (provider-specific)")
 	applyCmd.Flags().StringVar(&applyAITemplate, "ai-template", "", "Custom AI prompt template file")
 	applyCmd.Flags().StringVar(&applyAIToken, "ai-token", "", "AI API token/key (alternative to environment variable)")
+	applyCmd.Flags().Float64Var(&applyMinConfidence, "min-confidence", 0.8, "Minimum AI confidence (0.0-1.0) to apply without review")
+	applyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "Apply AI patches without confirmation when they meet --min-confidence")
 }
 
 func runApply(cmd *cobra.Command, args []string) error {
+	if applyMinConfidence < 0 || applyMinConfidence > 1 {
+		return fmt.Errorf("--min-confidence must be between 0.0 and 1.0, got %g", applyMinConfidence)
+	}
+
 	// Check if there are uncommitted changes
 	if err := checkCleanWorkingDirectory(); err != nil {
 		return err
@@ -53,8 +61,8 @@ func runApply(cmd *cobra.Command, args []string) error {
 
 	client := github.NewClient()
 	client.SetDebug(applyDebug)
-	if repoFlag != "" {
-		client.SetRepo(repoFlag)
+	if err := configureRepo(client); err != nil {
+		return err
 	}
 
 	prNumber, err := getPRNumberWithSelection(args, client)
@@ -98,6 +106,8 @@ func runApply(cmd *cobra.Command, args []string) error {
 	app := applier.New()
 	app.SetDebug(applyDebug)
 	app.SetGitHubClient(client) // Pass GitHub client for resolving threads
+	app.SetMinConfidence(applyMinConfidence)
+	app.SetAssumeYes(applyYes)
 
 	// Setup AI provider if needed (for interactive or --ai-auto)
 	if applyAIAuto || (!applyAll) {
@@ -147,41 +157,29 @@ func checkCleanWorkingDirectory() error {
 	return nil
 }
 
-// setupAIProvider creates and configures an AI provider based on flags and environment
+// setupAIProvider creates the AI provider the flags and environment select
 func setupAIProvider() (ai.AIProvider, error) {
-	// Start with config from environment
 	config := ai.LoadConfigFromEnv()
-
-	// Override with command-line flags if provided
 	if applyAIProvider != "" {
 		config.Provider = applyAIProvider
 	}
+	if applyAIToken != "" {
+		// A token is for one provider, so it can't go to an auto-selected one
+		if config.Provider == "" {
+			return nil, fmt.Errorf("--ai-token requires --ai-provider or GH_RC_AI_PROVIDER")
+		}
+		config.APIKey = applyAIToken
+	}
 	if applyAIModel != "" {
 		config.Model = applyAIModel
 	}
 	if applyAITemplate != "" {
 		config.CustomTemplatePath = applyAITemplate
 	}
-	if applyAIToken != "" {
-		config.APIKey = applyAIToken
-	}
-
-	// Validate we have an API key
-	if config.APIKey == "" {
-		meta, ok := ai.GetProviderMetadata(config.Provider)
-		if !ok || len(meta.EnvVars) == 0 {
-			return nil, fmt.Errorf("AI API key not found for provider %q. Use --ai-token flag or set the appropriate environment variable", config.Provider)
-		}
-
-		providerLabel := meta.Label
-		if providerLabel == "" {
-			providerLabel = strings.ToUpper(config.Provider)
-		}
 
-		return nil, fmt.Errorf("%s API key not found. Set %s or use --ai-token flag",
-			providerLabel, strings.Join(meta.EnvVars, " or "))
+	provider, err := ai.NewProviderWithConfig(config)
+	if errors.Is(err, ai.ErrMissingAPIKey) {
+		return nil, fmt.Errorf("%w or use --ai-token flag", err)
 	}
-
-	// Create the provider
-	return ai.NewProviderFromConfig(config)
+	return provider, err
 }
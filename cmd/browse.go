@@ -1,18 +1,34 @@
 package cmd
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/applier"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/config"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/diffposition"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/parser"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/state"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // Pre-compiled regexes for markdown stripping (avoids recompilation on each call)
@@ -21,7 +37,128 @@ var (
 	markdownLinkRe  = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
 )
 
-var browseDebug bool
+var (
+	browseDebug         bool
+	browseWatch         bool
+	browseWatchInterval time.Duration
+	browseCompact       bool
+	browseHideBots      bool
+	browseCollapseBots  bool
+	browseInclude       []string
+	browseMention       bool
+	browseNotify        bool
+	browseLimit         int
+	browseSelect        int64
+	browseReview        int64
+	browsePRNumber      int // PR to browse, when the caller already knows it (see reviews)
+	browseAligned       bool
+	browsePlain         bool
+	browseWrapQuotes    bool
+	browseQuoteContext  int
+	browseCarried       *browseFilters // filters kept across a PR switch (ctrl+p)
+)
+
+// browseFilters are the list filters a PR switch carries over to the next PR
+type browseFilters struct {
+	resolvedMode int // index into resolvedFilterModes
+	todoOnly     bool
+	hideOutdated bool
+	pathGlob     string
+}
+
+// browseRows decides which rows the browse list shows for a resolved filter
+// mode: the other filters, plus the files collapsed and those whose resolved
+// threads are expanded under their summary row
+type browseRows struct {
+	browseFilters
+	collapsed map[string]bool
+	expanded  map[string]bool
+	tag       func(commentID int64) state.Tag
+	login     func() string
+}
+
+// shows is the list's filter. A file header shows only while something under
+// it would, even if the file is collapsed, so a list the filters empty shows
+// the empty state instead of bare headers.
+func (r *browseRows) shows(item BrowseItem, mode int) bool {
+	// Files outside the path glob go entirely, headers included
+	if r.pathGlob != "" && !matchPathGlob(r.pathGlob, item.Path) {
+		return false
+	}
+
+	if item.Type == "file" {
+		return slices.ContainsFunc(item.Threads, func(c *github.ReviewComment) bool {
+			return r.showsThread(c, item.Path, mode)
+		}) || (mode == resolvedFilterSummarize && resolvedCount(item) > 0)
+	}
+	if r.collapsed[item.Path] {
+		return false
+	}
+
+	// Summary rows only stand in for resolved threads while collapsing them
+	if item.Type == "resolved_summary" {
+		return mode == resolvedFilterSummarize && resolvedCount(item) > 0
+	}
+	return r.showsThread(item.Comment, item.Path, mode)
+}
+
+// showsThread reports whether the filters keep comment's thread, in the file
+// at path, leaving collapse aside
+func (r *browseRows) showsThread(comment *github.ReviewComment, path string, mode int) bool {
+	// Tag filter hides comments not tagged todo
+	if r.todoOnly && r.tag(comment.ID) != state.TagTodo {
+		return false
+	}
+	if r.hideOutdated && comment.IsOutdated {
+		return false
+	}
+	if mode == resolvedFilterSummarize {
+		return !comment.IsResolved() || r.expanded[path]
+	}
+	return mode == resolvedFilterAll || matchesResolvedFilter(comment, mode, r.login())
+}
+
+// stdoutIsTerminal reports whether output goes to a terminal; browse prints
+// plain text instead of starting the TUI when it doesn't
+var stdoutIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// minWatchInterval keeps watch mode from exhausting the API rate limit
+const minWatchInterval = 5 * time.Second
+
+// Default preview lengths, used until the selector reports the list width
+const (
+	defaultTitlePreviewWidth  = 80
+	defaultThreadPreviewWidth = 100
+)
+
+// conversationGroup is the header grouping comments not attached to a file
+// (review summaries and issue comments included with --include)
+const conversationGroup = "(conversation)"
+
+// includeKinds maps --include values to the comment kinds they add
+var includeKinds = map[string]github.CommentKind{
+	"summary": github.CommentKindSummary,
+	"issue":   github.CommentKindIssue,
+}
+
+// previewIndent indents the body preview under its comment's title
+const previewIndent = "      "
+
+// Resolved-comment filter states, cycled with 'h'
+const (
+	resolvedFilterAll = iota
+	resolvedFilterHide
+	resolvedFilterMine
+	resolvedFilterSummarize
+)
+
+// resolvedFilterModes are the status bar labels for each resolved filter state
+var resolvedFilterModes = []string{"Showing all", "Hiding resolved", "Only resolved by me", "Collapsing resolved"}
+
+// rateLimitWarningThreshold is the remaining-request count below which browse warns
+const rateLimitWarningThreshold = 100
 
 var browseCmd = &cobra.Command{
 	Use:   "browse [PR_NUMBER] [COMMENT_ID]",
@@ -30,18 +167,54 @@ var browseCmd = &cobra.Command{
 
 When no arguments are provided, PR is inferred from the current branch and you can interactively select a comment.
 When one argument is provided, it's treated as COMMENT_ID and PR is inferred from the current branch.
-When two arguments are provided, the first is PR_NUMBER and the second is COMMENT_ID.`,
+When two arguments are provided, the first is PR_NUMBER and the second is COMMENT_ID.
+A comment URL copied from GitHub (e.g. .../pull/123#discussion_r456) opens the
+selector on that PR with the comment selected. Links to a GitHub Enterprise
+server are fetched from that host with gh's login for it.
+
+With --plain, or when output isn't a terminal, the comments are printed as
+text instead of opening the interactive selector.`,
 	Args: cobra.MaximumNArgs(2),
 	RunE: runBrowse,
 }
 
 func init() {
 	browseCmd.Flags().BoolVar(&browseDebug, "debug", false, "Enable debug output")
+	browseCmd.Flags().BoolVar(&browseWatch, "watch", false, "Poll for new comments and update the list live")
+	browseCmd.Flags().BoolVar(&browseCompact, "compact", false, "Show one line per comment instead of a body preview under each")
+	browseCmd.Flags().BoolVar(&browseAligned, "aligned", false, "Line up authors and line numbers in columns")
+	browseCmd.Flags().BoolVar(&browsePlain, "plain", false, "Print the comments as text and exit instead of opening the selector")
+	browseCmd.Flags().BoolVar(&browseHideBots, "hide-bots", false, "Hide comments from bots")
+	browseCmd.Flags().BoolVar(&browseCollapseBots, "collapse-bots", false, "Show bot threads without a body preview")
+	browseCmd.Flags().StringSliceVar(&browseInclude, "include", nil, "Also show other comment kinds: summary (review bodies), issue (conversation comments)")
+	browseCmd.Flags().BoolVar(&browseMention, "mention", false, "Start quote replies with an @mention of the quoted author")
+	browseCmd.Flags().BoolVar(&browseWrapQuotes, "wrap-quotes", false, "Wrap long lines of the quoted comment at 80 columns in quote replies")
+	browseCmd.Flags().IntVar(&browseQuoteContext, "quote-context", config.DefaultQuoteContext, "Diff lines kept above the commented line in quote-with-context replies (0 for the whole hunk)")
+	browseCmd.Flags().BoolVar(&browseNotify, "notify", false, "Ring the terminal bell when a refresh finds new comments")
+	browseCmd.Flags().Int64Var(&browseSelect, "select", 0, "Open the selector with this comment (or reply) ID selected")
+	browseCmd.Flags().Int64Var(&browseReview, "review", 0, "Only show threads from this review ID (see the reviews command)")
+	browseCmd.Flags().IntVar(&browseLimit, "limit", 0, "Show only the N most recently active threads, unresolved first (0 for all)")
+	browseCmd.Flags().DurationVar(&browseWatchInterval, "watch-interval", 30*time.Second, "How often to poll for new comments in watch mode")
 }
 
 func runBrowse(cmd *cobra.Command, args []string) error {
 	// Enable UI debug output if requested
 	ui.SetUIDebug(browseDebug)
+	ui.SetMentionOnReply(browseMention)
+
+	if browseWatch && browseWatchInterval < minWatchInterval {
+		return fmt.Errorf("--watch-interval must be at least %s", minWatchInterval)
+	}
+	if browseLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	include, err := parseIncludeKinds(browseInclude)
+	if err != nil {
+		return err
+	}
+	if err := ui.ValidateReactionOrder(cfg.Browse.ReactionOrder); err != nil {
+		return fmt.Errorf("invalid browse.reaction_order in config: %w", err)
+	}
 
 	// Start warming up the markdown renderer in the background
 	// This initializes glamour/chroma before the user needs it
@@ -49,26 +222,56 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 
 	client := github.NewClient()
 	client.SetDebug(browseDebug)
-	if repoFlag != "" {
-		client.SetRepo(repoFlag)
-	}
 
-	var prNumber int
+	prNumber := browsePRNumber
 	var commentID int64
-	var err error
+
+	// A review's own summary is part of what it said
+	if browseReview != 0 && !slices.Contains(include, github.CommentKindSummary) {
+		include = append(include, github.CommentKindSummary)
+	}
+
+	// A comment URL names the repo and PR, and selects the comment
+	if len(args) == 1 && strings.Contains(args[0], "://") {
+		host, repo, pr, id, err := parseBrowseURL(args[0])
+		if err != nil {
+			return err
+		}
+		// Conversation comments are only listed with --include issue
+		if strings.Contains(args[0], "#issuecomment-") && !slices.Contains(include, github.CommentKindIssue) {
+			include = append(include, github.CommentKindIssue)
+		}
+		client.SetHost(host)
+		client.SetRepo(repo)
+		prNumber, browseSelect, args = pr, id, nil
+	} else if err := configureRepo(client); err != nil {
+		return err
+	}
 
 	// Parse arguments based on count
 	if len(args) == 0 {
 		// No args: infer PR and let user select a comment interactively
-		prNumber, err = getPRNumberWithSelection([]string{}, client)
-		if err != nil {
-			return err
+		if prNumber == 0 {
+			prNumber, err = getPRNumberWithSelection([]string{}, client)
+			if err != nil {
+				return err
+			}
 		}
 
-		comments, err := client.FetchReviewComments(prNumber)
+		comments, err := client.FetchAllComments(prNumber, include)
 		if err != nil {
 			return fmt.Errorf("failed to fetch review comments: %w", err)
 		}
+		if browseHideBots {
+			comments = withoutBotComments(comments)
+		}
+		if browseReview != 0 {
+			comments = commentsInReview(comments, browseReview)
+			if len(comments) == 0 {
+				fmt.Printf("Review %d has no comments in PR #%d\n", browseReview, prNumber)
+				return nil
+			}
+		}
 		if len(comments) == 0 {
 			fmt.Printf("No review comments found in %s\n",
 				ui.CreateHyperlink(fmt.Sprintf("https://github.com/%s/pull/%d", getRepoFromClient(client), prNumber),
@@ -76,173 +279,451 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		// Track collapsed state
-		collapsedFiles := make(map[string]bool)
+		// --plain, or output that isn't a terminal: print the threads and exit
+		if browsePlain || !stdoutIsTerminal() {
+			if !stdoutIsTerminal() {
+				ui.SetColorEnabled(false)
+			}
+			printPlainBrowse(os.Stdout, getRepoFromClient(client), prNumber, buildCommentTree(limitComments(comments, browseLimit)))
+			return nil
+		}
+
+		// Load persisted collapse and read state for this PR
+		store := state.OpenForPR(getRepoFromClient(client), prNumber)
+		collapsedFiles := store.CollapsedFiles()
+		// Files whose resolved threads are expanded under their summary row
+		// while collapsing resolved (on 'h'); not persisted
+		expandedFiles := make(map[string]bool)
 
 		// Use interactive selector with resolve action
 		renderer := &browseItemRenderer{
 			repo:           getRepoFromClient(client),
 			prNumber:       prNumber,
 			collapsedFiles: collapsedFiles,
+			expandedFiles:  expandedFiles,
+			state:          store,
+			compact:        browseCompact,
+			collapseBots:   browseCollapseBots,
+			aligned:        browseAligned,
+		}
+		if root, err := repoRoot(); err == nil {
+			renderer.repoRoot = root
+		}
+		if stats, err := client.FetchPRFileStats(prNumber); err == nil {
+			renderer.fileStats = stats
 		}
 
+		isSelected := func(item BrowseItem) bool { return browseItemHasComment(item, browseSelect) }
+		var initialItem func(BrowseItem) bool
+		if browseSelect != 0 {
+			initialItem = isSelected
+		}
+
+		// --limit: list only the top threads until L loads the rest
+		limit := &threadLimit{max: browseLimit}
+
 		// Convert comments to tree structure
-		browseItems := buildCommentTree(comments)
+		browseItems := limit.items(comments)
 
-		// Create resolve actions
-		resolveAction := func(item BrowseItem) (string, error) {
-			if item.Type == "file" {
-				return "", nil // Cannot resolve a file header
+		// --select: the comment must be listed even if --limit left it out
+		if browseSelect != 0 && !slices.ContainsFunc(browseItems, isSelected) {
+			browseItems = limit.lift()
+			if !slices.ContainsFunc(browseItems, isSelected) {
+				return fmt.Errorf("comment ID %d not found in PR #%d", browseSelect, prNumber)
 			}
-			return resolveCommentAction(client, prNumber, item.Comment)
 		}
 
 		// Create open action (on 'o')
 		openAction := func(item BrowseItem) (string, error) {
-			if item.Type == "file" {
-				return "", nil // Cannot open a file header
+			if item.Comment == nil {
+				return "", nil // Cannot open a file header or summary
+			}
+			// Use cached URL from initial fetch - no additional API calls.
+			// A reply picked in comment-select mode anchors to that reply.
+			id, url := item.Comment.ID, item.Comment.HTMLURL
+			if item.SelectedCommentIdx > 0 && item.SelectedCommentIdx-1 < len(item.Comment.ThreadComments) {
+				tc := item.Comment.ThreadComments[item.SelectedCommentIdx-1]
+				id, url = tc.ID, tc.HTMLURL
 			}
-			// Use cached URL from initial fetch - no additional API calls
-			if item.Comment.HTMLURL == "" {
+			if url == "" {
 				return "", fmt.Errorf("comment has no URL")
 			}
-			if err := openURLInBrowser(item.Comment.HTMLURL); err != nil {
+			if err := openURLInBrowser(url); err != nil {
 				return "", err
 			}
-			return fmt.Sprintf("Opened comment %d in browser", item.Comment.ID), nil
+			return fmt.Sprintf("Opened comment %d in browser", id), nil
 		}
 
-		// Filter function (hide resolved and collapsed)
-		filterFunc := func(item BrowseItem, hideResolved bool) bool {
-			// 1. Check collapse state (Always applies)
-			if (item.Type == "comment" || item.Type == "comment_preview") && collapsedFiles[item.Path] {
-				return false
+		// Open file action (on 'O') - confirm the blob URL, then open it
+		openFilePreview := func(item BrowseItem) (string, error) {
+			if item.Type == "file" || item.Comment == nil {
+				return "", nil // Only comments have a line to link to
+			}
+			if err := requireInline(item.Comment, "open the file for"); err != nil {
+				return "", err
+			}
+			return fileBlobURL(renderer.repo, item.Comment)
+		}
+		openFileAction := func(item BrowseItem) (string, error) {
+			link, err := openFilePreview(item)
+			if err != nil || link == "" {
+				return "", err
+			}
+			if err := openURLInBrowser(link); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Opened %s:%d in browser", item.Comment.Path, item.Comment.Line), nil
+		}
+
+		// Copy command action (on 'y') - the gh CLI equivalent of the selected comment
+		copyCommand := func(item BrowseItem) (string, error) {
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot copy a command for a %s", rowLabel(item))
 			}
+			return ghCommandForComment(renderer.repo, item.Comment.ID), nil
+		}
 
-			// 2. Check resolved state (Only if hideResolved is true)
-			if hideResolved {
-				if item.Type == "file" {
-					return true // Always show headers
-				}
-				return !item.Comment.IsResolved()
+		// Copy permalink action (on 'p') - the commented code, pinned to its commit
+		copyPermalink := func(item BrowseItem) (string, error) {
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot copy a permalink for a %s", rowLabel(item))
 			}
+			if err := requireInline(item.Comment, "copy a code permalink for"); err != nil {
+				return "", err
+			}
+			return codePermalink(renderer.repo, item.Comment)
+		}
 
-			return true
+		// Copy checklist action (on 'Y') - unresolved comments as markdown for standup notes
+		copyChecklist := func(items []BrowseItem) (string, error) {
+			checklist := FormatChecklist(items)
+			if checklist == "" {
+				return "", fmt.Errorf("no unresolved comments to copy")
+			}
+			return checklist, nil
+		}
+
+		// Tag action (on 't') - cycle the local triage tag; never touches GitHub
+		tagAction := func(item BrowseItem) (string, error) {
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot tag a %s", rowLabel(item))
+			}
+			tag, err := store.CycleTag(item.Comment.ID)
+			if err != nil {
+				return "", err
+			}
+			if tag == state.TagNone {
+				return fmt.Sprintf("Cleared tag on comment %d", item.Comment.ID), nil
+			}
+			return fmt.Sprintf("Tagged comment %d as %s", item.Comment.ID, tag), nil
+		}
+
+		// Filters start as configured, or as they were before a PR switch
+		startFilters := browseFilters{resolvedMode: resolvedFilterAll}
+		if cfg.Browse.HideResolved {
+			startFilters.resolvedMode = resolvedFilterHide
+		}
+		if browseCarried != nil {
+			startFilters = *browseCarried
+			browseCarried = nil
+		}
+
+		// The authenticated user, for "only resolved by me" and for who
+		// resolved a thread; looked up once in the background when the list
+		// opens. Threads resolved before it arrives are credited when it does.
+		viewerLogin := ""
+
+		// What the list shows beside the h mode; the filters below change it
+		rows := &browseRows{
+			browseFilters: startFilters,
+			collapsed:     collapsedFiles,
+			expanded:      expandedFiles,
+			tag:           store.Tag,
+			login:         func() string { return viewerLogin },
+		}
+
+		// Tag filter (on 'T') - show only comments tagged todo
+		toggleTodoOnly := func() string {
+			rows.todoOnly = !rows.todoOnly
+			if rows.todoOnly {
+				return "Showing todo only"
+			}
+			return "Showing all tags"
+		}
+
+		// Outdated filter (on 'H') - hide comments on code that has since
+		// changed, whether or not they are resolved
+		toggleHideOutdated := func() string {
+			rows.hideOutdated = !rows.hideOutdated
+			if rows.hideOutdated {
+				return "Hiding outdated"
+			}
+			return "Showing outdated"
+		}
+
+		// Path filter (on 'I') - only files matching a glob such as pkg/ui/**
+		setPathGlob := func(glob string) error {
+			if err := validPathGlob(glob); err != nil {
+				return err
+			}
+			rows.pathGlob = glob
+			return nil
+		}
+
+		// Filter bar state for the browse-specific filters
+		activeFilters := func() []string {
+			var labels []string
+			if rows.pathGlob != "" {
+				labels = append(labels, "path "+rows.pathGlob)
+			}
+			if rows.todoOnly {
+				labels = append(labels, "todo only")
+			}
+			if rows.hideOutdated {
+				labels = append(labels, "hiding outdated")
+			}
+			return labels
+		}
+		clearFilters := func() {
+			rows.pathGlob = ""
+			rows.todoOnly = false
+			rows.hideOutdated = false
+		}
+
+		var resolvedBeforeLogin []*github.ReviewComment
+		preloadLogin := func() (func(), error) {
+			login, err := client.CurrentUser()
+			if err != nil {
+				return nil, explainAPIError(fmt.Errorf("couldn't look up your GitHub login, so \"only resolved by me\" lists nothing: %w", err))
+			}
+			return func() {
+				viewerLogin = login
+				creditResolvedBy(resolvedBeforeLogin, login)
+				resolvedBeforeLogin = nil
+			}, nil
+		}
+		// Background actions credit the resolver in their apply, on the UI
+		// goroutine, since viewerLogin is only safe to read there
+		creditResolver := resolvedByCredit(func() string { return viewerLogin }, &resolvedBeforeLogin)
+
+		// r/u flip the thread at once and resolve on GitHub in the background
+		resolveStart := func(item BrowseItem) (func() (string, func(), error), func(), error) {
+			if item.Comment == nil {
+				return nil, nil, nil // Cannot resolve a file header or summary
+			}
+			call, undo, err := startResolveToggle(item.Comment, viewerLogin, client.FindThreadID, client.ResolveThread, client.UnresolveThread)
+			if err == nil && viewerLogin == "" && item.Comment.IsResolved() {
+				resolvedBeforeLogin = append(resolvedBeforeLogin, item.Comment)
+			}
+			return call, undo, err
 		}
 
 		// Handle selection (Enter key)
 		onSelect := func(item BrowseItem) (string, error) {
 			if item.Type == "file" {
 				collapsedFiles[item.Path] = !collapsedFiles[item.Path]
+				_ = store.SetCollapsed(item.Path, collapsedFiles[item.Path])
 				return "", nil // Just toggle collapse
 			}
+			if item.Type == "resolved_summary" {
+				expandedFiles[item.Path] = !expandedFiles[item.Path]
+				return "", nil
+			}
 
 			// Return empty string to allow detail view to open
 			return "", nil
 		}
 
+		// Mark the thread as read once its detail view has been shown
+		onDetailOpen := func(item BrowseItem) {
+			if item.Type == "file" || item.Comment == nil {
+				return
+			}
+			_ = store.MarkRead(item.Comment.ID)
+			for _, tc := range item.Comment.ThreadComments {
+				_ = store.MarkRead(tc.ID)
+			}
+		}
+
 		// Editor actions for R (resolve with comment)
 		editorPrepareR := func(item BrowseItem) (string, error) {
-			if item.Type == "file" {
-				return "", fmt.Errorf("cannot add comment to file header")
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot add comment to %s", rowLabel(item))
 			}
-			if item.Comment.ThreadID == "" {
-				return "", fmt.Errorf("comment has no thread ID")
+			// The thread is looked up, if need be, when the reply is sent
+			if _, err := threadIDLookup(item.Comment, client.FindThreadID); err != nil {
+				return "", err
 			}
 			return "", nil // No initial content for resolve+comment
 		}
 
-		editorCompleteR := func(item BrowseItem, body string) (string, error) {
+		editorCompleteR := func(item BrowseItem, body string) (string, func(), error) {
 			comment := item.Comment
 			reply, err := client.ReplyToReviewComment(prNumber, comment.ID, body)
 			if err != nil {
-				return "", fmt.Errorf("failed to add comment: %w", err)
+				return "", nil, explainAPIError(fmt.Errorf("failed to add comment: %w", err))
 			}
 
 			// Add reply to local thread so it shows in details view
-			comment.ThreadComments = append(comment.ThreadComments, *reply)
+			addReply := replyApplied(comment, reply)
 
 			// Toggle resolved state
-			statusMsg, err := resolveCommentAction(client, prNumber, comment)
+			statusMsg, resolved, err := resolveCommentAction(client, comment, creditResolver)
 			if err != nil {
-				return "", err
+				return "", combineApplies(addReply, resolved), explainAPIError(err)
 			}
 
-			if reply != nil && reply.HTMLURL != "" {
+			if reply.HTMLURL != "" {
 				link := ui.CreateHyperlink(reply.HTMLURL, "a comment")
-				return fmt.Sprintf("%s\nPosted %s.", statusMsg, link), nil
+				return fmt.Sprintf("%s\nPosted %s.", statusMsg, link), combineApplies(addReply, resolved), nil
 			}
 
-			return statusMsg, nil
+			return statusMsg, combineApplies(addReply, resolved), nil
 		}
 
 		// Editor actions for Q (quote reply without context)
 		editorPrepareQ := func(item BrowseItem) (string, error) {
-			if item.Type == "file" {
-				return "", fmt.Errorf("cannot quote reply to file header")
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot quote reply to %s", rowLabel(item))
 			}
-			comment := item.Comment
-			// Get author and body based on selected comment index
-			author, body := comment.Author, comment.Body
-			if item.SelectedCommentIdx > 0 && item.SelectedCommentIdx-1 < len(comment.ThreadComments) {
-				tc := comment.ThreadComments[item.SelectedCommentIdx-1]
-				author, body = tc.Author, tc.Body
+			if err := requireInline(item.Comment, "reply to"); err != nil {
+				return "", err
 			}
+			comment := item.Comment
+			author, body := quotedComment(item)
 			return ui.FormatQuotedReply(
 				author,
 				body,
 				comment.DiffHunk,
 				comment.Path,
 				false, // Don't include context
+				0,
+				browseWrapQuotes,
 			), nil
 		}
 
-		editorCompleteQ := func(item BrowseItem, body string) (string, error) {
+		// P/Z keys: draft mode buffers replies until they are submitted as
+		// one review
+		drafts := &draftReview{}
+		submitDrafts := func() (func() (string, func(), error), error) {
+			return drafts.submission(func(replies []github.DraftReply) ([]github.ThreadComment, string, error) {
+				return client.SubmitDraftReplies(prNumber, replies)
+			})
+		}
+
+		editorCompleteQ := func(item BrowseItem, body string) (string, func(), error) {
 			comment := item.Comment
+			if drafts.on.Load() {
+				threadID, err := threadIDFor(comment, client.FindThreadID)
+				if err != nil {
+					return "", nil, err
+				}
+				return "Saved draft", func() {
+					comment.ThreadID = threadID
+					drafts.add(comment, body)
+				}, nil
+			}
 			reply, err := client.ReplyToReviewComment(prNumber, comment.ID, body)
 			if err != nil {
-				return "", fmt.Errorf("failed to post reply: %w", err)
+				return "", nil, explainAPIError(fmt.Errorf("failed to post reply: %w", err))
 			}
 
 			// Add reply to local thread so it shows in details view
-			comment.ThreadComments = append(comment.ThreadComments, *reply)
+			addReply := replyApplied(comment, reply)
 
 			url := reply.HTMLURL
 			if url == "" {
-				return fmt.Sprintf("Posted comment %d", reply.ID), nil
+				return fmt.Sprintf("Posted comment %d", reply.ID), addReply, nil
 			}
 
 			link := ui.CreateHyperlink(url, "a comment")
-			return fmt.Sprintf("Posted %s.", link), nil
+			return fmt.Sprintf("Posted %s.", link), addReply, nil
 		}
 
 		// Editor actions for C (quote reply with context)
 		editorPrepareC := func(item BrowseItem) (string, error) {
-			if item.Type == "file" {
-				return "", fmt.Errorf("cannot quote reply to file header")
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot quote reply to %s", rowLabel(item))
 			}
-			comment := item.Comment
-			// Get author and body based on selected comment index
-			author, body := comment.Author, comment.Body
-			if item.SelectedCommentIdx > 0 && item.SelectedCommentIdx-1 < len(comment.ThreadComments) {
-				tc := comment.ThreadComments[item.SelectedCommentIdx-1]
-				author, body = tc.Author, tc.Body
+			if err := requireInline(item.Comment, "reply to"); err != nil {
+				return "", err
 			}
+			comment := item.Comment
+			author, body := quotedComment(item)
 			return ui.FormatQuotedReply(
 				author,
 				body,
 				comment.DiffHunk,
 				comment.Path,
 				true, // Include context
+				browseQuoteContext,
+				browseWrapQuotes,
 			), nil
 		}
 
 		// editorCompleteC is the same as editorCompleteQ - just post the reply
 		editorCompleteC := editorCompleteQ
 
+		// c/v keys: the Q and C quotes, copied instead of posted
+		copyQuote := func(item BrowseItem) (string, error) {
+			return quoteForClipboard(item, false)
+		}
+		copyQuoteContext := func(item BrowseItem) (string, error) {
+			return quoteForClipboard(item, true)
+		}
+
+		// Editor actions for G (reply with a suggestion block seeded from the diff)
+		editorPrepareG := func(item BrowseItem) (string, error) {
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot suggest a change on a %s", rowLabel(item))
+			}
+			if item.Comment.DiffHunk == "" {
+				return "", fmt.Errorf("comment has no diff to suggest against")
+			}
+			return ui.FormatSuggestionTemplate(item.Comment.DiffHunk, item.Comment.Path), nil
+		}
+
+		// The suggestion block is posted as an ordinary reply
+		editorCompleteG := editorCompleteQ
+
+		// Editor actions for W (won't fix: reply with a reason and resolve)
+		wontFixPrefix := cmp.Or(cfg.Browse.WontFixPrefix, defaultWontFixPrefix)
+		editorPrepareW := func(item BrowseItem) (string, error) {
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot dismiss a %s", rowLabel(item))
+			}
+			if err := requireInline(item.Comment, "dismiss"); err != nil {
+				return "", err
+			}
+			if _, err := threadIDLookup(item.Comment, client.FindThreadID); err != nil {
+				return "", err
+			}
+			return wontFixTemplate(wontFixPrefix), nil
+		}
+		editorCompleteW := func(item BrowseItem, body string) (string, func(), error) {
+			reply := func(comment *github.ReviewComment, body string) (*github.ThreadComment, error) {
+				return client.ReplyToReviewComment(prNumber, comment.ID, body)
+			}
+			resolve := func(comment *github.ReviewComment) (string, func(), error) {
+				return resolveCommentAction(client, comment, creditResolver)
+			}
+			return wontFix(item.Comment, body, wontFixPrefix, reply, resolve)
+		}
+
+		// Inline reply (m): typed in the selector, posted like a quote reply
+		replyPrepare := func(item BrowseItem) (string, error) {
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot reply to %s", rowLabel(item))
+			}
+			if err := requireInline(item.Comment, "reply to"); err != nil {
+				return "", err
+			}
+			return "", nil
+		}
+
 		// Callback to check if an item is resolved (for dynamic help text)
 		isItemResolved := func(item BrowseItem) bool {
-			if item.Type == "file" {
+			if item.Comment == nil {
 				return false
 			}
 			return item.Comment.IsResolved()
@@ -250,17 +731,23 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 
 		// Callback to refresh items from the API
 		refreshItems := func() ([]BrowseItem, error) {
-			freshComments, err := client.FetchReviewComments(prNumber)
+			freshComments, err := client.FetchAllComments(prNumber, include)
 			if err != nil {
 				return nil, err
 			}
-			return buildCommentTree(freshComments), nil
+			if browseHideBots {
+				freshComments = withoutBotComments(freshComments)
+			}
+			if browseReview != 0 {
+				freshComments = commentsInReview(freshComments, browseReview)
+			}
+			return withResolvedSummaries(buildCommentTree(freshComments)), nil
 		}
 
 		// Agent action - launch coding agent with comment details
 		agentAction := func(item BrowseItem) (string, error) {
-			if item.Type == "file" {
-				return "", fmt.Errorf("cannot launch agent on file header")
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot launch agent on %s", rowLabel(item))
 			}
 			comment := item.Comment
 			// Get body based on selected comment index
@@ -272,21 +759,30 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 				comment.Path,
 				comment.Line,
 				body)
+			if cfg.AgentPrompt != "" {
+				prompt = cfg.AgentPrompt + "\n\n" + prompt
+			}
 			return "LAUNCH_AGENT:" + prompt, nil
 		}
 
 		// Edit action - open file in editor at comment line
 		editAction := func(item BrowseItem) (string, error) {
-			if item.Type == "file" {
-				return "", fmt.Errorf("cannot edit file header")
+			if item.Comment == nil {
+				return "", fmt.Errorf("cannot edit %s", rowLabel(item))
+			}
+			if err := requireInline(item.Comment, "edit the file for"); err != nil {
+				return "", err
 			}
-			return fmt.Sprintf("EDIT_FILE:%s:%d", item.Comment.Path, item.Comment.Line), nil
+			return fmt.Sprintf("EDIT_FILE:%s:%d", resolveRepoPath(item.Comment.Path), firstLine(item.Comment)), nil
 		}
 
 		// Reaction action - get comment ID for reaction
 		reactionAction := func(item BrowseItem) (int64, error) {
-			if item.Type == "file" {
-				return 0, fmt.Errorf("cannot react to file header")
+			if item.Comment == nil {
+				return 0, fmt.Errorf("cannot react to %s", rowLabel(item))
+			}
+			if err := requireInline(item.Comment, "react to"); err != nil {
+				return 0, err
 			}
 			comment := item.Comment
 			// Get the right comment based on SelectedCommentIdx
@@ -300,7 +796,7 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 		reactionComplete := func(item BrowseItem, commentID int64, apiName, displayEmoji string) (string, error) {
 			err := client.AddReactionToComment(prNumber, commentID, apiName)
 			if err != nil {
-				return "", err
+				return "", explainAPIError(err)
 			}
 
 			// Fetch updated reactions and update the cached comment
@@ -322,8 +818,7 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 				// Return a success message without the URL.
 				return fmt.Sprintf("%s reaction added.", displayEmoji), nil
 			}
-			url := fmt.Sprintf("https://github.com/%s/pull/%d#discussion_r%d", repo, prNumber, commentID)
-			link := ui.CreateHyperlink(url, "reaction added")
+			link := ui.CreateHyperlink(discussionURL(repo, prNumber, commentID), "reaction added")
 			return fmt.Sprintf("%s %s.", displayEmoji, link), nil
 		}
 
@@ -334,8 +829,8 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 		renderer.applier = app
 
 		checkSuggestionPreconditions := func(item BrowseItem) error {
-			if item.Type == "file" {
-				return fmt.Errorf("cannot apply to file header")
+			if item.Comment == nil {
+				return fmt.Errorf("cannot apply to %s", rowLabel(item))
 			}
 			if !item.Comment.HasSuggestion {
 				return fmt.Errorf("no suggestion to apply")
@@ -375,11 +870,42 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 					return msg + " (failed to resolve thread)", nil
 				}
 				item.Comment.SubjectType = "resolved"
+				item.Comment.ResolvedBy = viewerLogin
 				msg += " and resolved thread"
 			}
 			return msg, nil
 		}
 
+		// A key: apply without a preview, thank the reviewer and resolve
+		acceptSuggestionAction := func(item BrowseItem) (string, func(), error) {
+			if err := checkSuggestionPreconditions(item); err != nil {
+				return "", nil, err
+			}
+			reply := func(comment *github.ReviewComment) (func(), error) {
+				posted, err := client.ReplyToReviewComment(prNumber, comment.ID, acceptReply)
+				if err != nil {
+					return nil, explainAPIError(err)
+				}
+				return replyApplied(comment, posted), nil
+			}
+			resolve := func(comment *github.ReviewComment) (func(), error) {
+				_, apply, err := resolveCommentAction(client, comment, creditResolver)
+				return apply, explainAPIError(err)
+			}
+			return acceptSuggestion(item.Comment, app.ApplySuggestion, reply, resolve)
+		}
+
+		var watchInterval time.Duration
+		if browseWatch {
+			watchInterval = browseWatchInterval
+		}
+
+		// Comments show a body preview on a second line unless compact
+		rowHeight := 2
+		if browseCompact {
+			rowHeight = 1
+		}
+
 		selected, err := ui.Select(ui.SelectorOptions[BrowseItem]{
 			Items:    browseItems,
 			Renderer: renderer,
@@ -387,13 +913,80 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 			// Core callbacks
 			OnSelect:       onSelect,
 			OnOpen:         openAction,
-			FilterFunc:     filterFunc,
-			FilterDefault:  true, // Hide resolved comments by default
+			FilterModes:    resolvedFilterModes,
+			FilterModeFunc: rows.shows,
+			FilterDefault:  startFilters.resolvedMode != resolvedFilterAll, // As configured, or as before a PR switch
 			IsItemResolved: isItemResolved,
 			RefreshItems:   refreshItems,
+			OnDetailOpen:   onDetailOpen,
+			StatusWarning:  func() string { return rateLimitWarning(client) },
+			Preload:        preloadLogin,
+			PreviewWidth:   ui.PreviewWidthFit,
+			RowHeight:      rowHeight,
+			IsGroupHeader:  func(item BrowseItem) bool { return item.Type == "file" },
+			IsToggle:       func(item BrowseItem) bool { return item.Comment == nil },
+			InitialItem:    initialItem,
+
+			// space marks threads; B posts one reply to all of them
+			Markable:          canBatchReply,
+			BatchReplyPrepare: func(items []BrowseItem) (string, error) { return batchReplyTemplate(items), nil },
+			BatchReplyComplete: func(items []BrowseItem, body string, resume map[string]string) ui.BatchResult[BrowseItem] {
+				body, resolve := parseBatchReply(body)
+				reply := func(comment *github.ReviewComment) (func(), error) {
+					posted, err := client.ReplyToReviewComment(prNumber, comment.ID, body)
+					if err != nil {
+						return nil, explainAPIError(err)
+					}
+					return replyApplied(comment, posted), nil
+				}
+				resolveThread := func(comment *github.ReviewComment) (func(), error) {
+					if comment.IsResolved() {
+						return nil, nil
+					}
+					_, apply, err := resolveCommentAction(client, comment, creditResolver)
+					return apply, err
+				}
+				return replyToAll(items, resolve, resume, reply, resolveThread)
+			},
+			BatchReplyKey: "B batch reply",
+			BatchRetryKey: "F retry failed",
+
+			// g key: jump to a comment by ID
+			MatchesCommentID: browseItemHasComment,
+			RevealItem: func(item BrowseItem) {
+				if collapsedFiles[item.Path] {
+					collapsedFiles[item.Path] = false
+					_ = store.SetCollapsed(item.Path, false)
+				}
+				expandedFiles[item.Path] = true
+			},
+			JumpKey: "g go to comment",
+
+			Legend: browseLegend(),
+			Header: func(items []BrowseItem) string {
+				return browseHeader(renderer.repo, prNumber, items)
+			},
+
+			// Filter bar and X key: clear all filters
+			ActiveFilters:   activeFilters,
+			ClearFilters:    clearFilters,
+			ClearFiltersKey: "X clear filters",
+			EmptyMessage:    browseEmptyMessage,
+
+			// L key: lift --limit
+			LimitItems:  limit.refreshed,
+			LimitStatus: limit.status,
+			LoadAll:     limit.lift,
+			LoadAllKey:  "L load all",
+
+			// --watch: poll for new comments
+			WatchInterval:   watchInterval,
+			ItemKey:         browseItemKey,
+			DescribeChanges: describeNewComments,
+			Notify:          browseNotify,
 
 			// r/u key: resolve/unresolve
-			ResolveAction: resolveAction,
+			ResolveStart:  resolveStart,
 			ResolveKey:    "r resolve",
 			ResolveKeyAlt: "u unresolve",
 
@@ -413,6 +1006,21 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 			QuoteContextComplete: editorCompleteC,
 			QuoteContextKey:      "C quote+context",
 
+			// G key: suggestion reply via editor
+			SuggestPrepare:  editorPrepareG,
+			SuggestComplete: editorCompleteG,
+			SuggestKey:      "G suggest",
+
+			// W key: won't-fix reply and resolve
+			DismissPrepare:  editorPrepareW,
+			DismissComplete: editorCompleteW,
+			DismissKey:      "W won't fix",
+
+			// m key: short reply typed inline
+			ReplyPrepare:  replyPrepare,
+			ReplyComplete: editorCompleteQ,
+			ReplyKey:      "m reply",
+
 			// a key: launch coding agent
 			AgentAction: agentAction,
 			AgentKey:    "a agent",
@@ -425,6 +1033,56 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 			ReactionAction:   reactionAction,
 			ReactionComplete: reactionComplete,
 			ReactionKey:      "x react",
+			ReactionOrder:    cfg.Browse.ReactionOrder,
+
+			// O key: open file on GitHub at the comment line
+			OpenFilePreview: openFilePreview,
+			OpenFileAction:  openFileAction,
+			OpenFileKey:     "O open file",
+
+			// t/T keys: local triage tags
+			TagAction:       tagAction,
+			TagKey:          "t tag",
+			TagFilterToggle: toggleTodoOnly,
+			TagFilterKey:    "T todo only",
+
+			// H key: hide outdated comments
+			OutdatedFilterToggle: toggleHideOutdated,
+			OutdatedFilterKey:    "H hide outdated",
+
+			// I key: only files matching a path glob
+			SetPathGlob: setPathGlob,
+			PathGlob:    func() string { return rows.pathGlob },
+			PathGlobKey: "I path glob",
+
+			// ctrl+p: switch to another open PR, keeping the filters
+			SwitchKey: "ctrl+p switch PR",
+			OnSwitchRequested: func(mode int) {
+				browseCarried = &browseFilters{resolvedMode: mode, todoOnly: rows.todoOnly, hideOutdated: rows.hideOutdated, pathGlob: rows.pathGlob}
+			},
+			InitialFilterMode: startFilters.resolvedMode,
+
+			// D key: raw fields of the selected item (--debug only)
+			DebugInfo: browseDebugInfo,
+
+			// y key: copy gh CLI command
+			CopyCommand:    copyCommand,
+			CopyCommandKey: "y copy gh cmd",
+
+			// p key: copy a permalink to the commented code
+			CopyPermalink:    copyPermalink,
+			CopyPermalinkKey: "p copy permalink",
+
+			// c/v keys: copy the comment as a markdown quote, with v adding
+			// the diff context
+			CopyQuote:           copyQuote,
+			CopyQuoteKey:        "c copy quote",
+			CopyQuoteContext:    copyQuoteContext,
+			CopyQuoteContextKey: "v copy quote+context",
+
+			// Y key: copy unresolved comments as a markdown checklist
+			CopyAll:    copyChecklist,
+			CopyAllKey: "Y copy checklist",
 
 			// s key: apply suggestion
 			ApplySuggestionPreview: applySuggestionPreview,
@@ -434,15 +1092,32 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 			// S key: apply suggestion and resolve
 			ApplySuggestionResolveAction: applySuggestionResolveAction,
 			ApplySuggestionResolveKey:    "S apply+resolve",
+
+			// A key: apply, reply and resolve in one keystroke
+			AcceptSuggestionAction: acceptSuggestionAction,
+			AcceptSuggestionKey:    "A accept",
+
+			// P/Z keys: buffer replies as drafts, then post them as one review
+			DraftToggle:    drafts.toggle,
+			DraftCount:     func() int { return len(drafts.pending) },
+			DraftSubmit:    submitDrafts,
+			DraftKey:       "P draft mode",
+			DraftSubmitKey: "Z submit review",
 		})
+		if n := len(drafts.pending); n > 0 {
+			fmt.Fprintf(os.Stderr, "%s\n", ui.Colorize(ui.ColorYellow, fmt.Sprintf("Unsubmitted drafts discarded: %d", n)))
+		}
 		if err != nil {
 			if errors.Is(err, ui.ErrNoSelection) {
 				return nil
 			}
+			if errors.Is(err, ui.ErrSwitchRequested) {
+				return switchBrowsePR(cmd, client, prNumber)
+			}
 			return fmt.Errorf("selection cancelled: %w", err)
 		}
 
-		if selected.Type == "file" {
+		if selected.Comment == nil {
 			// If they selected a header and quit (enter), maybe just do nothing or open the file?
 			// For now, let's assume they meant to select a comment.
 			// But since we return on Enter, we need to handle it.
@@ -478,6 +1153,46 @@ func runBrowse(cmd *cobra.Command, args []string) error {
 	return openCommentInBrowser(client, prNumber, commentID)
 }
 
+// switchBrowsePR asks which open PR to browse next and reopens browse on it,
+// in the same repository and with the filters saved in browseCarried.
+// Leaving the picker goes back to the PR being browsed.
+func switchBrowsePR(cmd *cobra.Command, client *github.Client, current int) error {
+	prs, err := client.ListOpenPRs()
+	if err != nil {
+		return explainAPIError(fmt.Errorf("failed to list pull requests: %w", err))
+	}
+	next := current
+	if len(prs) > 0 {
+		selected, err := ui.SelectPR(prs)
+		switch {
+		case err == nil:
+			next = selected.Number
+		case !errors.Is(err, ui.ErrNoSelection):
+			return err
+		}
+	}
+
+	// A repo taken from a comment URL isn't in the flags; keep browsing it
+	if repo := switchRepoRef(client); repo != "" {
+		repoFlag = repo
+	}
+	browsePRNumber, browseSelect, browseReview = next, 0, 0
+	return runBrowse(cmd, nil)
+}
+
+// switchRepoRef names the client's repository for --repo, with its host
+// when it isn't gh's default, so a PR switch stays on the same server
+func switchRepoRef(client *github.Client) string {
+	repo, err := client.GetRepo()
+	if err != nil || repo == "" {
+		return ""
+	}
+	if host := client.Host(); host != "" {
+		return host + "/" + repo
+	}
+	return repo
+}
+
 func openCommentInBrowser(client *github.Client, prNumber int, commentID int64) error {
 	// Fetch review comments to find the comment URL
 	// Note: This function is only used from CLI path where we don't have cached data
@@ -524,63 +1239,409 @@ func openURLInBrowser(url string) error {
 	return nil
 }
 
-// BrowseItem represents an item in the browse list (either a file header or a comment)
+// BrowseItem represents an item in the browse list (a file header, a comment,
+// or the row standing in for a file's resolved threads)
 type BrowseItem struct {
-	Type               string // "file", "comment", "comment_preview"
+	Type               string // "file", "comment", "resolved_summary"
 	Path               string
 	Comment            *github.ReviewComment
-	IsPreview          bool
-	SelectedCommentIdx int // 0 = main comment, 1+ = thread reply index
+	SelectedCommentIdx int                     // 0 = main comment, 1+ = thread reply index
+	Threads            []*github.ReviewComment // file, resolved_summary: the file's threads, for filtering and counting as they resolve
 }
 
-// buildCommentTree converts a flat list of comments into a tree-like structure
-func buildCommentTree(comments []*github.ReviewComment) []BrowseItem {
-	// Sort comments by Path then Line
-	// We need a stable sort for the tree structure
-	// Make a copy to avoid modifying original slice if needed
-	sortedComments := make([]*github.ReviewComment, len(comments))
-	copy(sortedComments, comments)
-
-	// Simple bubble sort or similar isn't needed, just use standard sort with custom comparator
-	// But we need to import sort. Let's do it manually or add import.
-	// Since I can't easily add imports without context, I'll assume sort is available or use a simple swap.
-	// Actually, let's just use a simple grouping logic.
-
-	// Group by file
-	files := make(map[string][]*github.ReviewComment)
-	var filePaths []string
+// rowLabel names a row that isn't a comment, for errors from comment actions
+func rowLabel(item BrowseItem) string {
+	if item.Type == "resolved_summary" {
+		return "resolved summary"
+	}
+	return "file header"
+}
 
-	for _, c := range comments {
-		if _, exists := files[c.Path]; !exists {
-			filePaths = append(filePaths, c.Path)
+// withResolvedSummaries adds a resolved_summary row under each file header,
+// which the collapsing resolved filter shows in place of resolved threads.
+// Every file gets one since threads can be resolved after the list is built.
+// Headers and summaries both get the file's threads.
+func withResolvedSummaries(items []BrowseItem) []BrowseItem {
+	result := make([]BrowseItem, 0, len(items)+len(items)/4)
+	for i, item := range items {
+		if item.Type != "file" {
+			result = append(result, item)
+			continue
 		}
-		files[c.Path] = append(files[c.Path], c)
+		var threads []*github.ReviewComment
+		for _, next := range items[i+1:] {
+			if next.Type == "file" {
+				break
+			}
+			threads = append(threads, next.Comment)
+		}
+		item.Threads = threads
+		result = append(result, item, BrowseItem{Type: "resolved_summary", Path: item.Path, Threads: threads})
 	}
+	return result
+}
 
-	// Sort file paths
-	// We need to sort strings. I'll implement a simple string sort since I can't see imports easily.
-	for i := 0; i < len(filePaths); i++ {
-		for j := i + 1; j < len(filePaths); j++ {
-			if filePaths[i] > filePaths[j] {
-				filePaths[i], filePaths[j] = filePaths[j], filePaths[i]
-			}
+// resolvedCount is the number of a summary row's threads currently resolved
+func resolvedCount(item BrowseItem) int {
+	n := 0
+	for _, c := range item.Threads {
+		if c.IsResolved() {
+			n++
 		}
 	}
+	return n
+}
 
-	var items []BrowseItem
+// batchReplyDelay spaces out batch replies to stay clear of GitHub's
+// secondary rate limits on content creation
+var batchReplyDelay = time.Second
 
-	for _, path := range filePaths {
-		// Add File Header
-		items = append(items, BrowseItem{
-			Type: "file",
-			Path: path,
-		})
+// The steps of a batch reply an item can fail at, so a retry resumes there
+const (
+	batchStepReply   = "reply"
+	batchStepResolve = "resolve"
+)
 
-		// Sort comments in this file by line
+// resolveDirective on a line of its own in a batch reply resolves each
+// thread after replying
+const resolveDirective = "/resolve"
+
+// canBatchReply reports whether item is a thread that B can reply to
+func canBatchReply(item BrowseItem) bool {
+	return item.Type == "comment" && item.Comment != nil && item.Comment.Kind == github.CommentKindInline
+}
+
+// batchReplyTemplate is the editor content for a batch reply, listing the
+// marked threads below the template marker
+func batchReplyTemplate(items []BrowseItem) string {
+	instructions := []string{
+		fmt.Sprintf("Write one reply above; it is posted to each of these %d threads:", len(items)),
+	}
+	for _, item := range items {
+		instructions = append(instructions, fmt.Sprintf("  %s:%d @%s", item.Comment.Path, item.Comment.Line, item.Comment.Author))
+	}
+	instructions = append(instructions,
+		fmt.Sprintf("Add a line with just %s to also resolve each thread.", resolveDirective),
+		"Everything from the marker line down is ignored.",
+	)
+	return "\n" + ui.FormatEditorTemplate(instructions...)
+}
+
+// parseBatchReply removes resolveDirective lines from body and reports
+// whether one was present
+func parseBatchReply(body string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	kept := lines[:0]
+	resolve := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == resolveDirective {
+			resolve = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n")), resolve
+}
+
+// defaultWontFixPrefix opens W replies unless browse.wont_fix_prefix is set
+const defaultWontFixPrefix = "Won't fix: "
+
+// wontFixTemplate is the editor buffer for W: the canned opener, to be
+// finished with a reason
+func wontFixTemplate(prefix string) string {
+	return strings.TrimRight(prefix, " ") + " \n\n" + ui.FormatEditorTemplate(
+		"Finish the first line with your reason; the reply is posted and the thread resolved.",
+		"Everything from the marker line down is ignored.",
+	)
+}
+
+// wontFix posts body as a reply to the comment's thread and resolves it,
+// reporting both. A body that is only the prefix is refused so a thread
+// isn't dismissed without a reason.
+func wontFix(comment *github.ReviewComment, body, prefix string,
+	reply func(*github.ReviewComment, string) (*github.ThreadComment, error),
+	resolve func(*github.ReviewComment) (string, func(), error)) (string, func(), error) {
+	if strings.TrimSpace(strings.TrimPrefix(body, strings.TrimSpace(prefix))) == "" {
+		return "", nil, fmt.Errorf("add a reason after %q", strings.TrimSpace(prefix))
+	}
+
+	posted, err := reply(comment, body)
+	if err != nil {
+		return "", nil, explainAPIError(fmt.Errorf("failed to add comment: %w", err))
+	}
+	addReply := replyApplied(comment, posted)
+	link := ui.CreateHyperlink(posted.HTMLURL, "a won't-fix reply")
+
+	if comment.IsResolved() {
+		return fmt.Sprintf("Posted %s; the thread was already resolved.", link), addReply, nil
+	}
+	_, resolved, err := resolve(comment)
+	if err != nil {
+		return "", combineApplies(addReply, resolved), explainAPIError(fmt.Errorf("posted %s but failed to resolve the thread: %w", posted.HTMLURL, err))
+	}
+	return fmt.Sprintf("Posted %s and resolved the thread.", link), combineApplies(addReply, resolved), nil
+}
+
+// acceptReply is posted on a thread whose suggestion was accepted with A
+const acceptReply = "Applied, thanks!"
+
+// acceptSuggestion applies comment's suggestion, replies with acceptReply and
+// resolves the thread, reporting how each step went. If the apply fails, the
+// file is restored and nothing is posted. reply and resolve leave comment
+// alone and return what to record on it, which the result's apply does.
+func acceptSuggestion(comment *github.ReviewComment, apply func(*github.ReviewComment) error, reply, resolve func(*github.ReviewComment) (func(), error)) (string, func(), error) {
+	original, err := os.ReadFile(comment.Path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read %s: %w", comment.Path, err)
+	}
+	if err := apply(comment); err != nil {
+		if restoreErr := os.WriteFile(comment.Path, original, 0o644); restoreErr != nil {
+			return "", nil, fmt.Errorf("apply failed: %v; restoring %s also failed: %w", err, comment.Path, restoreErr)
+		}
+		return "", nil, fmt.Errorf("apply failed, %s left unchanged; nothing was posted: %w", comment.Path, err)
+	}
+
+	steps := []string{ui.Colorize(ui.ColorGreen, fmt.Sprintf("✓ Applied suggestion to %s:%d", comment.Path, comment.Line))}
+	addReply, err := reply(comment)
+	if err != nil {
+		steps = append(steps,
+			ui.Colorize(ui.ColorRed, "✗ Reply failed: "+err.Error()),
+			ui.Colorize(ui.ColorGray, "- Thread left unresolved"))
+		return strings.Join(steps, "\n"), nil, nil
+	}
+	steps = append(steps, ui.Colorize(ui.ColorGreen, fmt.Sprintf("✓ Replied %q", acceptReply)))
+
+	var resolved func()
+	if comment.IsResolved() {
+		steps = append(steps, ui.Colorize(ui.ColorGray, "- Thread was already resolved"))
+	} else if resolved, err = resolve(comment); err != nil {
+		steps = append(steps, ui.Colorize(ui.ColorRed, "✗ Resolve failed: "+err.Error()))
+	} else {
+		steps = append(steps, ui.Colorize(ui.ColorGreen, "✓ Resolved thread"))
+	}
+	return strings.Join(steps, "\n"), combineApplies(addReply, resolved), nil
+}
+
+// draftReview holds replies written in draft mode (P) until Z submits them
+// together as one review, the way "Start a review" does on GitHub. pending
+// only changes on the UI goroutine, where the footer reads it; on is also
+// read by replies being sent in the background.
+type draftReview struct {
+	on         atomic.Bool
+	pending    []draftComment
+	submitting bool
+}
+
+// draftComment is a buffered reply and the comment whose thread it goes to
+type draftComment struct {
+	comment *github.ReviewComment
+	body    string
+}
+
+// toggle switches draft mode, keeping any drafts already buffered
+func (d *draftReview) toggle() string {
+	on := !d.on.Load()
+	d.on.Store(on)
+	if on {
+		return "Draft mode: replies are saved until Z submits them as a review"
+	}
+	if n := len(d.pending); n > 0 {
+		return fmt.Sprintf("Draft mode off; %d pending, Z submits them", n)
+	}
+	return "Draft mode off"
+}
+
+// add buffers body as a reply to comment's thread
+func (d *draftReview) add(comment *github.ReviewComment, body string) {
+	d.pending = append(d.pending, draftComment{comment: comment, body: body})
+}
+
+// submission takes the buffered drafts and returns the call that posts them
+// with post in the background. The call leaves the drafts alone; its apply
+// adds each reply to its comment's local thread and drops the submitted
+// drafts, keeping any written meanwhile. The drafts are kept if posting fails
+// so they can be submitted again.
+func (d *draftReview) submission(post func([]github.DraftReply) ([]github.ThreadComment, string, error)) (func() (string, func(), error), error) {
+	if d.submitting {
+		return nil, errors.New("already submitting the review")
+	}
+	d.submitting = true
+	taken := slices.Clone(d.pending)
+	drafts := make([]github.DraftReply, len(taken))
+	for i, draft := range taken {
+		drafts[i] = github.DraftReply{ThreadID: draft.comment.ThreadID, Body: draft.body}
+	}
+
+	return func() (string, func(), error) {
+		replies, url, err := post(drafts)
+		if err != nil {
+			done := func() { d.submitting = false }
+			return "", done, explainAPIError(fmt.Errorf("failed to submit review, %d drafts kept: %w", len(drafts), err))
+		}
+		apply := func() {
+			d.submitting = false
+			for i, reply := range replies {
+				comment := taken[i].comment
+				comment.ThreadComments = append(comment.ThreadComments, reply)
+			}
+			d.pending = slices.Delete(d.pending, 0, len(taken))
+		}
+
+		review := "a review"
+		if url != "" {
+			review = ui.CreateHyperlink(url, review)
+		}
+		if len(replies) == 1 {
+			return fmt.Sprintf("Submitted %s with 1 reply.", review), apply, nil
+		}
+		return fmt.Sprintf("Submitted %s with %d replies.", review, len(replies)), apply, nil
+	}, nil
+}
+
+// replyToAll posts a reply to each item's thread in turn, pausing
+// batchReplyDelay between posts, and resolves each thread when asked. One
+// failure doesn't stop the rest; the result lists every thread that failed
+// and why, keyed by browseItemKey. reply and resolveThread leave the comments
+// alone and return what to record on them, gathered into the result's Apply.
+// resume is the FailedStep of the batch being retried, if any.
+func replyToAll(items []BrowseItem, resolve bool, resume map[string]string, reply, resolveThread func(*github.ReviewComment) (func(), error)) ui.BatchResult[BrowseItem] {
+	result := ui.BatchResult[BrowseItem]{Errors: make(map[string]error), FailedStep: make(map[string]string)}
+	var applies []func()
+	fail := func(item BrowseItem, step string, err error) {
+		result.Failed = append(result.Failed, item)
+		result.Errors[browseItemKey(item)] = err
+		result.FailedStep[browseItemKey(item)] = step
+	}
+	replied, resolved := 0, 0
+	for i, item := range items {
+		if i > 0 {
+			time.Sleep(batchReplyDelay)
+		}
+		comment := item.Comment
+		where := fmt.Sprintf("%s:%d", comment.Path, comment.Line)
+		// A retry of an item whose reply already went out only resolves
+		if resume[browseItemKey(item)] != batchStepResolve {
+			apply, err := reply(comment)
+			if err != nil {
+				fail(item, batchStepReply, fmt.Errorf("%s (%w)", where, err))
+				continue
+			}
+			applies = append(applies, apply)
+		}
+		replied++
+		if resolve {
+			apply, err := resolveThread(comment)
+			applies = append(applies, apply)
+			if err != nil {
+				fail(item, batchStepResolve, fmt.Errorf("%s resolve (%w)", where, err))
+				continue
+			}
+			resolved++
+		}
+		result.Succeeded = append(result.Succeeded, item)
+	}
+	result.Apply = combineApplies(applies...)
+
+	result.Summary = fmt.Sprintf("Replied to %d of %d threads", replied, len(items))
+	if resolve {
+		result.Summary += fmt.Sprintf(", resolved %d", resolved)
+	}
+	return result
+}
+
+// quotedComment returns the author and body of the comment or reply selected
+// in item
+func quotedComment(item BrowseItem) (string, string) {
+	comment := item.Comment
+	if item.SelectedCommentIdx > 0 && item.SelectedCommentIdx-1 < len(comment.ThreadComments) {
+		tc := comment.ThreadComments[item.SelectedCommentIdx-1]
+		return tc.Author, tc.Body
+	}
+	return comment.Author, comment.Body
+}
+
+// quoteForClipboard formats the selected comment the way Q (or C, with
+// includeContext) would quote it, without the blank lines left for a reply
+func quoteForClipboard(item BrowseItem, includeContext bool) (string, error) {
+	if item.Comment == nil {
+		return "", fmt.Errorf("cannot quote a %s", rowLabel(item))
+	}
+	comment := item.Comment
+	author, body := quotedComment(item)
+	contextLines := 0
+	if includeContext {
+		contextLines = browseQuoteContext
+	}
+	quote := ui.FormatQuotedReply(author, body, comment.DiffHunk, comment.Path, includeContext, contextLines, browseWrapQuotes)
+	return strings.TrimRight(quote, " \n"), nil
+}
+
+// browseItemHasComment reports whether id is the item's comment or one of its replies
+func browseItemHasComment(item BrowseItem, id int64) bool {
+	if item.Type != "comment" || item.Comment == nil {
+		return false
+	}
+	if item.Comment.ID == id {
+		return true
+	}
+	for _, tc := range item.Comment.ThreadComments {
+		if tc.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// buildCommentTree converts a flat list of comments into a tree-like structure:
+// a header per file followed by its comments in line order
+func buildCommentTree(comments []*github.ReviewComment) []BrowseItem {
+	// Sort comments by Path then Line
+	// We need a stable sort for the tree structure
+	// Make a copy to avoid modifying original slice if needed
+	sortedComments := make([]*github.ReviewComment, len(comments))
+	copy(sortedComments, comments)
+
+	// Simple bubble sort or similar isn't needed, just use standard sort with custom comparator
+	// But we need to import sort. Let's do it manually or add import.
+	// Since I can't easily add imports without context, I'll assume sort is available or use a simple swap.
+	// Actually, let's just use a simple grouping logic.
+
+	// Group by file
+	files := make(map[string][]*github.ReviewComment)
+	var filePaths []string
+
+	for _, c := range comments {
+		group := commentGroup(c)
+		if _, exists := files[group]; !exists {
+			filePaths = append(filePaths, group)
+		}
+		files[group] = append(files[group], c)
+	}
+
+	// Sort file paths
+	// We need to sort strings. I'll implement a simple string sort since I can't see imports easily.
+	for i := 0; i < len(filePaths); i++ {
+		for j := i + 1; j < len(filePaths); j++ {
+			if filePaths[i] > filePaths[j] {
+				filePaths[i], filePaths[j] = filePaths[j], filePaths[i]
+			}
+		}
+	}
+
+	var items []BrowseItem
+
+	for _, path := range filePaths {
+		// Add File Header
+		items = append(items, BrowseItem{
+			Type: "file",
+			Path: path,
+		})
+
+		// Sort comments in this file by line
 		fileComments := files[path]
 		for i := 0; i < len(fileComments); i++ {
 			for j := i + 1; j < len(fileComments); j++ {
-				if fileComments[i].Line > fileComments[j].Line {
+				if commentLess(fileComments[j], fileComments[i]) {
 					fileComments[i], fileComments[j] = fileComments[j], fileComments[i]
 				}
 			}
@@ -588,31 +1649,677 @@ func buildCommentTree(comments []*github.ReviewComment) []BrowseItem {
 
 		// Add Comments
 		for _, c := range fileComments {
-			// Main comment item
 			items = append(items, BrowseItem{
 				Type:    "comment",
 				Path:    path,
 				Comment: c,
 			})
-			// Preview item (skippable)
-			items = append(items, BrowseItem{
-				Type:      "comment_preview",
-				Path:      path,
-				Comment:   c,
-				IsPreview: true,
-			})
 		}
 	}
 
 	return items
 }
 
+// limitComments keeps the n threads to read first: unresolved before
+// resolved, then the most recently active. Ties go to the newer comment ID
+// so the choice doesn't depend on fetch order. 0 keeps every thread.
+func limitComments(comments []*github.ReviewComment, n int) []*github.ReviewComment {
+	if n <= 0 || len(comments) <= n {
+		return comments
+	}
+	ranked := slices.Clone(comments)
+	slices.SortStableFunc(ranked, func(a, b *github.ReviewComment) int {
+		if a.IsResolved() != b.IsResolved() {
+			if a.IsResolved() {
+				return 1
+			}
+			return -1
+		}
+		if c := lastActivity(b).Compare(lastActivity(a)); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return ranked[:n]
+}
+
+// threadLimit caps browse at the top max threads (--limit) until lifted.
+// It is only used on the UI goroutine.
+type threadLimit struct {
+	max    int
+	lifted bool
+	all    []*github.ReviewComment // every thread fetched, listed or not
+}
+
+// items lists comments' threads, capped unless the limit is lifted
+func (l *threadLimit) items(comments []*github.ReviewComment) []BrowseItem {
+	l.all = comments
+	if !l.lifted {
+		comments = limitComments(comments, l.max)
+	}
+	return withResolvedSummaries(buildCommentTree(comments))
+}
+
+// refreshed caps a refreshed list, whose items hold every thread fetched
+func (l *threadLimit) refreshed(items []BrowseItem) []BrowseItem {
+	if l.max <= 0 {
+		return items
+	}
+	var comments []*github.ReviewComment
+	for _, item := range items {
+		if item.Type == "comment" {
+			comments = append(comments, item.Comment)
+		}
+	}
+	return l.items(comments)
+}
+
+// status is e.g. "showing 50 of 312", or "" when nothing is left out
+func (l *threadLimit) status() string {
+	if l.lifted || l.max <= 0 || len(l.all) <= l.max {
+		return ""
+	}
+	return fmt.Sprintf("showing %d of %d", l.max, len(l.all))
+}
+
+// lift lists every thread from now on
+func (l *threadLimit) lift() []BrowseItem {
+	l.lifted = true
+	return l.items(l.all)
+}
+
+// lastActivity is when the thread was last commented on
+func lastActivity(c *github.ReviewComment) time.Time {
+	latest := c.CreatedAt
+	for _, reply := range c.ThreadComments {
+		if reply.CreatedAt.After(latest) {
+			latest = reply.CreatedAt
+		}
+	}
+	return latest
+}
+
+// commentGroup is the file header a comment is listed under
+func commentGroup(c *github.ReviewComment) string {
+	if c.Kind != github.CommentKindInline {
+		return conversationGroup
+	}
+	return c.Path
+}
+
+// commentLess orders comments by line, then by time for comments on the same
+// line (conversation comments all have line 0)
+func commentLess(a, b *github.ReviewComment) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
+
+// parseBrowseURL reads a comment URL given to browse: the GitHub host and
+// repository it belongs to, its PR number and the comment's ID
+func parseBrowseURL(arg string) (host, repo string, prNumber int, commentID int64, err error) {
+	prNumber, commentID, err = github.ParseCommentURL(arg)
+	if err != nil {
+		return "", "", 0, 0, err
+	}
+	// ParseCommentURL has checked the path starts with OWNER/REPO/pull
+	u, _ := url.Parse(strings.TrimSpace(arg))
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	host = strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	return host, parts[0] + "/" + parts[1], prNumber, commentID, nil
+}
+
+// parseIncludeKinds converts --include values to comment kinds
+func parseIncludeKinds(values []string) ([]github.CommentKind, error) {
+	var kinds []github.CommentKind
+	for _, v := range values {
+		kind, ok := includeKinds[strings.ToLower(strings.TrimSpace(v))]
+		if !ok {
+			return nil, fmt.Errorf("invalid --include value %q (want summary or issue)", v)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+// requireInline rejects actions that only work on inline review comments
+func requireInline(comment *github.ReviewComment, action string) error {
+	if comment.Kind != github.CommentKindInline {
+		return fmt.Errorf("cannot %s a %s", action, commentKindLabel(comment.Kind))
+	}
+	return nil
+}
+
+// commentKindLabel names a comment kind in titles and messages
+func commentKindLabel(kind github.CommentKind) string {
+	switch kind {
+	case github.CommentKindSummary:
+		return "review summary"
+	case github.CommentKindIssue:
+		return "conversation comment"
+	}
+	return "review comment"
+}
+
+// commentKindIcon marks comments that aren't inline review comments
+func commentKindIcon(kind github.CommentKind) string {
+	switch kind {
+	case github.CommentKindSummary:
+		return ui.EmojiText("📝", "[review]")
+	case github.CommentKindIssue:
+		return ui.EmojiText("💬", "[issue]")
+	}
+	return ""
+}
+
+// withoutBotComments drops threads started by bots (see ui.IsBotAuthor)
+func withoutBotComments(comments []*github.ReviewComment) []*github.ReviewComment {
+	filtered := make([]*github.ReviewComment, 0, len(comments))
+	for _, c := range comments {
+		if !ui.IsBotAuthor(c.Author) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// commentsInReview keeps the threads reviewID took part in: ones it started,
+// ones it replied to, and its own summary
+func commentsInReview(comments []*github.ReviewComment, reviewID int64) []*github.ReviewComment {
+	filtered := make([]*github.ReviewComment, 0, len(comments))
+	for _, c := range comments {
+		inReview := c.ReviewID == reviewID || (c.Kind == github.CommentKindSummary && c.ID == reviewID)
+		for _, tc := range c.ThreadComments {
+			inReview = inReview || tc.ReviewID == reviewID
+		}
+		if inReview {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// fileBlobURL links to the commented file at the comment's line on the commit
+// the comment was made against
+func fileBlobURL(repo string, comment *github.ReviewComment) (string, error) {
+	if comment.HeadSHA == "" {
+		return "", fmt.Errorf("comment has no commit SHA")
+	}
+	return blobURL(commentHost(comment), repo, comment.HeadSHA, comment.Path, comment.StartLine, comment.Line), nil
+}
+
+// codePermalink links to the lines a comment was first written on, pinned to
+// that commit so the link keeps pointing at the same code. Comments without
+// an original position fall back to their current one.
+func codePermalink(repo string, comment *github.ReviewComment) (string, error) {
+	if comment.DiffSide == diffposition.DiffSideLeft {
+		return "", fmt.Errorf("comment is on removed lines; there is no permalink to them on the PR's commits")
+	}
+	sha, start, end := comment.OriginalCommitID, comment.OriginalStartLine, comment.OriginalLine
+	if sha == "" || end == 0 {
+		sha, start, end = comment.HeadSHA, comment.StartLine, comment.Line
+	}
+	if sha == "" {
+		return "", fmt.Errorf("comment has no commit SHA")
+	}
+	return blobURL(commentHost(comment), repo, sha, comment.Path, start, end), nil
+}
+
+// commentHost returns the GitHub host from the comment's URL, so GitHub
+// Enterprise links work
+func commentHost(comment *github.ReviewComment) string {
+	if parsed, err := url.Parse(comment.HTMLURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return "github.com"
+}
+
+// blobURL links to path at sha, anchored to lines start-end, or just end when
+// start doesn't begin a range
+func blobURL(host, repo, sha, path string, start, end int) string {
+	link := fmt.Sprintf("https://%s/%s/blob/%s/%s", host, repo, sha, path)
+	switch {
+	case end > 0 && start > 0 && start < end:
+		link += fmt.Sprintf("#L%d-L%d", start, end)
+	case end > 0:
+		link += fmt.Sprintf("#L%d", end)
+	}
+	return link
+}
+
+// browseLegend explains the list's colors and markers in the help overlay,
+// rendering each sample with the same code that draws the list
+func browseLegend() []ui.LegendEntry {
+	header := (&browseItemRenderer{}).Title(BrowseItem{Type: "file", Path: "main.go"})
+	return []ui.LegendEntry{
+		{Sample: header, Meaning: "file (enter to collapse)"},
+		{Sample: ui.NewReviewListStyle("reviewer", false).FormatCommentTitle(0), Meaning: "open thread from a person, bold (a color per author)"},
+		{Sample: ui.NewReviewListStyle("ci[bot]", false).FormatCommentTitle(0), Meaning: "open thread from a bot, faint"},
+		{Sample: ui.NewReviewListStyle("reviewer", true).FormatCommentTitle(0), Meaning: "resolved thread, faint and crossed out"},
+		{Sample: ui.Colorize(ui.ColorGray, "▸ 3 resolved"), Meaning: "a file's resolved threads, collapsed by h (enter to expand)"},
+		{Sample: ui.Colorize(ui.ColorMagenta, ui.EmojiText("●", "*")), Meaning: "thread has unread comments"},
+		{Sample: ui.NewStatusStyle(false).Format(false), Meaning: "thread still open"},
+		{Sample: ui.NewStatusStyle(true).Format(false), Meaning: "thread resolved"},
+		{Sample: strings.TrimSpace(formatTag(state.TagTodo)), Meaning: "local triage tag (t)"},
+		{Sample: ui.Colorize(ui.ColorMagenta, "▶▶▶"), Meaning: "selected comment in the detail view"},
+	}
+}
+
+// rangeLength is how many lines a comment spans: 1 for a single line, 0 for
+// a file-level comment
+func rangeLength(comment *github.ReviewComment) int {
+	if comment.Line <= 0 {
+		return 0
+	}
+	return comment.Line - firstLine(comment) + 1
+}
+
+// firstLine is where a comment's line range starts; StartLine for a
+// multi-line comment, otherwise Line
+func firstLine(comment *github.ReviewComment) int {
+	if comment.StartLine > 0 && comment.StartLine < comment.Line {
+		return comment.StartLine
+	}
+	return comment.Line
+}
+
+// lineSpan formats a comment's lines as "12" or "10-14"
+func lineSpan(comment *github.ReviewComment) string {
+	if rangeLength(comment) > 1 {
+		return fmt.Sprintf("%d-%d", comment.StartLine, comment.Line)
+	}
+	return strconv.Itoa(comment.Line)
+}
+
+// lineLabel formats a comment's lines for titles: "Line 12" or "Lines 10-14"
+func lineLabel(comment *github.ReviewComment) string {
+	if rangeLength(comment) > 1 {
+		return "Lines " + lineSpan(comment)
+	}
+	return "Line " + lineSpan(comment)
+}
+
+// ghCommandForComment returns the gh CLI command that fetches a review comment
+func ghCommandForComment(repo string, commentID int64) string {
+	return fmt.Sprintf("gh api repos/%s/pulls/comments/%d", repo, commentID)
+}
+
+// localContextRadius is how many lines around the comment line "Current code" shows
+const localContextRadius = 3
+
+// ReadLocalContext returns lines first..last (1-based) plus radius lines on
+// either side in the file at path, as it currently exists in the local
+// checkout. Pass the same line twice for a single-line comment.
+func ReadLocalContext(path string, first, last, radius int) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if last < 1 || last > len(lines) {
+		return "", fmt.Errorf("line %d is beyond the end of %s (%d lines)", last, path, len(lines))
+	}
+	if first < 1 || first > last {
+		first = last
+	}
+
+	start := first - radius
+	if start < 1 {
+		start = 1
+	}
+	end := last + radius
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return strings.Join(lines[start-1:end], "\n"), nil
+}
+
+// renderLocalContext renders the current code around lines first..last with
+// syntax highlighting, or a note explaining why it isn't available
+func renderLocalContext(path string, first, last int) string {
+	code, err := ReadLocalContext(path, first, last, localContextRadius)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ui.Colorize(ui.ColorGray, "File not found in the local checkout (deleted or renamed?)")
+		}
+		return ui.Colorize(ui.ColorGray, fmt.Sprintf("Current code unavailable: %v", err))
+	}
+
+	md := fmt.Sprintf("```%s\n%s\n```", ui.CodeFenceLanguageFromPath(path), code)
+	if rendered, err := ui.RenderMarkdown(md); err == nil && rendered != "" {
+		return rendered
+	}
+	return code
+}
+
+// browseHeader summarizes the session for the list header:
+// "owner/repo #123 — 2 unresolved / 5 total"
+func browseHeader(repo string, prNumber int, items []BrowseItem) string {
+	total, unresolved := 0, 0
+	for _, item := range items {
+		if item.Type != "comment" {
+			continue
+		}
+		total++
+		if !item.Comment.IsResolved() {
+			unresolved++
+		}
+	}
+	return fmt.Sprintf("%s #%d — %d unresolved / %d total", repo, prNumber, unresolved, total)
+}
+
+// matchesResolvedFilter reports whether comment is shown in the given
+// resolved filter state; login is the authenticated user for resolvedFilterMine
+func matchesResolvedFilter(comment *github.ReviewComment, mode int, login string) bool {
+	switch mode {
+	case resolvedFilterHide:
+		return !comment.IsResolved()
+	case resolvedFilterMine:
+		return comment.IsResolved() && login != "" && strings.EqualFold(comment.ResolvedBy, login)
+	default:
+		return true
+	}
+}
+
+// browseEmptyMessage explains an empty list: nothing left unresolved, other
+// filters hiding everything, or no comments at all
+func browseEmptyMessage(filters []string) string {
+	switch {
+	case len(filters) == 0:
+		return "No review comments"
+	case len(filters) == 1 && filters[0] == strings.ToLower(resolvedFilterModes[resolvedFilterHide]):
+		return ui.EmojiText("No unresolved comments 🎉", "No unresolved comments") + " — press h to show all"
+	default:
+		return fmt.Sprintf("No comments match the filters (%s) — press X to clear them", strings.Join(filters, ", "))
+	}
+}
+
+// matchPathGlob reports whether name matches pattern, a path.Match glob in
+// which a "**" segment stands for any number of directories. A pattern
+// without a slash is matched against the file name in any directory, as in
+// .gitignore.
+func matchPathGlob(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(name))
+		return matched
+	}
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchGlobSegments matches a glob split at slashes against a path split the
+// same way, trying every number of directories for each "**"
+func matchGlobSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlobSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// validPathGlob checks a glob's syntax before it is applied, since a bad
+// pattern would otherwise just match nothing
+func validPathGlob(pattern string) error {
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("bad path glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// browseItemKey identifies an item across refreshes
+func browseItemKey(item BrowseItem) string {
+	if item.Comment == nil {
+		return item.Type + ":" + item.Path
+	}
+	return fmt.Sprintf("%s:%d", item.Type, item.Comment.ID)
+}
+
+// browseDebugInfo lists an item's raw fields for the --debug overlay, so
+// problems like a missing thread ID can be seen in the data itself
+func browseDebugInfo(item BrowseItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "type:        %s\n", item.Type)
+	fmt.Fprintf(&b, "path:        %s\n", item.Path)
+	c := item.Comment
+	if c == nil {
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "kind:        %s\n", commentKindLabel(c.Kind))
+	fmt.Fprintf(&b, "comment ID:  %d\n", c.ID)
+	fmt.Fprintf(&b, "node ID:     %s\n", debugValue(c.NodeID))
+	fmt.Fprintf(&b, "thread ID:   %s\n", debugValue(c.ThreadID))
+	fmt.Fprintf(&b, "review ID:   %d\n", c.ReviewID)
+	fmt.Fprintf(&b, "line:        %d (start %d, original %d-%d)\n", c.Line, c.StartLine, c.OriginalStartLine, c.OriginalLine)
+	fmt.Fprintf(&b, "side:        %s\n", debugValue(string(c.DiffSide)))
+	fmt.Fprintf(&b, "subject:     %s\n", debugValue(c.SubjectType))
+	fmt.Fprintf(&b, "resolved:    %t\n", c.IsResolved())
+	fmt.Fprintf(&b, "outdated:    %t\n", c.IsOutdated)
+	fmt.Fprintf(&b, "head SHA:    %s\n", debugValue(c.HeadSHA))
+	fmt.Fprintf(&b, "replies:     %d\n", len(c.ThreadComments))
+	fmt.Fprintf(&b, "URL:         %s\n", debugValue(c.HTMLURL))
+	for i, reply := range c.ThreadComments {
+		fmt.Fprintf(&b, "reply %d:     %d %s\n", i+1, reply.ID, debugValue(reply.HTMLURL))
+	}
+	return b.String()
+}
+
+// debugValue shows empty fields explicitly in the debug overlay
+func debugValue(s string) string {
+	if s == "" {
+		return "(empty)"
+	}
+	return s
+}
+
+// countNewComments returns how many comments and replies in updated are not in old
+func countNewComments(old, updated []BrowseItem) int {
+	seen := make(map[int64]bool)
+	for _, item := range old {
+		if item.Type != "comment" {
+			continue
+		}
+		seen[item.Comment.ID] = true
+		for _, tc := range item.Comment.ThreadComments {
+			seen[tc.ID] = true
+		}
+	}
+
+	count := 0
+	for _, item := range updated {
+		if item.Type != "comment" {
+			continue
+		}
+		if !seen[item.Comment.ID] {
+			count++
+		}
+		for _, tc := range item.Comment.ThreadComments {
+			if !seen[tc.ID] {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// describeNewComments is the watch-mode status for newly arrived comments
+func describeNewComments(old, updated []BrowseItem) string {
+	switch n := countNewComments(old, updated); n {
+	case 0:
+		return ""
+	case 1:
+		return "1 new comment"
+	default:
+		return fmt.Sprintf("%d new comments", n)
+	}
+}
+
+// formatTag renders a local triage tag as a colored title prefix
+func formatTag(tag state.Tag) string {
+	var color string
+	switch tag {
+	case state.TagTodo:
+		color = ui.ColorYellow
+	case state.TagDoing:
+		color = ui.ColorCyan
+	case state.TagDone:
+		color = ui.ColorGreen
+	default:
+		return ""
+	}
+	return ui.Colorize(color, "["+string(tag)+"]") + " "
+}
+
 // browseItemRenderer implements ui.ItemRenderer for BrowseItem
 type browseItemRenderer struct {
 	repo           string
 	prNumber       int
 	collapsedFiles map[string]bool
+	expandedFiles  map[string]bool // files whose resolved threads show under their summary row
 	applier        *applier.Applier
+	state          *state.Store
+	previewWidth   int                        // columns available to a list title; 0 uses the defaults
+	compact        bool                       // show the body on the comment row instead of the line below
+	collapseBots   bool                       // leave out the body preview for threads started by bots
+	repoRoot       string                     // local checkout root for reading current code; "" uses the working directory
+	fileStats      map[string]github.FileStat // diff size per path; files missing from the diff show none
+	foldReplies    bool                       // fold all but the latest replyLimit replies in the detail view
+	replyLimit     int
+	aligned        bool // pad paths, authors, and locations into columns
+	pathWidth      int  // column widths measured by MeasureColumns
+	authorWidth    int
+	locationWidth  int
+}
+
+// SetPreviewWidth implements ui.PreviewWidthSetter
+func (r *browseItemRenderer) SetPreviewWidth(width int) {
+	r.previewWidth = width
+}
+
+// MeasureColumns implements ui.ColumnMeasurer for the aligned layout
+func (r *browseItemRenderer) MeasureColumns(items []BrowseItem) {
+	if !r.aligned {
+		return
+	}
+	r.pathWidth, r.authorWidth, r.locationWidth = 0, 0, 0
+	for _, item := range items {
+		if item.Type == "file" {
+			r.pathWidth = max(r.pathWidth, ui.DisplayWidth(item.Path))
+			continue
+		}
+		if item.Comment == nil {
+			continue
+		}
+		r.authorWidth = max(r.authorWidth, ui.DisplayWidth(commentAuthorLabel(item.Comment)))
+		r.locationWidth = max(r.locationWidth, ui.DisplayWidth(commentLocation(item.Comment)))
+	}
+	// A deep path or long bot name would push every row's tail off a narrow
+	// terminal; longer values are left unpadded instead
+	if r.previewWidth > 0 {
+		r.pathWidth = min(r.pathWidth, r.previewWidth/2)
+		r.authorWidth = min(r.authorWidth, r.previewWidth/4)
+		r.locationWidth = min(r.locationWidth, r.previewWidth/4)
+	}
+}
+
+// padColumn pads s with spaces to width terminal columns
+func padColumn(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-ui.DisplayWidth(s)))
+}
+
+// SetVisibleReplies implements ui.ReplyFolder
+func (r *browseItemRenderer) SetVisibleReplies(n int) {
+	r.foldReplies = n >= 0
+	r.replyLimit = n
+}
+
+// foldedReplies is how many of a thread's oldest replies the detail view
+// folds; a highlighted reply is always shown
+func (r *browseItemRenderer) foldedReplies(replies, highlightIdx int) int {
+	if !r.foldReplies || replies <= r.replyLimit {
+		return 0
+	}
+	folded := replies - r.replyLimit
+	if highlightIdx > 0 && highlightIdx <= folded {
+		folded = highlightIdx - 1
+	}
+	return folded
+}
+
+// titlePreviewLimit returns how many columns of a comment body fit in a row's description
+func (r *browseItemRenderer) titlePreviewLimit() int {
+	if r.previewWidth <= 0 {
+		return defaultTitlePreviewWidth
+	}
+	limit := r.previewWidth - ui.DisplayWidth(previewIndent)
+	if limit < 10 {
+		limit = 10
+	}
+	return limit
+}
+
+// threadPreviewLimit returns how many columns of a thread comment's body fit
+// in the comment-selection status line, after its "@author: " prefix and the
+// selector's hints around it
+func (r *browseItemRenderer) threadPreviewLimit(prefix string, count int) int {
+	if r.previewWidth <= 0 {
+		return defaultThreadPreviewWidth
+	}
+	limit := r.previewWidth - ui.CommentSelectChromeWidth(count) - ui.DisplayWidth(prefix)
+	if limit < 20 {
+		limit = 20
+	}
+	return limit
+}
+
+// truncatePreview cuts s to at most limit columns, ending in the truncation
+// indicator when cut
+func truncatePreview(s string, limit int) string {
+	return ui.TruncateDisplay(s, limit)
+}
+
+// formatFileStat returns " (+12 -3)" for a file in the PR diff, or "" for
+// files the diff doesn't list (e.g. renamed since the comment was made)
+func (r *browseItemRenderer) formatFileStat(path string) string {
+	stat, ok := r.fileStats[path]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" (%s %s)",
+		ui.Colorize(ui.ColorGreen, fmt.Sprintf("+%d", stat.Additions)),
+		ui.Colorize(ui.ColorRed, fmt.Sprintf("-%d", stat.Deletions)))
+}
+
+// hasUnread reports whether any comment in the thread has not been viewed yet
+func (r *browseItemRenderer) hasUnread(comment *github.ReviewComment) bool {
+	if r.state == nil || comment == nil {
+		return false
+	}
+	if r.state.IsUnread(comment.ID) {
+		return true
+	}
+	for _, tc := range comment.ThreadComments {
+		if r.state.IsUnread(tc.ID) {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *browseItemRenderer) Title(item BrowseItem) string {
@@ -632,31 +2339,45 @@ func (r *browseItemRenderer) Title(item BrowseItem) string {
 		if folder != "" {
 			title = fmt.Sprintf("%s %s %s", icon, folder, item.Path)
 		}
-		return ui.Colorize(ui.ColorCyan, strings.TrimSpace(title))
-	}
-
-	if item.IsPreview {
-		// Show truncated body for preview item in gray
-		// Note: This works because IsSkippable returns false, so lipgloss
-		// won't re-style this text and interfere with the ANSI codes
-		body := ui.StripSuggestionBlock(item.Comment.Body)
-		lines := strings.Split(body, "\n")
-		preview := "..."
-		if len(lines) > 0 {
-			preview = lines[0]
-			if len(preview) > 80 {
-				preview = preview[:77] + "..."
-			} else if len(lines) > 1 {
-				preview += "..."
+		title = ui.Colorize(ui.ColorCyan, strings.TrimSpace(title))
+		if r.aligned {
+			if stat := r.formatFileStat(item.Path); stat != "" {
+				title += strings.Repeat(" ", max(0, r.pathWidth-ui.DisplayWidth(item.Path))) + stat
 			}
+			return title
 		}
-		return "      " + ui.Colorize(ui.ColorGray, preview)
+		return title + r.formatFileStat(item.Path)
+	}
+	if item.Type == "resolved_summary" {
+		return r.summaryTitle(item)
 	}
 
 	// Comment Metadata
 	style := ui.NewReviewListStyle(item.Comment.Author, item.Comment.IsResolved())
-	// Indent with tree structure
-	title := fmt.Sprintf("  └── %s Line %d", style.FormatCommentTitle(item.Comment.ID), item.Comment.Line)
+	// Indent with tree structure, marking threads with comments not yet viewed
+	unread := ""
+	if r.hasUnread(item.Comment) {
+		unread = ui.Colorize(ui.ColorMagenta, ui.EmojiText("●", "*")) + " "
+	}
+	tag := ""
+	if r.state != nil {
+		tag = formatTag(r.state.Tag(item.Comment.ID))
+	}
+	location := commentLocation(item.Comment)
+	var title string
+	if r.aligned {
+		// Fixed-width columns: the unread mark, author, location, then the tag
+		if unread == "" {
+			unread = "  "
+		}
+		title = fmt.Sprintf("  └── %s%s %s", unread,
+			padColumn(style.FormatCommentTitle(item.Comment.ID), r.authorWidth), padColumn(location, r.locationWidth))
+		if tag != "" {
+			title += " " + strings.TrimSuffix(tag, " ")
+		}
+	} else {
+		title = fmt.Sprintf("  └── %s%s%s %s", unread, tag, style.FormatCommentTitle(item.Comment.ID), location)
+	}
 	// Add reply count if there are replies
 	if len(item.Comment.ThreadComments) > 0 {
 		replyCount := len(item.Comment.ThreadComments)
@@ -667,21 +2388,106 @@ func (r *browseItemRenderer) Title(item BrowseItem) string {
 		title += fmt.Sprintf(" [%d %s]", replyCount, replyText)
 	}
 	title += " " + style.Status.Format(true)
+
+	// Compact rows are one line high, so the body goes on the same line
+	if r.compact {
+		title += r.compactBodySuffix(title, item.Comment.Body)
+	}
 	return title
 }
 
+// summaryTitle is a resolved_summary row: the file's resolved thread count
+// and whether enter shows or hides them
+func (r *browseItemRenderer) summaryTitle(item BrowseItem) string {
+	icon, action := "▸", "expand"
+	if r.expandedFiles[item.Path] {
+		icon, action = "▾", "collapse"
+	}
+	if !ui.ColorsEnabled() {
+		icon = "+"
+		if action == "collapse" {
+			icon = "-"
+		}
+	}
+	return ui.Colorize(ui.ColorGray, fmt.Sprintf("  └── %s %d resolved (enter to %s)", icon, resolvedCount(item), action))
+}
+
+// commentLocation is the comment's place in its file ("Line 12"), or its
+// kind for comments not attached to a line
+func commentLocation(c *github.ReviewComment) string {
+	if c.Kind != github.CommentKindInline {
+		return commentKindIcon(c.Kind) + " " + commentKindLabel(c.Kind)
+	}
+	return lineLabel(c)
+}
+
+// commentAuthorLabel is the author as shown in a comment's list title
+func commentAuthorLabel(c *github.ReviewComment) string {
+	return ui.NewReviewListStyle(c.Author, c.IsResolved()).FormatCommentTitle(c.ID)
+}
+
+// compactBodySuffix returns the body preview appended to a compact-mode title,
+// sized to the space left after title, or "" if there is no room
+func (r *browseItemRenderer) compactBodySuffix(title, body string) string {
+	const separator = "  "
+	limit := defaultTitlePreviewWidth
+	if r.previewWidth > 0 {
+		// The delegate cuts on visible width, so color codes don't need room
+		limit = r.previewWidth - ui.DisplayWidth(title) - len(separator)
+	}
+	if limit < 10 {
+		return ""
+	}
+	return separator + ui.Colorize(ui.ColorGray, bodyPreviewLine(body, limit))
+}
+
+// bodyPreviewLine returns the first line of a comment body (without suggestion
+// blocks) cut to limit characters, with "..." marking omitted text
+func bodyPreviewLine(body string, limit int) string {
+	lines := strings.Split(ui.StripSuggestionBlock(body), "\n")
+	preview := lines[0]
+	if len(lines) > 1 {
+		preview += "..."
+	}
+	return truncatePreview(preview, limit)
+}
+
+// Description is the gray body preview shown on the second line of a row
 func (r *browseItemRenderer) Description(item BrowseItem) string {
-	return ""
+	if item.Type == "file" || item.Comment == nil || r.compact {
+		return ""
+	}
+	if r.collapseBots && ui.IsBotAuthor(item.Comment.Author) {
+		return ""
+	}
+	// Note: This works because IsSkippable returns false, so lipgloss
+	// won't re-style this text and interfere with the ANSI codes
+	return previewIndent + ui.Colorize(ui.ColorGray, bodyPreviewLine(item.Comment.Body, r.titlePreviewLimit()))
 }
 
 func (r *browseItemRenderer) Preview(item BrowseItem) string {
-	return r.PreviewWithHighlight(item, -1) // No highlight
+	return r.PreviewWithHighlight(item, -1, "") // No highlight
 }
 
-func (r *browseItemRenderer) PreviewWithHighlight(item BrowseItem, highlightIdx int) string {
+func (r *browseItemRenderer) PreviewWithHighlight(item BrowseItem, highlightIdx int, query string) string {
+	return ui.HighlightMatches(r.renderPreview(item, highlightIdx, false), query)
+}
+
+// PreviewRaw is the detail view with comment bodies as markdown source, for
+// when rendering mangles them (tables, nested lists)
+func (r *browseItemRenderer) PreviewRaw(item BrowseItem, highlightIdx int, query string) string {
+	return ui.HighlightMatches(r.renderPreview(item, highlightIdx, true), query)
+}
+
+// renderPreview builds the detail view for an item before search
+// highlighting, with comment bodies rendered or, if raw, as source
+func (r *browseItemRenderer) renderPreview(item BrowseItem, highlightIdx int, raw bool) string {
 	if item.Type == "file" {
 		return fmt.Sprintf("File: %s\n\nSelect a comment below to view details.", item.Path)
 	}
+	if item.Type == "resolved_summary" {
+		return fmt.Sprintf("File: %s\n\n%d resolved threads, collapsed while collapsing resolved (h).\nPress enter on the row to show or hide them.", item.Path, resolvedCount(item))
+	}
 
 	// Reuse the logic from browseCommentRenderer but adapted for BrowseItem
 	comment := item.Comment
@@ -695,7 +2501,11 @@ func (r *browseItemRenderer) PreviewWithHighlight(item BrowseItem, highlightIdx
 		statusColor = ui.ColorGreen
 	}
 	preview.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("Author: @%s\n", comment.Author)))
-	preview.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("Location: %s:%d\n", comment.Path, comment.Line)))
+	if comment.Kind != github.CommentKindInline {
+		preview.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("Location: %s\n", commentKindLabel(comment.Kind))))
+	} else {
+		preview.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("Location: %s:%s\n", comment.Path, lineSpan(comment))))
+	}
 	preview.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("Status: %s\n", ui.Colorize(statusColor, status))))
 	if comment.HTMLURL != "" {
 		preview.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("URL: %s\n", ui.CreateHyperlink(comment.HTMLURL, comment.HTMLURL))))
@@ -729,9 +2539,10 @@ func (r *browseItemRenderer) PreviewWithHighlight(item BrowseItem, highlightIdx
 			body = strings.Join(bodyLines[:200], "\n") + "\n\n...(truncated, content too long)"
 		}
 
-		// Try to render markdown
-		rendered, err := ui.RenderMarkdown(body)
-		if err == nil && rendered != "" {
+		// Render markdown unless the source was asked for
+		if raw {
+			preview.WriteString(body)
+		} else if rendered, err := ui.RenderMarkdown(body); err == nil && rendered != "" {
 			preview.WriteString(rendered)
 		} else {
 			// Fallback to wrapped text
@@ -756,7 +2567,7 @@ func (r *browseItemRenderer) PreviewWithHighlight(item BrowseItem, highlightIdx
 		}
 		if !showedDiff {
 			preview.WriteString(ui.Colorize(ui.ColorCyan, "\n--- Suggested Code ---\n"))
-			lang := ui.CodeFenceLanguageFromPath(comment.Path)
+			lang := ui.CodeFenceLanguage(parser.ParseSuggestionLanguage(comment.Body), comment.Path)
 			md := fmt.Sprintf("```%s\n%s\n```", lang, comment.SuggestedCode)
 			if rendered, err := ui.RenderMarkdown(md); err == nil && rendered != "" {
 				preview.WriteString(rendered)
@@ -767,21 +2578,48 @@ func (r *browseItemRenderer) PreviewWithHighlight(item BrowseItem, highlightIdx
 		}
 	}
 
-	// Diff hunk/context (show the last lines closest to the comment)
+	// Diff hunk/context (show the last lines closest to the comment). The
+	// hunk ends at the comment's last line, so a range is its final lines.
+	// Lines are numbered from the hunk header before the tail is cut.
 	if comment.DiffHunk != "" {
 		diffLines := strings.Split(comment.DiffHunk, "\n")
 		if len(diffLines) > 2 {
 			preview.WriteString(ui.Colorize(ui.ColorCyan, "\n--- Context ---\n"))
-			truncated := ui.TruncateDiffTail(comment.DiffHunk, 8)
-			preview.WriteString(ui.ColorizeDiff(truncated))
+			if n := rangeLength(comment); n > 1 {
+				other := byte('-')
+				if comment.DiffSide == diffposition.DiffSideLeft {
+					other = '+'
+				}
+				preview.WriteString(ui.TruncateDiffTail(ui.ColorizeDiffRange(comment.DiffHunk, n, other), max(8, n+3)))
+			} else {
+				preview.WriteString(ui.TruncateDiffTail(ui.ColorizeDiffWithLineNumbers(comment.DiffHunk), 8))
+			}
 			preview.WriteString("\n")
 		}
 	}
 
+	// Current code from the local checkout, which may differ from the review-time hunk
+	if comment.Line > 0 {
+		preview.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("\n--- Current code (around %s) ---\n", strings.ToLower(lineLabel(comment)))))
+		preview.WriteString(renderLocalContext(filepath.Join(r.repoRoot, comment.Path), firstLine(comment), comment.Line))
+		preview.WriteString("\n")
+	}
+
 	// Thread replies (with markdown rendering, truncated to first 100 lines each)
 	if len(comment.ThreadComments) > 0 {
 		preview.WriteString("\n--- Replies ---\n")
+		folded := r.foldedReplies(len(comment.ThreadComments), highlightIdx)
+		if folded > 0 {
+			label := "earlier replies"
+			if folded == 1 {
+				label = "earlier reply"
+			}
+			preview.WriteString(ui.Colorize(ui.ColorGray, fmt.Sprintf("\n… %d %s (J to show one more, E to show all)\n", folded, label)))
+		}
 		for i, threadComment := range comment.ThreadComments {
+			if i < folded {
+				continue
+			}
 			// Add vertical spacing before each reply
 			preview.WriteString("\n")
 
@@ -815,8 +2653,9 @@ func (r *browseItemRenderer) PreviewWithHighlight(item BrowseItem, highlightIdx
 			}
 
 			// Render reply body with markdown
-			rendered, err := ui.RenderMarkdown(replyBody)
-			if err == nil && rendered != "" {
+			if raw {
+				preview.WriteString(replyBody)
+			} else if rendered, err := ui.RenderMarkdown(replyBody); err == nil && rendered != "" {
 				preview.WriteString(rendered)
 			} else {
 				preview.WriteString(ui.WrapText(replyBody, 80))
@@ -837,22 +2676,22 @@ func (r *browseItemRenderer) EditPath(item BrowseItem) string {
 }
 
 func (r *browseItemRenderer) EditLine(item BrowseItem) int {
-	if item.Type == "file" {
+	if item.Comment == nil {
 		return 0
 	}
-	return item.Comment.Line
+	return firstLine(item.Comment)
 }
 
 func (r *browseItemRenderer) FilterValue(item BrowseItem) string {
-	if item.Type == "file" {
+	if item.Comment == nil {
 		return item.Path
 	}
-	return item.Path + " " + r.Title(item) + " " + r.Description(item) + " " + item.Comment.Body
+	return item.Path + " " + r.Title(item) + " " + item.Comment.Body
 }
 
 func (r *browseItemRenderer) IsSkippable(item BrowseItem) bool {
-	// Preview items are supplementary info, not "skippable" in the sense of
-	// being invalid/crossed-out. Return false to avoid strikethrough styling.
+	// Nothing in the browse list is invalid/crossed-out; returning false
+	// also keeps lipgloss from restyling the colored description line.
 	return false
 }
 
@@ -888,12 +2727,9 @@ func (r *browseItemRenderer) ThreadCommentPreview(item BrowseItem, idx int) stri
 			nonQuotedLines = append(nonQuotedLines, trimmed)
 		}
 	}
+	prefix := fmt.Sprintf("@%s: ", author)
 	body = strings.Join(nonQuotedLines, " ")
-	// Truncate to ~100 chars
-	if len(body) > 100 {
-		body = body[:97] + "..."
-	}
-	return fmt.Sprintf("@%s: %s", author, body)
+	return prefix + truncatePreview(body, r.threadPreviewLimit(prefix, r.ThreadCommentCount(item)))
 }
 
 func (r *browseItemRenderer) WithSelectedComment(item BrowseItem, idx int) BrowseItem {
@@ -912,25 +2748,294 @@ func stripMarkdownForPreview(text string) string {
 	return strings.TrimSpace(text)
 }
 
-// resolveCommentAction resolves a review comment thread
-func resolveCommentAction(client *github.Client, prNumber int, comment *github.ReviewComment) (string, error) {
-	if comment.ThreadID == "" {
-		return "", fmt.Errorf("comment has no thread ID")
+// FormatChecklist renders unresolved comments as a markdown checklist, one
+// "- [ ] path:line — @author: first line (url)" entry per thread
+func FormatChecklist(items []BrowseItem) string {
+	var b strings.Builder
+	for _, item := range items {
+		if item.Type != "comment" || item.Comment == nil || item.Comment.IsResolved() {
+			continue
+		}
+		c := item.Comment
+		firstLine := strings.Split(stripMarkdownForPreview(ui.StripSuggestionBlock(c.Body)), "\n")[0]
+		location := c.Path
+		if c.Line > 0 {
+			location = fmt.Sprintf("%s:%d", c.Path, c.Line)
+		}
+		fmt.Fprintf(&b, "- [ ] %s — @%s: %s", location, c.Author, strings.TrimSpace(firstLine))
+		if c.HTMLURL != "" {
+			fmt.Fprintf(&b, " (%s)", c.HTMLURL)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// printPlainBrowse writes the comment tree for --plain: the session header,
+// then every file with its threads in list order
+func printPlainBrowse(w io.Writer, repo string, prNumber int, items []BrowseItem) {
+	fmt.Fprintln(w, browseHeader(repo, prNumber, items))
+	for _, item := range items {
+		if item.Type == "file" {
+			fmt.Fprintf(w, "\n%s\n", ui.Colorize(ui.ColorCyan, "=== "+item.Path+" ==="))
+			continue
+		}
+		fmt.Fprintf(w, "\n%s", FormatCommentPlain(item.Comment))
 	}
+}
 
+// FormatCommentPlain renders a thread as text with the detail view's
+// sections, but bodies as wrapped markdown source and links as bare URLs,
+// so it reads the same in a log or a pipe. Color follows --no-color/NO_COLOR.
+func FormatCommentPlain(comment *github.ReviewComment) string {
+	var b strings.Builder
+
+	status := "unresolved"
+	statusColor := ui.ColorYellow
 	if comment.IsResolved() {
-		// Unresolve
-		if err := client.UnresolveThread(comment.ThreadID); err != nil {
-			return "", err
+		status = "resolved"
+		statusColor = ui.ColorGreen
+	}
+	location := commentKindLabel(comment.Kind)
+	if comment.Kind == github.CommentKindInline {
+		location = comment.Path + ":" + lineSpan(comment)
+	}
+	b.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("Author: @%s\n", comment.Author)))
+	b.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("Location: %s\n", location)))
+	b.WriteString(ui.Colorize(ui.ColorCyan, "Status: ") + ui.Colorize(statusColor, status) + "\n")
+	if comment.HTMLURL != "" {
+		b.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("URL: %s\n", comment.HTMLURL)))
+	}
+	if !comment.CreatedAt.IsZero() {
+		b.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("Time: %s\n", ui.FormatRelativeTime(comment.CreatedAt))))
+	}
+	if reactions := ui.FormatReactions(ui.ReactionCountsFromGitHub(comment.Reactions)); reactions != "" {
+		b.WriteString(ui.Colorize(ui.ColorCyan, fmt.Sprintf("Reactions: %s\n", reactions)))
+	}
+	if comment.IsOutdated {
+		b.WriteString(ui.Colorize(ui.ColorYellow, "OUTDATED\n"))
+	}
+
+	if body := ui.StripSuggestionBlock(comment.Body); body != "" {
+		b.WriteString("\n--- Comment ---\n")
+		b.WriteString(ui.WrapText(body, 80) + "\n")
+	}
+	if comment.HasSuggestion && comment.SuggestedCode != "" {
+		b.WriteString(ui.Colorize(ui.ColorCyan, "\n--- Suggested Code ---\n"))
+		b.WriteString(comment.SuggestedCode + "\n")
+	}
+
+	if len(comment.ThreadComments) > 0 {
+		b.WriteString("\n--- Replies ---\n")
+		for i, reply := range comment.ThreadComments {
+			header := fmt.Sprintf("Reply %d by @%s", i+1, reply.Author)
+			if reply.HTMLURL != "" {
+				header += " | " + reply.HTMLURL
+			}
+			if !reply.CreatedAt.IsZero() {
+				header += " | " + ui.FormatRelativeTime(reply.CreatedAt)
+			}
+			b.WriteString("\n" + header + "\n")
+			b.WriteString(ui.WrapText(reply.Body, 80) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// rateLimitWarning returns a footer warning when few GitHub API requests
+// remain, for whichever of the REST and GraphQL limits is lower
+func rateLimitWarning(client *github.Client) string {
+	label, remaining, reset := "", rateLimitWarningThreshold, time.Time{}
+	for _, limit := range []struct{ resource, label string }{
+		{github.RateLimitCore, "REST"},
+		{github.RateLimitGraphQL, "GraphQL"},
+	} {
+		if left, resetAt := client.RateLimit(limit.resource); left >= 0 && left < remaining {
+			label, remaining, reset = limit.label, left, resetAt
+		}
+	}
+	if label == "" {
+		return ""
+	}
+	if reset.IsZero() {
+		return fmt.Sprintf("%d %s API requests left", remaining, label)
+	}
+	return fmt.Sprintf("%d %s API requests left (resets in %s)", remaining, label, github.FormatResetIn(reset))
+}
+
+// explainAPIError adds a hint to GitHub API failures the user can act on,
+// such as a 403 from a token that can't write to the repository. Other
+// errors are returned unchanged.
+func explainAPIError(err error) error {
+	// Rate limiting is also a 403 but already explains itself
+	var rateErr *github.RateLimitError
+	var apiErr *github.APIError
+	if errors.As(err, &rateErr) || !errors.As(err, &apiErr) {
+		return err
+	}
+	var hint string
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized:
+		hint = "not logged in; run `gh auth login`"
+	case http.StatusForbidden:
+		hint = "permission denied; check your access to the repository or run `gh auth refresh -s repo`"
+	case http.StatusNotFound:
+		hint = "not found; it may have been deleted, or your token can't see this repository"
+	case http.StatusUnprocessableEntity:
+		hint = "GitHub rejected the request"
+		if apiErr.Message != "" {
+			hint += ": " + apiErr.Message
 		}
+		if apiErr.DocURL != "" {
+			hint += " (see " + apiErr.DocURL + ")"
+		}
+	default:
+		return err
+	}
+	return fmt.Errorf("%s (%w)", hint, err)
+}
+
+// startResolveToggle flips comment's resolved state locally and returns the
+// API call that makes the change on GitHub, plus an undo for when it fails.
+// The call looks the thread up with find when the initial fetch left its ID
+// empty. It doesn't touch comment, so it is safe to run in the background;
+// its apply caches the thread ID on comment.
+func startResolveToggle(comment *github.ReviewComment, login string, find func(commentNodeID string) (string, error), resolve, unresolve func(threadID string) error) (func() (string, func(), error), func(), error) {
+	lookup, err := threadIDLookup(comment, find)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subjectType, resolvedBy := comment.SubjectType, comment.ResolvedBy
+	undo := func() {
+		comment.SubjectType, comment.ResolvedBy = subjectType, resolvedBy
+	}
+
+	toggle, status := resolve, "Marked as resolved"
+	if comment.IsResolved() {
+		toggle, status = unresolve, "Marked as unresolved"
 		comment.SubjectType = "line" // Reset to default
-		return "Marked as unresolved", nil
+		comment.ResolvedBy = ""
 	} else {
-		// Resolve
-		if err := client.ResolveThread(comment.ThreadID); err != nil {
-			return "", err
+		comment.SubjectType = "resolved"
+		comment.ResolvedBy = login
+	}
+	return func() (string, func(), error) {
+		threadID, err := lookup()
+		if err != nil {
+			return "", nil, err
+		}
+		setThreadID := func() { comment.ThreadID = threadID }
+		if err := toggle(threadID); err != nil {
+			return "", setThreadID, explainAPIError(err)
+		}
+		return status, setThreadID, nil
+	}, undo, nil
+}
+
+// creditResolvedBy records login as the resolver of the comments resolved
+// before it was known, skipping any unresolved or credited since
+func creditResolvedBy(comments []*github.ReviewComment, login string) {
+	for _, c := range comments {
+		if c.IsResolved() && c.ResolvedBy == "" {
+			c.ResolvedBy = login
+		}
+	}
+}
+
+// resolvedByCredit returns a credit func for resolveCommentAction that sets
+// ResolvedBy to login(), or adds the comment to pending while the login is
+// still unknown, for creditResolvedBy once it arrives. It reads login and
+// pending, so it runs on the UI goroutine.
+func resolvedByCredit(login func() string, pending *[]*github.ReviewComment) func(*github.ReviewComment) {
+	return func(comment *github.ReviewComment) {
+		if l := login(); l != "" {
+			comment.ResolvedBy = l
+			return
+		}
+		*pending = append(*pending, comment)
+	}
+}
+
+// threadIDLookup checks that comment's thread can be found, without a network
+// call, and returns the lookup: comment's ThreadID, or one found with find by
+// the comment's node ID when the initial fetch left it empty. The lookup
+// doesn't read comment, so it can run in the background.
+func threadIDLookup(comment *github.ReviewComment, find func(commentNodeID string) (string, error)) (func() (string, error), error) {
+	if threadID := comment.ThreadID; threadID != "" {
+		return func() (string, error) { return threadID, nil }, nil
+	}
+	if comment.NodeID == "" || comment.Kind != github.CommentKindInline {
+		return nil, fmt.Errorf("comment has no thread ID")
+	}
+	nodeID := comment.NodeID
+	return func() (string, error) {
+		threadID, err := find(nodeID)
+		if err != nil {
+			return "", fmt.Errorf("comment has no thread ID: %w", err)
 		}
+		return threadID, nil
+	}, nil
+}
+
+// threadIDFor runs comment's threadIDLookup. It doesn't change comment, so
+// it can run in the background.
+func threadIDFor(comment *github.ReviewComment, find func(commentNodeID string) (string, error)) (string, error) {
+	lookup, err := threadIDLookup(comment, find)
+	if err != nil {
+		return "", err
+	}
+	return lookup()
+}
+
+// resolveCommentAction resolves a review comment thread, or unresolves a
+// resolved one. It leaves comment alone so it can run in the background;
+// apply records the new state on it and calls credit for a resolve.
+func resolveCommentAction(client *github.Client, comment *github.ReviewComment, credit func(*github.ReviewComment)) (string, func(), error) {
+	threadID, err := threadIDFor(comment, client.FindThreadID)
+	if err != nil {
+		return "", nil, err
+	}
+	setThreadID := func() { comment.ThreadID = threadID }
+
+	if comment.IsResolved() {
+		// Unresolve
+		if err := client.UnresolveThread(threadID); err != nil {
+			return "", setThreadID, explainAPIError(err)
+		}
+		return "Marked as unresolved", func() {
+			setThreadID()
+			comment.SubjectType = "line" // Reset to default
+			comment.ResolvedBy = ""
+		}, nil
+	}
+
+	// Resolve
+	if err := client.ResolveThread(threadID); err != nil {
+		return "", setThreadID, explainAPIError(err)
+	}
+	return "Marked as resolved", func() {
+		setThreadID()
 		comment.SubjectType = "resolved"
-		return "Marked as resolved", nil
+		credit(comment)
+	}, nil
+}
+
+// replyApplied records a posted reply on comment's local thread, so it shows
+// in the detail view; run on the UI goroutine
+func replyApplied(comment *github.ReviewComment, reply *github.ThreadComment) func() {
+	return func() {
+		comment.ThreadComments = append(comment.ThreadComments, *reply)
+	}
+}
+
+// combineApplies runs each non-nil apply in order
+func combineApplies(applies ...func()) func() {
+	return func() {
+		for _, apply := range applies {
+			if apply != nil {
+				apply()
+			}
+		}
 	}
 }
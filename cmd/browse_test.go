@@ -1,14 +1,22 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/applier"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/diffposition"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/state"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
 )
 
@@ -45,20 +53,6 @@ func TestBrowseItemRenderer_IsSkippable(t *testing.T) {
 			},
 			expected: false,
 		},
-		{
-			name: "preview is not skippable (prevents strikethrough styling)",
-			item: BrowseItem{
-				Type:      "comment_preview",
-				Path:      "src/main.go",
-				IsPreview: true,
-				Comment: &github.ReviewComment{
-					ID:     123,
-					Author: "reviewer",
-					Body:   "Consider refactoring this",
-				},
-			},
-			expected: false,
-		},
 	}
 
 	for _, tt := range tests {
@@ -71,7 +65,7 @@ func TestBrowseItemRenderer_IsSkippable(t *testing.T) {
 	}
 }
 
-func TestBrowseItemRenderer_Title_PreviewUsesGrayColor(t *testing.T) {
+func TestBrowseItemRenderer_Description_UsesGrayColor(t *testing.T) {
 	renderer := &browseItemRenderer{
 		repo:           "owner/repo",
 		prNumber:       123,
@@ -79,9 +73,8 @@ func TestBrowseItemRenderer_Title_PreviewUsesGrayColor(t *testing.T) {
 	}
 
 	item := BrowseItem{
-		Type:      "comment_preview",
-		Path:      "src/main.go",
-		IsPreview: true,
+		Type: "comment",
+		Path: "src/main.go",
 		Comment: &github.ReviewComment{
 			ID:     123,
 			Author: "reviewer",
@@ -89,7 +82,7 @@ func TestBrowseItemRenderer_Title_PreviewUsesGrayColor(t *testing.T) {
 		},
 	}
 
-	title := renderer.Title(item)
+	title := renderer.Description(item)
 
 	// Title should contain the preview text
 	if !strings.Contains(title, "Consider refactoring") {
@@ -112,7 +105,7 @@ func TestBrowseItemRenderer_Title_PreviewUsesGrayColor(t *testing.T) {
 	}
 }
 
-func TestBrowseItemRenderer_Title_PreviewTruncation(t *testing.T) {
+func TestBrowseItemRenderer_Description_Truncation(t *testing.T) {
 	renderer := &browseItemRenderer{
 		repo:           "owner/repo",
 		prNumber:       123,
@@ -120,27 +113,27 @@ func TestBrowseItemRenderer_Title_PreviewTruncation(t *testing.T) {
 	}
 
 	tests := []struct {
-		name           string
-		body           string
-		shouldTruncate bool
+		name               string
+		body               string
+		shouldTruncate     bool
 		shouldHaveEllipsis bool
 	}{
 		{
-			name:           "short single line",
-			body:           "Short comment",
-			shouldTruncate: false,
+			name:               "short single line",
+			body:               "Short comment",
+			shouldTruncate:     false,
 			shouldHaveEllipsis: false,
 		},
 		{
-			name:           "multi-line adds ellipsis",
-			body:           "First line\nSecond line",
-			shouldTruncate: false,
+			name:               "multi-line adds ellipsis",
+			body:               "First line\nSecond line",
+			shouldTruncate:     false,
 			shouldHaveEllipsis: true,
 		},
 		{
-			name:           "very long line gets truncated",
-			body:           strings.Repeat("a", 100),
-			shouldTruncate: true,
+			name:               "very long line gets truncated",
+			body:               strings.Repeat("a", 100),
+			shouldTruncate:     true,
 			shouldHaveEllipsis: true,
 		},
 	}
@@ -148,9 +141,8 @@ func TestBrowseItemRenderer_Title_PreviewTruncation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			item := BrowseItem{
-				Type:      "comment_preview",
-				Path:      "src/main.go",
-				IsPreview: true,
+				Type: "comment",
+				Path: "src/main.go",
 				Comment: &github.ReviewComment{
 					ID:     123,
 					Author: "reviewer",
@@ -158,7 +150,7 @@ func TestBrowseItemRenderer_Title_PreviewTruncation(t *testing.T) {
 				},
 			}
 
-			title := renderer.Title(item)
+			title := renderer.Description(item)
 
 			if tt.shouldTruncate {
 				// Very long lines should be truncated to ~80 chars
@@ -208,9 +200,8 @@ func TestBuildCommentTree(t *testing.T) {
 
 	items := buildCommentTree(comments)
 
-	// Should have: file1 header + 2 comments + 2 previews + file2 header + 1 comment + 1 preview
-	// = 2 file headers + 3 comments + 3 previews = 8 items
-	expectedCount := 8
+	// Should have: file1 header + 2 comments + file2 header + 1 comment = 5 items
+	expectedCount := 5
 	if len(items) != expectedCount {
 		t.Errorf("buildCommentTree returned %d items, want %d", len(items), expectedCount)
 	}
@@ -240,19 +231,6 @@ func TestBuildCommentTree(t *testing.T) {
 	if file1Comments[1].Comment.Line != 10 {
 		t.Errorf("Second comment in file1.go should be at line 10, got %d", file1Comments[1].Comment.Line)
 	}
-
-	// Each comment should be followed by a preview
-	for i, item := range items {
-		if item.Type == "comment" && i+1 < len(items) {
-			next := items[i+1]
-			if next.Type != "comment_preview" {
-				t.Errorf("Comment at index %d should be followed by preview, got %q", i, next.Type)
-			}
-			if next.Comment.ID != item.Comment.ID {
-				t.Errorf("Preview should reference same comment, got IDs %d vs %d", next.Comment.ID, item.Comment.ID)
-			}
-		}
-	}
 }
 
 func TestBrowseItemRenderer_Title_ReplyCount(t *testing.T) {
@@ -412,7 +390,7 @@ func TestPreviewWithHighlight_SuggestionDiff(t *testing.T) {
 		},
 	}
 
-	preview := renderer.PreviewWithHighlight(item, -1)
+	preview := renderer.PreviewWithHighlight(item, -1, "")
 
 	// Should show "Suggestion Diff" header (not "Suggested Code")
 	if !strings.Contains(preview, "Suggestion Diff") {
@@ -452,7 +430,7 @@ func TestPreviewWithHighlight_SuggestionDiffFallback(t *testing.T) {
 		},
 	}
 
-	preview := renderer.PreviewWithHighlight(item, -1)
+	preview := renderer.PreviewWithHighlight(item, -1, "")
 
 	// Should fall back to "Suggested Code" header
 	if !strings.Contains(preview, "Suggested Code") {
@@ -484,7 +462,7 @@ func TestPreviewWithHighlight_NoApplier(t *testing.T) {
 		},
 	}
 
-	preview := renderer.PreviewWithHighlight(item, -1)
+	preview := renderer.PreviewWithHighlight(item, -1, "")
 
 	if !strings.Contains(preview, "Suggested Code") {
 		t.Errorf("preview should show \"Suggested Code\" without applier, got:\n%s", preview)
@@ -520,7 +498,7 @@ func TestPreviewWithHighlight_ContextShowsTail(t *testing.T) {
 		},
 	}
 
-	preview := renderer.PreviewWithHighlight(item, -1)
+	preview := renderer.PreviewWithHighlight(item, -1, "")
 
 	// Should contain "Context" header
 	if !strings.Contains(preview, "Context") {
@@ -539,3 +517,1375 @@ func TestPreviewWithHighlight_ContextShowsTail(t *testing.T) {
 		t.Errorf("preview context should not show lines from start of long hunk, got:\n%s", preview)
 	}
 }
+
+func TestBrowseItemRenderer_Title_UnreadMarker(t *testing.T) {
+	store := state.Open(filepath.Join(t.TempDir(), "pr-123.json"))
+	renderer := &browseItemRenderer{
+		repo:           "owner/repo",
+		prNumber:       123,
+		collapsedFiles: make(map[string]bool),
+		state:          store,
+	}
+
+	comment := &github.ReviewComment{
+		ID:     10,
+		Author: "reviewer",
+		Line:   5,
+		ThreadComments: []github.ThreadComment{
+			{ID: 11, Author: "author", Body: "Done"},
+		},
+	}
+	item := BrowseItem{Type: "comment", Path: "main.go", Comment: comment}
+	marker := ui.EmojiText("●", "*")
+
+	if title := renderer.Title(item); !strings.Contains(title, marker) {
+		t.Errorf("expected unread marker for unseen thread, got: %q", title)
+	}
+
+	// Reading only the main comment leaves the reply unread
+	_ = store.MarkRead(10)
+	if title := renderer.Title(item); !strings.Contains(title, marker) {
+		t.Errorf("expected unread marker while a reply is unseen, got: %q", title)
+	}
+
+	_ = store.MarkRead(11)
+	if title := renderer.Title(item); strings.Contains(title, marker) {
+		t.Errorf("expected no unread marker once all comments are read, got: %q", title)
+	}
+
+	// Renderers without a store never show the marker
+	renderer.state = nil
+	comment.ID = 99
+	if title := renderer.Title(item); strings.Contains(title, marker) {
+		t.Errorf("expected no unread marker without a state store, got: %q", title)
+	}
+}
+
+func TestDescribeNewComments(t *testing.T) {
+	thread := &github.ReviewComment{
+		ID:             1,
+		Path:           "main.go",
+		ThreadComments: []github.ThreadComment{{ID: 2}},
+	}
+	old := buildCommentTree([]*github.ReviewComment{thread})
+
+	if got := describeNewComments(old, old); got != "" {
+		t.Errorf("expected no change description, got %q", got)
+	}
+
+	repliedThread := *thread
+	repliedThread.ThreadComments = []github.ThreadComment{{ID: 2}, {ID: 3}}
+	updated := buildCommentTree([]*github.ReviewComment{
+		&repliedThread,
+		{ID: 4, Path: "util.go"},
+	})
+	if got := describeNewComments(old, updated); got != "2 new comments" {
+		t.Errorf("expected %q, got %q", "2 new comments", got)
+	}
+}
+
+func TestBrowseEmptyMessage(t *testing.T) {
+	tests := []struct {
+		filters []string
+		want    string
+	}{
+		{nil, "No review comments"},
+		{[]string{"hiding resolved"}, "press h to show all"},
+		{[]string{"hiding resolved", "todo only"}, "(hiding resolved, todo only) — press X"},
+	}
+	for _, tt := range tests {
+		if got := browseEmptyMessage(tt.filters); !strings.Contains(got, tt.want) {
+			t.Errorf("browseEmptyMessage(%v) = %q, want it to contain %q", tt.filters, got, tt.want)
+		}
+	}
+}
+
+func TestBrowseEmptyStateWithHeaders(t *testing.T) {
+	comments := []*github.ReviewComment{
+		{ID: 1, Path: "a.go", Line: 1, SubjectType: "resolved"},
+		{ID: 2, Path: "b.go", Line: 1, SubjectType: "resolved"},
+	}
+	collapsed, expanded := make(map[string]bool), make(map[string]bool)
+	rows := &browseRows{
+		collapsed: collapsed,
+		expanded:  expanded,
+		tag:       func(int64) state.Tag { return "" },
+		login:     func() string { return "" },
+	}
+	opts := ui.SelectorOptions[BrowseItem]{
+		Items:             withResolvedSummaries(buildCommentTree(comments)),
+		Renderer:          &browseItemRenderer{collapsedFiles: collapsed, expandedFiles: expanded},
+		FilterModes:       resolvedFilterModes,
+		FilterModeFunc:    rows.shows,
+		InitialFilterMode: resolvedFilterHide,
+		IsGroupHeader:     func(item BrowseItem) bool { return item.Type == "file" },
+		EmptyMessage:      browseEmptyMessage,
+	}
+	size := tea.WindowSizeMsg{Width: 120, Height: 30}
+
+	m, err := ui.RunSelection(opts, []tea.Msg{size})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	if view := m.View(); !strings.Contains(view, "No unresolved comments") || strings.Contains(view, "a.go") {
+		t.Errorf("expected the empty state in place of bare file headers, got:\n%s", view)
+	}
+
+	// A file with an unresolved thread keeps its header, even collapsed
+	comments[1].SubjectType = "line"
+	collapsed["b.go"] = true
+	m, err = ui.RunSelection(opts, []tea.Msg{size})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	if view := m.View(); strings.Contains(view, "No unresolved comments") || !strings.Contains(view, "b.go") || strings.Contains(view, "a.go") {
+		t.Errorf("expected only b.go's header, got:\n%s", view)
+	}
+}
+
+func TestBrowseItemKey(t *testing.T) {
+	comment := &github.ReviewComment{ID: 7, Path: "main.go"}
+	if got := browseItemKey(BrowseItem{Type: "file", Path: "main.go"}); got != "file:main.go" {
+		t.Errorf("unexpected file key %q", got)
+	}
+	if got := browseItemKey(BrowseItem{Type: "comment", Path: "main.go", Comment: comment}); got != "comment:7" {
+		t.Errorf("unexpected comment key %q", got)
+	}
+}
+
+func TestBrowseDebugInfo(t *testing.T) {
+	comment := &github.ReviewComment{
+		ID:             7,
+		NodeID:         "PRRC_7",
+		Path:           "main.go",
+		Line:           12,
+		HTMLURL:        "https://github.com/o/r/pull/1#discussion_r7",
+		ThreadComments: []github.ThreadComment{{ID: 8, HTMLURL: "https://github.com/o/r/pull/1#discussion_r8"}},
+	}
+	info := browseDebugInfo(BrowseItem{Type: "comment", Path: "main.go", Comment: comment})
+	for _, want := range []string{
+		"comment ID:  7",
+		"node ID:     PRRC_7",
+		"thread ID:   (empty)",
+		"resolved:    false",
+		"replies:     1",
+		"reply 1:     8 https://github.com/o/r/pull/1#discussion_r8",
+	} {
+		if !strings.Contains(info, want) {
+			t.Errorf("expected %q in debug info:\n%s", want, info)
+		}
+	}
+}
+
+func TestBrowseItemRenderer_PreviewWidth(t *testing.T) {
+	renderer := &browseItemRenderer{collapsedFiles: make(map[string]bool)}
+	comment := &github.ReviewComment{
+		ID:     1,
+		Author: "reviewer",
+		Body:   strings.Repeat("word ", 60),
+	}
+	item := BrowseItem{Type: "comment", Path: "main.go", Comment: comment}
+	ansiRegex := regexp.MustCompile("\x1b\\[[0-9;]*m")
+	plainTitle := func() string {
+		return ansiRegex.ReplaceAllString(strings.TrimPrefix(renderer.Description(item), previewIndent), "")
+	}
+
+	if got := len(plainTitle()); got != defaultTitlePreviewWidth {
+		t.Errorf("expected default preview of %d chars, got %d", defaultTitlePreviewWidth, got)
+	}
+
+	renderer.SetPreviewWidth(200)
+	wide := plainTitle()
+	if len(wide) <= defaultTitlePreviewWidth || !strings.HasSuffix(wide, "...") {
+		t.Errorf("expected a longer truncated preview on a wide list, got %d chars", len(wide))
+	}
+	if full := ui.DisplayWidth(renderer.Description(item)); full != 200 {
+		t.Errorf("expected the preview title to fill the 200 columns, got %d", full)
+	}
+
+	status := ui.DisplayWidth(renderer.ThreadCommentPreview(item, 0)) + ui.CommentSelectChromeWidth(1)
+	if status != 200 {
+		t.Errorf("expected the comment-selection status to fill the 200 columns, got %d", status)
+	}
+}
+
+func TestFileBlobURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  github.ReviewComment
+		expected string
+	}{
+		{
+			name:     "single line",
+			comment:  github.ReviewComment{Path: "pkg/ui/colors.go", Line: 42, HeadSHA: "abc123", HTMLURL: "https://github.com/owner/repo/pull/1#discussion_r1"},
+			expected: "https://github.com/owner/repo/blob/abc123/pkg/ui/colors.go#L42",
+		},
+		{
+			name:     "multi-line range",
+			comment:  github.ReviewComment{Path: "main.go", StartLine: 3, Line: 7, HeadSHA: "abc123"},
+			expected: "https://github.com/owner/repo/blob/abc123/main.go#L3-L7",
+		},
+		{
+			name:     "enterprise host from comment URL",
+			comment:  github.ReviewComment{Path: "main.go", Line: 1, HeadSHA: "abc123", HTMLURL: "https://ghe.example.com/owner/repo/pull/1#discussion_r1"},
+			expected: "https://ghe.example.com/owner/repo/blob/abc123/main.go#L1",
+		},
+		{
+			name:     "file-level comment has no anchor",
+			comment:  github.ReviewComment{Path: "main.go", HeadSHA: "abc123"},
+			expected: "https://github.com/owner/repo/blob/abc123/main.go",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fileBlobURL("owner/repo", &tt.comment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("fileBlobURL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := fileBlobURL("owner/repo", &github.ReviewComment{Path: "main.go"}); err == nil {
+		t.Error("expected an error when the comment has no commit SHA")
+	}
+}
+
+func TestQuoteForClipboard(t *testing.T) {
+	comment := &github.ReviewComment{
+		ID:             1,
+		Author:         "alice",
+		Body:           "Rename this",
+		Path:           "main.go",
+		DiffHunk:       "@@ -1 +1 @@\n+func f() {}",
+		ThreadComments: []github.ThreadComment{{ID: 2, Author: "bob", Body: "Agreed"}},
+	}
+
+	got, err := quoteForClipboard(BrowseItem{Type: "comment", Comment: comment}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "> @alice wrote:\n>\n> Rename this"; got != want {
+		t.Errorf("quoteForClipboard() = %q, want %q", got, want)
+	}
+
+	got, err = quoteForClipboard(BrowseItem{Type: "comment", Comment: comment, SelectedCommentIdx: 1}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "> ```diff\n> --- a/main.go") || !strings.HasSuffix(got, "> @bob wrote:\n>\n> Agreed") {
+		t.Errorf("expected the selected reply quoted under the diff, got:\n%s", got)
+	}
+
+	if _, err := quoteForClipboard(BrowseItem{Type: "file", Path: "main.go"}, false); err == nil {
+		t.Error("expected an error for a file header")
+	}
+}
+
+func TestCodePermalink(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  github.ReviewComment
+		expected string
+	}{
+		{
+			name: "original range on the original commit",
+			comment: github.ReviewComment{Path: "main.go", StartLine: 10, Line: 12, HeadSHA: "head",
+				OriginalCommitID: "orig", OriginalStartLine: 3, OriginalLine: 5},
+			expected: "https://github.com/owner/repo/blob/orig/main.go#L3-L5",
+		},
+		{
+			name:     "single original line",
+			comment:  github.ReviewComment{Path: "main.go", Line: 9, HeadSHA: "head", OriginalCommitID: "orig", OriginalStartLine: 4, OriginalLine: 4},
+			expected: "https://github.com/owner/repo/blob/orig/main.go#L4",
+		},
+		{
+			name:     "falls back to the current position",
+			comment:  github.ReviewComment{Path: "main.go", StartLine: 2, Line: 6, HeadSHA: "head"},
+			expected: "https://github.com/owner/repo/blob/head/main.go#L2-L6",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := codePermalink("owner/repo", &tt.comment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("codePermalink() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := codePermalink("owner/repo", &github.ReviewComment{Path: "main.go", Line: 1}); err == nil {
+		t.Error("expected an error when the comment has no commit SHA")
+	}
+	left := &github.ReviewComment{Path: "main.go", Line: 1, HeadSHA: "head", DiffSide: diffposition.DiffSideLeft}
+	if _, err := codePermalink("owner/repo", left); err == nil {
+		t.Error("expected an error for a comment on removed lines")
+	}
+}
+
+func TestGhCommandForComment(t *testing.T) {
+	got := ghCommandForComment("owner/repo", 12345)
+	if got != "gh api repos/owner/repo/pulls/comments/12345" {
+		t.Errorf("unexpected command %q", got)
+	}
+}
+
+func TestBrowseItemRenderer_Title_TagPrefix(t *testing.T) {
+	store := state.Open(filepath.Join(t.TempDir(), "pr-123.json"))
+	renderer := &browseItemRenderer{
+		repo:           "owner/repo",
+		prNumber:       123,
+		collapsedFiles: make(map[string]bool),
+		state:          store,
+	}
+	item := BrowseItem{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{ID: 10, Author: "reviewer", Line: 5}}
+
+	if title := renderer.Title(item); strings.Contains(title, "[todo]") {
+		t.Errorf("expected no tag prefix for an untagged comment, got: %q", title)
+	}
+
+	_ = store.SetTag(10, state.TagTodo)
+	if title := renderer.Title(item); !strings.Contains(title, "[todo]") {
+		t.Errorf("expected todo prefix, got: %q", title)
+	}
+
+	_ = store.SetTag(10, state.TagDone)
+	if title := renderer.Title(item); !strings.Contains(title, "[done]") {
+		t.Errorf("expected done prefix, got: %q", title)
+	}
+}
+
+func TestReadLocalContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	var lines []string
+	for i := 1; i <= 10; i++ {
+		lines = append(lines, "line "+strconv.Itoa(i))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadLocalContext(path, 5, 5, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := strings.Join(lines[1:8], "\n"); got != want {
+		t.Errorf("ReadLocalContext() = %q, want %q", got, want)
+	}
+
+	// Windows are clamped at the file boundaries
+	got, _ = ReadLocalContext(path, 1, 1, 3)
+	if want := strings.Join(lines[0:4], "\n"); got != want {
+		t.Errorf("ReadLocalContext() at start = %q, want %q", got, want)
+	}
+	got, _ = ReadLocalContext(path, 10, 10, 3)
+	if want := strings.Join(lines[6:10], "\n"); got != want {
+		t.Errorf("ReadLocalContext() at end = %q, want %q", got, want)
+	}
+
+	if _, err := ReadLocalContext(path, 11, 11, 3); err == nil {
+		t.Error("expected an error for a line past the end of the file")
+	}
+	if _, err := ReadLocalContext(filepath.Join(t.TempDir(), "missing.go"), 1, 1, 3); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestRenderLocalContext_MissingFile(t *testing.T) {
+	note := renderLocalContext(filepath.Join(t.TempDir(), "renamed.go"), 4, 4)
+	if !strings.Contains(note, "File not found in the local checkout") {
+		t.Errorf("expected a missing-file note, got %q", note)
+	}
+}
+
+func TestBrowseHeader(t *testing.T) {
+	items := buildCommentTree([]*github.ReviewComment{
+		{ID: 1, Path: "main.go", Line: 1},
+		{ID: 2, Path: "main.go", Line: 2, SubjectType: "resolved"},
+		{ID: 3, Path: "util.go", Line: 3},
+	})
+
+	got := browseHeader("owner/repo", 123, items)
+	if want := "owner/repo #123 — 2 unresolved / 3 total"; got != want {
+		t.Errorf("browseHeader() = %q, want %q", got, want)
+	}
+}
+
+func TestMatchesResolvedFilter(t *testing.T) {
+	open := &github.ReviewComment{ID: 1}
+	mine := &github.ReviewComment{ID: 2, SubjectType: "resolved", ResolvedBy: "Alice"}
+	other := &github.ReviewComment{ID: 3, SubjectType: "resolved", ResolvedBy: "bob"}
+
+	tests := []struct {
+		name    string
+		comment *github.ReviewComment
+		mode    int
+		login   string
+		want    bool
+	}{
+		{"all shows open", open, resolvedFilterAll, "", true},
+		{"all shows resolved", other, resolvedFilterAll, "", true},
+		{"hide shows open", open, resolvedFilterHide, "", true},
+		{"hide hides resolved", mine, resolvedFilterHide, "", false},
+		{"mine shows own resolutions", mine, resolvedFilterMine, "alice", true},
+		{"mine hides others' resolutions", other, resolvedFilterMine, "alice", false},
+		{"mine hides open", open, resolvedFilterMine, "alice", false},
+		{"mine without a login shows nothing", mine, resolvedFilterMine, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesResolvedFilter(tt.comment, tt.mode, tt.login); got != tt.want {
+				t.Errorf("matchesResolvedFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBrowseItemRenderer_Description_CompactAndBots(t *testing.T) {
+	human := BrowseItem{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{ID: 1, Author: "reviewer", Body: "first"}}
+	bot := BrowseItem{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{ID: 2, Author: "dependabot[bot]", Body: "bump"}}
+
+	renderer := &browseItemRenderer{collapsedFiles: make(map[string]bool), collapseBots: true}
+	if !strings.Contains(renderer.Description(human), "first") {
+		t.Errorf("expected a body preview for the human thread, got %q", renderer.Description(human))
+	}
+	if got := renderer.Description(bot); got != "" {
+		t.Errorf("expected no body preview for a collapsed bot thread, got %q", got)
+	}
+	if got := renderer.Description(BrowseItem{Type: "file", Path: "main.go"}); got != "" {
+		t.Errorf("expected no description for a file header, got %q", got)
+	}
+
+	renderer.compact = true
+	if got := renderer.Description(human); got != "" {
+		t.Errorf("expected no description in compact mode, got %q", got)
+	}
+}
+
+func TestWithoutBotComments(t *testing.T) {
+	comments := []*github.ReviewComment{
+		{ID: 1, Author: "reviewer"},
+		{ID: 2, Author: "coderabbitai[bot]"},
+		{ID: 3, Author: "Copilot"},
+	}
+
+	got := withoutBotComments(comments)
+	if len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("expected only the human comment, got %d comments", len(got))
+	}
+}
+
+func TestBrowseItemRenderer_Title_Compact(t *testing.T) {
+	renderer := &browseItemRenderer{collapsedFiles: make(map[string]bool), compact: true}
+	item := BrowseItem{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{
+		ID: 1, Author: "reviewer", Line: 3, Body: "Please rename this variable\nIt is unclear",
+	}}
+
+	title := renderer.Title(item)
+	if !strings.Contains(title, "Please rename this variable...") {
+		t.Errorf("expected body preview on the comment row, got %q", title)
+	}
+
+	narrow := ui.DisplayWidth(title) - 10
+	renderer.SetPreviewWidth(narrow)
+	if got := renderer.Title(item); ui.DisplayWidth(got) > narrow {
+		t.Errorf("expected title to fit %d columns, got %d: %q", narrow, ui.DisplayWidth(got), got)
+	}
+}
+
+func TestFormatChecklist(t *testing.T) {
+	items := buildCommentTree([]*github.ReviewComment{
+		{ID: 1, Path: "main.go", Line: 10, Author: "alice", Body: "See [the docs](https://example.com) for this\nmore detail",
+			HTMLURL: "https://github.com/o/r/pull/1#discussion_r1"},
+		{ID: 2, Path: "main.go", Line: 20, Author: "bob", Body: "Already fixed", SubjectType: "resolved"},
+		{ID: 3, Path: "util.go", Line: 5, Author: "carol", Body: "Rename this"},
+		{ID: 4, Path: "util.go", Author: "dave", Body: "Split this file", SubjectType: "file"},
+	})
+
+	want := "- [ ] main.go:10 — @alice: See the docs for this (https://github.com/o/r/pull/1#discussion_r1)\n" +
+		"- [ ] util.go — @dave: Split this file\n" +
+		"- [ ] util.go:5 — @carol: Rename this\n"
+	if got := FormatChecklist(items); got != want {
+		t.Errorf("FormatChecklist() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := FormatChecklist(nil); got != "" {
+		t.Errorf("FormatChecklist(nil) = %q, want empty", got)
+	}
+}
+
+func TestFormatCommentPlain(t *testing.T) {
+	comment := &github.ReviewComment{
+		ID: 1, Path: "main.go", Line: 12, Author: "alice",
+		Body:          "Use a **constant** here\n\n```suggestion\nconst limit = 10\n```",
+		HTMLURL:       "https://github.com/o/r/pull/1#discussion_r1",
+		HasSuggestion: true, SuggestedCode: "const limit = 10",
+		ThreadComments: []github.ThreadComment{
+			{ID: 2, Author: "bob", Body: "Done", HTMLURL: "https://github.com/o/r/pull/1#discussion_r2"},
+		},
+	}
+
+	got := FormatCommentPlain(comment)
+	for _, want := range []string{
+		"Author: @alice",
+		"Location: main.go:12",
+		"unresolved",
+		"URL: https://github.com/o/r/pull/1#discussion_r1",
+		"--- Comment ---\nUse a **constant** here\n",
+		"--- Suggested Code ---\n",
+		"const limit = 10\n",
+		"--- Replies ---\n\nReply 1 by @bob | https://github.com/o/r/pull/1#discussion_r2\nDone\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in plain output, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "```suggestion") || strings.Contains(got, "\x1b]8;") {
+		t.Errorf("expected no suggestion fence or terminal hyperlinks, got:\n%s", got)
+	}
+}
+
+func TestPrintPlainBrowse(t *testing.T) {
+	items := buildCommentTree([]*github.ReviewComment{
+		{ID: 1, Path: "main.go", Line: 10, Author: "alice", Body: "First"},
+		{ID: 2, Path: "util.go", Line: 5, Author: "bob", Body: "Second", SubjectType: "resolved"},
+	})
+
+	var out strings.Builder
+	printPlainBrowse(&out, "o/r", 7, items)
+	got := out.String()
+	if !strings.HasPrefix(got, "o/r #7 — 1 unresolved / 2 total\n") {
+		t.Errorf("expected the session header first, got:\n%s", got)
+	}
+	main, util := strings.Index(got, "=== main.go ==="), strings.Index(got, "=== util.go ===")
+	first, second := strings.Index(got, "First"), strings.Index(got, "Second")
+	if main < 0 || util < 0 || !(main < first && first < util && util < second) {
+		t.Errorf("expected each file followed by its threads in order, got:\n%s", got)
+	}
+}
+
+func TestBrowseItemRenderer_Title_FileStat(t *testing.T) {
+	renderer := &browseItemRenderer{
+		collapsedFiles: make(map[string]bool),
+		fileStats:      map[string]github.FileStat{"main.go": {Additions: 12, Deletions: 3}},
+	}
+
+	title := renderer.Title(BrowseItem{Type: "file", Path: "main.go"})
+	if !strings.Contains(title, ui.Colorize(ui.ColorGreen, "+12")) || !strings.Contains(title, ui.Colorize(ui.ColorRed, "-3")) {
+		t.Errorf("expected colored diff stat in file header, got %q", title)
+	}
+
+	renamed := renderer.Title(BrowseItem{Type: "file", Path: "old_name.go"})
+	if strings.Contains(renamed, "(") {
+		t.Errorf("expected no diff stat for a file missing from the diff, got %q", renamed)
+	}
+}
+
+func TestBuildCommentTree_ConversationComments(t *testing.T) {
+	comments := []*github.ReviewComment{
+		{ID: 1, Path: "main.go", Line: 4, Body: "inline"},
+		{ID: 2, Kind: github.CommentKindIssue, Body: "CI is red", CreatedAt: time.Unix(200, 0)},
+		{ID: 3, Kind: github.CommentKindSummary, Body: "Looks good", CreatedAt: time.Unix(100, 0)},
+	}
+
+	items := buildCommentTree(comments)
+	if len(items) != 5 {
+		t.Fatalf("expected two headers and three comments, got %d items", len(items))
+	}
+	if items[0].Type != "file" || items[0].Path != conversationGroup {
+		t.Errorf("expected the conversation group first, got %+v", items[0])
+	}
+	if items[1].Comment.ID != 3 || items[2].Comment.ID != 2 {
+		t.Errorf("expected conversation comments in time order, got %d, %d", items[1].Comment.ID, items[2].Comment.ID)
+	}
+
+	renderer := &browseItemRenderer{collapsedFiles: make(map[string]bool)}
+	title := renderer.Title(items[1])
+	if strings.Contains(title, "Line 0") || !strings.Contains(title, "review summary") {
+		t.Errorf("expected a kind label instead of a line number, got %q", title)
+	}
+}
+
+func TestWithResolvedSummaries(t *testing.T) {
+	comments := []*github.ReviewComment{
+		{ID: 1, Path: "a.go", Line: 1, SubjectType: "resolved"},
+		{ID: 2, Path: "a.go", Line: 2},
+		{ID: 3, Path: "a.go", Line: 3, SubjectType: "resolved"},
+		{ID: 4, Path: "b.go", Line: 1},
+	}
+
+	items := withResolvedSummaries(buildCommentTree(comments))
+	var types []string
+	for _, item := range items {
+		types = append(types, item.Type)
+	}
+	want := []string{"file", "resolved_summary", "comment", "comment", "comment", "file", "resolved_summary", "comment"}
+	if !slices.Equal(types, want) {
+		t.Fatalf("withResolvedSummaries() types = %v, want %v", types, want)
+	}
+	summary := items[1]
+	if summary.Path != "a.go" || len(summary.Threads) != 3 || resolvedCount(summary) != 2 {
+		t.Errorf("expected a.go's three threads with two resolved, got %+v", summary)
+	}
+	if n := resolvedCount(items[6]); n != 0 {
+		t.Errorf("expected no resolved threads in b.go, got %d", n)
+	}
+
+	// The count follows threads resolved after the list was built
+	comments[1].SubjectType = "resolved"
+	renderer := &browseItemRenderer{collapsedFiles: make(map[string]bool), expandedFiles: make(map[string]bool)}
+	if title := renderer.Title(summary); !strings.Contains(title, "3 resolved (enter to expand)") {
+		t.Errorf("expected a collapsed summary title, got %q", title)
+	}
+	renderer.expandedFiles["a.go"] = true
+	if title := renderer.Title(summary); !strings.Contains(title, "3 resolved (enter to collapse)") {
+		t.Errorf("expected an expanded summary title, got %q", title)
+	}
+	if desc := renderer.Description(summary); desc != "" {
+		t.Errorf("expected no description for a summary row, got %q", desc)
+	}
+	if rowLabel(summary) != "resolved summary" || rowLabel(items[0]) != "file header" {
+		t.Errorf("unexpected row labels %q, %q", rowLabel(summary), rowLabel(items[0]))
+	}
+}
+
+func TestLimitComments(t *testing.T) {
+	comments := []*github.ReviewComment{
+		{ID: 1, CreatedAt: time.Unix(100, 0)},
+		{ID: 2, CreatedAt: time.Unix(500, 0), SubjectType: "resolved"},
+		{ID: 3, CreatedAt: time.Unix(200, 0)},
+		{ID: 4, CreatedAt: time.Unix(50, 0), ThreadComments: []github.ThreadComment{{CreatedAt: time.Unix(300, 0)}}},
+		{ID: 5, CreatedAt: time.Unix(200, 0)},
+	}
+
+	limited := limitComments(comments, 3)
+	var ids []int64
+	for _, c := range limited {
+		ids = append(ids, c.ID)
+	}
+	// Unresolved first, latest activity (replies included) first, newer ID on ties
+	if want := []int64{4, 5, 3}; !slices.Equal(ids, want) {
+		t.Errorf("expected %v, got %v", want, ids)
+	}
+
+	reversed := slices.Clone(comments)
+	slices.Reverse(reversed)
+	if again := limitComments(reversed, 3); again[0].ID != 4 || again[1].ID != 5 || again[2].ID != 3 {
+		t.Error("expected the same threads regardless of fetch order")
+	}
+
+	if got := limitComments(comments, 0); len(got) != len(comments) {
+		t.Errorf("expected 0 to keep all threads, got %d", len(got))
+	}
+	if got := limitComments(comments, 10); len(got) != len(comments) {
+		t.Errorf("expected a limit above the count to keep all threads, got %d", len(got))
+	}
+}
+
+func TestThreadLimit(t *testing.T) {
+	comments := []*github.ReviewComment{
+		{ID: 1, Path: "a.go", CreatedAt: time.Unix(100, 0)},
+		{ID: 2, Path: "a.go", CreatedAt: time.Unix(200, 0)},
+		{ID: 3, Path: "b.go", CreatedAt: time.Unix(300, 0)},
+	}
+	countComments := func(items []BrowseItem) int {
+		n := 0
+		for _, item := range items {
+			if item.Type == "comment" {
+				n++
+			}
+		}
+		return n
+	}
+
+	limit := &threadLimit{max: 2}
+	if n := countComments(limit.items(comments)); n != 2 || limit.status() != "showing 2 of 3" {
+		t.Errorf("expected 2 of 3 threads listed, got %d with status %q", n, limit.status())
+	}
+
+	// A refresh lists the whole fetch; the cap is applied to it again
+	comments = append(comments, &github.ReviewComment{ID: 4, Path: "c.go", CreatedAt: time.Unix(400, 0)})
+	refreshed := limit.refreshed(withResolvedSummaries(buildCommentTree(comments)))
+	if n := countComments(refreshed); n != 2 || limit.status() != "showing 2 of 4" {
+		t.Errorf("expected 2 of 4 threads after a refresh, got %d with status %q", n, limit.status())
+	}
+
+	if n := countComments(limit.lift()); n != 4 || limit.status() != "" {
+		t.Errorf("expected every thread once lifted, got %d with status %q", n, limit.status())
+	}
+	if n := countComments(limit.refreshed(withResolvedSummaries(buildCommentTree(comments)))); n != 4 {
+		t.Errorf("expected a refresh to stay lifted, got %d", n)
+	}
+}
+
+func TestParseIncludeKinds(t *testing.T) {
+	kinds, err := parseIncludeKinds([]string{"summary", " Issue "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(kinds) != 2 || kinds[0] != github.CommentKindSummary || kinds[1] != github.CommentKindIssue {
+		t.Errorf("unexpected kinds: %v", kinds)
+	}
+	if _, err := parseIncludeKinds([]string{"inline"}); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+}
+
+func TestBrowseItemHasComment(t *testing.T) {
+	item := BrowseItem{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{
+		ID:             1,
+		ThreadComments: []github.ThreadComment{{ID: 1}, {ID: 2}},
+	}}
+	if !browseItemHasComment(item, 1) || !browseItemHasComment(item, 2) {
+		t.Error("expected the thread head and its reply to match")
+	}
+	if browseItemHasComment(item, 3) {
+		t.Error("expected an unrelated ID not to match")
+	}
+	if browseItemHasComment(BrowseItem{Type: "file", Path: "main.go"}, 1) {
+		t.Error("expected file headers never to match")
+	}
+}
+
+func TestParseBatchReply(t *testing.T) {
+	body, resolve := parseBatchReply("Fixed, thanks.\n/resolve\n")
+	if body != "Fixed, thanks." || !resolve {
+		t.Errorf("parseBatchReply() = %q, %v", body, resolve)
+	}
+	body, resolve = parseBatchReply("Will do /resolve later")
+	if body != "Will do /resolve later" || resolve {
+		t.Errorf("expected an inline /resolve to be kept, got %q, %v", body, resolve)
+	}
+}
+
+func TestWontFix(t *testing.T) {
+	var repliedWith string
+	reply := func(comment *github.ReviewComment, body string) (*github.ThreadComment, error) {
+		repliedWith = body
+		return &github.ThreadComment{ID: 9, Body: body, HTMLURL: "https://github.com/o/r/pull/1#discussion_r9"}, nil
+	}
+	resolved := 0
+	resolve := func(comment *github.ReviewComment) (string, func(), error) {
+		resolved++
+		return "Marked as resolved", func() { comment.SubjectType = "resolved" }, nil
+	}
+
+	// The template round-trips through the editor to just the opener
+	body := ui.SanitizeEditorContent(wontFixTemplate("Won't fix:"))
+	if body != "Won't fix:" {
+		t.Errorf("expected the template to sanitize to the opener, got %q", body)
+	}
+
+	comment := &github.ReviewComment{ID: 1, ThreadID: "T1"}
+	if _, _, err := wontFix(comment, body, defaultWontFixPrefix, reply, resolve); err == nil || repliedWith != "" {
+		t.Fatal("expected a reply without a reason to be refused before posting")
+	}
+
+	msg, apply, err := wontFix(comment, "Won't fix: out of scope", defaultWontFixPrefix, reply, resolve)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comment.ThreadComments) != 0 || comment.IsResolved() {
+		t.Fatal("expected the comment left alone until apply runs")
+	}
+	apply()
+	if repliedWith != "Won't fix: out of scope" || resolved != 1 || !comment.IsResolved() {
+		t.Errorf("expected the reply posted and the thread resolved (replied %q, resolved %d)", repliedWith, resolved)
+	}
+	if len(comment.ThreadComments) != 1 || !strings.Contains(msg, "resolved the thread") {
+		t.Errorf("expected the reply added locally and both reported, got %q", msg)
+	}
+
+	// An already resolved thread is left resolved rather than toggled
+	msg, _, err = wontFix(comment, "Won't fix: still", defaultWontFixPrefix, reply, resolve)
+	if err != nil || resolved != 1 || !strings.Contains(msg, "already resolved") {
+		t.Errorf("expected no second resolve, got %q, %v (resolved %d)", msg, err, resolved)
+	}
+
+	failing := func(*github.ReviewComment) (string, func(), error) { return "", nil, errors.New("boom") }
+	open := &github.ReviewComment{ID: 2, ThreadID: "T2"}
+	_, apply, err = wontFix(open, "Won't fix: x", defaultWontFixPrefix, reply, failing)
+	if err == nil || !strings.Contains(err.Error(), "discussion_r9") {
+		t.Errorf("expected the resolve failure to name the posted reply, got %v", err)
+	}
+	if apply(); len(open.ThreadComments) != 1 {
+		t.Error("expected the posted reply recorded even though the resolve failed")
+	}
+}
+
+func TestAcceptSuggestion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var replies []string
+	reply := func(comment *github.ReviewComment) (func(), error) {
+		replies = append(replies, acceptReply)
+		return nil, nil
+	}
+	resolve := func(comment *github.ReviewComment) (func(), error) {
+		return func() { comment.SubjectType = "resolved" }, nil
+	}
+
+	// A failed apply restores the file and posts nothing
+	broken := func(comment *github.ReviewComment) error {
+		_ = os.WriteFile(comment.Path, []byte("half-written"), 0o644)
+		return errors.New("hunk mismatch")
+	}
+	comment := &github.ReviewComment{ID: 1, Path: path, Line: 1}
+	if _, _, err := acceptSuggestion(comment, broken, reply, resolve); err == nil || !strings.Contains(err.Error(), "hunk mismatch") {
+		t.Fatalf("expected the apply error, got %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "old\n" || len(replies) != 0 {
+		t.Errorf("expected the file restored and no reply, got %q and %v", content, replies)
+	}
+
+	apply := func(comment *github.ReviewComment) error {
+		return os.WriteFile(comment.Path, []byte("new\n"), 0o644)
+	}
+	report, record, err := acceptSuggestion(comment, apply, reply, resolve)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.IsResolved() {
+		t.Error("expected the thread's state left alone until the result is applied")
+	}
+	record()
+	if len(replies) != 1 || !comment.IsResolved() {
+		t.Errorf("expected a reply and a resolved thread, got %v replies, resolved %t", replies, comment.IsResolved())
+	}
+	for _, want := range []string{"Applied suggestion", "Replied", "Resolved thread"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected %q in the report:\n%s", want, report)
+		}
+	}
+
+	// A failed reply keeps the change but leaves the thread alone
+	failingReply := func(*github.ReviewComment) (func(), error) { return nil, errors.New("forbidden") }
+	open := &github.ReviewComment{ID: 2, Path: path, Line: 1}
+	report, _, err = acceptSuggestion(open, apply, failingReply, resolve)
+	if err != nil || !strings.Contains(report, "Reply failed: forbidden") || open.IsResolved() {
+		t.Errorf("expected the reply failure reported and the thread left open, got %q, %v", report, err)
+	}
+}
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"pkg/ui/**", "pkg/ui/colors.go", true},
+		{"pkg/ui/**", "pkg/ui/testdata/golden.txt", true},
+		{"pkg/ui/**", "pkg/uix/colors.go", false},
+		{"pkg/**/*_test.go", "pkg/ui/colors_test.go", true},
+		{"pkg/**/*_test.go", "pkg/colors_test.go", true},
+		{"pkg/**/*_test.go", "cmd/browse_test.go", false},
+		{"cmd/*.go", "cmd/browse.go", true},
+		{"cmd/*.go", "cmd/sub/browse.go", false},
+		{"*.md", "docs/guide/README.md", true},
+		{"*.md", "main.go", false},
+	}
+	for _, tt := range tests {
+		if got := matchPathGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchPathGlob(%q, %q) = %t, want %t", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+
+	if err := validPathGlob("pkg/[ui/**"); err == nil {
+		t.Error("expected an error for an unclosed bracket")
+	}
+	if err := validPathGlob("pkg/ui/**"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDraftReview(t *testing.T) {
+	drafts := &draftReview{}
+	if drafts.toggle(); !drafts.on.Load() {
+		t.Fatal("expected toggle to turn draft mode on")
+	}
+	first := &github.ReviewComment{ID: 1, ThreadID: "T1"}
+	second := &github.ReviewComment{ID: 2, ThreadID: "T2"}
+	drafts.add(first, "one")
+	drafts.add(second, "two")
+
+	// A failed submission keeps the drafts for another try
+	var sent []github.DraftReply
+	failing := func(replies []github.DraftReply) ([]github.ThreadComment, string, error) {
+		sent = replies
+		return nil, "", errors.New("boom")
+	}
+	call, err := drafts.submission(failing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := drafts.submission(failing); err == nil {
+		t.Error("expected a second submission to wait for the first")
+	}
+	_, apply, err := call()
+	apply()
+	if err == nil || len(drafts.pending) != 2 {
+		t.Fatalf("expected the error and the drafts kept, got %v with %d pending", err, len(drafts.pending))
+	}
+	if want := []github.DraftReply{{ThreadID: "T1", Body: "one"}, {ThreadID: "T2", Body: "two"}}; !slices.Equal(sent, want) {
+		t.Errorf("sent %v, want %v", sent, want)
+	}
+
+	post := func(replies []github.DraftReply) ([]github.ThreadComment, string, error) {
+		posted := make([]github.ThreadComment, len(replies))
+		for i, reply := range replies {
+			posted[i] = github.ThreadComment{ID: int64(10 + i), Body: reply.Body}
+		}
+		return posted, "https://github.com/o/r/pull/1#pullrequestreview-5", nil
+	}
+	if call, err = drafts.submission(post); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status, apply, err := call()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(drafts.pending) != 2 || len(second.ThreadComments) != 0 {
+		t.Error("expected nothing recorded until apply runs")
+	}
+
+	// A draft written while the review was being submitted stays pending
+	third := &github.ReviewComment{ID: 3, ThreadID: "T3"}
+	drafts.add(third, "three")
+	apply()
+	if !strings.Contains(status, "2 replies") || len(drafts.pending) != 1 || drafts.pending[0].comment != third {
+		t.Errorf("expected the submitted drafts cleared and counted, got %q with %+v pending", status, drafts.pending)
+	}
+	if len(second.ThreadComments) != 1 || second.ThreadComments[0].Body != "two" {
+		t.Errorf("expected each reply added to its own thread, got %+v", second.ThreadComments)
+	}
+}
+
+func TestReplyToAll(t *testing.T) {
+	saved := batchReplyDelay
+	batchReplyDelay = 0
+	t.Cleanup(func() { batchReplyDelay = saved })
+
+	items := []BrowseItem{
+		{Type: "comment", Comment: &github.ReviewComment{ID: 1, Path: "a.go", Line: 1}},
+		{Type: "comment", Comment: &github.ReviewComment{ID: 2, Path: "b.go", Line: 2}},
+		{Type: "comment", Comment: &github.ReviewComment{ID: 3, Path: "c.go", Line: 3}},
+	}
+	var replied, resolved []int64
+	reply := func(c *github.ReviewComment) (func(), error) {
+		if c.ID == 2 {
+			return nil, errors.New("boom")
+		}
+		replied = append(replied, c.ID)
+		return func() { c.ThreadComments = append(c.ThreadComments, github.ThreadComment{Body: "done"}) }, nil
+	}
+	resolveThread := func(c *github.ReviewComment) (func(), error) {
+		resolved = append(resolved, c.ID)
+		return nil, nil
+	}
+
+	result := replyToAll(items, true, nil, reply, resolveThread)
+	if result.Summary != "Replied to 2 of 3 threads, resolved 2" {
+		t.Errorf("unexpected summary %q", result.Summary)
+	}
+	if len(result.Succeeded) != 2 || len(result.Failed) != 1 || result.Failed[0].Comment.ID != 2 {
+		t.Errorf("expected only b.go:2 to fail, got %+v", result)
+	}
+	if err := result.Errors[browseItemKey(items[1])]; err == nil || err.Error() != "b.go:2 (boom)" {
+		t.Errorf("expected the failure's reason keyed by item, got %v", err)
+	}
+	if len(replied) != 2 || len(resolved) != 2 {
+		t.Errorf("expected the other threads replied to and resolved, got %v %v", replied, resolved)
+	}
+	if len(items[0].Comment.ThreadComments) != 0 {
+		t.Error("expected the replies recorded only when Apply runs")
+	}
+	result.Apply()
+	if len(items[0].Comment.ThreadComments) != 1 || len(items[1].Comment.ThreadComments) != 0 {
+		t.Errorf("expected Apply to record the posted replies, got %+v", items)
+	}
+
+	result = replyToAll(items[:1], false, nil, func(*github.ReviewComment) (func(), error) { return nil, nil }, resolveThread)
+	if result.Summary != "Replied to 1 of 1 threads" || len(result.Failed) != 0 {
+		t.Errorf("replyToAll() = %+v", result)
+	}
+}
+
+func TestReplyToAllRetriesFailedStep(t *testing.T) {
+	saved := batchReplyDelay
+	batchReplyDelay = 0
+	t.Cleanup(func() { batchReplyDelay = saved })
+
+	items := []BrowseItem{
+		{Type: "comment", Comment: &github.ReviewComment{ID: 1, Path: "a.go", Line: 1}},
+		{Type: "comment", Comment: &github.ReviewComment{ID: 2, Path: "b.go", Line: 2}},
+	}
+	replies := map[int64]int{}
+	reply := func(c *github.ReviewComment) (func(), error) {
+		replies[c.ID]++
+		return nil, nil
+	}
+	resolveFails := true
+	resolveThread := func(c *github.ReviewComment) (func(), error) {
+		if c.ID == 2 && resolveFails {
+			return nil, errors.New("boom")
+		}
+		return nil, nil
+	}
+
+	result := replyToAll(items, true, nil, reply, resolveThread)
+	key := browseItemKey(items[1])
+	if len(result.Failed) != 1 || result.FailedStep[key] != batchStepResolve {
+		t.Fatalf("expected b.go:2 to fail at resolve, got %+v", result)
+	}
+
+	resolveFails = false
+	retry := replyToAll(result.Failed, true, result.FailedStep, reply, resolveThread)
+	if len(retry.Failed) != 0 || len(retry.FailedStep) != 0 {
+		t.Errorf("expected the retry to succeed, got %+v", retry)
+	}
+	if replies[2] != 1 {
+		t.Errorf("expected the retry to resolve without replying again, got %d replies", replies[2])
+	}
+
+	// The same body sent again as a new batch is a deliberate second reply
+	replyToAll(items[1:], false, nil, reply, resolveThread)
+	if replies[2] != 2 {
+		t.Errorf("expected a new batch to reply again, got %d replies", replies[2])
+	}
+}
+
+func TestExplainAPIError(t *testing.T) {
+	forbidden := &github.APIError{StatusCode: 403, Err: errors.New("exit status 1")}
+	err := explainAPIError(fmt.Errorf("failed to post reply: %w", forbidden))
+	if !strings.Contains(err.Error(), "gh auth refresh") {
+		t.Errorf("expected an auth hint for a 403, got %q", err)
+	}
+	if !errors.Is(err, forbidden) {
+		t.Error("expected the APIError to stay in the chain")
+	}
+
+	rateLimited := &github.RateLimitError{Err: forbidden}
+	if got := explainAPIError(rateLimited); got != error(rateLimited) {
+		t.Errorf("expected rate limit errors unchanged, got %v", got)
+	}
+	plain := errors.New("boom")
+	if got := explainAPIError(plain); got != plain {
+		t.Errorf("expected other errors unchanged, got %v", got)
+	}
+	if explainAPIError(nil) != nil {
+		t.Error("expected nil to stay nil")
+	}
+}
+
+func TestStartResolveToggle(t *testing.T) {
+	comment := &github.ReviewComment{ID: 1, ThreadID: "T_1", SubjectType: "line"}
+	var resolvedThread string
+	resolve := func(id string) error { resolvedThread = id; return nil }
+	unresolve := func(string) error { return errors.New("boom") }
+
+	find := func(string) (string, error) { return "", errors.New("unexpected lookup") }
+
+	call, undo, err := startResolveToggle(comment, "me", find, resolve, unresolve)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !comment.IsResolved() || comment.ResolvedBy != "me" {
+		t.Errorf("expected the comment flipped to resolved before the call, got %+v", comment)
+	}
+	if status, _, err := call(); err != nil || status != "Marked as resolved" || resolvedThread != "T_1" {
+		t.Errorf("call() = %q, %v (thread %q)", status, err, resolvedThread)
+	}
+
+	call, undo, _ = startResolveToggle(comment, "me", find, resolve, unresolve)
+	if comment.IsResolved() {
+		t.Error("expected the comment flipped back to unresolved")
+	}
+	if _, _, err := call(); err == nil {
+		t.Error("expected the unresolve error")
+	}
+	undo()
+	if !comment.IsResolved() || comment.ResolvedBy != "me" {
+		t.Errorf("expected undo to restore the resolved state, got %+v", comment)
+	}
+
+	if _, _, err := startResolveToggle(&github.ReviewComment{}, "me", find, resolve, unresolve); err == nil {
+		t.Error("expected an error for a comment without a thread")
+	}
+
+	// A thread the initial fetch missed is looked up in the call, and its
+	// ID recorded only by apply
+	found := &github.ReviewComment{ID: 2, NodeID: "PRRC_2", Kind: github.CommentKindInline}
+	lookups := 0
+	find = func(nodeID string) (string, error) { lookups++; return "T_2", nil }
+	if call, _, err = startResolveToggle(found, "me", find, resolve, unresolve); err != nil || lookups != 0 {
+		t.Fatalf("expected no lookup before the call (lookups=%d, err=%v)", lookups, err)
+	}
+	_, apply, err := call()
+	if err != nil || resolvedThread != "T_2" || found.ThreadID != "" {
+		t.Fatalf("expected T_2 resolved without changing the comment, got %v (thread %q, ID %q)", err, resolvedThread, found.ThreadID)
+	}
+	if apply(); found.ThreadID != "T_2" {
+		t.Errorf("expected apply to cache the thread ID, got %q", found.ThreadID)
+	}
+}
+
+func TestCreditResolvedBy(t *testing.T) {
+	pending := &github.ReviewComment{ID: 1, SubjectType: "resolved"}
+	reopened := &github.ReviewComment{ID: 2, SubjectType: "line"}
+	credited := &github.ReviewComment{ID: 3, SubjectType: "resolved", ResolvedBy: "bob"}
+
+	creditResolvedBy([]*github.ReviewComment{pending, reopened, credited}, "alice")
+	if pending.ResolvedBy != "alice" {
+		t.Errorf("expected the thread resolved before the login arrived credited, got %q", pending.ResolvedBy)
+	}
+	if reopened.ResolvedBy != "" || credited.ResolvedBy != "bob" {
+		t.Errorf("expected other threads left alone, got %q and %q", reopened.ResolvedBy, credited.ResolvedBy)
+	}
+}
+
+func TestResolvedByCredit(t *testing.T) {
+	login := ""
+	var pending []*github.ReviewComment
+	credit := resolvedByCredit(func() string { return login }, &pending)
+
+	early := &github.ReviewComment{ID: 1, SubjectType: "resolved"}
+	credit(early)
+	if early.ResolvedBy != "" || len(pending) != 1 {
+		t.Fatalf("expected a resolve before the login arrived to wait, got %q and %d pending", early.ResolvedBy, len(pending))
+	}
+
+	login = "alice"
+	late := &github.ReviewComment{ID: 2, SubjectType: "resolved"}
+	credit(late)
+	if late.ResolvedBy != "alice" || len(pending) != 1 {
+		t.Errorf("expected the known login credited, got %q and %d pending", late.ResolvedBy, len(pending))
+	}
+}
+
+func TestBrowseItemRenderer_LineRange(t *testing.T) {
+	renderer := &browseItemRenderer{collapsedFiles: make(map[string]bool)}
+	multi := BrowseItem{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{
+		ID: 1, Author: "reviewer", StartLine: 10, Line: 14, Body: "Extract this block",
+	}}
+	single := BrowseItem{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{
+		ID: 2, Author: "reviewer", StartLine: 7, Line: 7, Body: "Typo",
+	}}
+
+	if title := renderer.Title(multi); !strings.Contains(title, "Lines 10-14") {
+		t.Errorf("expected the line range in the title, got %q", title)
+	}
+	if title := renderer.Title(single); !strings.Contains(title, "Line 7") || strings.Contains(title, "Lines") {
+		t.Errorf("expected a single line in the title, got %q", title)
+	}
+	if got := renderer.EditLine(multi); got != 10 {
+		t.Errorf("EditLine() = %d, want the start of the range", got)
+	}
+	if got := renderer.EditLine(single); got != 7 {
+		t.Errorf("EditLine() = %d, want 7", got)
+	}
+}
+
+func TestBrowseItemRenderer_Aligned(t *testing.T) {
+	renderer := &browseItemRenderer{
+		collapsedFiles: make(map[string]bool),
+		aligned:        true,
+		fileStats: map[string]github.FileStat{
+			"main.go":          {Additions: 1, Deletions: 2},
+			"pkg/long/path.go": {Additions: 3, Deletions: 4},
+		},
+	}
+	items := []BrowseItem{
+		{Type: "file", Path: "main.go"},
+		{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{ID: 1, Author: "al", Line: 7, Body: "a"}},
+		{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{ID: 2, Author: "longer-name", StartLine: 10, Line: 140, Body: "b"}},
+		{Type: "file", Path: "pkg/long/path.go"},
+	}
+	renderer.MeasureColumns(items)
+
+	// columnOf is the display column where marker starts in s
+	columnOf := func(s, marker string) int {
+		return ui.DisplayWidth(s[:strings.Index(s, marker)])
+	}
+	first, second := renderer.Title(items[1]), renderer.Title(items[2])
+	if columnOf(first, "Line") != columnOf(second, "Line") {
+		t.Errorf("expected locations to line up:\n%q\n%q", first, second)
+	}
+	short, long := renderer.Title(items[0]), renderer.Title(items[3])
+	if columnOf(short, "+1") != columnOf(long, "+3") {
+		t.Errorf("expected file stats to line up:\n%q\n%q", short, long)
+	}
+
+	// A narrow list caps the columns instead of padding to the outlier
+	renderer.SetPreviewWidth(20)
+	renderer.MeasureColumns(items)
+	if renderer.pathWidth != 10 || renderer.authorWidth != 5 {
+		t.Errorf("expected columns capped to the width, got path %d, author %d", renderer.pathWidth, renderer.authorWidth)
+	}
+}
+
+func TestBrowseLegend(t *testing.T) {
+	var samples []string
+	for _, entry := range browseLegend() {
+		if entry.Sample == "" || entry.Meaning == "" {
+			t.Errorf("expected sample and meaning for every entry, got %+v", entry)
+		}
+		samples = append(samples, entry.Sample)
+	}
+	joined := strings.Join(samples, " ")
+	for _, want := range []string{ui.NewStatusStyle(true).Format(false), ui.NewAuthorStyle("ci[bot]").Format(false), "main.go"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected legend sample %q", want)
+		}
+	}
+}
+
+func TestBrowseItemRenderer_PreviewRaw(t *testing.T) {
+	renderer := &browseItemRenderer{collapsedFiles: make(map[string]bool)}
+	item := BrowseItem{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{
+		ID: 1, Author: "reviewer", Path: "main.go", Line: 3,
+		Body:           "| a | b |\n|---|---|\n| 1 | 2 |",
+		ThreadComments: []github.ThreadComment{{ID: 2, Author: "author", Body: "- **done**"}},
+	}}
+
+	raw := renderer.PreviewRaw(item, 1, "")
+	for _, want := range []string{"| a | b |\n|---|---|", "- **done**", "SELECTED REPLY"} {
+		if !strings.Contains(raw, want) {
+			t.Errorf("expected raw preview to contain %q, got:\n%s", want, raw)
+		}
+	}
+	if rendered := renderer.PreviewWithHighlight(item, 1, ""); strings.Contains(rendered, "- **done**") {
+		t.Errorf("expected the rendered preview to render markdown, got:\n%s", rendered)
+	}
+}
+
+func TestBrowseItemRenderer_FoldsOlderReplies(t *testing.T) {
+	renderer := &browseItemRenderer{collapsedFiles: make(map[string]bool)}
+	var replies []github.ThreadComment
+	for i := 1; i <= 6; i++ {
+		replies = append(replies, github.ThreadComment{ID: int64(i + 1), Author: "author", Body: fmt.Sprintf("reply body %d", i)})
+	}
+	item := BrowseItem{Type: "comment", Path: "main.go", Comment: &github.ReviewComment{
+		ID: 1, Author: "reviewer", Path: "main.go", Line: 3, Body: "head", ThreadComments: replies,
+	}}
+
+	if preview := renderer.PreviewRaw(item, -1, ""); !strings.Contains(preview, "reply body 1") {
+		t.Error("expected every reply before folding is configured")
+	}
+
+	renderer.SetVisibleReplies(ui.DefaultVisibleReplies)
+	preview := renderer.PreviewRaw(item, -1, "")
+	if !strings.Contains(preview, "… 3 earlier replies") {
+		t.Errorf("expected a folded replies note, got:\n%s", preview)
+	}
+	if strings.Contains(preview, "reply body 3") || !strings.Contains(preview, "reply body 4") {
+		t.Errorf("expected only the latest three replies, got:\n%s", preview)
+	}
+
+	// A reply selected for an action is unfolded
+	if preview := renderer.PreviewRaw(item, 2, ""); !strings.Contains(preview, "reply body 2") || !strings.Contains(preview, "… 1 earlier reply ") {
+		t.Errorf("expected the highlighted reply to be shown, got:\n%s", preview)
+	}
+
+	renderer.SetVisibleReplies(-1)
+	if preview := renderer.PreviewRaw(item, -1, ""); strings.Contains(preview, "earlier repl") {
+		t.Errorf("expected no folding when showing all replies, got:\n%s", preview)
+	}
+}
+
+func TestParseBrowseURL(t *testing.T) {
+	host, repo, pr, id, err := parseBrowseURL("https://github.com/owner/repo/pull/12/files#r345")
+	if err != nil {
+		t.Fatalf("parseBrowseURL returned error: %v", err)
+	}
+	if host != "github.com" || repo != "owner/repo" || pr != 12 || id != 345 {
+		t.Errorf("parseBrowseURL() = %q, %q, %d, %d; want github.com, owner/repo, 12, 345", host, repo, pr, id)
+	}
+	if host, _, _, _, _ := parseBrowseURL("https://ghe.example.com/owner/repo/pull/12#discussion_r3"); host != "ghe.example.com" {
+		t.Errorf("expected the enterprise host to be kept, got %q", host)
+	}
+	if _, _, _, _, err := parseBrowseURL("https://github.com/owner/repo/pull/12"); err == nil {
+		t.Error("expected a URL without a comment anchor to be rejected")
+	}
+}
+
+func TestSwitchRepoRefKeepsURLHost(t *testing.T) {
+	saved := repoFlag
+	t.Cleanup(func() { repoFlag = saved })
+	t.Setenv("GH_REPO", "")
+
+	host, repo, _, _, err := parseBrowseURL("https://ghe.example.com/owner/repo/pull/12#discussion_r3")
+	if err != nil {
+		t.Fatalf("parseBrowseURL returned error: %v", err)
+	}
+	client := github.NewClient()
+	client.SetHost(host)
+	client.SetRepo(repo)
+
+	repoFlag = switchRepoRef(client)
+	if repoFlag != "ghe.example.com/owner/repo" {
+		t.Fatalf("switchRepoRef() = %q, want ghe.example.com/owner/repo", repoFlag)
+	}
+	next := github.NewClient()
+	if err := configureRepo(next); err != nil {
+		t.Fatalf("configureRepo() error: %v", err)
+	}
+	if got, _ := next.GetRepo(); next.Host() != "ghe.example.com" || got != "owner/repo" {
+		t.Errorf("switched client on %q %q, want ghe.example.com owner/repo", next.Host(), got)
+	}
+}
+
+func TestThreadIDLookup(t *testing.T) {
+	lookups := 0
+	find := func(nodeID string) (string, error) {
+		lookups++
+		if nodeID != "PRRC_1" {
+			t.Errorf("looked up %q, want PRRC_1", nodeID)
+		}
+		return "PRRT_1", nil
+	}
+
+	comment := &github.ReviewComment{ID: 1, NodeID: "PRRC_1", Kind: github.CommentKindInline}
+	lookup, err := threadIDLookup(comment, find)
+	if err != nil {
+		t.Fatalf("threadIDLookup returned error: %v", err)
+	}
+	if lookups != 0 {
+		t.Error("expected the check not to look the thread up")
+	}
+	if threadID, err := lookup(); err != nil || threadID != "PRRT_1" || comment.ThreadID != "" {
+		t.Errorf("lookup() = %q, %v; want the ID found without changing the comment", threadID, err)
+	}
+
+	comment.ThreadID = "PRRT_1"
+	if threadID, err := threadIDFor(comment, find); err != nil || threadID != "PRRT_1" || lookups != 1 {
+		t.Errorf("expected a known thread ID not to be looked up again (lookups=%d, err=%v)", lookups, err)
+	}
+
+	if _, err := threadIDLookup(&github.ReviewComment{ID: 2}, find); err == nil {
+		t.Error("expected an error for a comment without a node ID")
+	}
+}
+
+func TestCommentsInReview(t *testing.T) {
+	comments := []*github.ReviewComment{
+		{ID: 1, ReviewID: 10},
+		{ID: 2, ReviewID: 20, ThreadComments: []github.ThreadComment{{ID: 3, ReviewID: 10}}},
+		{ID: 4, ReviewID: 20},
+		{ID: 10, Kind: github.CommentKindSummary},
+	}
+	var ids []int64
+	for _, c := range commentsInReview(comments, 10) {
+		ids = append(ids, c.ID)
+	}
+	if !slices.Equal(ids, []int64{1, 2, 10}) {
+		t.Errorf("commentsInReview() = %v, want the threads review 10 started or replied to and its summary", ids)
+	}
+}
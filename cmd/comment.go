@@ -45,8 +45,8 @@ func init() {
 func runComment(cmd *cobra.Command, args []string) error {
 	client := github.NewClient()
 	client.SetDebug(commentDebug)
-	if repoFlag != "" {
-		client.SetRepo(repoFlag)
+	if err := configureRepo(client); err != nil {
+		return err
 	}
 
 	var (
@@ -89,12 +89,12 @@ func runComment(cmd *cobra.Command, args []string) error {
 
 	reply, err := client.ReplyToReviewComment(prNumber, commentID, body)
 	if err != nil {
-		return err
+		return explainAPIError(err)
 	}
 
 	link := reply.HTMLURL
 	if link == "" {
-		link = fmt.Sprintf("https://github.com/%s/pull/%d#discussion_r%d", getRepoFromClient(client), prNumber, reply.ID)
+		link = discussionURL(getRepoFromClient(client), prNumber, reply.ID)
 	}
 
 	fmt.Printf("%sReply posted by @%s: %s\n",
@@ -107,7 +107,7 @@ func runComment(cmd *cobra.Command, args []string) error {
 		// Fetch the thread ID for this comment
 		comments, err := client.FetchReviewComments(prNumber)
 		if err != nil {
-			return fmt.Errorf("failed to fetch review comments: %w", err)
+			return explainAPIError(fmt.Errorf("failed to fetch review comments: %w", err))
 		}
 
 		var threadID string
@@ -123,7 +123,7 @@ func runComment(cmd *cobra.Command, args []string) error {
 		}
 
 		if err := client.ResolveThread(threadID); err != nil {
-			return fmt.Errorf("failed to resolve thread: %w", err)
+			return explainAPIError(fmt.Errorf("failed to resolve thread: %w", err))
 		}
 
 		fmt.Printf("%sThread marked as resolved\n",
@@ -170,7 +170,7 @@ func resolveCommentBody() (string, error) {
 }
 
 func promptForCommentBody() (string, error) {
-	template := "# Write your PR review comment above. Lines starting with # are ignored.\n"
+	template := "\n" + ui.FormatEditorTemplate("Write your PR review comment above. Everything from the marker line down is ignored.")
 
 	tmpFile, err := os.CreateTemp("", "gh-review-conductor-comment-*.md")
 	if err != nil {
@@ -189,16 +189,7 @@ func promptForCommentBody() (string, error) {
 		return "", fmt.Errorf("failed to close temporary file: %w", err)
 	}
 
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi"
-	}
-
-	editorParts := strings.Fields(editor)
-	if len(editorParts) == 0 {
-		return "", fmt.Errorf("invalid EDITOR value: %q", editor)
-	}
-
+	editorParts := ui.ResolveEditor()
 	editorCmd := exec.Command(editorParts[0], append(editorParts[1:], tmpFile.Name())...)
 	editorCmd.Stdin = os.Stdin
 	editorCmd.Stdout = os.Stdout
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var diffDebug bool
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [PR_NUMBER]",
+	Short: "View the pull request diff with its review comments",
+	Long: `Show the whole pull request diff in a scrollable view.
+
+Lines with review comments are marked; press n/N to move between them and
+Enter to read the comments. { and } jump between files. When PR_NUMBER is
+omitted, the PR is inferred from the current branch.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffDebug, "debug", false, "Enable debug output")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	ui.SetUIDebug(diffDebug)
+	ui.WarmupMarkdownRenderer()
+
+	client := github.NewClient()
+	client.SetDebug(diffDebug)
+	if err := configureRepo(client); err != nil {
+		return err
+	}
+	prNumber, err := getPRNumberWithSelection(args, client)
+	if err != nil {
+		return err
+	}
+
+	diff, err := client.FetchPRDiff(prNumber)
+	if err != nil {
+		return explainAPIError(err)
+	}
+	if diff == "" {
+		fmt.Printf("PR #%d has no changes\n", prNumber)
+		return nil
+	}
+	comments, err := client.FetchReviewComments(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to fetch review comments: %w", err)
+	}
+
+	// Comments read in the diff look the same as in browse's detail view
+	renderer := &browseItemRenderer{
+		repo:           getRepoFromClient(client),
+		prNumber:       prNumber,
+		collapsedFiles: make(map[string]bool),
+	}
+	if root, err := repoRoot(); err == nil {
+		renderer.repoRoot = root
+	}
+
+	return ui.ViewDiff(ui.DiffViewOptions{
+		Title:    fmt.Sprintf("%s #%d", renderer.repo, prNumber),
+		Diff:     diff,
+		Comments: comments,
+		Preview: func(comment *github.ReviewComment) string {
+			return renderer.Preview(BrowseItem{Type: "comment", Path: comment.Path, Comment: comment})
+		},
+	})
+}
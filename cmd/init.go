@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/config"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up the config file interactively",
+	Long: `Ask for your preferred editor, coding agent, markdown theme, default
+browse filters and list layout, and write them to the config file
+(~/.config/gh-review-conductor/config.yml, or $GH_RC_CONFIG).
+
+The current settings are offered as defaults, so init can be run again to
+change them; press Enter to keep one. Settings init doesn't ask about are
+kept, but comments in the file are not.`,
+	Args: cobra.NoArgs,
+	// init is how a broken config file gets fixed, so it skips the root
+	// hook, which fails on one
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		ui.SetColorEnabled(!noColor)
+	},
+	RunE: runInit,
+}
+
+// lookPath finds a program on PATH; replaceable in tests
+var lookPath = exec.LookPath
+
+func runInit(cmd *cobra.Command, args []string) error {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return err
+	}
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	c, err := loadForInit(p, path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Setting up %s\n\n", ui.Colorize(ui.ColorCyan, path))
+	if err := askInitSettings(p, c); err != nil {
+		return err
+	}
+
+	if err := config.Save(path, c); err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", ui.Colorize(ui.ColorGreen, "Saved "+path))
+	return nil
+}
+
+// loadForInit reads the config file at path for init to start from. A file
+// that doesn't parse is what init is there to fix, so it offers to start over
+// from the defaults instead of failing.
+func loadForInit(p *prompter, path string) (*config.Config, error) {
+	c, err := config.Load(path)
+	if !errors.Is(err, config.ErrInvalid) {
+		return c, err
+	}
+	fmt.Fprintln(p.out, ui.Colorize(ui.ColorYellow, err.Error()))
+	replace, askErr := p.confirm("Start over from the defaults? Saving replaces the whole file", false)
+	if askErr != nil {
+		return nil, askErr
+	}
+	if !replace {
+		return nil, err
+	}
+	return config.Default(), nil
+}
+
+// askInitSettings prompts for each setting init covers, storing the answers
+// in c
+func askInitSettings(p *prompter, c *config.Config) error {
+	var err error
+	c.Editor, err = p.ask("Editor for replies, used when $VISUAL and $EDITOR are unset (e.g. code --wait)", c.Editor, commandOnPath)
+	if err != nil {
+		return err
+	}
+	c.Agent, err = p.ask("Coding agent command for a, used when GH_REVIEW_CONDUCTOR_AGENT is unset (e.g. aider)", c.Agent, commandOnPath)
+	if err != nil {
+		return err
+	}
+	themes := ui.MarkdownThemes()
+	theme := c.Theme
+	if theme == "" {
+		theme = "dark"
+	}
+	c.Theme, err = p.ask("Markdown theme ("+strings.Join(themes, ", ")+")", theme, func(answer string) error {
+		if !slices.Contains(themes, answer) {
+			return fmt.Errorf("unknown theme %q", answer)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.Browse.HideResolved, err = p.confirm("Hide resolved threads when browse starts?", c.Browse.HideResolved)
+	if err != nil {
+		return err
+	}
+	c.Browse.HideBots, err = p.confirm("Hide comments from bots?", c.Browse.HideBots)
+	if err != nil {
+		return err
+	}
+	c.Browse.Compact, err = p.confirm("Show one line per comment instead of a body preview under each?", c.Browse.Compact)
+	return err
+}
+
+// commandOnPath checks that a command line's program can be run
+func commandOnPath(command string) error {
+	program := strings.Fields(command)[0]
+	if _, err := lookPath(program); err != nil {
+		return fmt.Errorf("%s was not found on PATH", program)
+	}
+	return nil
+}
+
+// prompter asks questions on out and reads the answers from in
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// readLine reads one trimmed answer
+func (p *prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// ask prompts for a value, keeping current on an empty answer. Other answers
+// are checked with validate and asked again until they pass.
+func (p *prompter) ask(label, current string, validate func(string) error) (string, error) {
+	for {
+		if current != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", label, current)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", label)
+		}
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			return current, nil
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintln(p.out, ui.Colorize(ui.ColorRed, err.Error()))
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// confirm asks a yes/no question, keeping current on an empty answer
+func (p *prompter) confirm(label string, current bool) (bool, error) {
+	choices := "y/N"
+	if current {
+		choices = "Y/n"
+	}
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, choices)
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return current, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, ui.Colorize(ui.ColorRed, "Please answer y or n"))
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/config"
+)
+
+func TestAskInitSettings(t *testing.T) {
+	original := lookPath
+	t.Cleanup(func() { lookPath = original })
+	lookPath = func(program string) (string, error) {
+		if program == "code" || program == "aider" {
+			return "/usr/bin/" + program, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	answers := strings.Join([]string{
+		"nosuchedit", // not on PATH, asked again
+		"code --wait",
+		"aider",
+		"solarized", // unknown theme, asked again
+		"light",
+		"",      // keep hiding resolved threads
+		"maybe", // asked again
+		"y",
+		"y", // compact list
+	}, "\n") + "\n"
+	var out strings.Builder
+	p := &prompter{in: bufio.NewReader(strings.NewReader(answers)), out: &out}
+
+	c := config.Default()
+	if err := askInitSettings(p, c); err != nil {
+		t.Fatalf("askInitSettings returned error: %v", err)
+	}
+
+	if c.Editor != "code --wait" || c.Agent != "aider" || c.Theme != "light" {
+		t.Errorf("unexpected settings: editor %q, agent %q, theme %q", c.Editor, c.Agent, c.Theme)
+	}
+	if !c.Browse.HideResolved || !c.Browse.HideBots || !c.Browse.Compact {
+		t.Errorf("unexpected filters: %+v", c.Browse)
+	}
+	for _, want := range []string{"nosuchedit was not found on PATH", `unknown theme "solarized"`, "Please answer y or n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in the prompts, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestAskInitSettingsEndOfInput(t *testing.T) {
+	p := &prompter{in: bufio.NewReader(strings.NewReader("")), out: &strings.Builder{}}
+	if err := askInitSettings(p, config.Default()); err == nil {
+		t.Error("expected an error when input ends before the last question")
+	}
+}
+
+func TestLoadForInitInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte("browse: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &prompter{in: bufio.NewReader(strings.NewReader("n\n")), out: &strings.Builder{}}
+	if _, err := loadForInit(p, path); !errors.Is(err, config.ErrInvalid) {
+		t.Errorf("expected the parse error when declining to start over, got %v", err)
+	}
+
+	p = &prompter{in: bufio.NewReader(strings.NewReader("y\n")), out: &strings.Builder{}}
+	c, err := loadForInit(p, path)
+	if err != nil {
+		t.Fatalf("loadForInit returned error: %v", err)
+	}
+	if !c.Browse.HideResolved {
+		t.Errorf("expected the defaults, got %+v", c.Browse)
+	}
+}
@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
@@ -10,39 +17,84 @@ import (
 )
 
 var (
-	listShowResolved bool
-	listDebug        bool
-	listLLM          bool
-	listJSON         bool
-	listCodeContext  bool
+	listShowResolved   bool
+	listUnresolvedOnly bool
+	listFileGlob       string
+	listDebug          bool
+	listLLM            bool
+	listJSON           bool
+	listCodeContext    bool
+	listTemplate       string
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list [PR_NUMBER] [THREAD_ID]",
 	Short: "List review comments for a pull request",
-	Long:  `List all review comments and suggestions for a pull request.`,
-	Args:  cobra.RangeArgs(0, 2),
-	RunE:  runList,
+	Long: `List all review comments and suggestions for a pull request.
+
+With --json, comments are printed as a stable JSON array without launching the
+TUI. As before, resolved threads are left out unless --all is given.
+
+With --template FILE, the Go text/template in FILE is executed once per comment
+instead, for custom report formats (CSV rows, HTML, Jira markup). It includes
+resolved threads unless --unresolved-only is given. The template sees these
+fields:
+
+  .ID        comment ID
+  .Path      file path
+  .Line      line number
+  .Author    login of the reviewer
+  .Body      comment text (markdown)
+  .Resolved  whether the thread is resolved
+  .URL       link to the comment
+  .Replies   replies, each with .Author, .Body and .URL`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: runList,
 }
 
 func init() {
 	listCmd.Flags().BoolVar(&listShowResolved, "all", false, "Show resolved/done suggestions")
 	listCmd.Flags().BoolVar(&listDebug, "debug", false, "Enable debug output")
 	listCmd.Flags().BoolVar(&listLLM, "llm", false, "Output in a format suitable for LLM consumption")
-	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output raw review comment JSON (includes thread replies)")
+	listCmd.Flags().BoolVar(&listUnresolvedOnly, "unresolved-only", false, "Only show unresolved comments (default unless --template)")
+	listCmd.Flags().StringVar(&listFileGlob, "file", "", "Only show comments on files matching a glob (e.g. 'pkg/*.go' or '*.go')")
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output review comments as a JSON array for scripting")
 	listCmd.Flags().BoolVar(&listCodeContext, "code-context", false, "Display surrounding diff context for each comment")
+	listCmd.Flags().StringVar(&listTemplate, "template", "", "Format each comment with the Go template in this file (see the fields above)")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
 	client := github.NewClient()
 	client.SetDebug(listDebug)
-	if repoFlag != "" {
-		client.SetRepo(repoFlag)
+	if err := configureRepo(client); err != nil {
+		return err
 	}
 
 	if listJSON && listLLM {
 		return fmt.Errorf("--json cannot be combined with --llm")
 	}
+	if listTemplate != "" && (listJSON || listLLM) {
+		return fmt.Errorf("--template cannot be combined with --json or --llm")
+	}
+	if listShowResolved && listUnresolvedOnly {
+		return fmt.Errorf("--all cannot be combined with --unresolved-only")
+	}
+	if listFileGlob != "" {
+		if _, err := path.Match(listFileGlob, ""); err != nil {
+			return fmt.Errorf("invalid --file pattern %q: %w", listFileGlob, err)
+		}
+	}
+
+	// Compile the template up front so a typo fails before any API calls
+	var tmpl *template.Template
+	if listTemplate != "" {
+		var err error
+		tmpl, err = parseCommentTemplate(listTemplate)
+		if err != nil {
+			return err
+		}
+	}
+	export := listJSON || tmpl != nil
 
 	prNumber, err := getPRNumberWithSelection(args, client)
 	if err != nil {
@@ -59,28 +111,37 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to fetch review comments: %w", err)
 	}
 
-	// Filter out resolved comments unless --all is specified
+	// Filter out resolved comments unless --all is specified. Template
+	// output includes everything by default so reports see the same data as
+	// the TUI; --json keeps the filtering it has always had.
+	showResolved := listShowResolved || (tmpl != nil && !listUnresolvedOnly)
 	filteredComments := make([]*github.ReviewComment, 0)
 	for _, comment := range comments {
-		if listShowResolved || !comment.IsResolved() {
-			filteredComments = append(filteredComments, comment)
+		if !showResolved && comment.IsResolved() {
+			continue
+		}
+		if listFileGlob != "" && !matchFileGlob(listFileGlob, comment.Path) {
+			continue
 		}
+		filteredComments = append(filteredComments, comment)
 	}
 
 	if threadID != "" {
 		filteredComments = filterByThreadID(filteredComments, threadID)
 	}
 
+	if export && len(filteredComments) == 0 && threadID != "" {
+		return fmt.Errorf("no review comments found for thread ID %s", threadID)
+	}
+	if tmpl != nil {
+		return executeCommentTemplate(os.Stdout, tmpl, filteredComments)
+	}
 	if listJSON {
-		if len(filteredComments) == 0 {
-			if threadID != "" {
-				return fmt.Errorf("no review comments found for thread ID %s", threadID)
-			}
-			fmt.Println("[]")
-			return nil
+		repo, err := client.GetRepo()
+		if err != nil {
+			return err
 		}
-
-		jsonOutput, err := dumpCommentsJSON(client, prNumber, filteredComments)
+		jsonOutput, err := formatCommentsJSON(commentAPIBase(client.Host(), repo), filteredComments)
 		if err != nil {
 			return err
 		}
@@ -93,7 +154,9 @@ func runList(cmd *cobra.Command, args []string) error {
 			fmt.Printf("No review comments found for thread ID %s.\n", threadID)
 			return nil
 		}
-		if listShowResolved {
+		if listFileGlob != "" {
+			fmt.Printf("No review comments found for files matching %s.\n", listFileGlob)
+		} else if listShowResolved {
 			fmt.Println("No review comments found.")
 		} else {
 			fmt.Println("No unresolved review comments found. Use --all to show resolved comments.")
@@ -127,29 +190,172 @@ func filterByThreadID(comments []*github.ReviewComment, threadID string) []*gith
 	return filtered
 }
 
-func dumpCommentsJSON(client *github.Client, prNumber int, comments []*github.ReviewComment) (string, error) {
-	commentIDs := collectCommentIDs(comments)
-	return client.DumpCommentsJSON(prNumber, commentIDs)
+// matchFileGlob reports whether path matches pattern. Patterns without a slash
+// are matched against the file's base name so "*.go" works at any depth.
+func matchFileGlob(pattern, filePath string) bool {
+	if ok, _ := path.Match(pattern, filePath); ok {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(filePath))
+		return ok
+	}
+	return false
 }
 
-func collectCommentIDs(comments []*github.ReviewComment) []int64 {
-	seen := make(map[int64]struct{})
-	ids := make([]int64, 0)
+// listCommentJSON is the stable JSON schema emitted by list --json, one
+// entry per comment with its replies after it. The GitHub API fields --json
+// printed before it had a schema keep their names and meaning, url included;
+// author, resolved and reply_count were added on top.
+type listCommentJSON struct {
+	ID                int64            `json:"id"`
+	NodeID            string           `json:"node_id,omitempty"`
+	ReviewID          int64            `json:"pull_request_review_id,omitempty"`
+	InReplyToID       int64            `json:"in_reply_to_id,omitempty"`
+	Path              string           `json:"path"`
+	Line              int              `json:"line"`
+	StartLine         int              `json:"start_line,omitempty"`
+	OriginalLine      int              `json:"original_line,omitempty"`
+	OriginalStartLine int              `json:"original_start_line,omitempty"`
+	Side              string           `json:"side,omitempty"`
+	SubjectType       string           `json:"subject_type,omitempty"`
+	DiffHunk          string           `json:"diff_hunk,omitempty"`
+	CommitID          string           `json:"commit_id,omitempty"`
+	OriginalCommitID  string           `json:"original_commit_id,omitempty"`
+	User              listUserJSON     `json:"user"`
+	Author            string           `json:"author"`
+	Resolved          bool             `json:"resolved"`
+	URL               string           `json:"url"`
+	HTMLURL           string           `json:"html_url"`
+	Body              string           `json:"body"`
+	CreatedAt         time.Time        `json:"created_at"`
+	Reactions         github.Reactions `json:"reactions"`
+	ReplyCount        int              `json:"reply_count"`
+}
+
+// listUserJSON is the user object of a listCommentJSON entry
+type listUserJSON struct {
+	Login string `json:"login"`
+}
 
-	addID := func(id int64) {
-		if _, ok := seen[id]; !ok {
-			seen[id] = struct{}{}
-			ids = append(ids, id)
+// commentAPIBase is the REST URL review comments of repo live under on host,
+// for the url field of list --json
+func commentAPIBase(host, repo string) string {
+	if host == "" || host == "github.com" {
+		return "https://api.github.com/repos/" + repo + "/pulls/comments/"
+	}
+	return "https://" + host + "/api/v3/repos/" + repo + "/pulls/comments/"
+}
+
+// formatCommentsJSON renders comments and their replies as an indented JSON
+// array, with url set to apiBase followed by the comment ID
+func formatCommentsJSON(apiBase string, comments []*github.ReviewComment) (string, error) {
+	out := make([]listCommentJSON, 0, len(comments))
+	for _, comment := range comments {
+		resolved := comment.IsResolved()
+		out = append(out, listCommentJSON{
+			ID:                comment.ID,
+			NodeID:            comment.NodeID,
+			ReviewID:          comment.ReviewID,
+			Path:              comment.Path,
+			Line:              comment.Line,
+			StartLine:         comment.StartLine,
+			OriginalLine:      comment.OriginalLine,
+			OriginalStartLine: comment.OriginalStartLine,
+			Side:              string(comment.DiffSide),
+			SubjectType:       comment.SubjectType,
+			DiffHunk:          comment.DiffHunk,
+			CommitID:          comment.HeadSHA,
+			OriginalCommitID:  comment.OriginalCommitID,
+			User:              listUserJSON{Login: comment.Author},
+			Author:            comment.Author,
+			Resolved:          resolved,
+			URL:               apiBase + strconv.FormatInt(comment.ID, 10),
+			HTMLURL:           comment.HTMLURL,
+			Body:              comment.Body,
+			CreatedAt:         comment.CreatedAt,
+			Reactions:         comment.Reactions,
+			ReplyCount:        len(comment.ThreadComments),
+		})
+		for _, reply := range comment.ThreadComments {
+			out = append(out, listCommentJSON{
+				ID:          reply.ID,
+				ReviewID:    reply.ReviewID,
+				InReplyToID: comment.ID,
+				Path:        comment.Path,
+				Line:        comment.Line,
+				User:        listUserJSON{Login: reply.Author},
+				Author:      reply.Author,
+				Resolved:    resolved,
+				URL:         apiBase + strconv.FormatInt(reply.ID, 10),
+				HTMLURL:     reply.HTMLURL,
+				Body:        reply.Body,
+				CreatedAt:   reply.CreatedAt,
+				Reactions:   reply.Reactions,
+			})
 		}
 	}
 
+	content, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode comments as JSON: %w", err)
+	}
+	return string(content), nil
+}
+
+// templateComment is what a list --template template is executed with, once
+// per comment. Its fields are documented in the list command's help.
+type templateComment struct {
+	ID       int64
+	Path     string
+	Line     int
+	Author   string
+	Body     string
+	Resolved bool
+	URL      string
+	Replies  []templateReply
+}
+
+// templateReply is a reply in templateComment.Replies
+type templateReply struct {
+	Author string
+	Body   string
+	URL    string
+}
+
+// parseCommentTemplate reads and compiles the template in file
+func parseCommentTemplate(file string) (*template.Template, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --template: %w", err)
+	}
+	tmpl, err := template.New(path.Base(file)).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// executeCommentTemplate writes tmpl executed for each comment to w
+func executeCommentTemplate(w io.Writer, tmpl *template.Template, comments []*github.ReviewComment) error {
 	for _, comment := range comments {
-		addID(comment.ID)
+		data := templateComment{
+			ID:       comment.ID,
+			Path:     comment.Path,
+			Line:     comment.Line,
+			Author:   comment.Author,
+			Body:     comment.Body,
+			Resolved: comment.IsResolved(),
+			URL:      comment.HTMLURL,
+		}
 		for _, reply := range comment.ThreadComments {
-			addID(reply.ID)
+			data.Replies = append(data.Replies, templateReply{Author: reply.Author, Body: reply.Body, URL: reply.HTMLURL})
+		}
+		if err := tmpl.Execute(w, data); err != nil {
+			return fmt.Errorf("--template failed on comment %d: %w", comment.ID, err)
 		}
 	}
-	return ids
+	return nil
 }
 
 // displayComment displays a single review comment with formatting
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+)
+
+func TestMatchFileGlob(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"exact path", "pkg/ui/colors.go", "pkg/ui/colors.go", true},
+		{"directory glob", "pkg/ui/*.go", "pkg/ui/colors.go", true},
+		{"directory glob does not cross directories", "pkg/*.go", "pkg/ui/colors.go", false},
+		{"base name glob matches at any depth", "*.go", "pkg/ui/colors.go", true},
+		{"base name glob mismatch", "*.md", "pkg/ui/colors.go", false},
+		{"invalid pattern never matches", "[", "pkg/ui/colors.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchFileGlob(tt.pattern, tt.path); got != tt.expected {
+				t.Errorf("matchFileGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatCommentsJSON(t *testing.T) {
+	comments := []*github.ReviewComment{
+		{
+			ID:          1,
+			Path:        "main.go",
+			Line:        10,
+			Author:      "reviewer",
+			Body:        "Please rename this",
+			HTMLURL:     "https://github.com/owner/repo/pull/1#discussion_r1",
+			SubjectType: "resolved",
+			ThreadComments: []github.ThreadComment{
+				{ID: 2, Author: "author", Body: "Done"},
+			},
+		},
+		{
+			ID:     3,
+			Path:   "pkg/util.go",
+			Line:   4,
+			Author: "reviewer",
+			Body:   "Typo",
+		},
+	}
+
+	output, err := formatCommentsJSON(commentAPIBase("", "owner/repo"), comments)
+	if err != nil {
+		t.Fatalf("formatCommentsJSON returned error: %v", err)
+	}
+
+	var decoded []map[string]any
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("output is not a JSON array: %v\n%s", err, output)
+	}
+	if len(decoded) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(decoded))
+	}
+
+	first := decoded[0]
+	for _, key := range []string{"id", "path", "line", "author", "resolved", "url", "body", "reply_count", "html_url", "user", "created_at"} {
+		if _, ok := first[key]; !ok {
+			t.Errorf("expected key %q in JSON output", key)
+		}
+	}
+	if first["url"] != "https://api.github.com/repos/owner/repo/pulls/comments/1" {
+		t.Errorf("expected url to be the comment's API URL, got %v", first["url"])
+	}
+	if first["resolved"] != true {
+		t.Errorf("expected first comment to be resolved, got %v", first["resolved"])
+	}
+	if first["reply_count"] != float64(1) {
+		t.Errorf("expected reply_count 1, got %v", first["reply_count"])
+	}
+	if reply := decoded[1]; reply["in_reply_to_id"] != float64(1) || reply["resolved"] != true {
+		t.Errorf("expected the reply to follow its comment, got %v", reply)
+	}
+	if decoded[2]["resolved"] != false {
+		t.Errorf("expected second comment to be unresolved, got %v", decoded[2]["resolved"])
+	}
+}
+
+func TestCommentAPIBase(t *testing.T) {
+	if got := commentAPIBase("ghe.example.com", "owner/repo"); got != "https://ghe.example.com/api/v3/repos/owner/repo/pulls/comments/" {
+		t.Errorf("commentAPIBase() = %q for an enterprise host", got)
+	}
+}
+
+func TestFormatCommentsJSON_Empty(t *testing.T) {
+	output, err := formatCommentsJSON(commentAPIBase("", "owner/repo"), nil)
+	if err != nil {
+		t.Fatalf("formatCommentsJSON returned error: %v", err)
+	}
+	if output != "[]" {
+		t.Errorf("expected empty JSON array, got %q", output)
+	}
+}
+
+func TestExecuteCommentTemplate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "report.tmpl")
+	content := `{{.Path}},{{.Line}},{{.Author}},{{.Resolved}},{{len .Replies}}{{range .Replies}},{{.Author}}{{end}}` + "\n"
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := parseCommentTemplate(file)
+	if err != nil {
+		t.Fatalf("parseCommentTemplate returned error: %v", err)
+	}
+
+	comments := []*github.ReviewComment{
+		{ID: 1, Path: "main.go", Line: 10, Author: "reviewer", SubjectType: "resolved",
+			ThreadComments: []github.ThreadComment{{ID: 2, Author: "author", Body: "Done"}}},
+		{ID: 3, Path: "pkg/util.go", Line: 4, Author: "reviewer"},
+	}
+	var out bytes.Buffer
+	if err := executeCommentTemplate(&out, tmpl, comments); err != nil {
+		t.Fatalf("executeCommentTemplate returned error: %v", err)
+	}
+	if want := "main.go,10,reviewer,true,1,author\npkg/util.go,4,reviewer,false,0\n"; out.String() != want {
+		t.Errorf("executeCommentTemplate() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestParseCommentTemplateRejectsInvalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "bad.tmpl")
+	if err := os.WriteFile(file, []byte("{{.Path"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseCommentTemplate(file); err == nil || !strings.Contains(err.Error(), "invalid --template") {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+
+	tmpl, err := template.New("fields").Parse("{{.Nope}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := executeCommentTemplate(io.Discard, tmpl, []*github.ReviewComment{{ID: 9}}); err == nil || !strings.Contains(err.Error(), "comment 9") {
+		t.Errorf("expected an unknown field to fail naming the comment, got %v", err)
+	}
+}
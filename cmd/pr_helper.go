@@ -4,7 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
@@ -40,6 +44,12 @@ func getPRNumberWithSelection(args []string, client *github.Client) (int, error)
 		return 0, fmt.Errorf("no open pull requests found")
 	}
 
+	// Offer the PRs the user is working on, if there are any
+	if login, err := client.CurrentUser(); err == nil {
+		prs = prsInvolving(prs, login)
+	}
+	fmt.Fprintln(os.Stderr, "No PR found for the current branch; pick one")
+
 	selected, err := ui.SelectPR(prs)
 	if err != nil {
 		if errors.Is(err, ui.ErrNoSelection) {
@@ -51,14 +61,90 @@ func getPRNumberWithSelection(args []string, client *github.Client) (int, error)
 	return selected.Number, nil
 }
 
-// getRepoFromClient extracts the repository name from the client
-func getRepoFromClient(client *github.Client) string {
-	// Use the global repoFlag if set
-	if repoFlag != "" {
-		return repoFlag
+// prsInvolving keeps the PRs login authored or is assigned to. When there are
+// none, every PR is kept so there is still something to pick from.
+func prsInvolving(prs []*github.PullRequest, login string) []*github.PullRequest {
+	var mine []*github.PullRequest
+	for _, pr := range prs {
+		if strings.EqualFold(pr.Author, login) || slices.ContainsFunc(pr.Assignees, func(assignee string) bool {
+			return strings.EqualFold(assignee, login)
+		}) {
+			mine = append(mine, pr)
+		}
+	}
+	if len(mine) == 0 {
+		return prs
+	}
+	return mine
+}
+
+// repoRoot returns the top-level directory of the current git checkout (or
+// worktree), so repo-relative comment paths resolve from any subdirectory
+func repoRoot() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find repository root: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// resolveRepoPath joins a repo-relative path onto the repository root. The
+// path is returned unchanged if it is already absolute or no root is found.
+func resolveRepoPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	root, err := repoRoot()
+	if err != nil {
+		return path
 	}
+	return filepath.Join(root, path)
+}
 
-	// Try to get repo from the client's internal method
+// configureRepo points the client at --repo, or at GH_REPO like gh does,
+// validating its format. Both accept gh's [HOST/]OWNER/REPO. Without either
+// the repository is inferred from the checkout, so running outside a git
+// repository is reported here rather than as a failed API call later.
+func configureRepo(client *github.Client) error {
+	repo, source := repoFlag, "--repo"
+	if repo == "" {
+		repo, source = strings.TrimSpace(os.Getenv("GH_REPO")), "GH_REPO"
+	}
+	if repo != "" {
+		var host string
+		if !strings.Contains(repo, "://") && strings.Count(repo, "/") == 2 {
+			host, repo, _ = strings.Cut(repo, "/")
+		}
+		owner, name, err := github.ParseRepo(repo)
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		if host != "" {
+			client.SetHost(host)
+		}
+		client.SetRepo(owner + "/" + name)
+		return nil
+	}
+	if !insideGitRepo() {
+		return errors.New("not in a git repository; run from a checkout of the PR's repository or pass --repo OWNER/REPO")
+	}
+	return nil
+}
+
+// insideGitRepo reports whether the working directory is inside a git work tree
+func insideGitRepo() bool {
+	out, err := exec.Command("git", "rev-parse", "--is-inside-work-tree").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// discussionURL links to a review comment in the PR's conversation
+func discussionURL(repo string, prNumber int, commentID int64) string {
+	return fmt.Sprintf("https://github.com/%s/pull/%d#discussion_r%d", repo, prNumber, commentID)
+}
+
+// getRepoFromClient extracts the repository name from the client
+func getRepoFromClient(client *github.Client) string {
+	// Try to get repo from the client (set from --repo or inferred)
 	repo, err := client.GetRepo()
 	if err == nil && repo != "" {
 		return repo
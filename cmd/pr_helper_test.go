@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+)
+
+func TestResolveRepoPath_FromSubdirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "init", "-q", root).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+	subdir := filepath.Join(root, "pkg", "ui")
+	if err := os.MkdirAll(subdir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(subdir)
+
+	got := resolveRepoPath("cmd/browse.go")
+	if want := filepath.Join(root, "cmd", "browse.go"); got != want {
+		t.Errorf("resolveRepoPath() = %q, want %q", got, want)
+	}
+
+	abs := filepath.Join(root, "main.go")
+	if got := resolveRepoPath(abs); got != abs {
+		t.Errorf("expected absolute path to be unchanged, got %q", got)
+	}
+}
+
+func TestResolveRepoPath_OutsideRepository(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	if got := resolveRepoPath("main.go"); got != "main.go" {
+		t.Errorf("expected path to be unchanged outside a repository, got %q", got)
+	}
+}
+
+func TestConfigureRepo(t *testing.T) {
+	saved := repoFlag
+	t.Cleanup(func() { repoFlag = saved })
+	t.Setenv("GH_REPO", "")
+
+	repoFlag = "https://github.com/owner/repo.git"
+	client := github.NewClient()
+	if err := configureRepo(client); err != nil {
+		t.Fatalf("configureRepo() error: %v", err)
+	}
+	if got, _ := client.GetRepo(); got != "owner/repo" {
+		t.Errorf("repo = %q, want owner/repo", got)
+	}
+
+	repoFlag = "not-a-repo"
+	if err := configureRepo(github.NewClient()); err == nil || !strings.Contains(err.Error(), "OWNER/REPO") {
+		t.Errorf("expected a format error for %q, got %v", repoFlag, err)
+	}
+}
+
+func TestConfigureRepo_GHRepo(t *testing.T) {
+	saved := repoFlag
+	t.Cleanup(func() { repoFlag = saved })
+	repoFlag = ""
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	t.Setenv("GH_REPO", "ghe.example.com/owner/repo")
+	client := github.NewClient()
+	if err := configureRepo(client); err != nil {
+		t.Fatalf("expected GH_REPO to work outside a checkout, got %v", err)
+	}
+	if got, _ := client.GetRepo(); got != "owner/repo" {
+		t.Errorf("repo = %q, want owner/repo", got)
+	}
+
+	t.Setenv("GH_REPO", "not-a-repo")
+	if err := configureRepo(github.NewClient()); err == nil || !strings.Contains(err.Error(), "GH_REPO") {
+		t.Errorf("expected a GH_REPO format error, got %v", err)
+	}
+}
+
+func TestConfigureRepo_OutsideRepository(t *testing.T) {
+	saved := repoFlag
+	t.Cleanup(func() { repoFlag = saved })
+	repoFlag = ""
+	t.Setenv("GH_REPO", "")
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	err := configureRepo(github.NewClient())
+	if err == nil || !strings.Contains(err.Error(), "--repo") {
+		t.Errorf("expected an error suggesting --repo, got %v", err)
+	}
+}
+
+func TestPRsInvolving(t *testing.T) {
+	prs := []*github.PullRequest{
+		{Number: 1, Author: "Alice"},
+		{Number: 2, Author: "bob", Assignees: []string{"carol", "alice"}},
+		{Number: 3, Author: "bob"},
+	}
+
+	var numbers []int
+	for _, pr := range prsInvolving(prs, "alice") {
+		numbers = append(numbers, pr.Number)
+	}
+	if len(numbers) != 2 || numbers[0] != 1 || numbers[1] != 2 {
+		t.Errorf("prsInvolving() = %v, want [1 2]", numbers)
+	}
+
+	if got := prsInvolving(prs, "dave"); len(got) != len(prs) {
+		t.Errorf("expected every PR when none involve the user, got %d", len(got))
+	}
+}
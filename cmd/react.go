@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reactDebug bool
+	reactJSON  bool
+)
+
+var reactCmd = &cobra.Command{
+	Use:   "react [PR_NUMBER] COMMENT_ID REACTION",
+	Short: "Add a reaction to a review comment",
+	Long: `Add an emoji reaction to a pull request review comment without opening the
+browser, and print the comment's URL.
+
+REACTION is one of GitHub's reaction names: +1, -1, laugh, confused, heart,
+hooray, rocket, eyes. thumbsup and thumbsdown stand for +1 and -1; -1 itself
+reads as a flag unless it comes after --:
+
+  gh review-conductor react 345 thumbsdown
+  gh review-conductor react -- 345 -1
+
+When PR_NUMBER is omitted, the PR is inferred from the current branch.`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: runReact,
+}
+
+func init() {
+	reactCmd.Flags().BoolVar(&reactDebug, "debug", false, "Enable debug output")
+	reactCmd.Flags().BoolVar(&reactJSON, "json", false, "Print the result as JSON for scripting")
+}
+
+// reactionAliases are REACTION spellings that don't start with a dash
+var reactionAliases = map[string]string{
+	"thumbsup":   "+1",
+	"thumbsdown": "-1",
+}
+
+// reactResult is the --json output of react
+type reactResult struct {
+	CommentID int64  `json:"comment_id"`
+	Reaction  string `json:"reaction"`
+	URL       string `json:"url"`
+}
+
+// parseReactArgs splits [PR_NUMBER] COMMENT_ID REACTION; prNumber is 0 when
+// it should be inferred from the branch
+func parseReactArgs(args []string) (prNumber int, commentID int64, reaction string, err error) {
+	if len(args) == 3 {
+		prNumber, err = strconv.Atoi(args[0])
+		if err != nil || prNumber <= 0 {
+			return 0, 0, "", fmt.Errorf("invalid PR number: %s", args[0])
+		}
+		args = args[1:]
+	}
+	if len(args) != 2 {
+		return 0, 0, "", fmt.Errorf("expected [PR_NUMBER] COMMENT_ID REACTION")
+	}
+
+	commentID, err = strconv.ParseInt(args[0], 10, 64)
+	if err != nil || commentID <= 0 {
+		return 0, 0, "", fmt.Errorf("invalid comment ID: %s", args[0])
+	}
+	reaction = args[1]
+	if name, ok := reactionAliases[reaction]; ok {
+		reaction = name
+	}
+	if err := ui.ValidateReaction(reaction); err != nil {
+		return 0, 0, "", err
+	}
+	return prNumber, commentID, reaction, nil
+}
+
+func runReact(cmd *cobra.Command, args []string) error {
+	prNumber, commentID, reaction, err := parseReactArgs(args)
+	if err != nil {
+		return err
+	}
+
+	client := github.NewClient()
+	client.SetDebug(reactDebug)
+	if err := configureRepo(client); err != nil {
+		return err
+	}
+	if prNumber == 0 {
+		prNumber, err = getPRNumberWithSelection([]string{}, client)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := client.AddReactionToComment(prNumber, commentID, reaction); err != nil {
+		return explainAPIError(err)
+	}
+
+	result := reactResult{
+		CommentID: commentID,
+		Reaction:  reaction,
+		URL:       discussionURL(getRepoFromClient(client), prNumber, commentID),
+	}
+	if reactJSON {
+		content, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode result as JSON: %w", err)
+		}
+		fmt.Println(string(content))
+		return nil
+	}
+	fmt.Println(result.URL)
+	return nil
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import "testing"
+
+func TestParseReactArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantPR        int
+		wantCommentID int64
+		wantReaction  string
+		wantErr       bool
+	}{
+		{name: "with PR", args: []string{"12", "345", "+1"}, wantPR: 12, wantCommentID: 345, wantReaction: "+1"},
+		{name: "PR inferred", args: []string{"345", "rocket"}, wantCommentID: 345, wantReaction: "rocket"},
+		{name: "thumbsup alias", args: []string{"345", "thumbsup"}, wantCommentID: 345, wantReaction: "+1"},
+		{name: "thumbsdown alias", args: []string{"12", "345", "thumbsdown"}, wantPR: 12, wantCommentID: 345, wantReaction: "-1"},
+		{name: "unknown reaction", args: []string{"345", "smile"}, wantErr: true},
+		{name: "bad comment ID", args: []string{"abc", "eyes"}, wantErr: true},
+		{name: "bad PR number", args: []string{"x", "345", "eyes"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr, id, reaction, err := parseReactArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReactArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if pr != tt.wantPR || id != tt.wantCommentID || reaction != tt.wantReaction {
+				t.Errorf("parseReactArgs() = %d, %d, %q; want %d, %d, %q", pr, id, reaction, tt.wantPR, tt.wantCommentID, tt.wantReaction)
+			}
+		})
+	}
+}
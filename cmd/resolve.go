@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"os/exec"
 	"strconv"
 	"strings"
 
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/diffhunk"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/diffposition"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
 	"github.com/spf13/cobra"
@@ -17,6 +20,8 @@ var (
 	resolveDebug     bool
 	resolveAll       bool
 	resolveComment   string
+	resolveAddressed bool
+	resolveSince     string
 )
 
 var resolveCmd = &cobra.Command{
@@ -25,7 +30,11 @@ var resolveCmd = &cobra.Command{
 	Long: `Mark review comment threads as resolved or unresolved. Use --all to apply the action to all unresolved comments on a PR.
 When no arguments are provided, PR is inferred from the current branch and you will be prompted for a comment ID.
 When one argument is provided, it's treated as COMMENT_ID and PR is inferred from the current branch.
-When two arguments are provided, the first is PR_NUMBER and the second is COMMENT_ID.`,
+When two arguments are provided, the first is PR_NUMBER and the second is COMMENT_ID.
+
+With --addressed [PR_NUMBER], unresolved threads on lines your local commits
+changed (git diff --since..HEAD, by default the last commit) are listed and
+resolved in bulk after you confirm.`,
 	Args: cobra.MinimumNArgs(0),
 	RunE: runResolve,
 }
@@ -35,19 +44,35 @@ func init() {
 	resolveCmd.Flags().BoolVar(&resolveDebug, "debug", false, "Enable debug output")
 	resolveCmd.Flags().BoolVar(&resolveAll, "all", false, "Apply action to all unresolved comments on the PR")
 	resolveCmd.Flags().StringVarP(&resolveComment, "comment", "c", "", "Add a comment when resolving")
+	resolveCmd.Flags().BoolVar(&resolveAddressed, "addressed", false, "Resolve unresolved threads on lines changed by local commits")
+	resolveCmd.Flags().StringVar(&resolveSince, "since", "HEAD~1", "With --addressed, the commit the changes are counted from")
 }
 
 func runResolve(cmd *cobra.Command, args []string) error {
 	client := github.NewClient()
 	client.SetDebug(resolveDebug)
-	if repoFlag != "" {
-		client.SetRepo(repoFlag)
+	if err := configureRepo(client); err != nil {
+		return err
 	}
 
 	var prNumber int
 	var commentID int64
 	var err error
 
+	if resolveAddressed {
+		if resolveAll || resolveUnresolve {
+			return fmt.Errorf("--addressed cannot be combined with --all or --unresolve")
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("--addressed takes at most a PR_NUMBER")
+		}
+		prNumber, err = getPRNumberWithSelection(args, client)
+		if err != nil {
+			return err
+		}
+		return resolveAddressedComments(client, prNumber, resolveSince)
+	}
+
 	// Parse arguments based on count
 	if len(args) == 0 {
 		// No args: infer PR and prompt for comment ID
@@ -127,6 +152,7 @@ func resolveCommentText(text string) (string, error) {
 
 func addCommentToReview(client *github.Client, prNumber int, commentID int64, commentBody string, commentLink string) error {
 	if _, err := client.ReplyToReviewComment(prNumber, commentID, commentBody); err != nil {
+		err = explainAPIError(err)
 		fmt.Printf("%sFailed to add comment to %s: %v\\n",
 			ui.Colorize(ui.ColorRed, ui.EmojiText("❌ ", "")),
 			ui.Colorize(ui.ColorCyan, commentLink),
@@ -161,11 +187,19 @@ func resolveAllComments(client *github.Client, prNumber int) error {
 		return nil
 	}
 
-	// Show summary and ask for confirmation
 	prLink := ui.CreateHyperlink(fmt.Sprintf("https://github.com/%s/pull/%d", getRepoFromClient(client), prNumber),
 		ui.Colorize(ui.ColorCyan, fmt.Sprintf("PR #%d", prNumber)))
-	fmt.Printf("Found %s unresolved comment(s) in %s:\n",
+	heading := fmt.Sprintf("Found %s unresolved comment(s) in %s:",
 		ui.Colorize(ui.ColorYellow, fmt.Sprintf("%d", len(unresolvedComments))), prLink)
+	return confirmAndResolveComments(client, prNumber, unresolvedComments, heading)
+}
+
+// confirmAndResolveComments lists comments under heading, asks for
+// confirmation, and then resolves (or with --unresolve, unresolves) each
+// thread, adding the --comment reply first when given
+func confirmAndResolveComments(client *github.Client, prNumber int, unresolvedComments []*github.ReviewComment, heading string) error {
+	// Show summary and ask for confirmation
+	fmt.Println(heading)
 
 	for _, comment := range unresolvedComments {
 		// Create clickable link to the review comment
@@ -234,7 +268,7 @@ func resolveAllComments(client *github.Client, prNumber int) error {
 				fmt.Printf("%sFailed to unresolve %s: %v\n",
 					ui.Colorize(ui.ColorRed, ui.EmojiText("❌ ", "")),
 					ui.Colorize(ui.ColorCyan, commentLink),
-					ui.Colorize(ui.ColorRed, err.Error()))
+					ui.Colorize(ui.ColorRed, explainAPIError(err).Error()))
 				errorCount++
 			} else {
 				fmt.Printf("%s%s marked as unresolved\n",
@@ -247,7 +281,7 @@ func resolveAllComments(client *github.Client, prNumber int) error {
 				fmt.Printf("%sFailed to resolve %s: %v\n",
 					ui.Colorize(ui.ColorRed, ui.EmojiText("❌ ", "")),
 					ui.Colorize(ui.ColorCyan, commentLink),
-					ui.Colorize(ui.ColorRed, err.Error()))
+					ui.Colorize(ui.ColorRed, explainAPIError(err).Error()))
 				errorCount++
 			} else {
 				fmt.Printf("%s%s marked as resolved\n",
@@ -265,6 +299,172 @@ func resolveAllComments(client *github.Client, prNumber int) error {
 	return nil
 }
 
+// resolveAddressedComments offers to resolve the unresolved threads on lines
+// the local commits since..HEAD changed
+func resolveAddressedComments(client *github.Client, prNumber int, since string) error {
+	diff, err := exec.Command("git", "diff", "--unified=0", "--no-color", "--no-ext-diff", since, "HEAD").Output()
+	if err != nil {
+		return fmt.Errorf("failed to diff %s..HEAD: %w", since, err)
+	}
+
+	comments, err := client.FetchReviewComments(prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to fetch review comments: %w", err)
+	}
+	var unresolvedComments []*github.ReviewComment
+	for _, comment := range comments {
+		if !comment.IsResolved() {
+			unresolvedComments = append(unresolvedComments, comment)
+		}
+	}
+
+	diffToSince := func(commit string) (string, error) {
+		out, err := exec.Command("git", "diff", "--unified=0", "--no-color", "--no-ext-diff", commit, since).Output()
+		return string(out), err
+	}
+	addressed, unmapped := commentsAddressedByDiff(unresolvedComments, string(diff), diffToSince)
+	if unmapped > 0 {
+		fmt.Printf("%s%d comment(s) skipped: their commits aren't in the local repository (try git fetch)\n",
+			ui.Colorize(ui.ColorYellow, ui.EmojiText("⚠️  ", "")), unmapped)
+	}
+	if len(addressed) == 0 {
+		fmt.Printf("No unresolved comments on lines changed since %s\n", since)
+		return nil
+	}
+
+	heading := fmt.Sprintf("Found %s unresolved comment(s) on lines changed since %s:",
+		ui.Colorize(ui.ColorYellow, fmt.Sprintf("%d", len(addressed))), since)
+	return confirmAndResolveComments(client, prNumber, addressed, heading)
+}
+
+// commentsAddressedByDiff returns the comments on lines a unified diff
+// changes. Lines are compared on the diff's old side: each comment's lines
+// are followed from the commit they refer to (the PR head it was made on,
+// or its original commit when outdated) through diffToSince, the diff from
+// that commit to the old side. Comments whose commit can't be diffed are
+// counted in unmapped. Comments on removed lines (the LEFT side) and on
+// whole files are left out.
+func commentsAddressedByDiff(comments []*github.ReviewComment, diff string, diffToSince func(commit string) (string, error)) (addressed []*github.ReviewComment, unmapped int) {
+	changed := changedOldLines(diff)
+	sinceHunks := make(map[string]map[string][]*diffhunk.DiffHunk)
+	for _, comment := range comments {
+		if comment.DiffSide == diffposition.DiffSideLeft {
+			continue
+		}
+		start, end, commit := comment.StartLine, comment.Line, comment.HeadSHA
+		if end == 0 {
+			start, end, commit = comment.OriginalStartLine, comment.OriginalLine, comment.OriginalCommitID
+		}
+		if end == 0 {
+			continue
+		}
+		if start == 0 || start > end {
+			start = end
+		}
+		if commit != "" {
+			hunks, ok := sinceHunks[commit]
+			if !ok {
+				d, err := diffToSince(commit)
+				if err == nil {
+					hunks = fileHunks(d)
+				}
+				sinceHunks[commit] = hunks
+			}
+			if hunks == nil {
+				unmapped++
+				continue
+			}
+			start, end = mapOldLine(hunks[comment.Path], start), mapOldLine(hunks[comment.Path], end)
+		}
+		for _, lines := range changed[comment.Path] {
+			if lines[0] <= end && start <= lines[1] {
+				addressed = append(addressed, comment)
+				break
+			}
+		}
+	}
+	return addressed, unmapped
+}
+
+// mapOldLine follows an old-side line through a file's hunks to its line on
+// the new side. A line the hunks changed maps into their replacement, or to
+// the line before it when the hunk only removes lines.
+func mapOldLine(hunks []*diffhunk.DiffHunk, line int) int {
+	delta := 0
+	for _, hunk := range hunks {
+		if hunk.OldLines == 0 {
+			// Lines inserted after OldStart
+			if line <= hunk.OldStart {
+				break
+			}
+			delta += hunk.NewLines
+			continue
+		}
+		if line < hunk.OldStart {
+			break
+		}
+		if line < hunk.OldStart+hunk.OldLines {
+			if hunk.NewLines == 0 {
+				return max(hunk.NewStart, 1)
+			}
+			return hunk.NewStart + min(line-hunk.OldStart, hunk.NewLines-1)
+		}
+		delta += hunk.NewLines - hunk.OldLines
+	}
+	return line + delta
+}
+
+// changedOldLines maps each file a unified diff modifies to the old-side
+// line ranges of its hunks. A pure insertion counts as touching the lines
+// on either side of it; new files have no old lines and are skipped.
+func changedOldLines(diff string) map[string][][2]int {
+	changed := make(map[string][][2]int)
+	for path, hunks := range fileHunks(diff) {
+		for _, hunk := range hunks {
+			start, end := hunk.OldStart, hunk.OldStart+hunk.OldLines-1
+			if hunk.OldLines == 0 {
+				// Lines inserted after OldStart
+				end = hunk.OldStart + 1
+			}
+			changed[path] = append(changed[path], [2]int{start, end})
+		}
+	}
+	return changed
+}
+
+// fileHunks maps each file a unified diff modifies, by its old path, to the
+// headers of its hunks. New files have no old path and are skipped.
+func fileHunks(diff string) map[string][]*diffhunk.DiffHunk {
+	files := make(map[string][]*diffhunk.DiffHunk)
+	path := ""
+	inHeader := false
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inHeader = true
+			path = ""
+		case inHeader && strings.HasPrefix(line, "--- "):
+			// A removed line starting with "-- " looks the same, so only
+			// file headers (before the first hunk) are read as paths
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+			if path == "/dev/null" {
+				path = ""
+			}
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+			if path == "" {
+				continue
+			}
+			hunk, err := diffhunk.ParseDiffHunk(line)
+			if err != nil {
+				continue
+			}
+			files[path] = append(files[path], hunk)
+		}
+	}
+	return files
+}
+
 func resolveIndividualComment(client *github.Client, prNumber int, commentID int64) error {
 	// Fetch review comments to find the thread ID
 	comments, err := client.FetchReviewComments(prNumber)
@@ -286,7 +486,7 @@ func resolveIndividualComment(client *github.Client, prNumber int, commentID int
 	}
 
 	// Resolve or unresolve the thread
-	commentLink := ui.CreateHyperlink(fmt.Sprintf("https://github.com/%s/pull/%d#discussion_r%d", getRepoFromClient(client), prNumber, commentID),
+	commentLink := ui.CreateHyperlink(discussionURL(getRepoFromClient(client), prNumber, commentID),
 		fmt.Sprintf("Comment %d", commentID))
 
 	if resolveComment != "" {
@@ -305,14 +505,14 @@ func resolveIndividualComment(client *github.Client, prNumber int, commentID int
 
 	if resolveUnresolve {
 		if err := client.UnresolveThread(threadID); err != nil {
-			return fmt.Errorf("failed to unresolve thread: %w", err)
+			return explainAPIError(fmt.Errorf("failed to unresolve thread: %w", err))
 		}
 		fmt.Printf("%sThread for %s marked as unresolved\n",
 			ui.Colorize(ui.ColorYellow, ui.EmojiText("✓ ", "")),
 			ui.Colorize(ui.ColorCyan, commentLink))
 	} else {
 		if err := client.ResolveThread(threadID); err != nil {
-			return fmt.Errorf("failed to resolve thread: %w", err)
+			return explainAPIError(fmt.Errorf("failed to resolve thread: %w", err))
 		}
 		fmt.Printf("%sThread for %s marked as resolved\n",
 			ui.Colorize(ui.ColorGreen, ui.EmojiText("✓ ", "")),
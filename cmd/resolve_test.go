@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/diffposition"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+)
+
+func TestCommentsAddressedByDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -10,2 +10,3 @@ func main() {
+-	old()
+--- not a header
++	new()
++	more()
++	again()
+@@ -40,0 +42 @@ func helper() {
++	inserted()
+diff --git a/added.go b/added.go
+new file mode 100644
+--- /dev/null
++++ b/added.go
+@@ -0,0 +1 @@
++package main
+`
+	comments := []*github.ReviewComment{
+		{ID: 1, Path: "main.go", StartLine: 11, Line: 11},                                      // inside the first hunk
+		{ID: 2, Path: "main.go", StartLine: 5, Line: 12},                                       // range overlapping it
+		{ID: 3, Path: "main.go", StartLine: 20, Line: 20},                                      // untouched
+		{ID: 4, Path: "main.go", StartLine: 41, Line: 41},                                      // just after the insertion
+		{ID: 5, Path: "main.go", OriginalStartLine: 10, OriginalLine: 10},                      // outdated, original line changed
+		{ID: 6, Path: "main.go", StartLine: 10, Line: 10, DiffSide: diffposition.DiffSideLeft}, // on a removed line
+		{ID: 7, Path: "other.go", StartLine: 10, Line: 10},                                     // file not in the diff
+		{ID: 8, Path: "added.go", StartLine: 1, Line: 1},                                       // new file
+		{ID: 9, Path: "main.go"},                                                               // file comment
+		{ID: 10, Path: "main.go", Line: 39, HeadSHA: "older"},                                  // moved down onto the insertion
+		{ID: 11, Path: "main.go", Line: 11, HeadSHA: "older"},                                  // moved down past the first hunk
+		{ID: 12, Path: "main.go", Line: 11, HeadSHA: "missing"},                                // commit not available
+	}
+
+	// Two lines were added near the top of main.go between "older" and the
+	// old side of diff
+	diffToSince := func(commit string) (string, error) {
+		switch commit {
+		case "older":
+			return "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -5,0 +6,2 @@\n+a\n+b\n", nil
+		case "missing":
+			return "", errors.New("bad object")
+		}
+		return "", nil
+	}
+
+	var ids []int64
+	addressed, unmapped := commentsAddressedByDiff(comments, diff, diffToSince)
+	for _, c := range addressed {
+		ids = append(ids, c.ID)
+	}
+	if want := []int64{1, 2, 4, 5, 10}; !slices.Equal(ids, want) {
+		t.Errorf("commentsAddressedByDiff() = %v, want %v", ids, want)
+	}
+	if unmapped != 1 {
+		t.Errorf("expected 1 unmapped comment, got %d", unmapped)
+	}
+}
+
+func TestMapOldLine(t *testing.T) {
+	hunks := fileHunks("diff --git a/f.go b/f.go\n--- a/f.go\n+++ b/f.go\n@@ -3,0 +4,2 @@\n@@ -10,3 +12 @@\n@@ -20,2 +19,0 @@\n")["f.go"]
+	for _, tc := range []struct{ line, want int }{
+		{2, 2},   // before everything
+		{5, 7},   // after the insertion
+		{11, 12}, // inside the replaced lines
+		{15, 15}, // +2 -2
+		{21, 19}, // removed, maps to the line before
+		{30, 28},
+	} {
+		if got := mapOldLine(hunks, tc.line); got != tc.want {
+			t.Errorf("mapOldLine(%d) = %d, want %d", tc.line, got, tc.want)
+		}
+	}
+}
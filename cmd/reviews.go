@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var reviewsDebug bool
+
+var reviewsCmd = &cobra.Command{
+	Use:   "reviews [PR_NUMBER]",
+	Short: "List the pull request's reviews and browse each one's comments",
+	Long: `List the reviews submitted on a pull request with their state (approved,
+changes requested, commented, dismissed), author and summary.
+
+Press Enter on a review to browse just the threads it started or replied to,
+as browse --review REVIEW_ID does. When PR_NUMBER is omitted, the PR is
+inferred from the current branch.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReviews,
+}
+
+func init() {
+	reviewsCmd.Flags().BoolVar(&reviewsDebug, "debug", false, "Enable debug output")
+}
+
+func runReviews(cmd *cobra.Command, args []string) error {
+	ui.SetUIDebug(reviewsDebug)
+	ui.WarmupMarkdownRenderer()
+
+	client := github.NewClient()
+	client.SetDebug(reviewsDebug)
+	if err := configureRepo(client); err != nil {
+		return err
+	}
+	prNumber, err := getPRNumberWithSelection(args, client)
+	if err != nil {
+		return err
+	}
+
+	reviews, err := client.FetchReviews(prNumber)
+	if err != nil {
+		return explainAPIError(err)
+	}
+	if len(reviews) == 0 {
+		fmt.Printf("No reviews found in PR #%d\n", prNumber)
+		return nil
+	}
+
+	selected, err := ui.SelectReview(reviews)
+	if err != nil {
+		if errors.Is(err, ui.ErrNoSelection) {
+			return nil
+		}
+		return err
+	}
+
+	prepareReviewBrowse(prNumber, selected.ID)
+	return runBrowse(browseCmd, nil)
+}
+
+// prepareReviewBrowse points browse at one review's threads on prNumber,
+// with the config file's defaults applied as for a plain browse
+func prepareReviewBrowse(prNumber int, reviewID int64) {
+	applyConfigDefaults(browseCmd.Flags(), cfg)
+	browseDebug = browseDebug || reviewsDebug
+	browseReview = reviewID
+	browsePRNumber = prNumber
+}
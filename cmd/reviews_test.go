@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/config"
+)
+
+func TestPrepareReviewBrowse(t *testing.T) {
+	originalCfg := cfg
+	t.Cleanup(func() {
+		cfg = originalCfg
+		browseCompact, browseReview, browsePRNumber = false, 0, 0
+	})
+	cfg = config.Default()
+	cfg.Browse.Compact = true
+
+	prepareReviewBrowse(12, 345)
+
+	if !browseCompact {
+		t.Error("expected browse.compact from the config file")
+	}
+	if browseReview != 345 || browsePRNumber != 12 {
+		t.Errorf("expected review 345 on PR #12, got review %d on PR #%d", browseReview, browsePRNumber)
+	}
+}
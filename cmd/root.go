@@ -1,47 +1,126 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/config"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/ui"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	repoFlag string
-	noColor  bool
+	repoFlag     string
+	noColor      bool
+	noHyperlinks bool
 )
 
+// cfg holds the config file defaults, loaded before any command runs
+var cfg = config.Default()
+
 var rootCmd = &cobra.Command{
 	Use:   "gh-review-conductor",
 	Short: "Apply GitHub review comments directly to your code",
 	Long: `gh-review-conductor is a GitHub CLI extension that allows you to fetch and apply
 review comments and suggestions from pull requests directly to your local code.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		loaded, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+		cfg = loaded
+		applyConfigDefaults(cmd.Flags(), cfg)
+
 		ui.SetColorEnabled(!noColor)
+		if noHyperlinks {
+			ui.SetHyperlinksEnabled(false)
+		}
+		ui.SetConfiguredEditor(cfg.Editor)
+		ui.SetAgentCommand(cfg.Agent)
+		if cfg.Ellipsis != "" {
+			ui.SetTruncationIndicator(cfg.Ellipsis)
+		}
+		if cfg.Theme != "" {
+			if err := ui.SetMarkdownTheme(cfg.Theme); err != nil {
+				return fmt.Errorf("invalid theme in config: %w", err)
+			}
+		}
+		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 0 {
 			return cmd.Help()
 		}
+		applyConfigDefaults(browseCmd.Flags(), cfg)
 		return browseCmd.RunE(browseCmd, []string{})
 	},
 }
 
+// configFlagValues maps config settings to the flags they provide defaults for
+func configFlagValues(c *config.Config) map[string]string {
+	values := map[string]string{
+		"watch-interval": c.Browse.WatchInterval.String(),
+		"quote-context":  strconv.Itoa(c.Browse.QuoteContext),
+	}
+	bools := map[string]bool{
+		"debug":         c.Debug,
+		"hide-bots":     c.Browse.HideBots,
+		"collapse-bots": c.Browse.CollapseBots,
+		"compact":       c.Browse.Compact,
+		"aligned":       c.Browse.Aligned,
+		"mention":       c.Browse.Mention,
+		"wrap-quotes":   c.Browse.WrapQuotes,
+		"notify":        c.Browse.Notify,
+	}
+	for name, enabled := range bools {
+		values[name] = strconv.FormatBool(enabled)
+	}
+	// The environment outranks the config file
+	if _, ok := os.LookupEnv("NO_COLOR"); !ok {
+		values["no-color"] = strconv.FormatBool(c.NoColor)
+	}
+	if os.Getenv("GH_RC_HYPERLINKS") == "" {
+		values["no-hyperlinks"] = strconv.FormatBool(c.NoHyperlinks)
+	}
+	if len(c.Browse.Include) > 0 {
+		values["include"] = strings.Join(c.Browse.Include, ",")
+	}
+	return values
+}
+
+// applyConfigDefaults sets flags not given on the command line from the
+// config file. Flags the command doesn't have are skipped.
+func applyConfigDefaults(flags *pflag.FlagSet, c *config.Config) {
+	for name, value := range configFlagValues(c) {
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		_ = flag.Value.Set(value)
+	}
+}
+
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
-	if _, ok := os.LookupEnv("NO_COLOR"); ok {
-		noColor = true
-	}
+	// NO_COLOR turns color off unless --no-color=false
+	_, noColorEnv := os.LookupEnv("NO_COLOR")
 
-	rootCmd.PersistentFlags().StringVarP(&repoFlag, "repo", "R", "", "Select a repository using the OWNER/REPO format")
-	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().StringVarP(&repoFlag, "repo", "R", "", "Select a repository using the OWNER/REPO format (or its GitHub URL)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", noColorEnv, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&noHyperlinks, "no-hyperlinks", false, "Print URLs instead of terminal hyperlinks")
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(applyCmd)
 	rootCmd.AddCommand(resolveCmd)
 	rootCmd.AddCommand(commentCmd)
 	rootCmd.AddCommand(browseCmd)
+	rootCmd.AddCommand(reactCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(reviewsCmd)
+	rootCmd.AddCommand(initCmd)
 }
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/config"
+	"github.com/spf13/pflag"
+)
+
+func TestApplyConfigDefaults(t *testing.T) {
+	flags := pflag.NewFlagSet("browse", pflag.ContinueOnError)
+	compact := flags.Bool("compact", false, "")
+	hideBots := flags.Bool("hide-bots", false, "")
+	interval := flags.Duration("watch-interval", 30*time.Second, "")
+	include := flags.StringSlice("include", nil, "")
+	if err := flags.Parse([]string{"--watch-interval=10s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := config.Default()
+	c.Browse.Compact = true
+	c.Browse.WatchInterval = time.Minute
+	c.Browse.Include = []string{"summary", "issue"}
+	applyConfigDefaults(flags, c)
+
+	if !*compact {
+		t.Error("expected compact from the config file")
+	}
+	if *hideBots {
+		t.Error("expected hide-bots to keep its default")
+	}
+	if *interval != 10*time.Second {
+		t.Errorf("expected the command-line watch interval to win, got %s", *interval)
+	}
+	if len(*include) != 2 || (*include)[1] != "issue" {
+		t.Errorf("expected include from the config file, got %v", *include)
+	}
+}
+
+func TestApplyConfigDefaultsFalseOverridesDefault(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	flags := pflag.NewFlagSet("browse", pflag.ContinueOnError)
+	wrapQuotes := flags.Bool("wrap-quotes", true, "")
+	noColor := flags.Bool("no-color", true, "")
+
+	c := config.Default()
+	c.Browse.WrapQuotes = false
+	c.NoColor = false
+	applyConfigDefaults(flags, c)
+
+	if *wrapQuotes {
+		t.Error("expected wrap_quotes = false in the config file to turn the flag off")
+	}
+	if !*noColor {
+		t.Error("expected NO_COLOR to outrank no_color = false in the config file")
+	}
+}
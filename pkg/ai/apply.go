@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// checkPatch validates a patch against the working tree. It is a variable so
+// tests can substitute a fake for git.
+var checkPatch = checkPatchApplies
+
+// ApplyWithRetry asks the provider for a patch and verifies it with
+// `git apply --check`. When the patch does not apply, the provider is asked
+// again with MismatchDetails describing the failure, up to maxRetries times.
+// If every attempt fails, the last response is returned along with the error.
+func ApplyWithRetry(ctx context.Context, provider AIProvider, req *SuggestionRequest, maxRetries int) (*SuggestionResponse, error) {
+	attemptReq := *req
+	var resp *SuggestionResponse
+	var lastErr error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		var err error
+		resp, err = provider.ApplySuggestion(ctx, &attemptReq)
+		if err != nil {
+			return nil, err
+		}
+
+		lastErr = checkPatch(ctx, resp.Patch)
+		if lastErr == nil {
+			return resp, nil
+		}
+
+		attemptReq.MismatchDetails = retryMismatchDetails(req.MismatchDetails, resp.Patch, lastErr)
+	}
+
+	return resp, fmt.Errorf("AI patch does not apply after %d attempt(s): %w", maxRetries+1, lastErr)
+}
+
+// retryMismatchDetails describes a rejected patch so the model can correct it
+func retryMismatchDetails(original, patch string, applyErr error) string {
+	var b strings.Builder
+	if original != "" {
+		b.WriteString(original)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Your previous patch was rejected by `git apply --check`:\n")
+	b.WriteString(applyErr.Error())
+	b.WriteString("\n\nRejected patch:\n")
+	b.WriteString(patch)
+	return b.String()
+}
+
+// checkPatchApplies runs a dry `git apply --check` against the working tree
+func checkPatchApplies(ctx context.Context, patch string) error {
+	cmd := exec.CommandContext(ctx, "git", "apply", "--check", "--unidiff-zero", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeProvider returns queued patches and records the requests it received
+type fakeProvider struct {
+	patches  []string
+	requests []SuggestionRequest
+}
+
+func (f *fakeProvider) ApplySuggestion(ctx context.Context, req *SuggestionRequest) (*SuggestionResponse, error) {
+	f.requests = append(f.requests, *req)
+	patch := f.patches[len(f.requests)-1]
+	return &SuggestionResponse{Patch: patch}, nil
+}
+
+func (f *fakeProvider) Name() string  { return "fake" }
+func (f *fakeProvider) Model() string { return "fake-model" }
+
+// stubCheckPatch rejects any patch containing "bad" for the duration of the test
+func stubCheckPatch(t *testing.T) {
+	t.Helper()
+	original := checkPatch
+	checkPatch = func(ctx context.Context, patch string) error {
+		if strings.Contains(patch, "bad") {
+			return errors.New("error: patch failed: main.go:3")
+		}
+		return nil
+	}
+	t.Cleanup(func() { checkPatch = original })
+}
+
+func TestApplyWithRetry_FailsThenSucceeds(t *testing.T) {
+	stubCheckPatch(t)
+	provider := &fakeProvider{patches: []string{"bad patch", "good patch"}}
+
+	resp, err := ApplyWithRetry(context.Background(), provider, &SuggestionRequest{FilePath: "main.go"}, 2)
+	if err != nil {
+		t.Fatalf("expected success after retry, got error: %v", err)
+	}
+	if resp.Patch != "good patch" {
+		t.Errorf("expected the corrected patch, got %q", resp.Patch)
+	}
+	if len(provider.requests) != 2 {
+		t.Fatalf("expected 2 provider calls, got %d", len(provider.requests))
+	}
+	if provider.requests[0].MismatchDetails != "" {
+		t.Errorf("first attempt should not carry mismatch details, got %q", provider.requests[0].MismatchDetails)
+	}
+	retryDetails := provider.requests[1].MismatchDetails
+	if !strings.Contains(retryDetails, "patch failed: main.go:3") || !strings.Contains(retryDetails, "bad patch") {
+		t.Errorf("retry should describe the git error and rejected patch, got %q", retryDetails)
+	}
+}
+
+func TestApplyWithRetry_ReturnsLastFailure(t *testing.T) {
+	stubCheckPatch(t)
+	provider := &fakeProvider{patches: []string{"bad 1", "bad 2", "bad 3"}}
+
+	resp, err := ApplyWithRetry(context.Background(), provider, &SuggestionRequest{}, 2)
+	if err == nil {
+		t.Fatal("expected an error when every patch fails")
+	}
+	if len(provider.requests) != 3 {
+		t.Errorf("expected 3 provider calls (1 + 2 retries), got %d", len(provider.requests))
+	}
+	if resp == nil || resp.Patch != "bad 3" {
+		t.Errorf("expected the last response to be returned, got %+v", resp)
+	}
+}
+
+func TestApplyWithRetry_KeepsOriginalMismatchDetails(t *testing.T) {
+	stubCheckPatch(t)
+	provider := &fakeProvider{patches: []string{"bad", "good"}}
+	req := &SuggestionRequest{MismatchDetails: "expected line not found"}
+
+	if _, err := ApplyWithRetry(context.Background(), provider, req, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(provider.requests[1].MismatchDetails, "expected line not found") {
+		t.Errorf("retry should keep original mismatch details, got %q", provider.requests[1].MismatchDetails)
+	}
+	if req.MismatchDetails != "expected line not found" {
+		t.Errorf("caller's request should not be modified, got %q", req.MismatchDetails)
+	}
+}
+
+func TestApplyWithRetry_ProviderError(t *testing.T) {
+	stubCheckPatch(t)
+	provider := &erroringProvider{}
+
+	if _, err := ApplyWithRetry(context.Background(), provider, &SuggestionRequest{}, 3); err == nil {
+		t.Fatal("expected provider error to be returned")
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider errors should not be retried, got %d calls", provider.calls)
+	}
+}
+
+type erroringProvider struct {
+	calls int
+}
+
+func (e *erroringProvider) ApplySuggestion(ctx context.Context, req *SuggestionRequest) (*SuggestionResponse, error) {
+	e.calls++
+	return nil, errors.New("quota exceeded")
+}
+
+func (e *erroringProvider) Name() string  { return "erroring" }
+func (e *erroringProvider) Model() string { return "erroring-model" }
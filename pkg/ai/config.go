@@ -1,9 +1,6 @@
 package ai
 
-import (
-	"fmt"
-	"os"
-)
+import "os"
 
 // ProviderMetadata holds information about an AI provider.
 type ProviderMetadata struct {
@@ -14,7 +11,7 @@ type ProviderMetadata struct {
 // providerInfo maps provider names to their metadata.
 var providerInfo = map[string]ProviderMetadata{
 	"gemini": {"Gemini", []string{"GEMINI_API_KEY", "GOOGLE_API_KEY"}},
-	"openai": {"OpenAI", []string{"OPENAI_API_KEY"}},    // Planned for future support
+	"openai": {"OpenAI", []string{"OPENAI_API_KEY"}},
 	"claude": {"Claude", []string{"ANTHROPIC_API_KEY"}}, // Planned for future support
 }
 
@@ -24,7 +21,7 @@ func GetProviderMetadata(provider string) (ProviderMetadata, bool) {
 	return info, ok
 }
 
-// Config holds AI provider configuration
+// Config holds AI provider configuration; see NewProviderWithConfig
 type Config struct {
 	Provider           string
 	Model              string
@@ -33,44 +30,18 @@ type Config struct {
 	CustomVariables    map[string]interface{}
 }
 
-// NewProviderFromConfig creates an AI provider based on configuration
-func NewProviderFromConfig(config *Config) (AIProvider, error) {
-	if config == nil {
-		return nil, fmt.Errorf("config is required")
-	}
-
-	templateConfig := &TemplateConfig{
-		CustomTemplatePath: config.CustomTemplatePath,
-		CustomVariables:    config.CustomVariables,
-	}
-
-	switch config.Provider {
-	case "gemini":
-		return NewGeminiProvider(config.APIKey, config.Model, templateConfig)
-	default:
-		return nil, fmt.Errorf("unsupported AI provider: %s (supported: gemini)", config.Provider)
-	}
-}
-
-// LoadConfigFromEnv loads AI configuration from environment variables
+// LoadConfigFromEnv loads AI configuration from environment variables.
+// GH_RC_AI_PROVIDER selects the provider; the older GH_PRREVIEW_AI_PROVIDER is
+// still honored when it is unset. The API key is left for NewProviderWithConfig to find
+// once the provider is settled.
 func LoadConfigFromEnv() *Config {
-	provider := getEnvWithDefault("GH_PRREVIEW_AI_PROVIDER", "")
+	provider := getEnvWithDefault("GH_RC_AI_PROVIDER", os.Getenv("GH_PRREVIEW_AI_PROVIDER"))
 
 	config := &Config{
 		Provider: provider,
 		Model:    os.Getenv("GH_PRREVIEW_AI_MODEL"),
 	}
 
-	// Load API key based on provider
-	if meta, ok := GetProviderMetadata(config.Provider); ok {
-		for _, envVar := range meta.EnvVars {
-			if key := os.Getenv(envVar); key != "" {
-				config.APIKey = key
-				break
-			}
-		}
-	}
-
 	// Load custom template path if set
 	config.CustomTemplatePath = os.Getenv("GH_PRREVIEW_AI_TEMPLATE")
 	return config
@@ -2,10 +2,7 @@ package ai
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"regexp"
-	"strings"
 
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/option"
@@ -102,40 +99,5 @@ func parseGeminiResponse(resp *genai.GenerateContentResponse) (*SuggestionRespon
 		return nil, fmt.Errorf("no text in Gemini response")
 	}
 
-	// Parse JSON response
-	var result struct {
-		Patch       string   `json:"patch"`
-		Explanation string   `json:"explanation"`
-		Confidence  float64  `json:"confidence"`
-		Warnings    []string `json:"warnings"`
-	}
-
-	// Clean up response text (remove markdown code blocks if present)
-	responseText = strings.TrimSpace(responseText)
-	re := regexp.MustCompile("(?s)```(?:json)?\\s*\\n?(.*)```")
-	if matches := re.FindStringSubmatch(responseText); len(matches) > 1 {
-		responseText = matches[1]
-	}
-	responseText = strings.TrimSpace(responseText)
-
-	if err := json.Unmarshal([]byte(responseText), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse Gemini JSON response: %w\nResponse: %s", err, responseText)
-	}
-
-	// Validate the response
-	if result.Patch == "" {
-		return nil, fmt.Errorf("gemini returned empty patch")
-	}
-
-	// Ensure warnings is not nil
-	if result.Warnings == nil {
-		result.Warnings = []string{}
-	}
-
-	return &SuggestionResponse{
-		Patch:       result.Patch,
-		Explanation: result.Explanation,
-		Confidence:  result.Confidence,
-		Warnings:    result.Warnings,
-	}, nil
+	return parseSuggestionJSON(responseText, "Gemini")
 }
@@ -0,0 +1,158 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const openAIChatCompletionsURL = "https://api.openai.com/v1/chat/completions"
+
+// OpenAIProvider implements AIProvider using OpenAI's chat completions API
+type OpenAIProvider struct {
+	apiKey         string
+	model          string
+	endpoint       string
+	httpClient     *http.Client
+	templateConfig *TemplateConfig
+}
+
+// NewOpenAIProvider creates a new OpenAI provider. An empty apiKey or model
+// falls back to OPENAI_API_KEY and OPENAI_MODEL respectively.
+func NewOpenAIProvider(apiKey string, model string, templateConfig *TemplateConfig) (*OpenAIProvider, error) {
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+
+	if model == "" {
+		model = getEnvWithDefault("OPENAI_MODEL", "gpt-4o-mini") // default model
+	}
+
+	return &OpenAIProvider{
+		apiKey:         apiKey,
+		model:          model,
+		endpoint:       openAIChatCompletionsURL,
+		httpClient:     http.DefaultClient,
+		templateConfig: templateConfig,
+	}, nil
+}
+
+// Name returns the provider name
+func (o *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+// Model returns the model name being used
+func (o *OpenAIProvider) Model() string {
+	return o.model
+}
+
+// openAIMessage is a single chat message in a completions request or response
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIChatRequest is the request body for the chat completions endpoint
+type openAIChatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []openAIMessage `json:"messages"`
+	ResponseFormat struct {
+		Type string `json:"type"`
+	} `json:"response_format"`
+}
+
+// openAIChatResponse is the subset of the chat completions response we use
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ApplySuggestion uses OpenAI to generate an adapted patch for the suggestion
+func (o *OpenAIProvider) ApplySuggestion(ctx context.Context, req *SuggestionRequest) (*SuggestionResponse, error) {
+	// Build the prompt from template
+	prompt, err := BuildPrompt(req, o.templateConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build prompt: %w", err)
+	}
+
+	chatReq := openAIChatRequest{
+		Model: o.model,
+		Messages: []openAIMessage{
+			{Role: "system", Content: "You adapt code review suggestions into unified diff patches. Respond only with the requested JSON object."},
+			{Role: "user", Content: prompt},
+		},
+	}
+	chatReq.ResponseFormat.Type = "json_object"
+
+	responseText, err := o.complete(ctx, &chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := parseSuggestionJSON(responseText, "OpenAI")
+	if err != nil {
+		return nil, err
+	}
+	// ApplyWithRetry retries on this; direct callers get a warning
+	if err := checkPatch(ctx, resp.Patch); err != nil {
+		resp.Warnings = append(resp.Warnings, "patch does not apply cleanly: "+err.Error())
+	}
+	return resp, nil
+}
+
+// complete sends a chat completions request and returns the first choice's content
+func (o *OpenAIProvider) complete(ctx context.Context, chatReq *openAIChatRequest) (string, error) {
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode OpenAI request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create OpenAI request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := o.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("openai API call failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read OpenAI response: %w", err)
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return "", fmt.Errorf("failed to parse OpenAI response (status %d): %w", httpResp.StatusCode, err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		if chatResp.Error != nil && chatResp.Error.Message != "" {
+			return "", fmt.Errorf("openai API call failed (status %d): %s", httpResp.StatusCode, chatResp.Error.Message)
+		}
+		return "", fmt.Errorf("openai API call failed (status %d)", httpResp.StatusCode)
+	}
+
+	if len(chatResp.Choices) == 0 || strings.TrimSpace(chatResp.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("empty response from OpenAI")
+	}
+
+	return chatResp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// openAITestProvider returns a provider whose requests go to handler
+func openAITestProvider(t *testing.T, handler http.HandlerFunc) *OpenAIProvider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &OpenAIProvider{
+		apiKey:     "test-key",
+		model:      "test-model",
+		endpoint:   server.URL,
+		httpClient: server.Client(),
+	}
+}
+
+func testSuggestionRequest() *SuggestionRequest {
+	return &SuggestionRequest{
+		ReviewComment:      "Use a constant",
+		SuggestedCode:      "const limit = 10",
+		FilePath:           "main.go",
+		CurrentFileContent: "package main\n\nvar limit = 10\n",
+		TargetLineNumber:   2,
+		FileLanguage:       "go",
+	}
+}
+
+func TestOpenAIApplySuggestion(t *testing.T) {
+	stubCheckPatch(t)
+	provider := openAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req openAIChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("request body: %v", err)
+		}
+		if req.Model != "test-model" || req.ResponseFormat.Type != "json_object" || len(req.Messages) != 2 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		content := `{"patch": "--- a/main.go\n+++ b/main.go\n", "explanation": "Made it a constant", "confidence": 0.9}`
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+	})
+
+	resp, err := provider.ApplySuggestion(context.Background(), testSuggestionRequest())
+	if err != nil {
+		t.Fatalf("ApplySuggestion returned error: %v", err)
+	}
+	if !strings.HasPrefix(resp.Patch, "--- a/main.go") || resp.Explanation != "Made it a constant" || resp.Confidence != 0.9 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if len(resp.Warnings) != 0 {
+		t.Errorf("expected no warnings for a patch that applies, got %v", resp.Warnings)
+	}
+}
+
+func TestOpenAIApplySuggestionWarnsOnBadPatch(t *testing.T) {
+	stubCheckPatch(t)
+	provider := openAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		content := `{"patch": "bad patch", "explanation": "", "confidence": 0.5}`
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+	})
+
+	resp, err := provider.ApplySuggestion(context.Background(), testSuggestionRequest())
+	if err != nil {
+		t.Fatalf("ApplySuggestion returned error: %v", err)
+	}
+	if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "does not apply") {
+		t.Errorf("expected a warning that the patch does not apply, got %v", resp.Warnings)
+	}
+}
+
+func TestOpenAIApplySuggestionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"error status", http.StatusUnauthorized, `{"error": {"message": "Incorrect API key"}}`, "status 401): Incorrect API key"},
+		{"malformed response", http.StatusOK, `not json`, "failed to parse OpenAI response"},
+		{"no choices", http.StatusOK, `{"choices": []}`, "empty response from OpenAI"},
+		{"content not JSON", http.StatusOK, `{"choices": [{"message": {"content": "sorry"}}]}`, "failed to parse OpenAI JSON response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := openAITestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			_, err := provider.ApplySuggestion(context.Background(), testSuggestionRequest())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewOpenAIProviderRequiresKey(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	if _, err := NewOpenAIProvider("", "", nil); err == nil {
+		t.Error("expected an error without an API key")
+	}
+
+	t.Setenv("OPENAI_API_KEY", "env-key")
+	t.Setenv("OPENAI_MODEL", "")
+	provider, err := NewOpenAIProvider("", "", nil)
+	if err != nil {
+		t.Fatalf("NewOpenAIProvider returned error: %v", err)
+	}
+	if provider.apiKey != "env-key" || provider.Model() != "gpt-4o-mini" {
+		t.Errorf("expected the key from OPENAI_API_KEY and the default model, got %q, %q", provider.apiKey, provider.Model())
+	}
+}
@@ -1,6 +1,12 @@
 package ai
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
 
 // AIProvider defines the interface for AI code assistance
 type AIProvider interface {
@@ -50,3 +56,44 @@ type SuggestionResponse struct {
 	// Any warnings the AI identified
 	Warnings []string
 }
+
+// parseSuggestionJSON parses the JSON object requested by the apply-suggestion
+// template into a SuggestionResponse. Markdown code fences around the object
+// are tolerated since models add them despite instructions.
+func parseSuggestionJSON(responseText string, providerLabel string) (*SuggestionResponse, error) {
+	var result struct {
+		Patch       string   `json:"patch"`
+		Explanation string   `json:"explanation"`
+		Confidence  float64  `json:"confidence"`
+		Warnings    []string `json:"warnings"`
+	}
+
+	// Clean up response text (remove markdown code blocks if present)
+	responseText = strings.TrimSpace(responseText)
+	re := regexp.MustCompile("(?s)```(?:json)?\\s*\\n?(.*)```")
+	if matches := re.FindStringSubmatch(responseText); len(matches) > 1 {
+		responseText = matches[1]
+	}
+	responseText = strings.TrimSpace(responseText)
+
+	if err := json.Unmarshal([]byte(responseText), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse %s JSON response: %w\nResponse: %s", providerLabel, err, responseText)
+	}
+
+	// Validate the response
+	if result.Patch == "" {
+		return nil, fmt.Errorf("%s returned empty patch", strings.ToLower(providerLabel))
+	}
+
+	// Ensure warnings is not nil
+	if result.Warnings == nil {
+		result.Warnings = []string{}
+	}
+
+	return &SuggestionResponse{
+		Patch:       result.Patch,
+		Explanation: result.Explanation,
+		Confidence:  result.Confidence,
+		Warnings:    result.Warnings,
+	}, nil
+}
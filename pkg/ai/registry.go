@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// providerOrder is the preference order used when auto-selecting a provider
+var providerOrder = []string{"gemini", "openai", "claude"}
+
+// providerConstructors builds each implemented provider. Providers in
+// providerInfo without one are planned but not yet supported.
+var providerConstructors = map[string]func(apiKey, model string, templateConfig *TemplateConfig) (AIProvider, error){
+	"gemini": func(apiKey, model string, templateConfig *TemplateConfig) (AIProvider, error) {
+		provider, err := NewGeminiProvider(apiKey, model, templateConfig)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	},
+	"openai": func(apiKey, model string, templateConfig *TemplateConfig) (AIProvider, error) {
+		provider, err := NewOpenAIProvider(apiKey, model, templateConfig)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	},
+}
+
+// ErrMissingAPIKey is wrapped by NewProviderWithConfig's error when the
+// provider has no API key
+var ErrMissingAPIKey = errors.New("API key not found")
+
+// NewProvider creates the named provider with its API key and settings from
+// the environment. An empty name auto-selects, like DefaultProvider.
+func NewProvider(name string) (AIProvider, error) {
+	config := LoadConfigFromEnv()
+	config.Provider = name
+	return NewProviderWithConfig(config)
+}
+
+// DefaultProvider creates the first supported provider with an API key
+// configured in the environment.
+func DefaultProvider() (AIProvider, error) {
+	return NewProvider("")
+}
+
+// NewProviderFromConfig creates the provider config names.
+//
+// Deprecated: Use NewProviderWithConfig, which also finds the API key in the
+// environment and auto-selects a provider when config names none.
+func NewProviderFromConfig(config *Config) (AIProvider, error) {
+	return NewProviderWithConfig(config)
+}
+
+// NewProviderWithConfig creates the provider config names, taking its API key
+// from the environment when config has none. With no provider named, the
+// first supported one with an API key in the environment is used.
+func NewProviderWithConfig(config *Config) (AIProvider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+
+	name := config.Provider
+	if name == "" {
+		var err error
+		if name, err = DefaultProviderName(); err != nil {
+			return nil, err
+		}
+	}
+	meta, ok := GetProviderMetadata(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown AI provider: %s (supported: %s)", name, strings.Join(providerOrder, ", "))
+	}
+	construct, ok := providerConstructors[name]
+	if !ok {
+		return nil, fmt.Errorf("%s provider is not yet supported", meta.Label)
+	}
+
+	apiKey := config.APIKey
+	if apiKey == "" {
+		apiKey = APIKeyFromEnv(name)
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("%s %w. Set %s", meta.Label, ErrMissingAPIKey, strings.Join(meta.EnvVars, " or "))
+	}
+
+	return construct(apiKey, config.Model, &TemplateConfig{
+		CustomTemplatePath: config.CustomTemplatePath,
+		CustomVariables:    config.CustomVariables,
+	})
+}
+
+// DefaultProviderName returns the first supported provider with an API key
+// configured in the environment.
+func DefaultProviderName() (string, error) {
+	missing := make([]string, 0, len(providerOrder))
+	for _, name := range providerOrder {
+		if _, ok := providerConstructors[name]; !ok {
+			continue
+		}
+		if APIKeyFromEnv(name) != "" {
+			return name, nil
+		}
+		meta, _ := GetProviderMetadata(name)
+		missing = append(missing, fmt.Sprintf("%s (%s)", strings.Join(meta.EnvVars, " or "), name))
+	}
+
+	return "", fmt.Errorf("no AI provider configured; missing API keys: %s", strings.Join(missing, ", "))
+}
+
+// APIKeyFromEnv returns the first non-empty API key environment variable for a provider
+func APIKeyFromEnv(provider string) string {
+	meta, ok := GetProviderMetadata(provider)
+	if !ok {
+		return ""
+	}
+	for _, envVar := range meta.EnvVars {
+		if key := os.Getenv(envVar); key != "" {
+			return key
+		}
+	}
+	return ""
+}
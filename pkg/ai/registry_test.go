@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// clearAPIKeys unsets every provider's API key variables for the test
+func clearAPIKeys(t *testing.T) {
+	t.Helper()
+	for _, meta := range providerInfo {
+		for _, envVar := range meta.EnvVars {
+			t.Setenv(envVar, "")
+		}
+	}
+}
+
+func TestProviderRegistration(t *testing.T) {
+	for _, name := range providerOrder {
+		if _, ok := GetProviderMetadata(name); !ok {
+			t.Errorf("provider %q in providerOrder has no metadata", name)
+		}
+	}
+	for name := range providerConstructors {
+		if !slices.Contains(providerOrder, name) {
+			t.Errorf("provider %q can be built but is never auto-selected", name)
+		}
+	}
+}
+
+func TestNewProviderWithConfig(t *testing.T) {
+	clearAPIKeys(t)
+
+	provider, err := NewProviderWithConfig(&Config{Provider: "openai", APIKey: "flag-key", Model: "gpt-test"})
+	if err != nil {
+		t.Fatalf("NewProviderWithConfig returned error: %v", err)
+	}
+	if provider.Name() != "openai" || provider.Model() != "gpt-test" {
+		t.Errorf("got provider %s with model %s", provider.Name(), provider.Model())
+	}
+
+	if _, err := NewProviderWithConfig(&Config{Provider: "openai"}); !errors.Is(err, ErrMissingAPIKey) || !strings.Contains(err.Error(), "OPENAI_API_KEY") {
+		t.Errorf("expected a missing key error naming OPENAI_API_KEY, got %v", err)
+	}
+	if _, err := NewProviderWithConfig(&Config{Provider: "nosuch"}); err == nil || !strings.Contains(err.Error(), "unknown AI provider: nosuch") {
+		t.Errorf("expected an unknown provider error, got %v", err)
+	}
+	if _, err := NewProviderWithConfig(&Config{Provider: "claude", APIKey: "key"}); err == nil || !strings.Contains(err.Error(), "not yet supported") {
+		t.Errorf("expected a not supported error, got %v", err)
+	}
+}
+
+func TestDefaultProviderSelection(t *testing.T) {
+	clearAPIKeys(t)
+	if _, err := DefaultProviderName(); err == nil || !strings.Contains(err.Error(), "OPENAI_API_KEY (openai)") {
+		t.Errorf("expected an error listing the missing keys, got %v", err)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "env-key")
+	provider, err := NewProviderWithConfig(&Config{})
+	if err != nil {
+		t.Fatalf("NewProviderWithConfig returned error: %v", err)
+	}
+	if provider.Name() != "openai" {
+		t.Errorf("expected openai, the only provider with a key, got %s", provider.Name())
+	}
+
+	t.Setenv("GOOGLE_API_KEY", "env-key")
+	if name, err := DefaultProviderName(); err != nil || name != "gemini" {
+		t.Errorf("expected gemini to be preferred when both have keys, got %q, %v", name, err)
+	}
+}
+
+func TestNewProviderFromEnv(t *testing.T) {
+	clearAPIKeys(t)
+	t.Setenv("GH_RC_AI_PROVIDER", "")
+	t.Setenv("GH_PRREVIEW_AI_PROVIDER", "")
+	t.Setenv("GH_PRREVIEW_AI_MODEL", "gpt-env")
+
+	if _, err := NewProvider("openai"); !errors.Is(err, ErrMissingAPIKey) {
+		t.Errorf("expected a missing key error, got %v", err)
+	}
+	if _, err := DefaultProvider(); err == nil || !strings.Contains(err.Error(), "missing API keys") {
+		t.Errorf("expected an error listing the missing keys, got %v", err)
+	}
+
+	t.Setenv("OPENAI_API_KEY", "env-key")
+	provider, err := NewProvider("openai")
+	if err != nil {
+		t.Fatalf("NewProvider returned error: %v", err)
+	}
+	if provider.Name() != "openai" || provider.Model() != "gpt-env" {
+		t.Errorf("got provider %s with model %s", provider.Name(), provider.Model())
+	}
+	if provider, err := DefaultProvider(); err != nil || provider.Name() != "openai" {
+		t.Errorf("expected DefaultProvider to pick openai, got %v", err)
+	}
+	if provider, err := NewProviderFromConfig(&Config{Provider: "openai"}); err != nil || provider.Name() != "openai" {
+		t.Errorf("expected NewProviderFromConfig to still work, got %v", err)
+	}
+}
@@ -20,10 +20,15 @@ import (
 // errEditApplied is a sentinel error indicating that a patch was successfully applied via the edit flow
 var errEditApplied = fmt.Errorf("patch applied after editing")
 
+// maxAIPatchRetries is how many times the AI is re-prompted when its patch doesn't apply
+const maxAIPatchRetries = 2
+
 type Applier struct {
-	debug        bool
-	aiProvider   ai.AIProvider
-	githubClient *github.Client
+	debug         bool
+	aiProvider    ai.AIProvider
+	githubClient  *github.Client
+	minConfidence float64
+	assumeYes     bool
 }
 
 func New() *Applier {
@@ -40,6 +45,25 @@ func (a *Applier) SetAIProvider(provider ai.AIProvider) {
 	a.aiProvider = provider
 }
 
+// SetMinConfidence sets the AI confidence (0.0-1.0) below which a patch always
+// requires interactive confirmation, even in auto-apply mode
+func (a *Applier) SetMinConfidence(minConfidence float64) {
+	a.minConfidence = minConfidence
+}
+
+// SetAssumeYes skips confirmation for AI patches that meet the minimum confidence
+func (a *Applier) SetAssumeYes(assumeYes bool) {
+	a.assumeYes = assumeYes
+}
+
+// needsAIConfirmation reports whether an AI patch must be confirmed by the user
+func (a *Applier) needsAIConfirmation(autoApply bool, confidence float64) bool {
+	if confidence < a.minConfidence {
+		return true
+	}
+	return !autoApply && !a.assumeYes
+}
+
 // SetGitHubClient sets the GitHub client for resolving threads
 func (a *Applier) SetGitHubClient(client *github.Client) {
 	a.githubClient = client
@@ -711,14 +735,17 @@ func (a *Applier) applyWithAI(comment *github.ReviewComment, autoApply bool) err
 	s.Suffix = fmt.Sprintf(" Analyzing code and generating patch with %s (%s)...", providerName, modelName)
 	s.Start()
 
-	// Call AI provider
-	resp, err := a.aiProvider.ApplySuggestion(ctx, req)
+	// Call AI provider, re-prompting with the git error if the patch doesn't apply
+	resp, patchErr := ai.ApplyWithRetry(ctx, a.aiProvider, req, maxAIPatchRetries)
 
 	// Stop spinner
 	s.Stop()
 
-	if err != nil {
-		return fmt.Errorf("AI provider error: %w", err)
+	// Without a response the provider itself failed. Once the retries run out
+	// there is still the last patch, which goes to review below and is saved
+	// for fixing by hand if it doesn't apply.
+	if resp == nil {
+		return fmt.Errorf("AI provider error: %w", patchErr)
 	}
 
 	// Show AI's explanation
@@ -740,9 +767,15 @@ func (a *Applier) applyWithAI(comment *github.ReviewComment, autoApply bool) err
 
 	a.debugLog("AI-generated patch:\n%s", resp.Patch)
 
-	// Ask for confirmation (unless auto-apply mode)
+	// Ask for confirmation (unless auto-apply mode and confident enough)
 	patchToApply := resp.Patch
-	if !autoApply {
+	if patchErr != nil || a.needsAIConfirmation(autoApply, resp.Confidence) {
+		if patchErr != nil {
+			fmt.Printf("\n%s\n", ui.Colorize(ui.ColorYellow, fmt.Sprintf("%s%v; review required.", ui.EmojiText("⚠️  ", ""), patchErr)))
+		} else if resp.Confidence < a.minConfidence {
+			fmt.Printf("\n%s\n", ui.Colorize(ui.ColorYellow, fmt.Sprintf("%sConfidence %.0f%% is below the minimum of %.0f%%; review required.",
+				ui.EmojiText("⚠️  ", ""), resp.Confidence*100, a.minConfidence*100)))
+		}
 		reader := bufio.NewReader(os.Stdin)
 	confirmationLoop:
 		for {
@@ -832,14 +865,8 @@ func (a *Applier) applyPatchAndEditFile(patch string, filePath string, comment *
 
 	fmt.Printf("%sPatch applied. Opening file for additional edits...\n", ui.EmojiText("✅ ", ""))
 
-	// Get editor from environment, default to vi
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi"
-	}
-
 	// Open the file in editor
-	editorParts := strings.Fields(editor)
+	editorParts := ui.ResolveEditor()
 	editorCmd := exec.Command(editorParts[0], append(editorParts[1:], filePath)...)
 	editorCmd.Stdin = os.Stdin
 	editorCmd.Stdout = os.Stdout
@@ -1027,10 +1054,15 @@ func (r *suggestionRenderer) Description(comment *github.ReviewComment) string {
 }
 
 func (r *suggestionRenderer) Preview(comment *github.ReviewComment) string {
-	return r.PreviewWithHighlight(comment, -1) // No highlight
+	return r.PreviewWithHighlight(comment, -1, "") // No highlight
+}
+
+func (r *suggestionRenderer) PreviewWithHighlight(comment *github.ReviewComment, highlightIdx int, query string) string {
+	return ui.HighlightMatches(r.renderPreview(comment), query)
 }
 
-func (r *suggestionRenderer) PreviewWithHighlight(comment *github.ReviewComment, highlightIdx int) string {
+// renderPreview builds the detail view for a suggestion before search highlighting
+func (r *suggestionRenderer) renderPreview(comment *github.ReviewComment) string {
 	var preview strings.Builder
 	maxLines := 20 // Limit preview to fit screen
 
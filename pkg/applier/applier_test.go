@@ -1,11 +1,14 @@
 package applier
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/ai"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
 )
 
@@ -638,3 +641,88 @@ func TestPreviewSuggestionRejectsTraversal(t *testing.T) {
 		t.Errorf("error should mention path is outside repository, got: %v", err)
 	}
 }
+
+func TestNeedsAIConfirmation(t *testing.T) {
+	tests := []struct {
+		name          string
+		minConfidence float64
+		assumeYes     bool
+		autoApply     bool
+		confidence    float64
+		want          bool
+	}{
+		{"interactive always confirms", 0.8, false, false, 0.95, true},
+		{"auto-apply above threshold skips", 0.8, false, true, 0.95, false},
+		{"auto-apply below threshold confirms", 0.8, false, true, 0.5, true},
+		{"yes above threshold skips", 0.8, true, false, 0.8, false},
+		{"yes below threshold confirms", 0.8, true, false, 0.79, true},
+		{"zero threshold keeps auto-apply unattended", 0, false, true, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New()
+			a.SetMinConfidence(tt.minConfidence)
+			a.SetAssumeYes(tt.assumeYes)
+			if got := a.needsAIConfirmation(tt.autoApply, tt.confidence); got != tt.want {
+				t.Errorf("needsAIConfirmation(%v, %v) = %v, want %v", tt.autoApply, tt.confidence, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeAIProvider returns the same response, or error, on every call
+type fakeAIProvider struct {
+	resp  *ai.SuggestionResponse
+	err   error
+	calls int
+}
+
+func (p *fakeAIProvider) ApplySuggestion(context.Context, *ai.SuggestionRequest) (*ai.SuggestionResponse, error) {
+	p.calls++
+	return p.resp, p.err
+}
+
+func (p *fakeAIProvider) Name() string  { return "fake" }
+func (p *fakeAIProvider) Model() string { return "test" }
+
+func TestApplyWithAIReviewsLastPatch(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	// Answer "no" at the review prompt
+	stdin, input, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := input.WriteString("n\n"); err != nil {
+		t.Fatal(err)
+	}
+	_ = input.Close()
+	saved := os.Stdin
+	os.Stdin = stdin
+	t.Cleanup(func() { os.Stdin = saved })
+
+	provider := &fakeAIProvider{resp: &ai.SuggestionResponse{Patch: "not a patch", Confidence: 1}}
+	a := New()
+	a.SetAIProvider(provider)
+	a.SetAssumeYes(true)
+
+	comment := &github.ReviewComment{ID: 1, Path: "main.go", Line: 1}
+	err = a.applyWithAI(comment, true)
+	if provider.calls != maxAIPatchRetries+1 {
+		t.Errorf("expected %d attempts, got %d", maxAIPatchRetries+1, provider.calls)
+	}
+	if err == nil || !strings.Contains(err.Error(), "cancelled by user") {
+		t.Errorf("expected the last patch to go to review even with --yes, got %v", err)
+	}
+
+	failing := &fakeAIProvider{err: errors.New("quota exceeded")}
+	a.SetAIProvider(failing)
+	if err := a.applyWithAI(comment, true); err == nil || !strings.Contains(err.Error(), "AI provider error") {
+		t.Errorf("expected the provider error, got %v", err)
+	}
+}
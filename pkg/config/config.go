@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configPathEnv points at a config file other than the default location
+const configPathEnv = "GH_RC_CONFIG"
+
+// DefaultQuoteContext is how many diff lines quote-with-context replies keep
+const DefaultQuoteContext = 10
+
+// ErrInvalid is wrapped by Load's error for a config file that doesn't parse
+var ErrInvalid = errors.New("invalid config")
+
+// Config holds user defaults read from the config file. Precedence, highest
+// first: command-line flags, environment variables, this file, built-in
+// defaults.
+type Config struct {
+	Debug        bool   `yaml:"debug"`         // --debug on every command
+	NoColor      bool   `yaml:"no_color"`      // --no-color
+	NoHyperlinks bool   `yaml:"no_hyperlinks"` // --no-hyperlinks; GH_RC_HYPERLINKS wins
+	Editor       string `yaml:"editor"`        // used when $VISUAL and $EDITOR are unset
+	Agent        string `yaml:"agent"`         // used when GH_REVIEW_CONDUCTOR_AGENT is unset
+	AgentPrompt  string `yaml:"agent_prompt"`  // instructions placed before the comment in agent prompts
+	Theme        string `yaml:"theme"`         // glamour style for rendered comments; "" for dark
+	Ellipsis     string `yaml:"ellipsis"`      // ends cut list rows and previews; "" for "..."
+
+	Browse BrowseConfig `yaml:"browse"`
+}
+
+// BrowseConfig holds defaults for the browse command
+type BrowseConfig struct {
+	HideResolved  bool          `yaml:"hide_resolved"` // start with resolved threads hidden
+	HideBots      bool          `yaml:"hide_bots"`
+	CollapseBots  bool          `yaml:"collapse_bots"`
+	Compact       bool          `yaml:"compact"`
+	Aligned       bool          `yaml:"aligned"`
+	Mention       bool          `yaml:"mention"`
+	WrapQuotes    bool          `yaml:"wrap_quotes"`
+	QuoteContext  int           `yaml:"quote_context"` // diff lines in C replies; 0 for the whole hunk
+	Notify        bool          `yaml:"notify"`
+	ReactionOrder []string      `yaml:"reaction_order"`  // reactions the x picker offers first
+	WontFixPrefix string        `yaml:"wont_fix_prefix"` // opener of W replies; "" for "Won't fix: "
+	Include       []string      `yaml:"include"`
+	WatchInterval time.Duration `yaml:"watch_interval"`
+}
+
+// Default returns the built-in defaults, matching the flag defaults
+func Default() *Config {
+	return &Config{
+		Browse: BrowseConfig{
+			HideResolved:  true,
+			QuoteContext:  DefaultQuoteContext,
+			WatchInterval: 30 * time.Second,
+		},
+	}
+}
+
+// DefaultPath returns the config file location:
+// $GH_RC_CONFIG, else $XDG_CONFIG_HOME/gh-review-conductor/config.yml,
+// else ~/.config/gh-review-conductor/config.yml
+func DefaultPath() (string, error) {
+	if path := os.Getenv(configPathEnv); path != "" {
+		return path, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh-review-conductor", "config.yml"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "gh-review-conductor", "config.yml"), nil
+}
+
+// LoadConfig reads the config file at DefaultPath. A missing file yields the
+// built-in defaults; a malformed one is an error so typos don't go unnoticed.
+func LoadConfig() (*Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return Default(), nil
+	}
+	return Load(path)
+}
+
+// Load reads the config file at path over the built-in defaults
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalid, path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes cfg to path as YAML, creating its directory if needed
+func Save(path string, cfg *Config) error {
+	content, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write config %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFileUsesDefaults(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.yml"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.Browse.HideResolved || cfg.Browse.WatchInterval != 30*time.Second || cfg.Browse.QuoteContext != DefaultQuoteContext {
+		t.Errorf("expected built-in defaults, got %+v", cfg.Browse)
+	}
+}
+
+func TestLoadOverridesDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	content := `
+debug: true
+editor: code --wait
+agent_prompt: Fix this and run the tests.
+ellipsis: "…"
+browse:
+  hide_resolved: false
+  include: [summary, issue]
+  watch_interval: 1m
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.Debug || cfg.Editor != "code --wait" || cfg.AgentPrompt != "Fix this and run the tests." || cfg.Ellipsis != "…" {
+		t.Errorf("unexpected top-level settings: %+v", cfg)
+	}
+	if cfg.Browse.HideResolved {
+		t.Error("expected hide_resolved: false to override the default")
+	}
+	if len(cfg.Browse.Include) != 2 || cfg.Browse.WatchInterval != time.Minute {
+		t.Errorf("unexpected browse settings: %+v", cfg.Browse)
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte("browse:\n  hide_bot: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "hide_bot") {
+		t.Errorf("expected an error naming the unknown key, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected the error to wrap ErrInvalid, got %v", err)
+	}
+}
+
+func TestDefaultPathPrecedence(t *testing.T) {
+	t.Setenv(configPathEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if got, _ := DefaultPath(); got != filepath.Join("/xdg", "gh-review-conductor", "config.yml") {
+		t.Errorf("DefaultPath() = %q, want the XDG location", got)
+	}
+
+	t.Setenv(configPathEnv, "/custom.yml")
+	if got, _ := DefaultPath(); got != "/custom.yml" {
+		t.Errorf("DefaultPath() = %q, want %s to win", got, configPathEnv)
+	}
+}
+
+func TestSaveRoundTrips(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yml")
+	cfg := Default()
+	cfg.Editor = "code --wait"
+	cfg.Theme = "light"
+	cfg.Browse.HideBots = true
+
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if loaded.Editor != "code --wait" || loaded.Theme != "light" || !loaded.Browse.HideBots {
+		t.Errorf("unexpected settings after a round trip: %+v", loaded)
+	}
+	if loaded.Browse.WatchInterval != 30*time.Second {
+		t.Errorf("expected the watch interval to survive, got %s", loaded.Browse.WatchInterval)
+	}
+}
@@ -1,22 +1,45 @@
 package github
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cli/go-gh/v2"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/diffposition"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/parser"
+	"golang.org/x/sync/errgroup"
 )
 
+// errIncompleteThreads is wrapped by getReviewThreads' error when replies past
+// the first page of a long thread couldn't be loaded
+var errIncompleteThreads = errors.New("failed to load every reply of long review threads")
+
+// defaultConcurrency is the default number of parallel API requests
+const defaultConcurrency = 8
+
 type Client struct {
-	repo  string
-	debug bool
+	repo        string
+	host        string // GitHub host for API calls; empty uses gh's default
+	debug       bool
+	maxRetries  int
+	concurrency int
+	loginMu     sync.Mutex
+	login       string // authenticated user, cached by CurrentUser
+	token       string // personal access token; when set, API calls skip gh
+
+	// Rate limit state by resource (RateLimitCore, RateLimitGraphQL), from
+	// the most recent response that reported it
+	rateMu     sync.Mutex
+	rateLimits map[string]rateLimitState
 }
 
 // Reactions represents the reaction counts on a comment
@@ -32,9 +55,24 @@ type Reactions struct {
 	TotalCount int `json:"total_count"`
 }
 
+// CommentKind says where on the PR a comment was made
+type CommentKind string
+
+const (
+	// CommentKindInline is a review comment on a line of the diff (the zero value)
+	CommentKindInline CommentKind = ""
+	// CommentKindSummary is the body of a submitted review
+	CommentKindSummary CommentKind = "summary"
+	// CommentKindIssue is a comment on the PR's conversation tab
+	CommentKindIssue CommentKind = "issue"
+)
+
 type ReviewComment struct {
 	ID                int64
+	Kind              CommentKind
+	NodeID            string // GraphQL node ID of the comment itself
 	ThreadID          string // GraphQL node ID for resolving the thread
+	ReviewID          int64  // review the comment was submitted in
 	Path              string
 	Line              int
 	Body              string
@@ -51,6 +89,9 @@ type ReviewComment struct {
 	DiffSide          diffposition.DiffSide
 	SubjectType       string
 	HTMLURL           string
+	HeadSHA           string // commit the comment was made against
+	OriginalCommitID  string // commit the comment was first made on; OriginalLine refers to it
+	ResolvedBy        string // login of the user who resolved the thread, if any
 	CreatedAt         time.Time
 	IsOutdated        bool
 	Reactions         Reactions
@@ -59,6 +100,7 @@ type ReviewComment struct {
 
 type ThreadComment struct {
 	ID        int64
+	ReviewID  int64 // review the reply was submitted in
 	Body      string
 	Author    string
 	HTMLURL   string
@@ -66,6 +108,17 @@ type ThreadComment struct {
 	Reactions Reactions
 }
 
+// Review is a review submitted on a pull request
+type Review struct {
+	ID           int64
+	State        string // APPROVED, CHANGES_REQUESTED, COMMENTED, DISMISSED
+	Author       string
+	Body         string
+	HTMLURL      string
+	SubmittedAt  time.Time
+	CommentCount int // inline comments submitted with the review
+}
+
 // PullRequest represents a GitHub pull request with display-relevant fields
 type PullRequest struct {
 	Number         int
@@ -74,7 +127,8 @@ type PullRequest struct {
 	State          string
 	IsDraft        bool
 	HeadRefName    string
-	ReviewDecision string // APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED, etc.
+	ReviewDecision string   // APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED, etc.
+	Assignees      []string // logins
 }
 
 // IsResolved returns true if the comment thread has been marked as resolved/done
@@ -83,7 +137,11 @@ func (rc *ReviewComment) IsResolved() bool {
 }
 
 func NewClient() *Client {
-	return &Client{}
+	return &Client{
+		maxRetries:  defaultMaxRetries,
+		concurrency: defaultConcurrency,
+		token:       tokenFromEnv(os.Getenv),
+	}
 }
 
 // SetDebug enables or disables debug output
@@ -91,11 +149,45 @@ func (c *Client) SetDebug(debug bool) {
 	c.debug = debug
 }
 
+// SetMaxRetries sets how many times transient API failures (5xx, 429,
+// connection resets) are retried. Zero disables retries.
+func (c *Client) SetMaxRetries(n int) {
+	if n < 0 {
+		n = 0
+	}
+	c.maxRetries = n
+}
+
+// SetConcurrency sets how many API requests may run in parallel when loading
+// review threads. Values below 1 are treated as 1.
+func (c *Client) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.concurrency = n
+}
+
 // SetRepo sets the repository to use (format: "owner/repo")
 func (c *Client) SetRepo(repo string) {
 	c.repo = repo
 }
 
+// SetHost points API calls at a GitHub host, such as a GitHub Enterprise
+// server. Token calls only reach api.github.com, so any other host ignores
+// the token and goes through gh and its own auth for that host.
+func (c *Client) SetHost(host string) {
+	c.host = host
+	if host != "github.com" && c.token != "" {
+		c.debugLog("Ignoring the API token for %s; using gh's auth for that host", host)
+		c.token = ""
+	}
+}
+
+// Host returns the GitHub host set with SetHost, or "" for gh's default
+func (c *Client) Host() string {
+	return c.host
+}
+
 // GetRepo returns the current repository (format: "owner/repo")
 func (c *Client) GetRepo() (string, error) {
 	return c.getRepo()
@@ -112,9 +204,100 @@ func (c *Client) debugLog(format string, args ...any) {
 type ThreadInfo struct {
 	ID         string // GraphQL node ID for resolving the thread
 	IsResolved bool
+	ResolvedBy string // login of the user who resolved the thread, if any
 	Comments   []ThreadComment
 }
 
+// threadCommentFields is the GraphQL selection for a review thread comment
+const threadCommentFields = `
+	databaseId
+	pullRequestReview {
+		databaseId
+	}
+	body
+	url
+	createdAt
+	author {
+		login
+	}
+	reactionGroups {
+		content
+		reactors {
+			totalCount
+		}
+	}`
+
+// threadCommentPage is one page of a review thread's comments connection
+type threadCommentPage struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []graphQLThreadComment `json:"nodes"`
+}
+
+// graphQLThreadComment is a review thread comment as returned by GraphQL
+type graphQLThreadComment struct {
+	DatabaseID int64     `json:"databaseId"`
+	Body       string    `json:"body"`
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Author     struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	ReactionGroups []struct {
+		Content  string `json:"content"`
+		Reactors struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"reactors"`
+	} `json:"reactionGroups"`
+	PullRequestReview *struct {
+		DatabaseID int64 `json:"databaseId"`
+	} `json:"pullRequestReview"`
+}
+
+// toThreadComment converts a GraphQL comment, including its reaction groups
+func (gc graphQLThreadComment) toThreadComment() ThreadComment {
+	reactions := Reactions{}
+	for _, rg := range gc.ReactionGroups {
+		count := rg.Reactors.TotalCount
+		switch rg.Content {
+		case "THUMBS_UP":
+			reactions.PlusOne = count
+		case "THUMBS_DOWN":
+			reactions.MinusOne = count
+		case "LAUGH":
+			reactions.Laugh = count
+		case "HOORAY":
+			reactions.Hooray = count
+		case "CONFUSED":
+			reactions.Confused = count
+		case "HEART":
+			reactions.Heart = count
+		case "ROCKET":
+			reactions.Rocket = count
+		case "EYES":
+			reactions.Eyes = count
+		}
+		reactions.TotalCount += count
+	}
+
+	var reviewID int64
+	if gc.PullRequestReview != nil {
+		reviewID = gc.PullRequestReview.DatabaseID
+	}
+
+	return ThreadComment{
+		ID:        gc.DatabaseID,
+		ReviewID:  reviewID,
+		Body:      gc.Body,
+		Author:    gc.Author.Login,
+		HTMLURL:   gc.URL,
+		CreatedAt: gc.CreatedAt,
+		Reactions: reactions,
+	}
+}
+
 // getReviewThreads fetches review threads with all comments using GraphQL
 func (c *Client) getReviewThreads(repo string, prNumber int) (map[int64]*ThreadInfo, error) {
 	parts := strings.Split(repo, "/")
@@ -134,21 +317,15 @@ func (c *Client) getReviewThreads(repo string, prNumber int) (map[int64]*ThreadI
 						nodes {
 							id
 							isResolved
+							resolvedBy {
+								login
+							}
 							comments(first: 50) {
-								nodes {
-									databaseId
-									body
-									url
-									createdAt
-									author {
-										login
-									}
-									reactionGroups {
-										content
-										reactors {
-											totalCount
-										}
-									}
+								pageInfo {
+									hasNextPage
+									endCursor
+								}
+								nodes {%s
 								}
 							}
 						}
@@ -156,11 +333,11 @@ func (c *Client) getReviewThreads(repo string, prNumber int) (map[int64]*ThreadI
 				}
 			}
 		}
-	`, owner, name, prNumber)
+	`, owner, name, prNumber, threadCommentFields)
 
 	c.debugLog("GraphQL query: %s", query)
 
-	stdOut, _, err := gh.Exec("api", "graphql", "-f", fmt.Sprintf("query=%s", query))
+	stdOut, _, err := c.ghAPI("graphql", "-f", fmt.Sprintf("query=%s", query))
 	if err != nil {
 		c.debugLog("GraphQL query failed: %v", err)
 		return nil, err
@@ -176,23 +353,10 @@ func (c *Client) getReviewThreads(repo string, prNumber int) (map[int64]*ThreadI
 						Nodes []struct {
 							ID         string `json:"id"`
 							IsResolved bool   `json:"isResolved"`
-							Comments   struct {
-								Nodes []struct {
-									DatabaseID int64     `json:"databaseId"`
-									Body       string    `json:"body"`
-									URL        string    `json:"url"`
-									CreatedAt  time.Time `json:"createdAt"`
-									Author     struct {
-										Login string `json:"login"`
-									} `json:"author"`
-									ReactionGroups []struct {
-										Content  string `json:"content"`
-										Reactors struct {
-											TotalCount int `json:"totalCount"`
-										} `json:"reactors"`
-									} `json:"reactionGroups"`
-								} `json:"nodes"`
-							} `json:"comments"`
+							ResolvedBy *struct {
+								Login string `json:"login"`
+							} `json:"resolvedBy"`
+							Comments threadCommentPage `json:"comments"`
 						} `json:"nodes"`
 					} `json:"reviewThreads"`
 				} `json:"pullRequest"`
@@ -211,6 +375,7 @@ func (c *Client) getReviewThreads(repo string, prNumber int) (map[int64]*ThreadI
 	c.debugLog("Found %d review threads", len(result.Data.Repository.PullRequest.ReviewThreads.Nodes))
 
 	threads := make(map[int64]*ThreadInfo)
+	var overflow []threadPageRequest
 	for i, thread := range result.Data.Repository.PullRequest.ReviewThreads.Nodes {
 		if len(thread.Comments.Nodes) == 0 {
 			c.debugLog("Thread %d: no comments, skipping", i)
@@ -227,40 +392,7 @@ func (c *Client) getReviewThreads(repo string, prNumber int) (map[int64]*ThreadI
 		for j, comment := range thread.Comments.Nodes {
 			c.debugLog("  Comment %d: ID=%d, author=%s, body_len=%d",
 				j, comment.DatabaseID, comment.Author.Login, len(comment.Body))
-
-			// Convert reactionGroups to Reactions struct
-			reactions := Reactions{}
-			for _, rg := range comment.ReactionGroups {
-				count := rg.Reactors.TotalCount
-				switch rg.Content {
-				case "THUMBS_UP":
-					reactions.PlusOne = count
-				case "THUMBS_DOWN":
-					reactions.MinusOne = count
-				case "LAUGH":
-					reactions.Laugh = count
-				case "HOORAY":
-					reactions.Hooray = count
-				case "CONFUSED":
-					reactions.Confused = count
-				case "HEART":
-					reactions.Heart = count
-				case "ROCKET":
-					reactions.Rocket = count
-				case "EYES":
-					reactions.Eyes = count
-				}
-				reactions.TotalCount += count
-			}
-
-			threadComments = append(threadComments, ThreadComment{
-				ID:        comment.DatabaseID,
-				Body:      comment.Body,
-				Author:    comment.Author.Login,
-				HTMLURL:   comment.URL,
-				CreatedAt: comment.CreatedAt,
-				Reactions: reactions,
-			})
+			threadComments = append(threadComments, comment.toThreadComment())
 		}
 
 		threads[firstCommentID] = &ThreadInfo{
@@ -268,6 +400,28 @@ func (c *Client) getReviewThreads(repo string, prNumber int) (map[int64]*ThreadI
 			IsResolved: thread.IsResolved,
 			Comments:   threadComments,
 		}
+		if thread.ResolvedBy != nil {
+			threads[firstCommentID].ResolvedBy = thread.ResolvedBy.Login
+		}
+
+		if thread.Comments.PageInfo.HasNextPage {
+			overflow = append(overflow, threadPageRequest{
+				firstCommentID: firstCommentID,
+				threadID:       thread.ID,
+				cursor:         thread.Comments.PageInfo.EndCursor,
+			})
+		}
+	}
+
+	// Long threads don't fit in the first page; load the rest in parallel
+	if len(overflow) > 0 {
+		remaining, err := c.fetchRemainingThreadComments(overflow)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errIncompleteThreads, err)
+		}
+		for i, req := range overflow {
+			threads[req.firstCommentID].Comments = append(threads[req.firstCommentID].Comments, remaining[i]...)
+		}
 	}
 
 	c.debugLog("Returning %d threads", len(threads))
@@ -275,6 +429,80 @@ func (c *Client) getReviewThreads(repo string, prNumber int) (map[int64]*ThreadI
 	return threads, nil
 }
 
+// threadPageRequest identifies a thread whose comments continue past the first page
+type threadPageRequest struct {
+	firstCommentID int64
+	threadID       string
+	cursor         string
+}
+
+// fetchRemainingThreadComments loads the comments after each request's cursor
+// using up to c.concurrency workers. Results are indexed like reqs so ordering
+// is deterministic; on error, results hold whatever was fetched.
+func (c *Client) fetchRemainingThreadComments(reqs []threadPageRequest) ([][]ThreadComment, error) {
+	results := make([][]ThreadComment, len(reqs))
+
+	var g errgroup.Group
+	g.SetLimit(c.concurrency)
+	for i, req := range reqs {
+		g.Go(func() error {
+			comments, err := c.fetchThreadComments(req.threadID, req.cursor)
+			results[i] = comments
+			return err
+		})
+	}
+
+	return results, g.Wait()
+}
+
+// fetchThreadComments pages through a thread's comments starting after cursor
+func (c *Client) fetchThreadComments(threadID, cursor string) ([]ThreadComment, error) {
+	var comments []ThreadComment
+	for {
+		query := fmt.Sprintf(`
+			query {
+				node(id: "%s") {
+					... on PullRequestReviewThread {
+						comments(first: 100, after: "%s") {
+							pageInfo {
+								hasNextPage
+								endCursor
+							}
+							nodes {%s
+							}
+						}
+					}
+				}
+			}
+		`, threadID, cursor, threadCommentFields)
+
+		stdOut, _, err := c.ghAPI("graphql", "-f", fmt.Sprintf("query=%s", query))
+		if err != nil {
+			return comments, fmt.Errorf("failed to fetch comments for thread %s: %w", threadID, err)
+		}
+
+		var result struct {
+			Data struct {
+				Node struct {
+					Comments threadCommentPage `json:"comments"`
+				} `json:"node"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(stdOut.Bytes(), &result); err != nil {
+			return comments, fmt.Errorf("failed to parse comments for thread %s: %w", threadID, err)
+		}
+
+		page := result.Data.Node.Comments
+		for _, comment := range page.Nodes {
+			comments = append(comments, comment.toThreadComment())
+		}
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			return comments, nil
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+}
+
 // getReplyCommentIDs returns a set of comment IDs that are replies (not first comments in threads)
 func (c *Client) getReplyCommentIDs(threads map[int64]*ThreadInfo) map[int64]bool {
 	replyIDs := make(map[int64]bool)
@@ -292,17 +520,54 @@ func (c *Client) getRepo() (string, error) {
 	if c.repo != "" {
 		return c.repo, nil
 	}
+	if c.token != "" {
+		return "", errors.New("could not determine the GitHub repository: with GH_TOKEN or GITHUB_TOKEN set, gh isn't used to look it up; pass --repo OWNER/REPO")
+	}
 
 	stdOut, _, err := gh.Exec("repo", "view", "--json", "nameWithOwner", "--jq", ".nameWithOwner")
 	if err != nil {
-		return "", fmt.Errorf("not in a GitHub repository (or no remote configured)")
+		return "", fmt.Errorf("could not determine the GitHub repository (no GitHub remote?); pass --repo OWNER/REPO")
 	}
 
 	c.repo = strings.TrimSpace(stdOut.String())
 	return c.repo, nil
 }
 
+// CurrentUser returns the login of the authenticated user, cached after the first call
+func (c *Client) CurrentUser() (string, error) {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+	if c.login != "" {
+		return c.login, nil
+	}
+
+	stdOut, _, err := c.ghAPI("user")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch current user: %w", err)
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := json.Unmarshal(stdOut.Bytes(), &user); err != nil {
+		return "", fmt.Errorf("failed to parse current user: %w", err)
+	}
+
+	c.login = user.Login
+	return c.login, nil
+}
+
 func (c *Client) GetCurrentBranchPR() (int, error) {
+	// Without gh, only the branch's remotes can find the PR
+	if c.token != "" {
+		prNumber, err := c.findForkPR()
+		if err != nil {
+			c.debugLog("Remote-based PR detection failed: %v", err)
+			return 0, errors.New("no PR found for current branch: with GH_TOKEN or GITHUB_TOKEN set, gh isn't used to look it up; pass --repo OWNER/REPO and a PR number")
+		}
+		return prNumber, nil
+	}
+
 	stdOut, _, err := gh.Exec("pr", "view", "--json", "number", "--jq", ".number")
 	if err == nil {
 		var prNumber int
@@ -386,25 +651,21 @@ func (c *Client) findForkPR() (int, error) {
 		c.debugLog("Trying fork PR detection: head=%s:%s in repo %s", owner, branch, repo)
 
 		// Query REST API for PRs with this fork-qualified head ref
-		stdOut, _, err := gh.Exec("api",
-			fmt.Sprintf("repos/%s/pulls?head=%s:%s&state=open", repo, owner, branch),
-			"--jq", ".[0].number")
+		stdOut, _, err := c.ghAPI(
+			fmt.Sprintf("repos/%s/pulls?head=%s:%s&state=open", repo, owner, branch))
 		if err != nil {
 			continue
 		}
 
-		numStr := strings.TrimSpace(stdOut.String())
-		if numStr == "" || numStr == "null" {
-			continue
+		var prs []struct {
+			Number int `json:"number"`
 		}
-
-		var prNumber int
-		if err := json.Unmarshal([]byte(numStr), &prNumber); err != nil {
+		if err := json.Unmarshal(stdOut.Bytes(), &prs); err != nil {
 			continue
 		}
 
-		if prNumber > 0 {
-			return prNumber, nil
+		if len(prs) > 0 && prs[0].Number > 0 {
+			return prs[0].Number, nil
 		}
 	}
 
@@ -431,6 +692,76 @@ func extractGitHubOwner(remoteURL string) string {
 	return ""
 }
 
+// ParseRepo splits a repository reference into owner and name. It accepts
+// "owner/repo" as well as URLs like https://github.com/owner/repo(.git).
+func ParseRepo(s string) (owner, name string, err error) {
+	ref := strings.TrimSpace(s)
+	if strings.Contains(ref, "://") {
+		u, err := url.Parse(ref)
+		if err != nil || u.Host == "" {
+			return "", "", fmt.Errorf("invalid repository URL %q", s)
+		}
+		ref = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	}
+
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || !validRepoPart(parts[0]) || !validRepoPart(parts[1]) {
+		return "", "", fmt.Errorf("invalid repository %q: expected OWNER/REPO", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+// commentAnchorPrefixes are the URL fragments GitHub links comments with:
+// review comments from the conversation and files tabs, then PR comments
+var commentAnchorPrefixes = []string{"discussion_r", "r", "issuecomment-"}
+
+// ParseCommentURL reads the PR number and comment ID from a comment link
+// copied from GitHub, such as .../owner/repo/pull/123#discussion_r456,
+// .../pull/123/files#r456 or .../pull/123#issuecomment-456
+func ParseCommentURL(rawURL string) (pr int, commentID int64, err error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return 0, 0, fmt.Errorf("invalid comment URL %q", rawURL)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return 0, 0, fmt.Errorf("invalid comment URL %q: expected an http(s) link", rawURL)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[2] != "pull" {
+		return 0, 0, fmt.Errorf("invalid comment URL %q: expected a pull request link", rawURL)
+	}
+	if _, _, err := ParseRepo(parts[0] + "/" + parts[1]); err != nil {
+		return 0, 0, fmt.Errorf("invalid comment URL %q: %w", rawURL, err)
+	}
+	pr, err = strconv.Atoi(parts[3])
+	if err != nil || pr <= 0 {
+		return 0, 0, fmt.Errorf("invalid comment URL %q: bad PR number %q", rawURL, parts[3])
+	}
+
+	for _, prefix := range commentAnchorPrefixes {
+		if digits, ok := strings.CutPrefix(u.Fragment, prefix); ok {
+			if id, err := strconv.ParseInt(digits, 10, 64); err == nil && id > 0 {
+				return pr, id, nil
+			}
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid comment URL %q: no comment anchor (#discussion_r..., #r... or #issuecomment-...)", rawURL)
+}
+
+// validRepoPart reports whether s can be a GitHub owner or repository name
+func validRepoPart(s string) bool {
+	if s == "" || s == "." || s == ".." {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
 // ListOpenPRs fetches all open pull requests for the repository
 func (c *Client) ListOpenPRs() ([]*PullRequest, error) {
 	repo, err := c.getRepo()
@@ -460,6 +791,11 @@ func (c *Client) ListOpenPRs() ([]*PullRequest, error) {
 						isDraft
 						headRefName
 						reviewDecision
+						assignees(first: 10) {
+							nodes {
+								login
+							}
+						}
 					}
 				}
 			}
@@ -468,7 +804,7 @@ func (c *Client) ListOpenPRs() ([]*PullRequest, error) {
 
 	c.debugLog("GraphQL query: %s", query)
 
-	stdOut, _, err := gh.Exec("api", "graphql", "-f", fmt.Sprintf("query=%s", query))
+	stdOut, _, err := c.ghAPI("graphql", "-f", fmt.Sprintf("query=%s", query))
 	if err != nil {
 		c.debugLog("GraphQL query failed: %v", err)
 		return nil, fmt.Errorf("failed to fetch pull requests: %w", err)
@@ -489,6 +825,11 @@ func (c *Client) ListOpenPRs() ([]*PullRequest, error) {
 						IsDraft        bool   `json:"isDraft"`
 						HeadRefName    string `json:"headRefName"`
 						ReviewDecision string `json:"reviewDecision"`
+						Assignees      struct {
+							Nodes []struct {
+								Login string `json:"login"`
+							} `json:"nodes"`
+						} `json:"assignees"`
 					} `json:"nodes"`
 				} `json:"pullRequests"`
 			} `json:"repository"`
@@ -505,6 +846,10 @@ func (c *Client) ListOpenPRs() ([]*PullRequest, error) {
 
 	prs := make([]*PullRequest, 0, len(result.Data.Repository.PullRequests.Nodes))
 	for _, node := range result.Data.Repository.PullRequests.Nodes {
+		var assignees []string
+		for _, assignee := range node.Assignees.Nodes {
+			assignees = append(assignees, assignee.Login)
+		}
 		prs = append(prs, &PullRequest{
 			Number:         node.Number,
 			Title:          node.Title,
@@ -512,6 +857,7 @@ func (c *Client) ListOpenPRs() ([]*PullRequest, error) {
 			IsDraft:        node.IsDraft,
 			HeadRefName:    node.HeadRefName,
 			ReviewDecision: node.ReviewDecision,
+			Assignees:      assignees,
 		})
 	}
 
@@ -520,70 +866,54 @@ func (c *Client) ListOpenPRs() ([]*PullRequest, error) {
 	return prs, nil
 }
 
-// DumpCommentsJSON returns raw JSON for the selected comment IDs. When commentIDs is empty, all
-// review comments for the PR are returned.
-func (c *Client) DumpCommentsJSON(prNumber int, commentIDs []int64) (string, error) {
+// FileStat is the size of a file's change in a pull request
+type FileStat struct {
+	Additions int
+	Deletions int
+}
+
+// FetchPRFileStats returns added/removed line counts for each file in the PR
+// diff, keyed by path
+func (c *Client) FetchPRFileStats(prNumber int) (map[string]FileStat, error) {
 	repo, err := c.getRepo()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	query := fmt.Sprintf("repos/%s/pulls/%d/comments", repo, prNumber)
-	stdOut, _, err := gh.Exec("api", query, "--paginate")
+	stdOut, _, err := c.ghAPI(fmt.Sprintf("repos/%s/pulls/%d/files", repo, prNumber), "--paginate")
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch review comments: %w", err)
+		return nil, fmt.Errorf("failed to fetch PR files: %w", err)
 	}
 
-	var rawComments []json.RawMessage
-	if err := json.Unmarshal(stdOut.Bytes(), &rawComments); err != nil {
-		return "", fmt.Errorf("failed to parse comments: %w", err)
-	}
-
-	includeAll := len(commentIDs) == 0
-	wanted := make(map[int64]struct{}, len(commentIDs))
-	for _, id := range commentIDs {
-		wanted[id] = struct{}{}
+	var files []struct {
+		Filename  string `json:"filename"`
+		Additions int    `json:"additions"`
+		Deletions int    `json:"deletions"`
 	}
-
-	selected := make([]json.RawMessage, 0)
-	for _, raw := range rawComments {
-		if includeAll {
-			selected = append(selected, raw)
-			continue
-		}
-
-		var comment struct {
-			ID int64 `json:"id"`
-		}
-		if err := json.Unmarshal(raw, &comment); err != nil {
-			continue
-		}
-		if _, ok := wanted[comment.ID]; ok {
-			selected = append(selected, raw)
-		}
+	if err := json.Unmarshal(stdOut.Bytes(), &files); err != nil {
+		return nil, fmt.Errorf("failed to parse PR files: %w", err)
 	}
 
-	if len(selected) == 0 {
-		return "[]", nil
+	stats := make(map[string]FileStat, len(files))
+	for _, f := range files {
+		stats[f.Filename] = FileStat{Additions: f.Additions, Deletions: f.Deletions}
 	}
+	return stats, nil
+}
 
-	var rawBuffer bytes.Buffer
-	rawBuffer.WriteByte('[')
-	for i, raw := range selected {
-		rawBuffer.Write(raw)
-		if i < len(selected)-1 {
-			rawBuffer.WriteByte(',')
-		}
+// FetchPRDiff returns the unified diff of the whole pull request
+func (c *Client) FetchPRDiff(prNumber int) (string, error) {
+	repo, err := c.getRepo()
+	if err != nil {
+		return "", err
 	}
-	rawBuffer.WriteByte(']')
 
-	var pretty bytes.Buffer
-	if err := json.Indent(&pretty, rawBuffer.Bytes(), "", "  "); err != nil {
-		// If pretty printing fails, return the raw buffer contents
-		return rawBuffer.String(), nil
+	stdOut, _, err := c.ghAPI(fmt.Sprintf("repos/%s/pulls/%d", repo, prNumber),
+		"-H", "Accept: application/vnd.github.diff")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch PR diff: %w", err)
 	}
-
-	return pretty.String(), nil
+	return stdOut.String(), nil
 }
 
 func (c *Client) FetchReviewComments(prNumber int) ([]*ReviewComment, error) {
@@ -594,6 +924,10 @@ func (c *Client) FetchReviewComments(prNumber int) ([]*ReviewComment, error) {
 
 	// First, get review threads with all comments using GraphQL
 	reviewThreads, err := c.getReviewThreads(repo, prNumber)
+	if errors.Is(err, errIncompleteThreads) {
+		// Showing the threads without some of their replies would mislead
+		return nil, err
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Could not fetch review threads: %v\n", err)
 		reviewThreads = make(map[int64]*ThreadInfo)
@@ -601,23 +935,27 @@ func (c *Client) FetchReviewComments(prNumber int) ([]*ReviewComment, error) {
 
 	// Fetch review comments using gh api
 	query := fmt.Sprintf("repos/%s/pulls/%d/comments", repo, prNumber)
-	stdOut, _, err := gh.Exec("api", query, "--paginate")
+	stdOut, _, err := c.ghAPI(query, "--paginate")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch review comments: %w", err)
 	}
 
 	var rawComments []struct {
 		ID        int64  `json:"id"`
+		NodeID    string `json:"node_id"`
+		ReviewID  int64  `json:"pull_request_review_id"`
 		Path      string `json:"path"`
 		Line      int    `json:"line"`
 		StartLine int    `json:"start_line"`
 		Body      string `json:"body"`
 		DiffHunk  string `json:"diff_hunk"`
 		HTMLURL   string `json:"html_url"`
+		CommitID  string `json:"commit_id"`
 		Side      string `json:"side"`
 		User      struct {
 			Login string `json:"login"`
 		} `json:"user"`
+		OriginalCommitID  string    `json:"original_commit_id"`
 		OriginalLine      int       `json:"original_line"`
 		OriginalStartLine int       `json:"original_start_line"`
 		SubjectType       string    `json:"subject_type"`
@@ -646,7 +984,7 @@ func (c *Client) FetchReviewComments(prNumber int) ([]*ReviewComment, error) {
 		threadInfo := reviewThreads[raw.ID]
 		subjectType := raw.SubjectType
 		var threadComments []ThreadComment
-		var threadID string
+		var threadID, resolvedBy string
 
 		if threadInfo != nil {
 			c.debugLog("Comment %d: Found thread with %d total comments, resolved=%v",
@@ -654,6 +992,7 @@ func (c *Client) FetchReviewComments(prNumber int) ([]*ReviewComment, error) {
 			threadID = threadInfo.ID
 			if threadInfo.IsResolved {
 				subjectType = "resolved"
+				resolvedBy = threadInfo.ResolvedBy
 			}
 			// Skip the first comment (it's the main review comment we're already showing)
 			if len(threadInfo.Comments) > 1 {
@@ -699,7 +1038,9 @@ func (c *Client) FetchReviewComments(prNumber int) ([]*ReviewComment, error) {
 
 		comment := &ReviewComment{
 			ID:                raw.ID,
+			NodeID:            raw.NodeID,
 			ThreadID:          threadID,
+			ReviewID:          raw.ReviewID,
 			Path:              raw.Path,
 			Line:              raw.Line,
 			StartLine:         startLine,
@@ -713,6 +1054,9 @@ func (c *Client) FetchReviewComments(prNumber int) ([]*ReviewComment, error) {
 			OriginalEndLine:   originalEndLine,
 			SubjectType:       subjectType,
 			HTMLURL:           raw.HTMLURL,
+			HeadSHA:           raw.CommitID,
+			OriginalCommitID:  raw.OriginalCommitID,
+			ResolvedBy:        resolvedBy,
 			CreatedAt:         raw.CreatedAt,
 			IsOutdated:        isOutdated,
 			Reactions:         raw.Reactions,
@@ -734,6 +1078,238 @@ func (c *Client) FetchReviewComments(prNumber int) ([]*ReviewComment, error) {
 	return comments, nil
 }
 
+// FetchAllComments returns the PR's inline review comments plus the other
+// kinds listed in include (review summaries and/or issue comments). Those
+// have no Path or Line.
+func (c *Client) FetchAllComments(prNumber int, include []CommentKind) ([]*ReviewComment, error) {
+	comments, err := c.FetchReviewComments(prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := c.getRepo()
+	if err != nil {
+		return nil, err
+	}
+	for _, kind := range include {
+		var extra []*ReviewComment
+		switch kind {
+		case CommentKindSummary:
+			extra, err = c.fetchReviewSummaries(repo, prNumber)
+		case CommentKindIssue:
+			extra, err = c.fetchIssueComments(repo, prNumber)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, extra...)
+	}
+	return comments, nil
+}
+
+// FetchReviews returns the PR's submitted reviews, oldest first, with their
+// state and how many inline comments each carried
+func (c *Client) FetchReviews(prNumber int) ([]*Review, error) {
+	repo, err := c.getRepo()
+	if err != nil {
+		return nil, err
+	}
+	owner, name, err := ParseRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	c.debugLog("Fetching reviews for %s PR #%d", repo, prNumber)
+
+	query := `query Reviews($owner: String!, $name: String!, $number: Int!, $after: String) {
+		repository(owner: $owner, name: $name) {
+			pullRequest(number: $number) {
+				reviews(first: 100, after: $after) {
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+					nodes {
+						databaseId
+						state
+						body
+						url
+						submittedAt
+						author {
+							login
+						}
+						comments {
+							totalCount
+						}
+					}
+				}
+			}
+		}
+	}`
+
+	var reviews []*Review
+	after := ""
+	for {
+		args := []string{"graphql",
+			"-f", fmt.Sprintf("query=%s", query),
+			"-f", fmt.Sprintf("owner=%s", owner),
+			"-f", fmt.Sprintf("name=%s", name),
+			"-F", fmt.Sprintf("number=%d", prNumber)}
+		if after != "" {
+			args = append(args, "-f", fmt.Sprintf("after=%s", after))
+		}
+		stdOut, _, err := c.ghAPI(args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+		}
+
+		var result struct {
+			Data struct {
+				Repository struct {
+					PullRequest struct {
+						Reviews struct {
+							PageInfo struct {
+								HasNextPage bool   `json:"hasNextPage"`
+								EndCursor   string `json:"endCursor"`
+							} `json:"pageInfo"`
+							Nodes []struct {
+								DatabaseID  int64     `json:"databaseId"`
+								State       string    `json:"state"`
+								Body        string    `json:"body"`
+								URL         string    `json:"url"`
+								SubmittedAt time.Time `json:"submittedAt"`
+								Author      struct {
+									Login string `json:"login"`
+								} `json:"author"`
+								Comments struct {
+									TotalCount int `json:"totalCount"`
+								} `json:"comments"`
+							} `json:"nodes"`
+						} `json:"reviews"`
+					} `json:"pullRequest"`
+				} `json:"repository"`
+			} `json:"data"`
+			Errors []struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		if err := json.Unmarshal(stdOut.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("failed to parse reviews: %w", err)
+		}
+		if len(result.Errors) > 0 {
+			return nil, newGraphQLError(result.Errors[0].Type, result.Errors[0].Message)
+		}
+
+		page := result.Data.Repository.PullRequest.Reviews
+		for _, node := range page.Nodes {
+			// Pending reviews are drafts only their author can see
+			if node.State == "PENDING" {
+				continue
+			}
+			reviews = append(reviews, &Review{
+				ID:           node.DatabaseID,
+				State:        node.State,
+				Author:       node.Author.Login,
+				Body:         node.Body,
+				HTMLURL:      node.URL,
+				SubmittedAt:  node.SubmittedAt,
+				CommentCount: node.Comments.TotalCount,
+			})
+		}
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			break
+		}
+		after = page.PageInfo.EndCursor
+	}
+
+	c.debugLog("Found %d reviews", len(reviews))
+	return reviews, nil
+}
+
+// fetchReviewSummaries returns the bodies of submitted reviews, skipping
+// reviews submitted without one (e.g. a bare approval)
+func (c *Client) fetchReviewSummaries(repo string, prNumber int) ([]*ReviewComment, error) {
+	stdOut, _, err := c.ghAPI(fmt.Sprintf("repos/%s/pulls/%d/reviews", repo, prNumber), "--paginate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+
+	var rawReviews []struct {
+		ID      int64  `json:"id"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+		State   string `json:"state"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		CommitID    string    `json:"commit_id"`
+		SubmittedAt time.Time `json:"submitted_at"`
+	}
+	if err := json.Unmarshal(stdOut.Bytes(), &rawReviews); err != nil {
+		return nil, fmt.Errorf("failed to parse reviews: %w", err)
+	}
+
+	c.debugLog("Processing %d reviews from REST API", len(rawReviews))
+
+	comments := make([]*ReviewComment, 0, len(rawReviews))
+	for _, raw := range rawReviews {
+		if strings.TrimSpace(raw.Body) == "" || raw.State == "PENDING" {
+			continue
+		}
+		comments = append(comments, &ReviewComment{
+			ID:        raw.ID,
+			Kind:      CommentKindSummary,
+			Body:      raw.Body,
+			Author:    raw.User.Login,
+			HTMLURL:   raw.HTMLURL,
+			HeadSHA:   raw.CommitID,
+			CreatedAt: raw.SubmittedAt,
+		})
+	}
+	return comments, nil
+}
+
+// fetchIssueComments returns the comments on the PR's conversation tab
+func (c *Client) fetchIssueComments(repo string, prNumber int) ([]*ReviewComment, error) {
+	stdOut, _, err := c.ghAPI(fmt.Sprintf("repos/%s/issues/%d/comments", repo, prNumber), "--paginate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue comments: %w", err)
+	}
+
+	var rawComments []struct {
+		ID      int64  `json:"id"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		CreatedAt time.Time `json:"created_at"`
+		Reactions Reactions `json:"reactions"`
+	}
+	if err := json.Unmarshal(stdOut.Bytes(), &rawComments); err != nil {
+		return nil, fmt.Errorf("failed to parse issue comments: %w", err)
+	}
+
+	c.debugLog("Processing %d issue comments from REST API", len(rawComments))
+
+	comments := make([]*ReviewComment, 0, len(rawComments))
+	for _, raw := range rawComments {
+		comments = append(comments, &ReviewComment{
+			ID:        raw.ID,
+			Kind:      CommentKindIssue,
+			Body:      raw.Body,
+			Author:    raw.User.Login,
+			HTMLURL:   raw.HTMLURL,
+			CreatedAt: raw.CreatedAt,
+			Reactions: raw.Reactions,
+		})
+	}
+	return comments, nil
+}
+
 // calculateOriginalLines determines how many lines from the original file
 // should be replaced based on the diff hunk
 func calculateOriginalLines(diffHunk string) int {
@@ -774,7 +1350,7 @@ func (c *Client) ResolveThread(threadID string) error {
 
 	c.debugLog("GraphQL mutation: %s (threadId=%s)", mutation, threadID)
 
-	stdOut, stdErr, err := gh.Exec("api", "graphql",
+	stdOut, stdErr, err := c.ghAPI("graphql",
 		"-f", fmt.Sprintf("query=%s", mutation),
 		"-F", fmt.Sprintf("threadId=%s", threadID))
 	if err != nil {
@@ -798,6 +1374,7 @@ func (c *Client) ResolveThread(threadID string) error {
 			} `json:"resolveReviewThread"`
 		} `json:"data"`
 		Errors []struct {
+			Type    string `json:"type"`
 			Message string `json:"message"`
 		} `json:"errors"`
 	}
@@ -811,7 +1388,7 @@ func (c *Client) ResolveThread(threadID string) error {
 	}
 
 	if len(result.Errors) > 0 {
-		return fmt.Errorf("GraphQL error: %s", result.Errors[0].Message)
+		return newGraphQLError(result.Errors[0].Type, result.Errors[0].Message)
 	}
 
 	if !result.Data.ResolveReviewThread.Thread.IsResolved {
@@ -843,7 +1420,7 @@ func (c *Client) UnresolveThread(threadID string) error {
 
 	c.debugLog("GraphQL mutation: %s", mutation)
 
-	stdOut, stdErr, err := gh.Exec("api", "graphql", "-f", fmt.Sprintf("query=%s", mutation))
+	stdOut, stdErr, err := c.ghAPI("graphql", "-f", fmt.Sprintf("query=%s", mutation))
 	if err != nil {
 		c.debugLog("GraphQL mutation failed: %v", err)
 		if stdErr.Len() > 0 {
@@ -865,6 +1442,7 @@ func (c *Client) UnresolveThread(threadID string) error {
 			} `json:"unresolveReviewThread"`
 		} `json:"data"`
 		Errors []struct {
+			Type    string `json:"type"`
 			Message string `json:"message"`
 		} `json:"errors"`
 	}
@@ -875,7 +1453,7 @@ func (c *Client) UnresolveThread(threadID string) error {
 	}
 
 	if len(result.Errors) > 0 {
-		return fmt.Errorf("GraphQL error: %s", result.Errors[0].Message)
+		return newGraphQLError(result.Errors[0].Type, result.Errors[0].Message)
 	}
 
 	if result.Data.UnresolveReviewThread.Thread.IsResolved {
@@ -886,6 +1464,97 @@ func (c *Client) UnresolveThread(threadID string) error {
 	return nil
 }
 
+// FindThreadID looks up the review thread a comment starts by the comment's
+// GraphQL node ID. FetchReviewComments only reads the first 100 threads, so
+// comments on busier PRs come back without a ThreadID; this finds theirs.
+func (c *Client) FindThreadID(commentNodeID string) (string, error) {
+	if commentNodeID == "" {
+		return "", fmt.Errorf("comment node ID is required")
+	}
+
+	c.debugLog("Looking up thread for comment node %s", commentNodeID)
+
+	query := `query FindThread($id: ID!, $after: String) {
+		node(id: $id) {
+			... on PullRequestReviewComment {
+				pullRequest {
+					reviewThreads(first: 100, after: $after) {
+						pageInfo {
+							hasNextPage
+							endCursor
+						}
+						nodes {
+							id
+							comments(first: 1) {
+								nodes {
+									id
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`
+
+	after := ""
+	for {
+		args := []string{"graphql", "-f", fmt.Sprintf("query=%s", query), "-f", fmt.Sprintf("id=%s", commentNodeID)}
+		if after != "" {
+			args = append(args, "-f", fmt.Sprintf("after=%s", after))
+		}
+		stdOut, _, err := c.ghAPI(args...)
+		if err != nil {
+			return "", fmt.Errorf("failed to look up thread: %w", err)
+		}
+
+		var result struct {
+			Data struct {
+				Node struct {
+					PullRequest struct {
+						ReviewThreads struct {
+							PageInfo struct {
+								HasNextPage bool   `json:"hasNextPage"`
+								EndCursor   string `json:"endCursor"`
+							} `json:"pageInfo"`
+							Nodes []struct {
+								ID       string `json:"id"`
+								Comments struct {
+									Nodes []struct {
+										ID string `json:"id"`
+									} `json:"nodes"`
+								} `json:"comments"`
+							} `json:"nodes"`
+						} `json:"reviewThreads"`
+					} `json:"pullRequest"`
+				} `json:"node"`
+			} `json:"data"`
+			Errors []struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		if err := json.Unmarshal(stdOut.Bytes(), &result); err != nil {
+			return "", fmt.Errorf("failed to parse response: %w", err)
+		}
+		if len(result.Errors) > 0 {
+			return "", newGraphQLError(result.Errors[0].Type, result.Errors[0].Message)
+		}
+
+		threads := result.Data.Node.PullRequest.ReviewThreads
+		for _, thread := range threads.Nodes {
+			if len(thread.Comments.Nodes) > 0 && thread.Comments.Nodes[0].ID == commentNodeID {
+				c.debugLog("Comment node %s is in thread %s", commentNodeID, thread.ID)
+				return thread.ID, nil
+			}
+		}
+		if !threads.PageInfo.HasNextPage || threads.PageInfo.EndCursor == "" {
+			return "", fmt.Errorf("no review thread found for comment")
+		}
+		after = threads.PageInfo.EndCursor
+	}
+}
+
 // ReplyToReviewComment posts a reply to an existing pull request review comment.
 func (c *Client) ReplyToReviewComment(prNumber int, commentID int64, body string) (*ThreadComment, error) {
 	if commentID == 0 {
@@ -919,7 +1588,10 @@ func (c *Client) ReplyToReviewComment(prNumber int, commentID int64, body string
 		return nil, fmt.Errorf("failed to close temporary file: %w", err)
 	}
 
-	stdOut, stdErr, err := gh.Exec("api", endpoint, "-X", "POST", "-F", fmt.Sprintf("body=@%s", tmpFile.Name()))
+	// Replies aren't idempotent, so only retry when GitHub rejected the request outright
+	stdOut, stdErr, err := c.ghAPIWithPolicy(func(apiErr *APIError) bool {
+		return apiErr.StatusCode == http.StatusTooManyRequests
+	}, endpoint, "-X", "POST", "-F", fmt.Sprintf("body=@%s", tmpFile.Name()))
 	if err != nil {
 		c.debugLog("Failed to post review comment reply: %v", err)
 		if stdErr.Len() > 0 {
@@ -956,6 +1628,193 @@ func (c *Client) ReplyToReviewComment(prNumber int, commentID int64, body string
 	}, nil
 }
 
+// DraftReply is a reply held back to be posted with others as one review
+type DraftReply struct {
+	ThreadID string // GraphQL node ID of the thread replied to
+	Body     string
+}
+
+// SubmitDraftReplies posts drafts as one review, the way GitHub's "Start a
+// review" batches replies: a pending review is created, each reply is added
+// to it, and it is submitted as a comment review. If a reply can't be added,
+// the pending review is deleted so nothing is half-posted. It returns the
+// posted replies, in the order of drafts, and the review's URL.
+func (c *Client) SubmitDraftReplies(prNumber int, drafts []DraftReply) ([]ThreadComment, string, error) {
+	if len(drafts) == 0 {
+		return nil, "", fmt.Errorf("no draft replies to submit")
+	}
+	pullRequestID, err := c.pullRequestNodeID(prNumber)
+	if err != nil {
+		return nil, "", err
+	}
+
+	c.debugLog("Submitting %d draft replies as a review on PR #%d", len(drafts), prNumber)
+
+	var started struct {
+		AddPullRequestReview struct {
+			PullRequestReview struct {
+				ID string `json:"id"`
+			} `json:"pullRequestReview"`
+		} `json:"addPullRequestReview"`
+	}
+	err = c.graphQLMutation("start review", `mutation StartReview($pullRequestId: ID!) {
+		addPullRequestReview(input: {pullRequestId: $pullRequestId}) {
+			pullRequestReview {
+				id
+			}
+		}
+	}`, []string{"pullRequestId=" + pullRequestID}, &started)
+	if err != nil {
+		return nil, "", err
+	}
+	reviewID := started.AddPullRequestReview.PullRequestReview.ID
+
+	replies := make([]ThreadComment, 0, len(drafts))
+	for _, draft := range drafts {
+		var added struct {
+			AddPullRequestReviewThreadReply struct {
+				Comment graphQLThreadComment `json:"comment"`
+			} `json:"addPullRequestReviewThreadReply"`
+		}
+		err := c.graphQLMutation("add reply", fmt.Sprintf(`mutation AddReply($reviewId: ID!, $threadId: ID!, $body: String!) {
+			addPullRequestReviewThreadReply(input: {pullRequestReviewId: $reviewId, pullRequestReviewThreadId: $threadId, body: $body}) {
+				comment {
+					%s
+				}
+			}
+		}`, threadCommentFields), []string{"reviewId=" + reviewID, "threadId=" + draft.ThreadID, "body=" + draft.Body}, &added)
+		if err != nil {
+			c.deletePendingReview(reviewID)
+			return nil, "", err
+		}
+		replies = append(replies, added.AddPullRequestReviewThreadReply.Comment.toThreadComment())
+	}
+
+	var submitted struct {
+		SubmitPullRequestReview struct {
+			PullRequestReview struct {
+				URL string `json:"url"`
+			} `json:"pullRequestReview"`
+		} `json:"submitPullRequestReview"`
+	}
+	err = c.graphQLMutation("submit review", `mutation SubmitReview($reviewId: ID!) {
+		submitPullRequestReview(input: {pullRequestReviewId: $reviewId, event: COMMENT}) {
+			pullRequestReview {
+				url
+			}
+		}
+	}`, []string{"reviewId=" + reviewID}, &submitted)
+	if err != nil {
+		c.deletePendingReview(reviewID)
+		return nil, "", err
+	}
+
+	c.debugLog("Review submitted with %d replies", len(replies))
+	return replies, submitted.SubmitPullRequestReview.PullRequestReview.URL, nil
+}
+
+// pullRequestNodeID looks up the GraphQL node ID of a pull request
+func (c *Client) pullRequestNodeID(prNumber int) (string, error) {
+	repo, err := c.getRepo()
+	if err != nil {
+		return "", err
+	}
+	owner, name, err := ParseRepo(repo)
+	if err != nil {
+		return "", err
+	}
+
+	query := `query PullRequestID($owner: String!, $name: String!, $number: Int!) {
+		repository(owner: $owner, name: $name) {
+			pullRequest(number: $number) {
+				id
+			}
+		}
+	}`
+	stdOut, _, err := c.ghAPI("graphql",
+		"-f", fmt.Sprintf("query=%s", query),
+		"-f", fmt.Sprintf("owner=%s", owner),
+		"-f", fmt.Sprintf("name=%s", name),
+		"-F", fmt.Sprintf("number=%d", prNumber))
+	if err != nil {
+		return "", fmt.Errorf("failed to look up PR #%d: %w", prNumber, err)
+	}
+
+	var result struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					ID string `json:"id"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(stdOut.Bytes(), &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return "", newGraphQLError(result.Errors[0].Type, result.Errors[0].Message)
+	}
+	if result.Data.Repository.PullRequest.ID == "" {
+		return "", fmt.Errorf("PR #%d not found in %s", prNumber, repo)
+	}
+	return result.Data.Repository.PullRequest.ID, nil
+}
+
+// deletePendingReview discards a pending review after a failed submission.
+// It is best effort: the error that led here is the one worth reporting.
+func (c *Client) deletePendingReview(reviewID string) {
+	err := c.graphQLMutation("delete review", `mutation DeleteReview($reviewId: ID!) {
+		deletePullRequestReview(input: {pullRequestReviewId: $reviewId}) {
+			clientMutationId
+		}
+	}`, []string{"reviewId=" + reviewID}, nil)
+	if err != nil {
+		c.debugLog("Failed to delete pending review %s: %v", reviewID, err)
+	}
+}
+
+// graphQLMutation runs a GraphQL mutation with string variables given as
+// name=value and decodes its data into out (unless nil). Mutations aren't
+// idempotent, so only requests GitHub rejected outright are retried.
+func (c *Client) graphQLMutation(action, mutation string, vars []string, out any) error {
+	args := []string{"graphql", "-f", fmt.Sprintf("query=%s", mutation)}
+	for _, v := range vars {
+		args = append(args, "-f", v)
+	}
+	stdOut, _, err := c.ghAPIWithPolicy(func(apiErr *APIError) bool {
+		return apiErr.StatusCode == http.StatusTooManyRequests
+	}, args...)
+	if err != nil {
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(stdOut.Bytes(), &result); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", action, err)
+	}
+	if len(result.Errors) > 0 {
+		return newGraphQLError(result.Errors[0].Type, result.Errors[0].Message)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", action, err)
+	}
+	return nil
+}
+
 // AddReactionToComment adds an emoji reaction to a review comment.
 // Supported emojis: +1, -1, laugh, confused, heart, hooray, rocket, eyes
 func (c *Client) AddReactionToComment(prNumber int, commentID int64, emoji string) error {
@@ -985,7 +1844,7 @@ func (c *Client) AddReactionToComment(prNumber int, commentID int64, emoji strin
 	}
 
 	endpoint := fmt.Sprintf("repos/%s/pulls/comments/%d/reactions", repo, commentID)
-	stdOut, stdErr, err := gh.Exec("api", endpoint,
+	stdOut, stdErr, err := c.ghAPI(endpoint,
 		"-X", "POST",
 		"--header", "Accept: application/vnd.github.squirrel-girl-preview+json",
 		"--input", tmpFile.Name())
@@ -1008,7 +1867,7 @@ func (c *Client) FetchCommentReactions(prNumber int, commentID int64) (*Reaction
 	c.debugLog("Fetching reactions for comment %d on PR %d", commentID, prNumber)
 
 	endpoint := fmt.Sprintf("repos/%s/pulls/comments/%d/reactions", repo, commentID)
-	stdOut, stdErr, err := gh.Exec("api", endpoint,
+	stdOut, stdErr, err := c.ghAPI(endpoint,
 		"--header", "Accept: application/vnd.github.squirrel-girl-preview+json",
 		"--paginate")
 	if err != nil {
@@ -1,6 +1,11 @@
 package github
 
-import "testing"
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
 
 func TestExtractGitHubOwner(t *testing.T) {
 	tests := []struct {
@@ -49,3 +54,295 @@ func TestExtractGitHubOwner(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRepo(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantOwner string
+		wantName  string
+		wantErr   bool
+	}{
+		{in: "owner/repo", wantOwner: "owner", wantName: "repo"},
+		{in: " my-org/my.repo_2 ", wantOwner: "my-org", wantName: "my.repo_2"},
+		{in: "https://github.com/owner/repo", wantOwner: "owner", wantName: "repo"},
+		{in: "https://github.com/owner/repo.git", wantOwner: "owner", wantName: "repo"},
+		{in: "https://github.com/owner/repo/", wantOwner: "owner", wantName: "repo"},
+		{in: "", wantErr: true},
+		{in: "repo", wantErr: true},
+		{in: "owner/", wantErr: true},
+		{in: "/repo", wantErr: true},
+		{in: "owner/repo/extra", wantErr: true},
+		{in: "owner/re po", wantErr: true},
+		{in: "https://github.com/owner", wantErr: true},
+		{in: "https://github.com/owner/repo/pull/1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			owner, name, err := ParseRepo(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRepo(%q) = %q, %q; want error", tt.in, owner, name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRepo(%q) error: %v", tt.in, err)
+			}
+			if owner != tt.wantOwner || name != tt.wantName {
+				t.Errorf("ParseRepo(%q) = %q, %q; want %q, %q", tt.in, owner, name, tt.wantOwner, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestParseCommentURL(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantPR  int
+		wantID  int64
+		wantErr bool
+	}{
+		{in: "https://github.com/owner/repo/pull/123#discussion_r456", wantPR: 123, wantID: 456},
+		{in: " https://github.com/owner/repo/pull/123/files#r456 ", wantPR: 123, wantID: 456},
+		{in: "https://github.com/owner/repo/pull/7#issuecomment-2001", wantPR: 7, wantID: 2001},
+		{in: "https://ghe.example.com/owner/repo/pull/5#discussion_r6", wantPR: 5, wantID: 6},
+		{in: "ftp://github.com/owner/repo/pull/123#discussion_r456", wantErr: true},
+		{in: "https://github.com/owner/repo/pull/123", wantErr: true},
+		{in: "https://github.com/owner/repo/pull/123#pullrequestreview-9", wantErr: true},
+		{in: "https://github.com/owner/repo/pull/abc#discussion_r456", wantErr: true},
+		{in: "https://github.com/owner/repo/issues/123#issuecomment-456", wantErr: true},
+		{in: "https://github.com/owner/repo/pull/123#discussion_rX", wantErr: true},
+		{in: "owner/repo/pull/123#discussion_r456", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			pr, id, err := ParseCommentURL(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseCommentURL(%q) = %d, %d; want error", tt.in, pr, id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCommentURL(%q) returned error: %v", tt.in, err)
+			}
+			if pr != tt.wantPR || id != tt.wantID {
+				t.Errorf("ParseCommentURL(%q) = %d, %d; want %d, %d", tt.in, pr, id, tt.wantPR, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestFetchPRFileStats(t *testing.T) {
+	var gotArgs []string
+	stubGHExec(t, func(args ...string) (bytes.Buffer, bytes.Buffer, error) {
+		gotArgs = args
+		var out bytes.Buffer
+		out.WriteString(`[{"filename":"main.go","additions":12,"deletions":3},{"filename":"docs/README.md","additions":0,"deletions":7}]`)
+		return out, bytes.Buffer{}, nil
+	})
+
+	client := NewClient()
+	client.SetRepo("owner/repo")
+	stats, err := client.FetchPRFileStats(42)
+	if err != nil {
+		t.Fatalf("FetchPRFileStats returned error: %v", err)
+	}
+
+	if len(gotArgs) < 2 || gotArgs[1] != "repos/owner/repo/pulls/42/files" {
+		t.Errorf("unexpected gh args: %v", gotArgs)
+	}
+	if got := stats["main.go"]; got != (FileStat{Additions: 12, Deletions: 3}) {
+		t.Errorf("main.go stat = %+v", got)
+	}
+	if got := stats["docs/README.md"]; got != (FileStat{Deletions: 7}) {
+		t.Errorf("docs/README.md stat = %+v", got)
+	}
+}
+
+func TestFetchPRDiff(t *testing.T) {
+	var gotArgs []string
+	stubGHExec(t, func(args ...string) (bytes.Buffer, bytes.Buffer, error) {
+		gotArgs = args
+		var out bytes.Buffer
+		out.WriteString("HTTP/2.0 200 OK\r\nContent-Type: text/plain\r\n\r\ndiff --git a/main.go b/main.go\n")
+		return out, bytes.Buffer{}, nil
+	})
+
+	client := NewClient()
+	client.SetRepo("owner/repo")
+	diff, err := client.FetchPRDiff(42)
+	if err != nil {
+		t.Fatalf("FetchPRDiff returned error: %v", err)
+	}
+	if diff != "diff --git a/main.go b/main.go\n" {
+		t.Errorf("unexpected diff %q", diff)
+	}
+	if !slices.Contains(gotArgs, "repos/owner/repo/pulls/42") || !slices.Contains(gotArgs, "Accept: application/vnd.github.diff") {
+		t.Errorf("unexpected gh args: %v", gotArgs)
+	}
+}
+
+func TestFetchAllCommentsIncludesRequestedKinds(t *testing.T) {
+	responses := map[string]string{
+		"repos/owner/repo/pulls/7/comments": `[{"id":1,"path":"main.go","line":3,"body":"inline","user":{"login":"alice"}}]`,
+		"repos/owner/repo/pulls/7/reviews": `[{"id":2,"body":"Looks good overall","state":"COMMENTED","user":{"login":"bob"}},` +
+			`{"id":3,"body":"","state":"APPROVED","user":{"login":"carol"}}]`,
+		"repos/owner/repo/issues/7/comments": `[{"id":4,"body":"CI is red","user":{"login":"dave"}}]`,
+	}
+	stubGHExec(t, func(args ...string) (bytes.Buffer, bytes.Buffer, error) {
+		var out bytes.Buffer
+		if len(args) > 1 {
+			if body, ok := responses[args[1]]; ok {
+				out.WriteString(body)
+				return out, bytes.Buffer{}, nil
+			}
+		}
+		out.WriteString(`{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[]}}}}}`)
+		return out, bytes.Buffer{}, nil
+	})
+
+	client := NewClient()
+	client.SetRepo("owner/repo")
+
+	inline, err := client.FetchAllComments(7, nil)
+	if err != nil {
+		t.Fatalf("FetchAllComments returned error: %v", err)
+	}
+	if len(inline) != 1 || inline[0].Kind != CommentKindInline {
+		t.Fatalf("expected only the inline comment by default, got %+v", inline)
+	}
+
+	all, err := client.FetchAllComments(7, []CommentKind{CommentKindSummary, CommentKindIssue})
+	if err != nil {
+		t.Fatalf("FetchAllComments returned error: %v", err)
+	}
+	var kinds []CommentKind
+	for _, c := range all {
+		kinds = append(kinds, c.Kind)
+	}
+	want := []CommentKind{CommentKindInline, CommentKindSummary, CommentKindIssue}
+	if len(kinds) != len(want) {
+		t.Fatalf("kinds = %v, want %v (empty review bodies are skipped)", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("kinds[%d] = %q, want %q", i, kinds[i], want[i])
+		}
+	}
+	if all[2].Author != "dave" || all[2].Path != "" {
+		t.Errorf("unexpected issue comment: %+v", all[2])
+	}
+}
+
+func TestFindThreadIDPaginates(t *testing.T) {
+	calls := 0
+	stubGHExec(t, func(args ...string) (bytes.Buffer, bytes.Buffer, error) {
+		calls++
+		var out bytes.Buffer
+		if !slices.Contains(args, "after=CUR1") {
+			out.WriteString(`{"data":{"node":{"pullRequest":{"reviewThreads":{"pageInfo":{"hasNextPage":true,"endCursor":"CUR1"},"nodes":[{"id":"PRRT_a","comments":{"nodes":[{"id":"PRRC_a"}]}}]}}}}}`)
+		} else {
+			out.WriteString(`{"data":{"node":{"pullRequest":{"reviewThreads":{"pageInfo":{"hasNextPage":false,"endCursor":"CUR2"},"nodes":[{"id":"PRRT_b","comments":{"nodes":[{"id":"PRRC_b"}]}}]}}}}}`)
+		}
+		return out, bytes.Buffer{}, nil
+	})
+
+	client := NewClient()
+	threadID, err := client.FindThreadID("PRRC_b")
+	if err != nil {
+		t.Fatalf("FindThreadID returned error: %v", err)
+	}
+	if threadID != "PRRT_b" || calls != 2 {
+		t.Errorf("FindThreadID() = %q after %d calls, want PRRT_b after 2", threadID, calls)
+	}
+
+	if _, err := client.FindThreadID("PRRC_missing"); err == nil {
+		t.Error("expected an error when no thread starts with the comment")
+	}
+}
+
+func TestFetchReviews(t *testing.T) {
+	var gotArgs []string
+	stubGHExec(t, func(args ...string) (bytes.Buffer, bytes.Buffer, error) {
+		gotArgs = args
+		var out bytes.Buffer
+		out.WriteString(`{"data":{"repository":{"pullRequest":{"reviews":{"pageInfo":{"hasNextPage":false},"nodes":[` +
+			`{"databaseId":1,"state":"APPROVED","body":"","url":"https://github.com/owner/repo/pull/7#pullrequestreview-1","submittedAt":"2024-01-02T00:00:00Z","author":{"login":"alice"},"comments":{"totalCount":0}},` +
+			`{"databaseId":2,"state":"PENDING","body":"draft","author":{"login":"bob"},"comments":{"totalCount":1}},` +
+			`{"databaseId":3,"state":"CHANGES_REQUESTED","body":"Needs tests","author":{"login":"carol"},"comments":{"totalCount":2}}]}}}}}`)
+		return out, bytes.Buffer{}, nil
+	})
+
+	client := NewClient()
+	client.SetRepo("owner/repo")
+	reviews, err := client.FetchReviews(7)
+	if err != nil {
+		t.Fatalf("FetchReviews returned error: %v", err)
+	}
+	if !slices.Contains(gotArgs, "number=7") || !slices.Contains(gotArgs, "owner=owner") {
+		t.Errorf("expected the PR passed as variables, got %v", gotArgs)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("expected the pending review skipped, got %d reviews", len(reviews))
+	}
+	if r := reviews[1]; r.ID != 3 || r.State != "CHANGES_REQUESTED" || r.Author != "carol" || r.CommentCount != 2 || r.Body != "Needs tests" {
+		t.Errorf("unexpected review %+v", r)
+	}
+}
+
+func TestSubmitDraftReplies(t *testing.T) {
+	var mutations []string
+	stubGHExec(t, func(args ...string) (bytes.Buffer, bytes.Buffer, error) {
+		var out bytes.Buffer
+		query := strings.Join(args, " ")
+		switch {
+		case strings.Contains(query, "query PullRequestID"):
+			out.WriteString(`{"data":{"repository":{"pullRequest":{"id":"PR_1"}}}}`)
+		case strings.Contains(query, "mutation StartReview"):
+			mutations = append(mutations, "start")
+			out.WriteString(`{"data":{"addPullRequestReview":{"pullRequestReview":{"id":"PRR_1"}}}}`)
+		case strings.Contains(query, "mutation AddReply"):
+			mutations = append(mutations, "reply")
+			if slices.Contains(args, "threadId=PRRT_bad") {
+				out.WriteString(`{"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a node"}]}`)
+			} else {
+				out.WriteString(`{"data":{"addPullRequestReviewThreadReply":{"comment":{"databaseId":11,"body":"Done","author":{"login":"me"}}}}}`)
+			}
+		case strings.Contains(query, "mutation SubmitReview"):
+			mutations = append(mutations, "submit")
+			out.WriteString(`{"data":{"submitPullRequestReview":{"pullRequestReview":{"url":"https://github.com/owner/repo/pull/7#pullrequestreview-5"}}}}`)
+		case strings.Contains(query, "mutation DeleteReview"):
+			mutations = append(mutations, "delete")
+			out.WriteString(`{"data":{"deletePullRequestReview":{"clientMutationId":null}}}`)
+		}
+		return out, bytes.Buffer{}, nil
+	})
+
+	client := NewClient()
+	client.SetRepo("owner/repo")
+	replies, url, err := client.SubmitDraftReplies(7, []DraftReply{{ThreadID: "PRRT_a", Body: "Done"}, {ThreadID: "PRRT_b", Body: "Done"}})
+	if err != nil {
+		t.Fatalf("SubmitDraftReplies returned error: %v", err)
+	}
+	if len(replies) != 2 || replies[0].ID != 11 || replies[0].Author != "me" {
+		t.Errorf("unexpected replies %+v", replies)
+	}
+	if url != "https://github.com/owner/repo/pull/7#pullrequestreview-5" {
+		t.Errorf("url = %q", url)
+	}
+	if want := []string{"start", "reply", "reply", "submit"}; !slices.Equal(mutations, want) {
+		t.Errorf("mutations = %v, want %v", mutations, want)
+	}
+
+	mutations = nil
+	if _, _, err := client.SubmitDraftReplies(7, []DraftReply{{ThreadID: "PRRT_bad", Body: "Done"}}); err == nil {
+		t.Fatal("expected an error when a reply can't be added")
+	}
+	if want := []string{"start", "reply", "delete"}; !slices.Equal(mutations, want) {
+		t.Errorf("mutations = %v, want the pending review deleted: %v", mutations, want)
+	}
+}
@@ -0,0 +1,132 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitError is returned when GitHub rejects a request because the API
+// rate limit (primary or secondary) has been exceeded.
+type RateLimitError struct {
+	Reset time.Time // when the limit resets; zero if GitHub didn't say
+	Err   error
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return "rate limited by GitHub, try again later"
+	}
+	return fmt.Sprintf("rate limited, resets in %s", FormatResetIn(e.Reset))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// FormatResetIn formats the time remaining until reset, e.g. "4m" or "30s"
+func FormatResetIn(reset time.Time) string {
+	d := time.Until(reset)
+	switch {
+	case d <= 0:
+		return "0s"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Round(time.Minute).Minutes()))
+	}
+}
+
+// Rate limit resources; GitHub counts REST and GraphQL requests separately
+const (
+	RateLimitCore    = "core"
+	RateLimitGraphQL = "graphql"
+)
+
+// rateLimitState is the last rate limit a response reported for a resource
+type rateLimitState struct {
+	remaining int
+	reset     time.Time
+}
+
+// RateLimit returns the remaining request count and reset time last reported
+// for resource (RateLimitCore or RateLimitGraphQL). remaining is -1 until a
+// response reports it.
+func (c *Client) RateLimit(resource string) (remaining int, reset time.Time) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	state, ok := c.rateLimits[resource]
+	if !ok {
+		return -1, time.Time{}
+	}
+	return state.remaining, state.reset
+}
+
+// recordRateLimit stores X-RateLimit-Remaining/Reset from a response to
+// endpoint under the resource the response names, or the one endpoint
+// counts against when it doesn't say
+func (c *Client) recordRateLimit(endpoint string, header http.Header) {
+	remaining, reset, ok := parseRateLimitHeaders(header)
+	if !ok {
+		return
+	}
+	resource := strings.TrimSpace(header.Get("X-RateLimit-Resource"))
+	if resource == "" {
+		resource = RateLimitCore
+		if endpoint == "graphql" {
+			resource = RateLimitGraphQL
+		}
+	}
+
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	if c.rateLimits == nil {
+		c.rateLimits = make(map[string]rateLimitState)
+	}
+	c.rateLimits[resource] = rateLimitState{remaining: remaining, reset: reset}
+}
+
+// parseRateLimitHeaders extracts the remaining count and reset time from headers
+func parseRateLimitHeaders(header http.Header) (remaining int, reset time.Time, ok bool) {
+	if header == nil {
+		return 0, time.Time{}, false
+	}
+
+	remaining, err := strconv.Atoi(strings.TrimSpace(header.Get("X-RateLimit-Remaining")))
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	if epoch, err := strconv.ParseInt(strings.TrimSpace(header.Get("X-RateLimit-Reset")), 10, 64); err == nil {
+		reset = time.Unix(epoch, 0)
+	}
+	return remaining, reset, true
+}
+
+// newRateLimitError returns a RateLimitError if apiErr was caused by rate
+// limiting, or nil otherwise
+func newRateLimitError(apiErr *APIError, header http.Header) *RateLimitError {
+	limited := false
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests:
+		limited = true
+	case http.StatusForbidden:
+		limited = strings.Contains(strings.ToLower(apiErr.Stderr), "rate limit")
+		if remaining, _, ok := parseRateLimitHeaders(header); ok && remaining == 0 {
+			limited = true
+		}
+	}
+	if !limited {
+		return nil
+	}
+
+	rateErr := &RateLimitError{Err: apiErr}
+	if _, reset, ok := parseRateLimitHeaders(header); ok && !reset.IsZero() {
+		rateErr.Reset = reset
+	} else if apiErr.RetryAfter > 0 {
+		rateErr.Reset = time.Now().Add(apiErr.RetryAfter)
+	}
+	return rateErr
+}
@@ -0,0 +1,98 @@
+package github
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientRateLimit_FromHeaders(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute).Unix()
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		fmt.Fprintf(&stdout, "HTTP/2.0 200 OK\r\nX-Ratelimit-Remaining: 42\r\nX-Ratelimit-Reset: %d\r\n\r\n{}", reset)
+		return stdout, stderr, nil
+	})
+
+	c := NewClient()
+	if remaining, _ := c.RateLimit(RateLimitGraphQL); remaining != -1 {
+		t.Errorf("expected -1 before any response, got %d", remaining)
+	}
+
+	if _, _, err := c.ghAPI("graphql"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remaining, resetAt := c.RateLimit(RateLimitGraphQL)
+	if remaining != 42 {
+		t.Errorf("expected 42 remaining, got %d", remaining)
+	}
+	if resetAt.Unix() != reset {
+		t.Errorf("expected reset %d, got %d", reset, resetAt.Unix())
+	}
+	if remaining, _ := c.RateLimit(RateLimitCore); remaining != -1 {
+		t.Errorf("expected a GraphQL response to leave the REST limit unknown, got %d", remaining)
+	}
+}
+
+func TestClientRateLimit_ResourceHeader(t *testing.T) {
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		stdout.WriteString("HTTP/2.0 200 OK\r\nX-Ratelimit-Remaining: 7\r\nX-Ratelimit-Resource: graphql\r\n\r\n{}")
+		return stdout, stderr, nil
+	})
+
+	c := NewClient()
+	if _, _, err := c.ghAPI("repos/o/r"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if remaining, _ := c.RateLimit(RateLimitGraphQL); remaining != 7 {
+		t.Errorf("expected the resource GitHub names to win over the endpoint, got %d", remaining)
+	}
+}
+
+func TestGhAPI_ReturnsRateLimitError(t *testing.T) {
+	stubSleep(t)
+	reset := time.Now().Add(4 * time.Minute).Unix()
+	calls := 0
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		calls++
+		fmt.Fprintf(&stdout, "HTTP/2.0 403 Forbidden\r\nX-Ratelimit-Remaining: 0\r\nX-Ratelimit-Reset: %d\r\n\r\n{}", reset)
+		stderr.WriteString("gh: API rate limit exceeded for user ID 1. (HTTP 403)")
+		return stdout, stderr, errors.New("exit status 1")
+	})
+
+	c := NewClient()
+	_, _, err := c.ghAPI("graphql")
+
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("expected RateLimitError, got %T: %v", err, err)
+	}
+	if calls != 1 {
+		t.Errorf("primary rate limits should not be retried, got %d calls", calls)
+	}
+	if !strings.Contains(rateErr.Error(), "rate limited, resets in 4m") {
+		t.Errorf("unexpected message: %q", rateErr.Error())
+	}
+}
+
+func TestNewRateLimitError_IgnoresOtherForbidden(t *testing.T) {
+	apiErr := newAPIError(errors.New("exit status 1"), "gh: Resource not accessible by integration (HTTP 403)", nil)
+	if rateErr := newRateLimitError(apiErr, nil); rateErr != nil {
+		t.Errorf("expected nil for non-rate-limit 403, got %v", rateErr)
+	}
+}
+
+func TestFormatResetIn(t *testing.T) {
+	if got := FormatResetIn(time.Now().Add(30 * time.Second)); !strings.HasSuffix(got, "s") {
+		t.Errorf("expected seconds for sub-minute reset, got %q", got)
+	}
+	if got := FormatResetIn(time.Now().Add(4*time.Minute + 10*time.Second)); got != "4m" {
+		t.Errorf("expected 4m, got %q", got)
+	}
+	if got := FormatResetIn(time.Now().Add(-time.Minute)); got != "0s" {
+		t.Errorf("expected 0s for past reset, got %q", got)
+	}
+}
@@ -0,0 +1,318 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2"
+)
+
+// linkNextPattern finds the next page in a Link header
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// defaultMaxRetries is how many times a failed API call is retried by default
+const defaultMaxRetries = 3
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// ghExec and sleep are variables so tests can stub out the gh CLI and waiting
+var (
+	ghExec = gh.Exec
+	sleep  = time.Sleep
+)
+
+// httpStatusPattern matches the status gh prints on failure, e.g. "gh: Not Found (HTTP 404)"
+var httpStatusPattern = regexp.MustCompile(`\(HTTP (\d{3})\)`)
+
+// transientErrorMarkers are stderr fragments that indicate a network blip
+var transientErrorMarkers = []string{
+	"connection reset",
+	"connection refused",
+	"unexpected EOF",
+	"i/o timeout",
+	"TLS handshake timeout",
+	"timeout awaiting response headers",
+}
+
+// APIError describes a failed GitHub API call. Use errors.As to tell
+// failures apart, e.g. a 403 from a token without access to the repository.
+type APIError struct {
+	StatusCode int           // HTTP status (or one mapped from a GraphQL error type); 0 if unknown
+	Message    string        // GitHub's error message, if the response had one
+	DocURL     string        // GitHub's documentation_url for the error, if any
+	RetryAfter time.Duration // from the Retry-After header, if present
+	Stderr     string
+	Retryable  bool
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if msg := strings.TrimSpace(e.Stderr); msg != "" {
+		return fmt.Sprintf("%v: %s", e.Err, msg)
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("%v: %s", e.Err, e.Message)
+	}
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError classifies a gh failure from its stderr and response headers
+func newAPIError(err error, stderr string, header http.Header) *APIError {
+	apiErr := &APIError{Err: err, Stderr: stderr}
+
+	if m := httpStatusPattern.FindStringSubmatch(stderr); m != nil {
+		apiErr.StatusCode, _ = strconv.Atoi(m[1])
+	}
+	if header != nil {
+		if secs, convErr := strconv.Atoi(strings.TrimSpace(header.Get("Retry-After"))); convErr == nil && secs >= 0 {
+			apiErr.RetryAfter = time.Duration(secs) * time.Second
+		}
+	}
+
+	switch {
+	case apiErr.StatusCode == http.StatusTooManyRequests, apiErr.StatusCode >= 500:
+		apiErr.Retryable = true
+	case apiErr.StatusCode == 0:
+		for _, marker := range transientErrorMarkers {
+			if strings.Contains(stderr, marker) {
+				apiErr.Retryable = true
+				break
+			}
+		}
+	}
+
+	return apiErr
+}
+
+// graphQLErrorStatus maps GraphQL error types to the HTTP status REST uses
+var graphQLErrorStatus = map[string]int{
+	"FORBIDDEN": http.StatusForbidden,
+	"NOT_FOUND": http.StatusNotFound,
+}
+
+// readBody fills in Message and DocURL from an error response body, either
+// REST's {"message", "documentation_url"} or GraphQL's {"errors": [...]}
+func (e *APIError) readBody(body []byte) {
+	var parsed struct {
+		Message          string `json:"message"`
+		DocumentationURL string `json:"documentation_url"`
+		Errors           []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &parsed) != nil {
+		return
+	}
+	e.Message, e.DocURL = parsed.Message, parsed.DocumentationURL
+	if e.Message == "" && len(parsed.Errors) > 0 {
+		e.Message = parsed.Errors[0].Message
+		if e.StatusCode == 0 {
+			e.StatusCode = graphQLErrorStatus[parsed.Errors[0].Type]
+		}
+	}
+}
+
+// newGraphQLError describes an error GraphQL reported in a successful response
+func newGraphQLError(errType, message string) *APIError {
+	return &APIError{
+		StatusCode: graphQLErrorStatus[errType],
+		Message:    message,
+		Err:        errors.New("GraphQL error"),
+	}
+}
+
+// doWithRetry calls fn up to attempts times, retrying only errors marked as
+// retryable with exponential backoff (or the server's Retry-After).
+func doWithRetry(fn func() error, attempts int) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.Retryable || attempt == attempts-1 {
+			return err
+		}
+
+		delay := retryBaseDelay << attempt
+		if apiErr.RetryAfter > 0 {
+			delay = apiErr.RetryAfter
+		}
+		if delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+		sleep(delay)
+	}
+
+	return err
+}
+
+// ghAPI runs `gh api` with the given arguments, retrying transient failures.
+// Clients with a token send the same request over HTTPS instead.
+func (c *Client) ghAPI(args ...string) (stdout, stderr bytes.Buffer, err error) {
+	return c.ghAPIWithPolicy(nil, args...)
+}
+
+// ghAPIWithPolicy runs `gh api`, letting retryable narrow which classified
+// failures are retried. Non-idempotent requests use this to retry only when
+// the request was rejected before being processed.
+//
+// With --paginate, pages are requested one at a time by following their
+// Link headers rather than by gh, so every page's rate limit headers are
+// read (and each page is retried on its own). args[0] must be the endpoint.
+func (c *Client) ghAPIWithPolicy(retryable func(*APIError) bool, args ...string) (stdout, stderr bytes.Buffer, err error) {
+	if !containsArg(args, "--paginate") {
+		stdout, stderr, _, err = c.ghAPIPage(retryable, args...)
+		return stdout, stderr, err
+	}
+
+	pageArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "--paginate" {
+			pageArgs = append(pageArgs, arg)
+		}
+	}
+	var pages [][]byte
+	for {
+		page, pageErr, header, err := c.ghAPIPage(retryable, pageArgs...)
+		if err != nil {
+			return page, pageErr, err
+		}
+		pages = append(pages, page.Bytes())
+		next := linkNextPattern.FindStringSubmatch(header.Get("Link"))
+		if next == nil {
+			stdout.Write(mergePages(pages))
+			return stdout, pageErr, nil
+		}
+		pageArgs[0] = next[1]
+	}
+}
+
+// ghAPIPage runs one `gh api` request with --include, retrying transient
+// failures, and returns the response body and headers separately
+func (c *Client) ghAPIPage(retryable func(*APIError) bool, args ...string) (stdout, stderr bytes.Buffer, header http.Header, err error) {
+	fullArgs := append([]string{"api"}, args...)
+	fullArgs = append(fullArgs, "--include")
+
+	attempt := 0
+	err = doWithRetry(func() error {
+		attempt++
+		var execErr error
+		stdout, stderr, execErr = c.runAPI(fullArgs...)
+
+		var body []byte
+		header, body = splitIncludedHeaders(stdout.Bytes())
+		stdout.Reset()
+		stdout.Write(body)
+		c.recordRateLimit(args[0], header)
+
+		if execErr == nil {
+			return nil
+		}
+
+		apiErr := newAPIError(execErr, stderr.String(), header)
+		apiErr.readBody(stdout.Bytes())
+		if rateErr := newRateLimitError(apiErr, header); rateErr != nil {
+			// Primary limits reset too far out to wait for; 429s are still retried
+			apiErr.Retryable = apiErr.Retryable && apiErr.StatusCode == http.StatusTooManyRequests
+			if !apiErr.Retryable {
+				return rateErr
+			}
+		}
+		if retryable != nil {
+			apiErr.Retryable = apiErr.Retryable && retryable(apiErr)
+		}
+		if apiErr.Retryable {
+			c.debugLog("gh api attempt %d failed (status %d), retrying: %v", attempt, apiErr.StatusCode, execErr)
+		}
+		return apiErr
+	}, c.maxRetries+1)
+
+	// Surface exhausted 429 retries as rate limiting too
+	var rateErr *RateLimitError
+	var apiErr *APIError
+	if !errors.As(err, &rateErr) && errors.As(err, &apiErr) {
+		if rateErr := newRateLimitError(apiErr, nil); rateErr != nil {
+			err = rateErr
+		}
+	}
+
+	return stdout, stderr, header, err
+}
+
+// mergePages joins paginated JSON arrays into one, as gh does; anything
+// else is concatenated
+func mergePages(pages [][]byte) []byte {
+	if len(pages) == 1 {
+		return pages[0]
+	}
+	var merged []json.RawMessage
+	for _, page := range pages {
+		var items []json.RawMessage
+		if json.Unmarshal(page, &items) != nil {
+			return bytes.Join(pages, nil)
+		}
+		merged = append(merged, items...)
+	}
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return bytes.Join(pages, nil)
+	}
+	return out
+}
+
+// splitIncludedHeaders separates the HTTP status line and headers printed by
+// `gh api --include` from the response body. Output without headers is
+// returned unchanged.
+func splitIncludedHeaders(out []byte) (http.Header, []byte) {
+	if !bytes.HasPrefix(out, []byte("HTTP/")) {
+		return nil, out
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(out))
+	tp := textproto.NewReader(reader)
+	if _, err := tp.ReadLine(); err != nil {
+		return nil, out
+	}
+	mimeHeader, err := tp.ReadMIMEHeader()
+	if err != nil && mimeHeader == nil {
+		return nil, out
+	}
+
+	var body bytes.Buffer
+	_, _ = body.ReadFrom(reader)
+	return http.Header(mimeHeader), body.Bytes()
+}
+
+// containsArg reports whether args contains flag
+func containsArg(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+package github
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// stubSleep records requested delays instead of waiting
+func stubSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var delays []time.Duration
+	original := sleep
+	sleep = func(d time.Duration) { delays = append(delays, d) }
+	t.Cleanup(func() { sleep = original })
+	return &delays
+}
+
+// stubGHExec replaces the gh CLI with fn for the duration of the test
+func stubGHExec(t testing.TB, fn func(args ...string) (bytes.Buffer, bytes.Buffer, error)) {
+	t.Helper()
+	// A token in the environment would bypass gh
+	for _, name := range tokenEnvVars {
+		t.Setenv(name, "")
+	}
+	original := ghExec
+	ghExec = fn
+	t.Cleanup(func() { ghExec = original })
+}
+
+func TestNewAPIError_Classification(t *testing.T) {
+	tests := []struct {
+		name      string
+		stderr    string
+		status    int
+		retryable bool
+	}{
+		{"server error", "gh: Bad Gateway (HTTP 502)", 502, true},
+		{"rate limited", "gh: API rate limit exceeded (HTTP 429)", 429, true},
+		{"not found fails fast", "gh: Not Found (HTTP 404)", 404, false},
+		{"validation fails fast", "gh: Validation Failed (HTTP 422)", 422, false},
+		{"connection reset", "Post \"https://api.github.com/graphql\": read: connection reset by peer", 0, true},
+		{"unknown failure", "something else went wrong", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newAPIError(errors.New("exit status 1"), tt.stderr, nil)
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Retryable != tt.retryable {
+				t.Errorf("Retryable = %v, want %v", apiErr.Retryable, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestDoWithRetry_RetriesTransientErrors(t *testing.T) {
+	delays := stubSleep(t)
+	calls := 0
+	err := doWithRetry(func() error {
+		calls++
+		if calls < 3 {
+			return &APIError{StatusCode: 503, Retryable: true, Err: errors.New("unavailable")}
+		}
+		return nil
+	}, 4)
+
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+	if len(*delays) != 2 || (*delays)[1] <= (*delays)[0] {
+		t.Errorf("expected two increasing backoff delays, got %v", *delays)
+	}
+}
+
+func TestDoWithRetry_NonRetryableFailsFast(t *testing.T) {
+	delays := stubSleep(t)
+	calls := 0
+	err := doWithRetry(func() error {
+		calls++
+		return &APIError{StatusCode: 404, Err: errors.New("not found")}
+	}, 4)
+
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 || len(*delays) != 0 {
+		t.Errorf("expected a single call without sleeping, got %d calls and delays %v", calls, *delays)
+	}
+}
+
+func TestDoWithRetry_GivesUpAfterAttempts(t *testing.T) {
+	stubSleep(t)
+	calls := 0
+	err := doWithRetry(func() error {
+		calls++
+		return &APIError{StatusCode: 500, Retryable: true, Err: errors.New("boom")}
+	}, 3)
+
+	if err == nil {
+		t.Fatal("expected the last error to be returned")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestDoWithRetry_HonorsRetryAfter(t *testing.T) {
+	delays := stubSleep(t)
+	calls := 0
+	_ = doWithRetry(func() error {
+		calls++
+		if calls == 1 {
+			return &APIError{StatusCode: 429, RetryAfter: 7 * time.Second, Retryable: true, Err: errors.New("slow down")}
+		}
+		return nil
+	}, 2)
+
+	if len(*delays) != 1 || (*delays)[0] != 7*time.Second {
+		t.Errorf("expected a single 7s delay from Retry-After, got %v", *delays)
+	}
+}
+
+func TestGhAPI_StripsHeadersAndRetries(t *testing.T) {
+	stubSleep(t)
+	calls := 0
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		calls++
+		if args[len(args)-1] != "--include" {
+			t.Errorf("expected --include for non-paginated calls, got %v", args)
+		}
+		if calls == 1 {
+			stdout.WriteString("HTTP/2.0 502 Bad Gateway\r\nRetry-After: 2\r\n\r\n{}")
+			stderr.WriteString("gh: Bad Gateway (HTTP 502)")
+			return stdout, stderr, errors.New("exit status 1")
+		}
+		stdout.WriteString("HTTP/2.0 200 OK\r\nContent-Type: application/json\r\n\r\n{\"ok\":true}")
+		return stdout, stderr, nil
+	})
+
+	c := NewClient()
+	stdout, _, err := c.ghAPI("graphql", "-f", "query=x")
+	if err != nil {
+		t.Fatalf("expected success after retry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+	if stdout.String() != `{"ok":true}` {
+		t.Errorf("expected headers stripped from body, got %q", stdout.String())
+	}
+}
+
+func TestGhAPI_ReturnsAPIError(t *testing.T) {
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		stdout.WriteString("HTTP/2.0 403 Forbidden\r\nContent-Type: application/json\r\n\r\n" +
+			`{"message":"Resource not accessible by integration","documentation_url":"https://docs.github.com/rest"}`)
+		stderr.WriteString("gh: Resource not accessible by integration (HTTP 403)")
+		return stdout, stderr, errors.New("exit status 1")
+	})
+
+	c := NewClient()
+	c.SetRepo("o/r")
+	_, err := c.ReplyToReviewComment(1, 2, "hi")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != 403 || apiErr.Message != "Resource not accessible by integration" || apiErr.DocURL != "https://docs.github.com/rest" {
+		t.Errorf("unexpected APIError fields: %+v", apiErr)
+	}
+}
+
+func TestResolveThread_GraphQLErrorIsAPIError(t *testing.T) {
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		stdout.WriteString(`{"data":null,"errors":[{"type":"FORBIDDEN","message":"Must have push access"}]}`)
+		return stdout, stderr, nil
+	})
+
+	err := NewClient().ResolveThread("T_1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 403 || apiErr.Message != "Must have push access" {
+		t.Fatalf("expected a 403 APIError, got %v", err)
+	}
+	if err.Error() != "GraphQL error: Must have push access" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
+
+func TestGhAPI_Hostname(t *testing.T) {
+	var got []string
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		got = args
+		stdout.WriteString("{}")
+		return stdout, stderr, nil
+	})
+
+	c := NewClient()
+	c.SetToken("ghp_a")
+	c.SetHost("ghe.example.com")
+	if c.UsesToken() {
+		t.Error("expected another host to go through gh")
+	}
+	if _, _, err := c.ghAPI("user"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got[len(got)-2:], []string{"--hostname", "ghe.example.com"}) {
+		t.Errorf("expected the host passed to gh, got %v", got)
+	}
+}
+
+func TestGhAPI_PaginatesByLinkHeader(t *testing.T) {
+	var endpoints []string
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		if containsArg(args, "--paginate") || !containsArg(args, "--include") {
+			t.Errorf("expected pages requested one at a time with headers, got %v", args)
+		}
+		endpoints = append(endpoints, args[1])
+		if len(endpoints) == 1 {
+			stdout.WriteString("HTTP/2.0 200 OK\r\nLink: <https://api.github.com/repositories/1/pulls/1/comments?page=2>; rel=\"next\"\r\nX-Ratelimit-Remaining: 50\r\n\r\n[1,2]")
+		} else {
+			stdout.WriteString("HTTP/2.0 200 OK\r\nX-Ratelimit-Remaining: 49\r\n\r\n[3]")
+		}
+		return stdout, stderr, nil
+	})
+
+	c := NewClient()
+	stdout, _, err := c.ghAPI("repos/o/r/pulls/1/comments", "--paginate")
+	if err != nil || stdout.String() != "[1,2,3]" {
+		t.Errorf("unexpected result %q, %v", stdout.String(), err)
+	}
+	if len(endpoints) != 2 || endpoints[1] != "https://api.github.com/repositories/1/pulls/1/comments?page=2" {
+		t.Errorf("expected the second page from the Link header, got %v", endpoints)
+	}
+	if remaining, _ := c.RateLimit(RateLimitCore); remaining != 49 {
+		t.Errorf("expected the last page's rate limit, got %d", remaining)
+	}
+}
+
+func TestSetMaxRetries(t *testing.T) {
+	stubSleep(t)
+	calls := 0
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		calls++
+		stderr.WriteString("gh: Service Unavailable (HTTP 503)")
+		return stdout, stderr, errors.New("exit status 1")
+	})
+
+	c := NewClient()
+	c.SetMaxRetries(1)
+	if _, _, err := c.ghAPI("graphql"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 2 {
+		t.Errorf("expected 1 retry (2 calls), got %d calls", calls)
+	}
+}
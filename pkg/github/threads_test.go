@@ -0,0 +1,192 @@
+package github
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var threadNodePattern = regexp.MustCompile(`node\(id: "T_(\d+)"\)`)
+
+// stubThreadsAPI serves a PR with threadCount threads of commentsPerThread
+// comments each. The initial query only returns each thread's first comment,
+// so every thread needs a follow-up page fetch.
+func stubThreadsAPI(t testing.TB, threadCount, commentsPerThread int, latency time.Duration) *int32 {
+	t.Helper()
+	var inFlight, maxInFlight int32
+
+	commentJSON := func(thread, idx int) string {
+		return fmt.Sprintf(`{"databaseId":%d,"body":"comment %d","url":"","createdAt":"2024-01-01T00:00:00Z","author":{"login":"u"},"reactionGroups":[]}`,
+			thread*1000+idx, idx)
+	}
+
+	stubGHExec(t, func(args ...string) (bytes.Buffer, bytes.Buffer, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(latency)
+
+		var out bytes.Buffer
+		query := strings.Join(args, " ")
+		if m := threadNodePattern.FindStringSubmatch(query); m != nil {
+			var thread int
+			fmt.Sscan(m[1], &thread)
+			nodes := make([]string, 0, commentsPerThread-1)
+			for i := 1; i < commentsPerThread; i++ {
+				nodes = append(nodes, commentJSON(thread, i))
+			}
+			fmt.Fprintf(&out, `{"data":{"node":{"comments":{"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[%s]}}}}`,
+				strings.Join(nodes, ","))
+			return out, bytes.Buffer{}, nil
+		}
+
+		threads := make([]string, 0, threadCount)
+		for i := 0; i < threadCount; i++ {
+			threads = append(threads, fmt.Sprintf(`{"id":"T_%d","isResolved":false,"comments":{"pageInfo":{"hasNextPage":true,"endCursor":"c%d"},"nodes":[%s]}}`,
+				i, i, commentJSON(i, 0)))
+		}
+		fmt.Fprintf(&out, `{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[%s]}}}}}`,
+			strings.Join(threads, ","))
+		return out, bytes.Buffer{}, nil
+	})
+
+	return &maxInFlight
+}
+
+func TestGetReviewThreads_FetchesRemainingPagesInOrder(t *testing.T) {
+	maxInFlight := stubThreadsAPI(t, 20, 5, time.Millisecond)
+
+	client := NewClient()
+	client.SetConcurrency(4)
+	threads, err := client.getReviewThreads("owner/repo", 1)
+	if err != nil {
+		t.Fatalf("getReviewThreads returned error: %v", err)
+	}
+
+	if len(threads) != 20 {
+		t.Fatalf("expected 20 threads, got %d", len(threads))
+	}
+	for i := 0; i < 20; i++ {
+		thread, ok := threads[int64(i*1000)]
+		if !ok {
+			t.Fatalf("missing thread %d", i)
+		}
+		if len(thread.Comments) != 5 {
+			t.Fatalf("thread %d: expected 5 comments, got %d", i, len(thread.Comments))
+		}
+		for j, comment := range thread.Comments {
+			if comment.ID != int64(i*1000+j) {
+				t.Errorf("thread %d comment %d: got ID %d", i, j, comment.ID)
+			}
+		}
+	}
+
+	if got := atomic.LoadInt32(maxInFlight); got > 4 {
+		t.Errorf("expected at most 4 concurrent requests, saw %d", got)
+	}
+}
+
+func TestFetchReviewComments_IncompleteThreads(t *testing.T) {
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		if threadNodePattern.MatchString(strings.Join(args, " ")) {
+			stderr.WriteString("gh: Not Found (HTTP 404)")
+			return stdout, stderr, errors.New("exit status 1")
+		}
+		stdout.WriteString(`{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[` +
+			`{"id":"T_1","isResolved":false,"comments":{"pageInfo":{"hasNextPage":true,"endCursor":"c1"},"nodes":[{"databaseId":1,"author":{"login":"bob"}}]}}` +
+			`]}}}}}`)
+		return stdout, stderr, nil
+	})
+
+	client := NewClient()
+	client.SetRepo("owner/repo")
+	if _, err := client.FetchReviewComments(1); !errors.Is(err, errIncompleteThreads) {
+		t.Errorf("expected an error instead of truncated threads, got %v", err)
+	}
+}
+
+func TestSetConcurrency(t *testing.T) {
+	client := NewClient()
+	if client.concurrency != defaultConcurrency {
+		t.Errorf("expected default concurrency %d, got %d", defaultConcurrency, client.concurrency)
+	}
+	client.SetConcurrency(0)
+	if client.concurrency != 1 {
+		t.Errorf("expected concurrency to be clamped to 1, got %d", client.concurrency)
+	}
+}
+
+// BenchmarkGetReviewThreads measures loading a synthetic 200-comment PR
+// (40 threads of 5 comments) with simulated API latency.
+func BenchmarkGetReviewThreads(b *testing.B) {
+	for _, concurrency := range []int{1, defaultConcurrency} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			stubThreadsAPI(b, 40, 5, 2*time.Millisecond)
+			client := NewClient()
+			client.SetConcurrency(concurrency)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.getReviewThreads("owner/repo", 1); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetReviewThreads_ResolvedBy(t *testing.T) {
+	stubGHExec(t, func(args ...string) (bytes.Buffer, bytes.Buffer, error) {
+		var out bytes.Buffer
+		out.WriteString(`{"data":{"repository":{"pullRequest":{"reviewThreads":{"nodes":[` +
+			`{"id":"T_1","isResolved":true,"resolvedBy":{"login":"alice"},"comments":{"pageInfo":{"hasNextPage":false},"nodes":[{"databaseId":1,"author":{"login":"bob"}}]}},` +
+			`{"id":"T_2","isResolved":false,"resolvedBy":null,"comments":{"pageInfo":{"hasNextPage":false},"nodes":[{"databaseId":2,"author":{"login":"bob"}}]}}` +
+			`]}}}}}`)
+		return out, bytes.Buffer{}, nil
+	})
+
+	threads, err := NewClient().getReviewThreads("owner/repo", 1)
+	if err != nil {
+		t.Fatalf("getReviewThreads returned error: %v", err)
+	}
+	if got := threads[1].ResolvedBy; got != "alice" {
+		t.Errorf("resolved thread ResolvedBy = %q, want %q", got, "alice")
+	}
+	if got := threads[2].ResolvedBy; got != "" {
+		t.Errorf("unresolved thread ResolvedBy = %q, want empty", got)
+	}
+}
+
+func TestCurrentUser_Cached(t *testing.T) {
+	calls := 0
+	stubGHExec(t, func(args ...string) (bytes.Buffer, bytes.Buffer, error) {
+		calls++
+		var out bytes.Buffer
+		out.WriteString(`{"login":"alice"}`)
+		return out, bytes.Buffer{}, nil
+	})
+
+	client := NewClient()
+	for i := 0; i < 2; i++ {
+		login, err := client.CurrentUser()
+		if err != nil {
+			t.Fatalf("CurrentUser returned error: %v", err)
+		}
+		if login != "alice" {
+			t.Errorf("CurrentUser = %q, want %q", login, "alice")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 API call, got %d", calls)
+	}
+}
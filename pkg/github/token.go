@@ -0,0 +1,297 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiBaseURL is the REST root token-authenticated requests go to; GraphQL
+// is at graphql under it. A variable so tests can use a local server.
+var apiBaseURL = "https://api.github.com/"
+
+// tokenHTTPClient sends the requests of clients that have a token
+var tokenHTTPClient = &http.Client{Timeout: 60 * time.Second}
+
+// tokenEnvVars hold a token for github.com, in the order gh reads them
+var tokenEnvVars = []string{"GH_TOKEN", "GITHUB_TOKEN"}
+
+// tokenFromEnv returns the first token set in tokenEnvVars. Like gh, they
+// only apply to github.com, so GH_HOST naming another host turns them off.
+func tokenFromEnv(getenv func(string) string) string {
+	if host := getenv("GH_HOST"); host != "" && host != "github.com" {
+		return ""
+	}
+	for _, name := range tokenEnvVars {
+		if token := strings.TrimSpace(getenv(name)); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// SetToken makes the client call the GitHub API over HTTPS with a personal
+// access token instead of shelling out to gh, so it runs where gh isn't
+// installed. NewClient picks up GH_TOKEN or GITHUB_TOKEN itself; an empty
+// token goes back to gh's own auth.
+func (c *Client) SetToken(token string) {
+	c.token = strings.TrimSpace(token)
+}
+
+// UsesToken reports whether API calls are made with a token rather than gh
+func (c *Client) UsesToken() bool {
+	return c.token != ""
+}
+
+// runAPI runs a `gh api` command line, over HTTPS when the client has a token
+func (c *Client) runAPI(args ...string) (stdout, stderr bytes.Buffer, err error) {
+	if c.token != "" {
+		return c.tokenExec(args...)
+	}
+	if c.host != "" {
+		args = append(args[:len(args):len(args)], "--hostname", c.host)
+	}
+	return ghExec(args...)
+}
+
+// apiRequest is a `gh api` command line parsed for tokenExec
+type apiRequest struct {
+	method   string
+	endpoint string
+	fields   map[string]any
+	header   http.Header
+	input    string
+	include  bool
+}
+
+// parseAPIArgs parses the subset of `gh api` flags the client uses
+func parseAPIArgs(args []string) (*apiRequest, error) {
+	if len(args) == 0 || args[0] != "api" {
+		return nil, fmt.Errorf("unsupported gh command %q", strings.Join(args, " "))
+	}
+	req := &apiRequest{fields: make(map[string]any), header: make(http.Header)}
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--include":
+			req.include = true
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") {
+			req.endpoint = arg
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("flag %s needs a value", arg)
+		}
+		i++
+		value := args[i]
+		switch arg {
+		case "-X", "--method":
+			req.method = value
+		case "-H", "--header":
+			name, val, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header %q", value)
+			}
+			req.header.Add(strings.TrimSpace(name), strings.TrimSpace(val))
+		case "--input":
+			req.input = value
+		case "-f", "--raw-field", "-F", "--field":
+			key, val, ok := strings.Cut(value, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid field %q", value)
+			}
+			if arg == "-f" || arg == "--raw-field" {
+				req.fields[key] = val
+				continue
+			}
+			typed, err := typedFieldValue(val)
+			if err != nil {
+				return nil, err
+			}
+			req.fields[key] = typed
+		default:
+			return nil, fmt.Errorf("unsupported gh api flag %s", arg)
+		}
+	}
+	if req.endpoint == "" {
+		return nil, errors.New("gh api: missing endpoint")
+	}
+	return req, nil
+}
+
+// typedFieldValue converts a -F value the way gh does: @file reads the
+// file, and true, false, null and integers become JSON literals
+func typedFieldValue(value string) (any, error) {
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return string(content), nil
+	}
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, nil
+	}
+	return value, nil
+}
+
+// body encodes the request body: the --input file, or the fields as JSON
+// (GraphQL takes the query on top and everything else as variables)
+func (r *apiRequest) body() ([]byte, error) {
+	if r.input != "" {
+		return os.ReadFile(r.input)
+	}
+	if len(r.fields) == 0 {
+		return nil, nil
+	}
+	if r.endpoint != "graphql" {
+		return json.Marshal(r.fields)
+	}
+	payload := map[string]any{}
+	variables := map[string]any{}
+	for key, value := range r.fields {
+		if key == "query" || key == "operationName" {
+			payload[key] = value
+		} else {
+			variables[key] = value
+		}
+	}
+	if len(variables) > 0 {
+		payload["variables"] = variables
+	}
+	return json.Marshal(payload)
+}
+
+// tokenExec does what `gh api` would for args with the client's token,
+// producing the same stdout, stderr and failure so retries, rate limits
+// and error classification work unchanged
+func (c *Client) tokenExec(args ...string) (stdout, stderr bytes.Buffer, err error) {
+	req, err := parseAPIArgs(args)
+	if err != nil {
+		return stdout, stderr, err
+	}
+	body, err := req.body()
+	if err != nil {
+		return stdout, stderr, err
+	}
+	method := req.method
+	if method == "" {
+		method = http.MethodGet
+		if body != nil {
+			method = http.MethodPost
+		}
+	}
+
+	url := req.endpoint
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		url = apiBaseURL + strings.TrimPrefix(url, "/")
+	}
+
+	c.debugLog("%s %s (token auth)", method, url)
+	resp, respBody, err := c.tokenRequest(method, url, body, req.header)
+	if err != nil {
+		// Network failures keep their text so transient ones are retried
+		stderr.WriteString(err.Error())
+		return stdout, stderr, err
+	}
+
+	writeResponse(&stdout, resp, respBody, req.include)
+	if resp.StatusCode >= 400 {
+		fmt.Fprintf(&stderr, "%s (HTTP %d)\n", responseMessage(resp, respBody), resp.StatusCode)
+		return stdout, stderr, fmt.Errorf("%s %s failed", method, req.endpoint)
+	}
+	if req.endpoint == "graphql" {
+		if messages := graphQLErrorMessages(respBody); len(messages) > 0 {
+			stderr.WriteString(strings.Join(messages, "\n") + "\n")
+			return stdout, stderr, errors.New("GraphQL request failed")
+		}
+	}
+	return stdout, stderr, nil
+}
+
+// tokenRequest sends one request with the token and reads the response
+func (c *Client) tokenRequest(method, url string, body []byte, header http.Header) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	httpReq.Header.Set("Authorization", "token "+c.token)
+	httpReq.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range header {
+		httpReq.Header[name] = values
+	}
+
+	resp, err := tokenHTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, respBody, nil
+}
+
+// writeResponse writes body, after the status line and headers when
+// include is set, as `gh api --include` prints them
+func writeResponse(out *bytes.Buffer, resp *http.Response, body []byte, include bool) {
+	if include {
+		fmt.Fprintf(out, "%s %s\r\n", resp.Proto, resp.Status)
+		_ = resp.Header.Write(out)
+		out.WriteString("\r\n")
+	}
+	out.Write(body)
+}
+
+// responseMessage is GitHub's error message from body, or the status text
+func responseMessage(resp *http.Response, body []byte) string {
+	var parsed struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && parsed.Message != "" {
+		return parsed.Message
+	}
+	return http.StatusText(resp.StatusCode)
+}
+
+// graphQLErrorMessages returns the errors a GraphQL response reported
+func graphQLErrorMessages(body []byte) []string {
+	var parsed struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &parsed) != nil {
+		return nil
+	}
+	messages := make([]string, 0, len(parsed.Errors))
+	for _, e := range parsed.Errors {
+		messages = append(messages, e.Message)
+	}
+	return messages
+}
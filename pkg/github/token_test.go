@@ -0,0 +1,155 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serveAPI points token-authenticated requests at handler for the test
+func serveAPI(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	original := apiBaseURL
+	apiBaseURL = server.URL + "/"
+	t.Cleanup(func() { apiBaseURL = original })
+}
+
+// tokenClient returns a client using token auth that never shells out to gh
+func tokenClient(t *testing.T) *Client {
+	t.Helper()
+	stubGHExec(t, func(args ...string) (stdout, stderr bytes.Buffer, err error) {
+		t.Fatalf("unexpected gh call: %v", args)
+		return
+	})
+	c := NewClient()
+	c.SetToken("test-token")
+	c.SetRepo("owner/repo")
+	return c
+}
+
+func TestTokenFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"none", map[string]string{}, ""},
+		{"GITHUB_TOKEN", map[string]string{"GITHUB_TOKEN": "ghp_b"}, "ghp_b"},
+		{"GH_TOKEN wins", map[string]string{"GH_TOKEN": "ghp_a", "GITHUB_TOKEN": "ghp_b"}, "ghp_a"},
+		{"github.com host", map[string]string{"GH_HOST": "github.com", "GH_TOKEN": "ghp_a"}, "ghp_a"},
+		{"other host", map[string]string{"GH_HOST": "ghe.example.com", "GH_TOKEN": "ghp_a"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenFromEnv(func(name string) string { return tt.env[name] })
+			if got != tt.want {
+				t.Errorf("tokenFromEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenAuthPaginates(t *testing.T) {
+	serveAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token test-token" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		switch r.URL.RawQuery {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=2>; rel="next"`, r.Host, r.URL.Path))
+			fmt.Fprint(w, `[{"filename":"a.go","additions":1,"deletions":2}]`)
+		case "page=2":
+			fmt.Fprint(w, `[{"filename":"b.go","additions":3,"deletions":0}]`)
+		default:
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+	})
+	c := tokenClient(t)
+
+	stats, err := c.FetchPRFileStats(7)
+	if err != nil {
+		t.Fatalf("FetchPRFileStats returned error: %v", err)
+	}
+	if len(stats) != 2 || stats["a.go"].Deletions != 2 || stats["b.go"].Additions != 3 {
+		t.Errorf("expected both pages merged, got %v", stats)
+	}
+}
+
+func TestTokenAuthGraphQLVariables(t *testing.T) {
+	serveAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var payload struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("invalid GraphQL body %s: %v", body, err)
+		}
+		if payload.Query == "" || payload.Variables["threadId"] != "T_1" {
+			t.Errorf("expected the query with threadId as a variable, got %s", body)
+		}
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		fmt.Fprint(w, `{"data":{"resolveReviewThread":{"thread":{"id":"T_1","isResolved":true}}}}`)
+	})
+	c := tokenClient(t)
+
+	if err := c.ResolveThread("T_1"); err != nil {
+		t.Fatalf("ResolveThread returned error: %v", err)
+	}
+	if remaining, _ := c.RateLimit(RateLimitGraphQL); remaining != 4999 {
+		t.Errorf("expected the rate limit from the response headers, got %d", remaining)
+	}
+}
+
+func TestTokenAuthErrorsMatchGH(t *testing.T) {
+	serveAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found","documentation_url":"https://docs.github.com/rest"}`)
+	})
+	c := tokenClient(t)
+
+	_, err := c.FetchPRDiff(1)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "Not Found" || apiErr.Retryable {
+		t.Errorf("expected a non-retryable 404 with GitHub's message, got %+v", apiErr)
+	}
+}
+
+func TestParseAPIArgsRejectsUnknownFlags(t *testing.T) {
+	if _, err := parseAPIArgs([]string{"api", "user", "--jq", ".login"}); err == nil {
+		t.Error("expected --jq to be rejected")
+	}
+	req, err := parseAPIArgs([]string{"api", "repos/o/r/issues/1/comments", "-X", "POST", "-F", "count=3", "-f", "raw=3"})
+	if err != nil {
+		t.Fatalf("parseAPIArgs returned error: %v", err)
+	}
+	if req.method != "POST" || req.fields["count"] != 3 || req.fields["raw"] != "3" {
+		t.Errorf("unexpected parse %+v", req)
+	}
+}
+
+func TestTokenAuthNeedsRepoAndPR(t *testing.T) {
+	c := tokenClient(t)
+	c.SetRepo("")
+
+	if _, err := c.GetRepo(); err == nil || !strings.Contains(err.Error(), "pass --repo") {
+		t.Errorf("expected an error asking for --repo, got %v", err)
+	}
+	if _, err := c.GetCurrentBranchPR(); err == nil || !strings.Contains(err.Error(), "a PR number") {
+		t.Errorf("expected an error asking for a PR number, got %v", err)
+	}
+}
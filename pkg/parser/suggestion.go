@@ -5,8 +5,11 @@ import (
 	"strings"
 )
 
-// Pre-compiled regex for suggestion parsing (avoids recompilation on each call)
-var suggestionRe = regexp.MustCompile("(?s)```suggestion\\s*\\n(.*?)```")
+// Pre-compiled regex for suggestion parsing (avoids recompilation on each call).
+// The opening fence may carry a language hint: ```suggestion yaml
+// The fence may be indented (e.g. inside a list item); the indentation is
+// captured so it can be removed from the suggested lines.
+var suggestionRe = regexp.MustCompile("(?s)(?:(?m:^)([ \t]*))?```suggestion(?:[ \t]+([\\w+#.-]+))?\\s*\\n(.*?)[ \t]*```")
 
 // ParseSuggestion extracts the suggested code from a GitHub review comment body
 // GitHub suggestions are in the format:
@@ -16,11 +19,21 @@ var suggestionRe = regexp.MustCompile("(?s)```suggestion\\s*\\n(.*?)```")
 func ParseSuggestion(body string) string {
 	matches := suggestionRe.FindStringSubmatch(body)
 
-	if len(matches) < 2 {
+	if len(matches) < 4 {
 		return ""
 	}
 
-	return strings.TrimRight(matches[1], "\n")
+	return suggestionCode(matches[1], matches[3])
+}
+
+// ParseSuggestionLanguage returns the language hint on the first suggestion
+// block's opening fence (```suggestion yaml), or "" if it has none
+func ParseSuggestionLanguage(body string) string {
+	matches := suggestionRe.FindStringSubmatch(body)
+	if len(matches) < 4 {
+		return ""
+	}
+	return strings.ToLower(matches[2])
 }
 
 // ParseMultipleSuggestions extracts all suggestions from a comment body
@@ -29,10 +42,30 @@ func ParseMultipleSuggestions(body string) []string {
 
 	suggestions := make([]string, 0, len(matches))
 	for _, match := range matches {
-		if len(match) >= 2 {
-			suggestions = append(suggestions, strings.TrimRight(match[1], "\n"))
+		if len(match) >= 4 {
+			suggestions = append(suggestions, suggestionCode(match[1], match[3]))
 		}
 	}
 
 	return suggestions
 }
+
+// suggestionCode returns the code of a suggestion block whose fence was
+// indented by indent. As in markdown, up to that much leading whitespace is
+// removed from each line, so indentation the code itself has (Python, YAML)
+// is kept.
+func suggestionCode(indent, code string) string {
+	code = strings.TrimRight(code, "\n")
+	if indent == "" {
+		return code
+	}
+	lines := strings.Split(code, "\n")
+	for i, line := range lines {
+		trim := 0
+		for trim < len(indent) && trim < len(line) && (line[trim] == ' ' || line[trim] == '\t') {
+			trim++
+		}
+		lines[i] = line[trim:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -30,6 +30,31 @@ func TestParseSuggestion(t *testing.T) {
 			body:     "Here's an example:\n```go\nfmt.Println(\"test\")\n```",
 			expected: "",
 		},
+		{
+			name:     "suggestion with language hint",
+			body:     "```suggestion yaml\nretries: 3\n```",
+			expected: "retries: 3",
+		},
+		{
+			name:     "indented fence in a list item",
+			body:     "- Return early:\n  ```suggestion\n  def check(x):\n      if not x:\n          return None\n  ```\n- Also rename it",
+			expected: "def check(x):\n    if not x:\n        return None",
+		},
+		{
+			name:     "indented yaml suggestion",
+			body:     "1. Bump it:\n   ```suggestion yaml\n   retries:\n     max: 3\n   ```",
+			expected: "retries:\n  max: 3",
+		},
+		{
+			name:     "tab-indented fence",
+			body:     "\t```suggestion\n\t\tx := 1\n\t```",
+			expected: "\tx := 1",
+		},
+		{
+			name:     "fence after text keeps code as is",
+			body:     "Try ```suggestion\n    x = 1\n```",
+			expected: "    x = 1",
+		},
 		{
 			name:     "multiline suggestion",
 			body:     "```suggestion\nif err != nil {\n    return fmt.Errorf(\"failed: %w\", err)\n}\n```",
@@ -70,3 +95,25 @@ Option 2:
 		t.Errorf("Second suggestion = %q, want %q", suggestions[1], "const timeout = 60")
 	}
 }
+
+func TestParseSuggestionLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"no hint", "```suggestion\nx := 1\n```", ""},
+		{"hint", "```suggestion YAML\nretries: 3\n```", "yaml"},
+		{"hint with trailing space", "```suggestion c++  \nint x;\n```", "c++"},
+		{"indented fence", "- Bump:\n  ```suggestion yaml\n  retries: 3\n  ```", "yaml"},
+		{"no suggestion", "```yaml\nretries: 3\n```", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSuggestionLanguage(tt.body); got != tt.expected {
+				t.Errorf("ParseSuggestionLanguage() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
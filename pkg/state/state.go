@@ -0,0 +1,192 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Tag is a local triage bucket for a comment. Tags never touch GitHub.
+type Tag string
+
+const (
+	TagNone  Tag = ""
+	TagTodo  Tag = "todo"
+	TagDoing Tag = "doing"
+	TagDone  Tag = "done"
+)
+
+// NextTag returns the tag after t in the cycle none → todo → doing → done → none
+func NextTag(t Tag) Tag {
+	switch t {
+	case TagNone:
+		return TagTodo
+	case TagTodo:
+		return TagDoing
+	case TagDoing:
+		return TagDone
+	default:
+		return TagNone
+	}
+}
+
+// Store persists per-PR browse state (collapsed files, read comments, tags) between
+// sessions. Every mutation is written through to disk so state survives the
+// TUI being killed with ctrl+c.
+type Store struct {
+	path string
+	mu   sync.Mutex
+	data storeData
+}
+
+// storeData is the on-disk JSON representation of a Store
+type storeData struct {
+	CollapsedFiles map[string]bool `json:"collapsed_files"`
+	ReadComments   map[int64]bool  `json:"read_comments"`
+	Tags           map[int64]Tag   `json:"tags,omitempty"`
+}
+
+// DefaultPath returns the state file location for a PR:
+// ~/.config/gh-review-conductor/state/<owner>/<repo>/pr-<number>.json
+func DefaultPath(repo string, prNumber int) (string, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid repo format: %s", repo)
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".config", "gh-review-conductor", "state",
+		parts[0], parts[1], fmt.Sprintf("pr-%d.json", prNumber)), nil
+}
+
+// Open loads the store at path. A missing or unreadable file yields an empty
+// store; state is a convenience and must never block browsing.
+func Open(path string) *Store {
+	s := &Store{path: path}
+	if content, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(content, &s.data)
+	}
+	if s.data.CollapsedFiles == nil {
+		s.data.CollapsedFiles = make(map[string]bool)
+	}
+	if s.data.ReadComments == nil {
+		s.data.ReadComments = make(map[int64]bool)
+	}
+	if s.data.Tags == nil {
+		s.data.Tags = make(map[int64]Tag)
+	}
+	return s
+}
+
+// OpenForPR opens the store at DefaultPath for the given repo and PR.
+// If the path cannot be determined, an in-memory store is returned.
+func OpenForPR(repo string, prNumber int) *Store {
+	path, err := DefaultPath(repo, prNumber)
+	if err != nil {
+		return Open("")
+	}
+	return Open(path)
+}
+
+// CollapsedFiles returns a copy of the persisted collapsed-file set
+func (s *Store) CollapsedFiles() map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	collapsed := make(map[string]bool, len(s.data.CollapsedFiles))
+	for path, v := range s.data.CollapsedFiles {
+		if v {
+			collapsed[path] = true
+		}
+	}
+	return collapsed
+}
+
+// SetCollapsed records whether a file header is collapsed
+func (s *Store) SetCollapsed(path string, collapsed bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if collapsed {
+		s.data.CollapsedFiles[path] = true
+	} else {
+		delete(s.data.CollapsedFiles, path)
+	}
+	return s.save()
+}
+
+// MarkRead records that the comment with the given ID has been viewed
+func (s *Store) MarkRead(commentID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.data.ReadComments[commentID] {
+		return nil
+	}
+	s.data.ReadComments[commentID] = true
+	return s.save()
+}
+
+// IsUnread returns true if the comment with the given ID has not been viewed
+func (s *Store) IsUnread(commentID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return !s.data.ReadComments[commentID]
+}
+
+// Tag returns the local tag for the comment with the given ID
+func (s *Store) Tag(commentID int64) Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data.Tags[commentID]
+}
+
+// SetTag records the local tag for a comment; TagNone clears it
+func (s *Store) SetTag(commentID int64, tag Tag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tag == TagNone {
+		delete(s.data.Tags, commentID)
+	} else {
+		s.data.Tags[commentID] = tag
+	}
+	return s.save()
+}
+
+// CycleTag advances a comment's tag with NextTag and returns the new tag
+func (s *Store) CycleTag(commentID int64) (Tag, error) {
+	next := NextTag(s.Tag(commentID))
+	return next, s.SetTag(commentID, next)
+}
+
+// save writes the store to disk. Callers must hold s.mu.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write state file %s: %w", s.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStore_MarkReadPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "pr-1.json")
+
+	s := Open(path)
+	if !s.IsUnread(42) {
+		t.Fatal("expected comment 42 to be unread in a new store")
+	}
+	if err := s.MarkRead(42); err != nil {
+		t.Fatalf("MarkRead returned error: %v", err)
+	}
+	if s.IsUnread(42) {
+		t.Error("expected comment 42 to be read after MarkRead")
+	}
+
+	reopened := Open(path)
+	if reopened.IsUnread(42) {
+		t.Error("expected read state to persist across Open")
+	}
+	if !reopened.IsUnread(43) {
+		t.Error("expected unrelated comment to remain unread")
+	}
+}
+
+func TestStore_CollapsedFilesPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pr-1.json")
+
+	s := Open(path)
+	if err := s.SetCollapsed("main.go", true); err != nil {
+		t.Fatalf("SetCollapsed returned error: %v", err)
+	}
+	if err := s.SetCollapsed("util.go", true); err != nil {
+		t.Fatalf("SetCollapsed returned error: %v", err)
+	}
+	if err := s.SetCollapsed("util.go", false); err != nil {
+		t.Fatalf("SetCollapsed returned error: %v", err)
+	}
+
+	collapsed := Open(path).CollapsedFiles()
+	if !collapsed["main.go"] {
+		t.Error("expected main.go to be collapsed after reopen")
+	}
+	if collapsed["util.go"] {
+		t.Error("expected util.go to be expanded after reopen")
+	}
+}
+
+func TestStore_TagsCycleAndPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pr-1.json")
+
+	s := Open(path)
+	if s.Tag(7) != TagNone {
+		t.Fatalf("expected no tag in a new store, got %q", s.Tag(7))
+	}
+
+	for _, want := range []Tag{TagTodo, TagDoing, TagDone} {
+		got, err := s.CycleTag(7)
+		if err != nil {
+			t.Fatalf("CycleTag returned error: %v", err)
+		}
+		if got != want {
+			t.Fatalf("expected tag %q, got %q", want, got)
+		}
+	}
+
+	if got := Open(path).Tag(7); got != TagDone {
+		t.Errorf("expected tag to persist across Open, got %q", got)
+	}
+
+	if got, _ := s.CycleTag(7); got != TagNone {
+		t.Errorf("expected cycle to wrap to no tag, got %q", got)
+	}
+	if got := Open(path).Tag(7); got != TagNone {
+		t.Errorf("expected cleared tag to persist, got %q", got)
+	}
+}
+
+func TestStore_CorruptFileYieldsEmptyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pr-1.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := Open(path)
+	if !s.IsUnread(1) {
+		t.Error("expected corrupt state file to be treated as empty")
+	}
+	if err := s.MarkRead(1); err != nil {
+		t.Fatalf("MarkRead returned error: %v", err)
+	}
+}
+
+func TestStore_InMemoryWhenPathEmpty(t *testing.T) {
+	s := Open("")
+	if err := s.MarkRead(7); err != nil {
+		t.Fatalf("MarkRead returned error: %v", err)
+	}
+	if s.IsUnread(7) {
+		t.Error("expected in-memory store to track read state")
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	path, err := DefaultPath("owner/repo", 12)
+	if err != nil {
+		t.Fatalf("DefaultPath returned error: %v", err)
+	}
+	if !strings.HasSuffix(path, filepath.Join("gh-review-conductor", "state", "owner", "repo", "pr-12.json")) {
+		t.Errorf("unexpected path: %s", path)
+	}
+
+	if _, err := DefaultPath("invalid", 1); err == nil {
+		t.Error("expected error for invalid repo format")
+	}
+}
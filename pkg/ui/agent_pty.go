@@ -0,0 +1,85 @@
+//go:build !windows
+
+package ui
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/creack/pty"
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+)
+
+// ptyExec runs c on a pseudo-terminal while the TUI is suspended
+func ptyExec(c *exec.Cmd, fn tea.ExecCallback) tea.Cmd {
+	return tea.Exec(&ptyCommand{cmd: c}, fn)
+}
+
+// ptyCommand is a tea.ExecCommand that gives the process a pseudo-terminal
+// sized like the real one, relaying input and output between them
+type ptyCommand struct {
+	cmd    *exec.Cmd
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func (p *ptyCommand) SetStdin(r io.Reader)  { p.stdin = r }
+func (p *ptyCommand) SetStdout(w io.Writer) { p.stdout = w }
+func (p *ptyCommand) SetStderr(io.Writer)   {} // the pty carries stderr with stdout
+
+// Run starts the process and relays until it exits. The real terminal is put
+// in raw mode so keys reach the pty untouched, and restored before returning
+// so bubbletea can take the screen back.
+func (p *ptyCommand) Run() error {
+	if p.stdin == nil {
+		p.stdin = os.Stdin
+	}
+	if p.stdout == nil {
+		p.stdout = os.Stdout
+	}
+
+	// Cancelable so the relay stops reading once the agent exits and the
+	// next keypress goes to the TUI instead of a closed pty
+	input, err := cancelreader.NewReader(p.stdin)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = input.Close() }()
+
+	ptmx, err := pty.Start(p.cmd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ptmx.Close() }()
+
+	if tty, ok := p.stdin.(*os.File); ok && term.IsTerminal(int(tty.Fd())) {
+		_ = pty.InheritSize(tty, ptmx)
+		resize := make(chan os.Signal, 1)
+		signal.Notify(resize, syscall.SIGWINCH)
+		defer func() {
+			signal.Stop(resize)
+			close(resize)
+		}()
+		go func() {
+			for range resize {
+				_ = pty.InheritSize(tty, ptmx)
+			}
+		}()
+
+		if state, err := term.MakeRaw(int(tty.Fd())); err == nil {
+			defer func() { _ = term.Restore(int(tty.Fd()), state) }()
+		}
+	}
+
+	go func() { _, _ = io.Copy(ptmx, input) }()
+	defer input.Cancel()
+
+	// Returns once the agent closes its side of the pty
+	_, _ = io.Copy(p.stdout, ptmx)
+	return p.cmd.Wait()
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package ui
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestPTYCommandRunsOnTerminal(t *testing.T) {
+	var out bytes.Buffer
+	c := &ptyCommand{cmd: exec.Command("sh", "-c", "if test -t 1; then echo on-a-tty; else echo no-tty; fi")}
+	c.SetStdin(strings.NewReader(""))
+	c.SetStdout(&out)
+
+	if err := c.Run(); err != nil {
+		t.Skipf("pty unavailable: %v", err)
+	}
+	if !strings.Contains(out.String(), "on-a-tty") {
+		t.Errorf("Expected the command to see a terminal, got %q", out.String())
+	}
+}
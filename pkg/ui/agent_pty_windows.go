@@ -0,0 +1,12 @@
+package ui
+
+import (
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ptyExec falls back to a plain exec since Windows has no pty support here
+func ptyExec(c *exec.Cmd, fn tea.ExecCallback) tea.Cmd {
+	return tea.ExecProcess(c, fn)
+}
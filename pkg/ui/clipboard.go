@@ -0,0 +1,18 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+)
+
+// clipboardWriteAll is a variable so tests can stub out the system clipboard
+var clipboardWriteAll = clipboard.WriteAll
+
+// CopyToClipboard copies text to the system clipboard
+func CopyToClipboard(text string) error {
+	if err := clipboardWriteAll(text); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return nil
+}
@@ -1,18 +1,25 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"maps"
 	"os"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
 	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
 	"github.com/muesli/termenv"
 )
 
@@ -36,16 +43,72 @@ func SetUIDebug(enabled bool) {
 	uiDebug.Store(enabled)
 }
 
-// Cached glamour renderer for markdown rendering (created once, reused)
+// rendererKey identifies a glamour renderer by its style and wrap width
+type rendererKey struct {
+	theme string
+	width int
+}
+
+// defaultRendererKey is the renderer RenderMarkdown uses
+var defaultRendererKey = rendererKey{theme: "dark", width: 80}
+
+// MarkdownThemes lists the glamour styles comments can be rendered with
+func MarkdownThemes() []string {
+	return slices.Sorted(maps.Keys(glamour.DefaultStyles))
+}
+
+// SetMarkdownTheme selects the glamour style comments are rendered with, e.g.
+// "light" for terminals with a light background. Call it before any rendering.
+func SetMarkdownTheme(theme string) error {
+	if _, ok := glamour.DefaultStyles[theme]; !ok {
+		return fmt.Errorf("unknown theme %q (want one of %s)", theme, strings.Join(MarkdownThemes(), ", "))
+	}
+	defaultRendererKey.theme = theme
+	return nil
+}
+
+// Cached glamour renderers, created on first use of each key and reused.
+// Only a handful of theme/width combinations ever occur, so nothing is
+// evicted except after a timed-out render; a key whose renderer failed to
+// build caches nil.
 var (
-	cachedMarkdownRenderer *glamour.TermRenderer
-	rendererInitOnce       sync.Once
+	markdownRenderers   = map[rendererKey]*glamour.TermRenderer{}
+	markdownRenderersMu sync.Mutex
 )
 
+// markdownRenderTimeout is how long RenderMarkdown waits for glamour before
+// falling back to wrapped plain text, so a pathological comment can't freeze
+// the detail view
+var markdownRenderTimeout = 500 * time.Millisecond
+
+// markdownRenderMu serializes glamour renders on the cached renderers, which
+// keep state between calls. A render abandoned after a timeout may still be
+// running and holding it, so abandonMarkdownRenderers swaps in new renderers
+// with a new lock. Guarded by markdownRenderersMu.
+var markdownRenderMu = &sync.Mutex{}
+
+// glamourRender renders text with r; replaceable in tests
+var glamourRender = func(r *glamour.TermRenderer, text string) (string, error) {
+	return r.Render(text)
+}
+
+// errRenderTimeout is returned by renderWithTimeout when render is too slow
+var errRenderTimeout = errors.New("markdown rendering timed out")
+
 // Pre-compiled regexes for StripSuggestionBlock (avoids recompilation on each call)
 var (
-	suggestionBlockRe = regexp.MustCompile("(?s)```suggestion\\s*\\n.*?```")
+	suggestionBlockRe = regexp.MustCompile("(?s)[ \t]*```suggestion(?:[ \t]+[\\w+#.-]+)?\\s*\\n.*?```")
 	imageMarkdownRe   = regexp.MustCompile(`!\[.*?\]\(.*?\)`)
+	hunkHeaderRe      = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+)
+
+// terminalEscapeRe matches CSI sequences (colors, styles) and OSC sequences
+// (e.g. hyperlinks) so highlighting never splits them
+var terminalEscapeRe = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+const (
+	highlightStart = "\033[7m"  // inverse video on
+	highlightEnd   = "\033[27m" // inverse video off, keeping other attributes
 )
 
 // WarmupMarkdownRenderer initializes the markdown renderer and warms up the
@@ -54,13 +117,16 @@ var (
 func WarmupMarkdownRenderer() {
 	go func() {
 		start := time.Now()
-		// Initialize the renderer (this creates glamour's TermRenderer)
+		// Prime the default renderer (this creates glamour's TermRenderer)
 		r := getMarkdownRenderer()
 		if r != nil {
 			// Warm up chroma's lexers by rendering some code blocks
 			// This triggers lazy initialization of syntax highlighters
+			mu := markdownRenderLock()
+			mu.Lock()
 			_, _ = r.Render("```go\nfunc main() {}\n```")
 			_, _ = r.Render("```js\nconst x = 1;\n```")
+			mu.Unlock()
 		}
 		if uiDebug.Load() {
 			fmt.Fprintf(os.Stderr, "[DEBUG] Markdown warmup completed in %v\n", time.Since(start))
@@ -98,6 +164,45 @@ func Colorize(color, text string) string {
 	return color + text + ColorReset
 }
 
+// HighlightMatches marks case-insensitive occurrences of query in text with
+// inverse video. Only the plain runs between escape sequences are searched,
+// so output from RenderMarkdown or Colorize stays intact. Returns text
+// unchanged when query is empty or colors are disabled.
+func HighlightMatches(text, query string) string {
+	if query == "" || !colorEnabled {
+		return text
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, loc := range terminalEscapeRe.FindAllStringIndex(text, -1) {
+		out.WriteString(highlightPlain(text[last:loc[0]], query))
+		out.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	out.WriteString(highlightPlain(text[last:], query))
+	return out.String()
+}
+
+// highlightPlain highlights query matches in text that contains no escape sequences
+func highlightPlain(text, query string) string {
+	var out strings.Builder
+	start := 0
+	for i := 0; i+len(query) <= len(text); {
+		if strings.EqualFold(text[i:i+len(query)], query) {
+			out.WriteString(text[start:i])
+			out.WriteString(highlightStart + text[i:i+len(query)] + highlightEnd)
+			i += len(query)
+			start = i
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	out.WriteString(text[start:])
+	return out.String()
+}
+
 // FormatDiffWithHeaders prepends git-style --- a/ and +++ b/ headers to a diff hunk.
 // This is useful for displaying diff context with file path information.
 func FormatDiffWithHeaders(diffHunk, path string) string {
@@ -160,12 +265,103 @@ func ColorizeDiff(diff string) string {
 	return strings.Join(coloredLines, "\n")
 }
 
+// ColorizeDiffWithLineNumbers is ColorizeDiff with old and new line numbers
+// in a gutter, counted from each "@@ -a,b +c,d @@" header. Lines before the
+// first header, or after a malformed one, get a blank gutter.
+func ColorizeDiffWithLineNumbers(diff string) string {
+	lines := strings.Split(ColorizeDiff(diff), "\n")
+	for i, gutter := range diffLineGutters(diff) {
+		lines[i] = Colorize(ColorGray, gutter) + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffLineGutters returns the "old new " line number gutter for each line
+func diffLineGutters(diff string) []string {
+	lines := strings.Split(diff, "\n")
+	oldNums := make([]int, len(lines))
+	newNums := make([]int, len(lines))
+
+	oldLine, newLine := 0, 0 // 0 = unknown
+	widest := 0
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			oldLine, newLine = 0, 0
+			if m := hunkHeaderRe.FindStringSubmatch(line); m != nil {
+				oldLine, _ = strconv.Atoi(m[1])
+				newLine, _ = strconv.Atoi(m[2])
+			}
+			continue
+		}
+		if oldLine == 0 && newLine == 0 {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "-"):
+			oldNums[i] = oldLine
+			oldLine++
+		case strings.HasPrefix(line, "+"):
+			newNums[i] = newLine
+			newLine++
+		case strings.HasPrefix(line, "\\"):
+			// "\ No newline at end of file" belongs to neither side
+		default:
+			oldNums[i], newNums[i] = oldLine, newLine
+			oldLine++
+			newLine++
+		}
+		widest = max(widest, oldNums[i], newNums[i])
+	}
+
+	width := len(strconv.Itoa(widest))
+	format := func(n int) string {
+		if n == 0 {
+			return strings.Repeat(" ", width)
+		}
+		return fmt.Sprintf("%*d", width, n)
+	}
+	gutters := make([]string, len(lines))
+	for i := range lines {
+		gutters[i] = format(oldNums[i]) + " " + format(newNums[i]) + " "
+	}
+	return gutters
+}
+
+// ColorizeDiffRange is ColorizeDiffWithLineNumbers with a bar on the last n
+// lines of one side of the hunk, for comments that span a line range. Lines
+// starting with other belong to the opposite side and are neither marked nor
+// counted.
+func ColorizeDiffRange(diff string, n int, other byte) string {
+	lines := strings.Split(ColorizeDiffWithLineNumbers(diff), "\n")
+	raw := strings.Split(diff, "\n")
+
+	inRange := make([]bool, len(raw))
+	for i := len(raw) - 1; i >= 0 && n > 0; i-- {
+		if len(raw[i]) > 0 && (raw[i][0] == other || raw[i][0] == '@') {
+			continue
+		}
+		inRange[i] = true
+		n--
+	}
+
+	for i := range lines {
+		if inRange[i] {
+			lines[i] = Colorize(ColorYellow, "▌") + lines[i]
+		} else {
+			lines[i] = " " + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // ColorizeCode applies syntax highlighting to suggested code
 func ColorizeCode(code string) string {
 	return Colorize(ColorGreen, code)
 }
 
-// CreateHyperlink creates an OSC8 hyperlink
+// CreateHyperlink creates an OSC8 hyperlink, or "text (url)" when the
+// terminal doesn't support hyperlinks (see SetHyperlinksEnabled)
 func CreateHyperlink(url, text string) string {
 	if !colorEnabled {
 		return text
@@ -173,6 +369,12 @@ func CreateHyperlink(url, text string) string {
 	if url == "" {
 		return text
 	}
+	if !hyperlinksEnabled {
+		if text == url {
+			return url
+		}
+		return fmt.Sprintf("%s (%s)", text, url)
+	}
 	return fmt.Sprintf("\033]8;;%s\033\\%s\033]8;;\033\\", url, text)
 }
 
@@ -189,34 +391,76 @@ func StripSuggestionBlock(body string) string {
 	return strings.TrimSpace(result)
 }
 
-// WrapText wraps text to a maximum line width
+// WrapText wraps text to a maximum line width, breaking words (such as
+// long URLs) that don't fit on a line of their own
 func WrapText(text string, width int) string {
-	return wordwrap.String(text, width)
+	lines := strings.Split(wordwrap.String(text, width), "\n")
+	for i, line := range lines {
+		// Only lines still too wide are hard-wrapped; wrap expands tabs
+		if lipgloss.Width(line) > width {
+			lines[i] = wrap.String(line, width)
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
-// getMarkdownRenderer returns a cached glamour renderer, creating it once if needed
+// getMarkdownRenderer returns the cached renderer for the default key
 func getMarkdownRenderer() *glamour.TermRenderer {
-	rendererInitOnce.Do(func() {
-		var start time.Time
-		if uiDebug.Load() {
-			start = time.Now()
-			fmt.Fprintf(os.Stderr, "[DEBUG] Creating glamour renderer...\n")
-		}
-		// Create renderer once and cache it
-		// Use dark style directly instead of WithAutoStyle() which can be slow
-		// due to terminal capability detection
-		r, err := glamour.NewTermRenderer(
-			glamour.WithStandardStyle("dark"),
-			glamour.WithWordWrap(80),
-		)
-		if err == nil {
-			cachedMarkdownRenderer = r
-		}
-		if uiDebug.Load() {
-			fmt.Fprintf(os.Stderr, "[DEBUG] Glamour renderer created in %v\n", time.Since(start))
-		}
-	})
-	return cachedMarkdownRenderer
+	return markdownRenderer(defaultRendererKey)
+}
+
+// markdownRenderLock returns the lock renders on the cached renderers take
+func markdownRenderLock() *sync.Mutex {
+	markdownRenderersMu.Lock()
+	defer markdownRenderersMu.Unlock()
+	return markdownRenderMu
+}
+
+// abandonMarkdownRenderers leaves the cached renderers and their lock to a
+// render that timed out, so later renders get their own instead of waiting
+// on it
+func abandonMarkdownRenderers() {
+	markdownRenderersMu.Lock()
+	defer markdownRenderersMu.Unlock()
+	markdownRenderers = map[rendererKey]*glamour.TermRenderer{}
+	markdownRenderMu = &sync.Mutex{}
+}
+
+// markdownRenderer returns the cached glamour renderer for key, creating it
+// on first use; nil if it couldn't be created
+func markdownRenderer(key rendererKey) *glamour.TermRenderer {
+	r, _ := markdownRendererWithLock(key)
+	return r
+}
+
+// markdownRendererWithLock is markdownRenderer along with the lock to hold
+// while rendering with it
+func markdownRendererWithLock(key rendererKey) (*glamour.TermRenderer, *sync.Mutex) {
+	markdownRenderersMu.Lock()
+	defer markdownRenderersMu.Unlock()
+	if r, ok := markdownRenderers[key]; ok {
+		return r, markdownRenderMu
+	}
+
+	var start time.Time
+	if uiDebug.Load() {
+		start = time.Now()
+		fmt.Fprintf(os.Stderr, "[DEBUG] Creating glamour renderer (%s, width %d)...\n", key.theme, key.width)
+	}
+	// Use a named style directly instead of WithAutoStyle() which can be slow
+	// due to terminal capability detection
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(key.theme),
+		glamour.WithWordWrap(key.width),
+	)
+	if err != nil {
+		r = nil
+	}
+	markdownRenderers[key] = r
+	if uiDebug.Load() {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Glamour renderer created in %v\n", time.Since(start))
+	}
+	return r, markdownRenderMu
 }
 
 // RenderMarkdown renders markdown text with glamour
@@ -229,10 +473,13 @@ func RenderMarkdown(text string) (string, error) {
 		return strings.TrimSpace(text), nil
 	}
 
-	r := getMarkdownRenderer()
+	// Plain text wrapped to a usual width stands in when glamour can't help
+	fallback := WrapText(text, 80)
+
+	r, mu := markdownRendererWithLock(defaultRendererKey)
 	if r == nil {
-		// Fallback to plain text if renderer creation failed
-		return text, nil
+		// Renderer creation failed
+		return fallback, nil
 	}
 
 	var start time.Time
@@ -240,20 +487,52 @@ func RenderMarkdown(text string) (string, error) {
 		start = time.Now()
 	}
 
-	rendered, err := r.Render(text)
+	rendered, err := renderWithTimeout(mu, func() (string, error) {
+		return glamourRender(r, text)
+	}, markdownRenderTimeout)
 
 	if uiDebug.Load() {
-		fmt.Fprintf(os.Stderr, "[DEBUG] RenderMarkdown took %v for %d bytes\n", time.Since(start), len(text))
+		fmt.Fprintf(os.Stderr, "[DEBUG] RenderMarkdown took %v for %d bytes (err: %v)\n", time.Since(start), len(text), err)
 	}
 
+	if errors.Is(err, errRenderTimeout) {
+		abandonMarkdownRenderers()
+	}
 	if err != nil {
-		// Fallback to plain text if rendering fails
-		return text, nil
+		return fallback, nil
 	}
 
 	return strings.TrimSpace(rendered), nil
 }
 
+// renderWithTimeout runs render in the background holding mu, giving up
+// after timeout. A panic in render is returned as an error.
+func renderWithTimeout(mu *sync.Mutex, render func() (string, error), timeout time.Duration) (string, error) {
+	type result struct {
+		rendered string
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		mu.Lock()
+		defer mu.Unlock()
+		defer func() {
+			if p := recover(); p != nil {
+				done <- result{err: fmt.Errorf("markdown rendering panicked: %v", p)}
+			}
+		}()
+		rendered, err := render()
+		done <- result{rendered, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.rendered, res.err
+	case <-time.After(timeout):
+		return "", errRenderTimeout
+	}
+}
+
 // ============================================================================
 // Author Styling
 // ============================================================================
@@ -261,31 +540,80 @@ func RenderMarkdown(text string) (string, error) {
 // AuthorStyle represents styling information for a GitHub author.
 type AuthorStyle struct {
 	Name  string // Author name (without @ symbol)
-	IsBot bool   // True if author name ends with [bot]
-	Color string // ANSI color code (cyan for users, yellow for bots)
+	IsBot bool   // True if IsBotAuthor matches the author
+	Color string // ANSI color code from authorColor
+}
+
+// botAuthorColor is a muted yellow (256-color 136) kept for bots; no human
+// author is hashed onto it
+const botAuthorColor = "\033[38;5;136m"
+
+// authorPalette holds the 256-color indexes authors are hashed onto: the
+// 6x6x6 color cube without its darkest and palest corners, which are hard
+// to read on dark and light backgrounds, and without botAuthorColor
+var authorPalette = func() []int {
+	var palette []int
+	for r := 0; r < 6; r++ {
+		for g := 0; g < 6; g++ {
+			for b := 0; b < 6; b++ {
+				index := 16 + 36*r + 6*g + b
+				if r+g+b < 4 || min(r, g, b) >= 4 || index == 136 {
+					continue
+				}
+				palette = append(palette, index)
+			}
+		}
+	}
+	return palette
+}()
+
+// authorColor returns a stable color for author so a reviewer can be
+// followed through the list: bots share botAuthorColor, and everyone else
+// gets a palette color picked by hashing their login (case-insensitively,
+// like GitHub)
+func authorColor(author string) string {
+	if IsBotAuthor(author) {
+		return botAuthorColor
+	}
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(author)))
+	return fmt.Sprintf("\033[38;5;%dm", authorPalette[h.Sum32()%uint32(len(authorPalette))])
+}
+
+// botAuthorsEnv names extra bot logins, comma-separated, for bots that
+// don't post through a GitHub App (and so lack the "[bot]" suffix)
+const botAuthorsEnv = "GH_REVIEW_CONDUCTOR_BOTS"
+
+// IsBotAuthor reports whether author is a bot: a GitHub App ("[bot]" suffix),
+// Copilot, or a login listed in GH_REVIEW_CONDUCTOR_BOTS.
+func IsBotAuthor(author string) bool {
+	if strings.HasSuffix(author, "[bot]") || strings.EqualFold(author, "Copilot") {
+		return true
+	}
+	for _, bot := range strings.Split(os.Getenv(botAuthorsEnv), ",") {
+		bot = strings.TrimSpace(bot)
+		if bot != "" && strings.EqualFold(strings.TrimSuffix(bot, "[bot]"), strings.TrimSuffix(author, "[bot]")) {
+			return true
+		}
+	}
+	return false
 }
 
 // NewAuthorStyle creates a new author style based on the author name.
-// Bots (ending with [bot]) are colored yellow, regular users in cyan.
+// Each author gets their own color (see authorColor); bots (see
+// IsBotAuthor) share a muted yellow.
 func NewAuthorStyle(author string) *AuthorStyle {
-	isBot := strings.HasSuffix(author, "[bot]") || strings.EqualFold(author, "Copilot")
+	isBot := IsBotAuthor(author)
 	name := author
 	if strings.HasSuffix(author, "[bot]") {
 		name = strings.TrimSuffix(author, "[bot]")
 	}
 
-	style := &AuthorStyle{
+	return &AuthorStyle{
 		Name:  name,
 		IsBot: isBot,
+		Color: authorColor(author),
 	}
-
-	if style.IsBot {
-		style.Color = ColorYellow
-	} else {
-		style.Color = ColorCyan
-	}
-
-	return style
 }
 
 // Format returns the formatted author string with color (colored "@authorname").
@@ -345,6 +673,37 @@ func (ss *StatusStyle) Format(includeEmoji bool) string {
 	return Colorize(ss.Color, ss.Label)
 }
 
+// ============================================================================
+// Row Emphasis
+// ============================================================================
+
+// Row emphasis, layered over the author's color so threads still waiting on
+// a person stand out and finished ones recede
+const (
+	EmphasisBright = "\033[1m"   // unresolved, from a person: bold
+	EmphasisMuted  = "\033[2m"   // unresolved, from a bot: faint
+	EmphasisDone   = "\033[2;9m" // resolved: faint and struck through
+)
+
+// RowEmphasis picks a comment row's emphasis from its thread's state and
+// whether its author is a bot (see IsBotAuthor). A resolved thread is done
+// whoever wrote it.
+func RowEmphasis(isResolved, isBot bool) string {
+	switch {
+	case isResolved:
+		return EmphasisDone
+	case isBot:
+		return EmphasisMuted
+	default:
+		return EmphasisBright
+	}
+}
+
+// Emphasize applies emphasis to text, which may already be colored
+func Emphasize(emphasis, text string) string {
+	return Colorize(emphasis, text)
+}
+
 // ============================================================================
 // Review List Item Styling
 // ============================================================================
@@ -381,9 +740,15 @@ func NewSuggestionListStyle(authorName string, isResolved bool) *SuggestionListS
 	return NewReviewListStyle(authorName, isResolved)
 }
 
-// FormatCommentTitle returns a formatted title for comment list display: "@author".
+// Emphasis returns the row emphasis for the comment (see RowEmphasis).
+func (rls *ReviewListStyle) Emphasis() string {
+	return RowEmphasis(rls.Status.IsResolved, rls.Author.IsBot)
+}
+
+// FormatCommentTitle returns a formatted title for comment list display:
+// "@author", emphasized by the thread's state (see RowEmphasis).
 func (rls *ReviewListStyle) FormatCommentTitle(commentID int64) string {
-	return rls.Author.Format(false)
+	return Emphasize(rls.Emphasis(), rls.Author.Format(false))
 }
 
 // FormatCommentDescription returns a formatted description for comment list: "file:line [emoji status]".
@@ -1,9 +1,15 @@
 package ui
 
 import (
+	"errors"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestFormatDiffWithHeaders(t *testing.T) {
@@ -91,6 +97,11 @@ func TestStripSuggestionBlock(t *testing.T) {
 			body:     "  ```suggestion\ncode\n```  ",
 			expected: "",
 		},
+		{
+			name:     "indented suggestion in a list item",
+			body:     "- Return early:\n  ```suggestion\n  def check(x):\n      return None\n  ```\n- Also rename it",
+			expected: "- Return early:\n\n- Also rename it",
+		},
 		{
 			name:     "removes markdown images",
 			body:     "See this: ![screenshot](https://example.com/img.png)",
@@ -254,6 +265,9 @@ func TestCreateHyperlink(t *testing.T) {
 	// Save original state and restore after test
 	originalEnabled := colorEnabled
 	defer func() { colorEnabled = originalEnabled }()
+	originalHyperlinks := hyperlinksEnabled
+	defer SetHyperlinksEnabled(originalHyperlinks)
+	SetHyperlinksEnabled(true)
 
 	tests := []struct {
 		name         string
@@ -315,8 +329,6 @@ func TestRenderMarkdownCaching(t *testing.T) {
 	originalEnabled := colorEnabled
 	defer func() {
 		colorEnabled = originalEnabled
-		// Note: We do NOT restore cachedMarkdownRenderer because sync.Once
-		// has already run. Restoring it to nil would break subsequent tests.
 	}()
 
 	colorEnabled = true
@@ -331,9 +343,9 @@ func TestRenderMarkdownCaching(t *testing.T) {
 	}
 
 	// Capture the cached renderer
-	firstRenderer := cachedMarkdownRenderer
+	firstRenderer := cachedDefaultRenderer()
 	if firstRenderer == nil {
-		t.Fatal("cachedMarkdownRenderer should be set after first call")
+		t.Fatal("the default renderer should be cached after first call")
 	}
 
 	// Second call should reuse the same renderer
@@ -346,8 +358,42 @@ func TestRenderMarkdownCaching(t *testing.T) {
 	}
 
 	// Verify the same renderer is used (not recreated)
-	if cachedMarkdownRenderer != firstRenderer {
-		t.Error("cachedMarkdownRenderer should be reused, not recreated")
+	if cachedDefaultRenderer() != firstRenderer {
+		t.Error("the default renderer should be reused, not recreated")
+	}
+}
+
+// cachedDefaultRenderer returns the cached default renderer without creating it
+func cachedDefaultRenderer() *glamour.TermRenderer {
+	markdownRenderersMu.Lock()
+	defer markdownRenderersMu.Unlock()
+	return markdownRenderers[defaultRendererKey]
+}
+
+func TestMarkdownRendererCachedPerKey(t *testing.T) {
+	narrow := rendererKey{theme: "dark", width: 40}
+	light := rendererKey{theme: "light", width: 80}
+
+	r := markdownRenderer(narrow)
+	if r == nil {
+		t.Fatal("expected a renderer for a valid theme and width")
+	}
+	if markdownRenderer(narrow) != r {
+		t.Error("expected the same key to reuse its renderer")
+	}
+	if markdownRenderer(light) == r || markdownRenderer(defaultRendererKey) == r {
+		t.Error("expected other themes and widths to get their own renderers")
+	}
+
+	// Words wrap at the key's width
+	rendered, err := markdownRenderer(narrow).Render(strings.Repeat("word ", 20))
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	for _, line := range strings.Split(rendered, "\n") {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Errorf("expected lines of at most 40 columns, got %d: %q", w, line)
+		}
 	}
 }
 
@@ -396,6 +442,24 @@ func TestSetUIDebug(t *testing.T) {
 	}
 }
 
+func TestSetMarkdownTheme(t *testing.T) {
+	original := defaultRendererKey
+	t.Cleanup(func() { defaultRendererKey = original })
+
+	if err := SetMarkdownTheme("light"); err != nil {
+		t.Fatalf("SetMarkdownTheme(light) returned error: %v", err)
+	}
+	if defaultRendererKey.theme != "light" {
+		t.Errorf("expected the light theme, got %q", defaultRendererKey.theme)
+	}
+	if err := SetMarkdownTheme("solarized"); err == nil || !strings.Contains(err.Error(), "dark") {
+		t.Errorf("expected an error listing the themes, got %v", err)
+	}
+	if defaultRendererKey.theme != "light" {
+		t.Error("expected an unknown theme to leave the current one")
+	}
+}
+
 func TestWarmupMarkdownRenderer(t *testing.T) {
 	// Save original state and restore after test
 	originalEnabled := colorEnabled
@@ -409,17 +473,15 @@ func TestWarmupMarkdownRenderer(t *testing.T) {
 	uiDebug.Store(false) // Disable debug to avoid stderr output
 
 	// Call warmup - it runs in a goroutine and should not panic
-	// Note: sync.Once means the renderer may already be initialized from other tests
+	// Note: the renderer may already be cached from other tests
 	WarmupMarkdownRenderer()
 
 	// Give the goroutine time to complete
 	time.Sleep(100 * time.Millisecond)
 
-	// After warmup (or if already initialized), the renderer should be cached
-	// We can't reset sync.Once, so we just verify it doesn't panic and the
-	// renderer is non-nil after the call
-	if cachedMarkdownRenderer == nil {
-		t.Error("cachedMarkdownRenderer should be initialized after WarmupMarkdownRenderer")
+	// After warmup (or if already initialized), the default renderer should be cached
+	if cachedDefaultRenderer() == nil {
+		t.Error("the default renderer should be cached after WarmupMarkdownRenderer")
 	}
 }
 
@@ -435,7 +497,7 @@ func TestGetMarkdownRenderer(t *testing.T) {
 	colorEnabled = true
 	uiDebug.Store(false) // Disable debug output
 
-	// Note: sync.Once means the renderer may already be initialized from other tests
+	// Note: the renderer may already be cached from other tests
 	// We test that getMarkdownRenderer returns a consistent non-nil value
 	r := getMarkdownRenderer()
 	if r == nil {
@@ -675,3 +737,310 @@ func TestTruncateDiffTail(t *testing.T) {
 		})
 	}
 }
+
+func TestHighlightMatches(t *testing.T) {
+	originalEnabled := colorEnabled
+	defer func() { colorEnabled = originalEnabled }()
+	colorEnabled = true
+
+	tests := []struct {
+		name     string
+		text     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "empty query",
+			text:     "rename this",
+			query:    "",
+			expected: "rename this",
+		},
+		{
+			name:     "case insensitive, all occurrences",
+			text:     "Nit: nit",
+			query:    "nit",
+			expected: highlightStart + "Nit" + highlightEnd + ": " + highlightStart + "nit" + highlightEnd,
+		},
+		{
+			name:     "escape sequences are left intact",
+			text:     ColorRed + "error" + ColorReset,
+			query:    "3",
+			expected: ColorRed + "error" + ColorReset,
+		},
+		{
+			name:     "hyperlink target is not highlighted",
+			text:     "\033]8;;https://example.com/fix\033\\fix\033]8;;\033\\",
+			query:    "fix",
+			expected: "\033]8;;https://example.com/fix\033\\" + highlightStart + "fix" + highlightEnd + "\033]8;;\033\\",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HighlightMatches(tt.text, tt.query); got != tt.expected {
+				t.Errorf("HighlightMatches(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.expected)
+			}
+		})
+	}
+
+	colorEnabled = false
+	if got := HighlightMatches("nit", "nit"); got != "nit" {
+		t.Errorf("expected no highlighting with colors disabled, got %q", got)
+	}
+}
+
+func TestIsBotAuthor(t *testing.T) {
+	t.Setenv(botAuthorsEnv, "review-helper, Lint-Bot[bot]")
+
+	tests := []struct {
+		author string
+		want   bool
+	}{
+		{"dependabot[bot]", true},
+		{"Copilot", true},
+		{"review-helper", true},
+		{"REVIEW-HELPER", true},
+		{"lint-bot", true},
+		{"lint-bot[bot]", true},
+		{"octocat", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsBotAuthor(tt.author); got != tt.want {
+			t.Errorf("IsBotAuthor(%q) = %v, want %v", tt.author, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorColor(t *testing.T) {
+	t.Setenv(botAuthorsEnv, "review-helper")
+
+	if authorColor("octocat") != authorColor("octocat") || authorColor("octocat") != authorColor("OctoCat") {
+		t.Error("expected the same color for the same author every time")
+	}
+	if NewAuthorStyle("octocat").Color != authorColor("octocat") {
+		t.Error("expected the review list style to use the author's color")
+	}
+
+	for _, bot := range []string{"dependabot[bot]", "Copilot", "review-helper"} {
+		if got := authorColor(bot); got != botAuthorColor {
+			t.Errorf("authorColor(%q) = %q, want the bot color", bot, got)
+		}
+	}
+
+	colors := make(map[string]bool)
+	for i := 0; i < 200; i++ {
+		color := authorColor("reviewer" + strconv.Itoa(i))
+		if color == botAuthorColor {
+			t.Fatalf("reviewer%d got the color reserved for bots", i)
+		}
+		colors[color] = true
+	}
+	if len(colors) < 50 {
+		t.Errorf("expected authors spread over the palette, got %d colors for 200 authors", len(colors))
+	}
+}
+
+func TestRowEmphasis(t *testing.T) {
+	tests := []struct {
+		name       string
+		isResolved bool
+		isBot      bool
+		want       string
+	}{
+		{"unresolved from a person", false, false, EmphasisBright},
+		{"unresolved from a bot", false, true, EmphasisMuted},
+		{"resolved from a person", true, false, EmphasisDone},
+		{"resolved from a bot", true, true, EmphasisDone},
+	}
+	for _, tt := range tests {
+		if got := RowEmphasis(tt.isResolved, tt.isBot); got != tt.want {
+			t.Errorf("%s: RowEmphasis() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// The emphasis wraps the author's own color
+	title := NewReviewListStyle("octocat", true).FormatCommentTitle(1)
+	if want := EmphasisDone + NewAuthorStyle("octocat").Format(false); !strings.HasPrefix(title, want) {
+		t.Errorf("FormatCommentTitle() = %q, want it to start with %q", title, want)
+	}
+
+	originalEnabled := colorEnabled
+	defer func() { colorEnabled = originalEnabled }()
+	colorEnabled = false
+	if title := NewReviewListStyle("octocat", true).FormatCommentTitle(1); title != "@octocat" {
+		t.Errorf("FormatCommentTitle() = %q without colors, want plain @octocat", title)
+	}
+}
+
+func TestCreateHyperlink_Fallback(t *testing.T) {
+	originalEnabled := colorEnabled
+	defer func() { colorEnabled = originalEnabled }()
+	originalHyperlinks := hyperlinksEnabled
+	defer SetHyperlinksEnabled(originalHyperlinks)
+
+	colorEnabled = true
+	SetHyperlinksEnabled(false)
+
+	if got := CreateHyperlink("https://github.com", "GitHub"); got != "GitHub (https://github.com)" {
+		t.Errorf("CreateHyperlink() = %q, want plain text with the URL", got)
+	}
+	if got := CreateHyperlink("https://github.com", "https://github.com"); got != "https://github.com" {
+		t.Errorf("CreateHyperlink() = %q, want the URL once", got)
+	}
+}
+
+func TestDetectHyperlinkSupport(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"unknown terminal", map[string]string{"TERM": "xterm-256color"}, false},
+		{"dumb terminal", map[string]string{"TERM": "dumb", "TERM_PROGRAM": "iTerm.app"}, false},
+		{"iTerm2", map[string]string{"TERM_PROGRAM": "iTerm.app"}, true},
+		{"Windows Terminal", map[string]string{"WT_SESSION": "abc"}, true},
+		{"new VTE", map[string]string{"VTE_VERSION": "6800"}, true},
+		{"old VTE", map[string]string{"VTE_VERSION": "4800"}, false},
+		{"kitty by TERM", map[string]string{"TERM": "xterm-kitty"}, true},
+		{"override on", map[string]string{hyperlinksEnvVar: "1", "TERM": "xterm"}, true},
+		{"override off", map[string]string{hyperlinksEnvVar: "0", "TERM_PROGRAM": "WezTerm"}, false},
+		{"invalid override falls back to detection", map[string]string{hyperlinksEnvVar: "maybe", "TERM_PROGRAM": "vscode"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := detectHyperlinkSupport(getenv); got != tt.want {
+				t.Errorf("detectHyperlinkSupport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorizeDiffRange(t *testing.T) {
+	originalEnabled := colorEnabled
+	defer func() { colorEnabled = originalEnabled }()
+	colorEnabled = false
+
+	diff := "@@ -1,4 +1,4 @@\n context\n-old\n+new one\n-old two\n+new two"
+	got := ColorizeDiffRange(diff, 2, '-')
+	want := "     @@ -1,4 +1,4 @@\n 1 1  context\n 2   -old\n▌  2 +new one\n 3   -old two\n▌  3 +new two"
+	if got != want {
+		t.Errorf("ColorizeDiffRange() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdownFallsBackOnTimeout(t *testing.T) {
+	originalEnabled, originalTimeout, originalRender := colorEnabled, markdownRenderTimeout, glamourRender
+	release := make(chan struct{})
+	defer func() {
+		close(release)
+		colorEnabled, markdownRenderTimeout, glamourRender = originalEnabled, originalTimeout, originalRender
+	}()
+
+	colorEnabled = true
+	markdownRenderTimeout = 20 * time.Millisecond
+	glamourRender = func(r *glamour.TermRenderer, text string) (string, error) {
+		<-release // a render that never finishes on its own
+		return "rendered", nil
+	}
+
+	// A huge table stands in for input glamour chokes on
+	body := "| a | b |\n|---|---|\n" + strings.Repeat("| "+strings.Repeat("x", 200)+" | y |\n", 2000)
+	start := time.Now()
+	got, err := RenderMarkdown(body)
+	if err != nil {
+		t.Fatalf("RenderMarkdown returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("RenderMarkdown took %v, want it to give up after the timeout", elapsed)
+	}
+	if got != WrapText(body, 80) {
+		t.Error("Expected the wrapped plain text fallback after a timeout")
+	}
+
+	// The stuck render still holds its renderer; the next one doesn't wait
+	glamourRender = func(r *glamour.TermRenderer, text string) (string, error) {
+		return "rendered", nil
+	}
+	if got, _ := RenderMarkdown("**next**"); got != "rendered" {
+		t.Errorf("Expected the next render to run on a fresh renderer, got %q", got)
+	}
+
+	// A failed render falls back to the same plain text as a timeout
+	glamourRender = func(r *glamour.TermRenderer, text string) (string, error) {
+		return "", errors.New("boom")
+	}
+	if got, _ := RenderMarkdown(body); got != WrapText(body, 80) {
+		t.Error("Expected the wrapped plain text fallback after an error")
+	}
+}
+
+func TestRenderWithTimeoutRecoversPanics(t *testing.T) {
+	var mu sync.Mutex
+	_, err := renderWithTimeout(&mu, func() (string, error) {
+		panic("bad input")
+	}, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "bad input") {
+		t.Errorf("Expected the panic as an error, got %v", err)
+	}
+
+	// The lock is released after a panic, so later renders still run
+	got, err := renderWithTimeout(&mu, func() (string, error) { return "ok", nil }, time.Second)
+	if err != nil || got != "ok" {
+		t.Errorf("renderWithTimeout() = %q, %v; want ok", got, err)
+	}
+}
+
+func TestColorizeDiffWithLineNumbers(t *testing.T) {
+	originalEnabled := colorEnabled
+	defer func() { colorEnabled = originalEnabled }()
+	colorEnabled = false
+
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{
+			name: "counts each side from the header",
+			diff: "@@ -8,3 +9,4 @@ func main() {\n ctx\n-old\n+new\n+added\n\\ No newline at end of file\n end",
+			want: "      @@ -8,3 +9,4 @@ func main() {\n 8  9  ctx\n 9    -old\n   10 +new\n   11 +added\n      \\ No newline at end of file\n10 12  end",
+		},
+		{
+			name: "header without counts",
+			diff: "@@ -5 +5 @@\n-a\n+b",
+			want: "    @@ -5 +5 @@\n5   -a\n  5 +b",
+		},
+		{
+			name: "malformed header leaves the gutter blank",
+			diff: "@@ garbage @@\n ctx\n+new",
+			want: "    @@ garbage @@\n     ctx\n    +new",
+		},
+		{
+			name: "no header",
+			diff: " ctx",
+			want: "     ctx",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ColorizeDiffWithLineNumbers(tt.diff); got != tt.want {
+				t.Errorf("ColorizeDiffWithLineNumbers() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapTextBreaksLongTokens(t *testing.T) {
+	url := "https://example.com/" + strings.Repeat("a", 100)
+	got := WrapText("See "+url+" for details", 40)
+	for _, line := range strings.Split(got, "\n") {
+		if len(line) > 40 {
+			t.Errorf("line longer than 40 columns: %q", line)
+		}
+	}
+	if !strings.Contains(strings.ReplaceAll(got, "\n", ""), url) {
+		t.Errorf("expected the URL split across lines with nothing lost, got:\n%s", got)
+	}
+}
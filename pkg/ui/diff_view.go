@@ -0,0 +1,398 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/diffposition"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+)
+
+// DiffViewOptions configures ViewDiff
+type DiffViewOptions struct {
+	Title    string                             // shown above the diff, e.g. "owner/repo #12"
+	Diff     string                             // unified diff of the whole pull request
+	Comments []*github.ReviewComment            // marked on the lines they were made on
+	Preview  func(*github.ReviewComment) string // comment detail opened with Enter
+}
+
+// diffLine is one line of a pull request diff with its place in the file
+type diffLine struct {
+	text     string
+	path     string
+	oldLine  int  // 0 when the line isn't in the old file
+	newLine  int  // 0 when the line isn't in the new file
+	fileHead bool // starts a file ("diff --git ...")
+}
+
+// diffMarker is a diff line that review comments were made on
+type diffMarker struct {
+	line     int // index into the diff's lines
+	comments []*github.ReviewComment
+}
+
+// diffViewModel shows a pull request diff in a viewport, with markers on
+// commented lines that open the comments' detail
+type diffViewModel struct {
+	opts       DiffViewOptions
+	lines      []diffLine
+	files      []int // indexes of file heads
+	markers    []diffMarker
+	current    int // selected marker, or -1
+	gutter     int // width of each line number column
+	viewport   viewport.Model
+	detail     viewport.Model
+	showDetail bool
+}
+
+// ViewDiff shows a pull request diff until the user quits
+func ViewDiff(opts DiffViewOptions) error {
+	_, err := tea.NewProgram(newDiffViewModel(opts), tea.WithAltScreen()).Run()
+	return err
+}
+
+// newDiffViewModel parses the diff and places the comments on it
+func newDiffViewModel(opts DiffViewOptions) diffViewModel {
+	m := diffViewModel{opts: opts, current: -1}
+	m.lines = parsePRDiff(opts.Diff)
+	widest := 0
+	for i, line := range m.lines {
+		if line.fileHead {
+			m.files = append(m.files, i)
+		}
+		widest = max(widest, line.oldLine, line.newLine)
+	}
+	m.gutter = len(strconv.Itoa(widest))
+	m.markers = placeComments(m.lines, opts.Comments)
+	m.viewport = viewport.New(0, 0)
+	m.viewport.SetHorizontalStep(4)
+	m.detail = viewport.New(0, 0)
+	return m
+}
+
+// parsePRDiff splits a multi-file unified diff into lines, numbering the
+// lines inside hunks on each side
+func parsePRDiff(diff string) []diffLine {
+	raw := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	lines := make([]diffLine, len(raw))
+	path := ""
+	oldLine, newLine := 0, 0 // 0 = outside a hunk
+	for i, text := range raw {
+		lines[i].text = text
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			// "diff --git a/old b/new"; +++ below corrects paths with spaces
+			if idx := strings.LastIndex(text, " b/"); idx >= 0 {
+				path = text[idx+len(" b/"):]
+			}
+			oldLine, newLine = 0, 0
+			lines[i].fileHead = true
+		case oldLine == 0 && newLine == 0 && strings.HasPrefix(text, "+++ b/"):
+			path = strings.TrimPrefix(text, "+++ b/")
+		case strings.HasPrefix(text, "@@"):
+			oldLine, newLine = 0, 0
+			if m := hunkHeaderRe.FindStringSubmatch(text); m != nil {
+				oldLine, _ = strconv.Atoi(m[1])
+				newLine, _ = strconv.Atoi(m[2])
+			}
+		case oldLine == 0 && newLine == 0:
+			// File metadata (index, mode, ---/+++) or a malformed hunk
+		case strings.HasPrefix(text, "-"):
+			lines[i].oldLine = oldLine
+			oldLine++
+		case strings.HasPrefix(text, "+"):
+			lines[i].newLine = newLine
+			newLine++
+		case strings.HasPrefix(text, "\\"):
+			// "\ No newline at end of file" belongs to neither side
+		default:
+			lines[i].oldLine, lines[i].newLine = oldLine, newLine
+			oldLine++
+			newLine++
+		}
+		lines[i].path = path
+	}
+	// Give a file's head and metadata the path its +++ line settled on
+	for i := len(lines) - 1; i > 0; i-- {
+		if lines[i-1].path != lines[i].path && !lines[i].fileHead {
+			lines[i-1].path = lines[i].path
+		}
+	}
+	return lines
+}
+
+// placeComments finds the line each inline comment was made on. Outdated
+// comments and those on lines the diff doesn't show aren't placed.
+func placeComments(lines []diffLine, comments []*github.ReviewComment) []diffMarker {
+	byLine := make(map[int]int) // line index -> marker index
+	var markers []diffMarker
+	for _, c := range comments {
+		if c.Kind != github.CommentKindInline || c.Line == 0 {
+			continue
+		}
+		for i, line := range lines {
+			if line.path != c.Path {
+				continue
+			}
+			onLine := line.newLine == c.Line
+			if c.DiffSide == diffposition.DiffSideLeft {
+				onLine = line.oldLine == c.Line
+			}
+			if !onLine {
+				continue
+			}
+			if idx, ok := byLine[i]; ok {
+				markers[idx].comments = append(markers[idx].comments, c)
+			} else {
+				byLine[i] = len(markers)
+				markers = append(markers, diffMarker{line: i, comments: []*github.ReviewComment{c}})
+			}
+			break
+		}
+	}
+	// Keep markers in diff order for n/N
+	for i := 1; i < len(markers); i++ {
+		for j := i; j > 0 && markers[j].line < markers[j-1].line; j-- {
+			markers[j], markers[j-1] = markers[j-1], markers[j]
+		}
+	}
+	return markers
+}
+
+// render colors the diff with line numbers and appends the comment markers
+func (m *diffViewModel) render() string {
+	marked := make(map[int]int, len(m.markers))
+	for i, marker := range m.markers {
+		marked[marker.line] = i
+	}
+	format := func(n int) string {
+		if n == 0 {
+			return strings.Repeat(" ", m.gutter)
+		}
+		return fmt.Sprintf("%*d", m.gutter, n)
+	}
+
+	out := make([]string, len(m.lines))
+	for i, line := range m.lines {
+		text := ColorizeDiff(line.text)
+		if line.oldLine == 0 && line.newLine == 0 && !strings.HasPrefix(line.text, "@@") {
+			// File metadata: "--- a/x" and "+++ b/x" aren't changes
+			text = Colorize(ColorCyan, line.text)
+		}
+		gutter := Colorize(ColorGray, format(line.oldLine)+" "+format(line.newLine)+" ")
+		out[i] = gutter + text
+		if idx, ok := marked[i]; ok {
+			out[i] += "  " + m.markerLabel(idx)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// markerLabel names who commented on a marked line; the selected marker is
+// highlighted with a hint to open it
+func (m *diffViewModel) markerLabel(idx int) string {
+	comments := m.markers[idx].comments
+	label := EmojiText("💬", "*") + " @" + comments[0].Author
+	if replies := len(comments[0].ThreadComments); replies > 0 {
+		label += fmt.Sprintf(" [+%d]", replies)
+	}
+	if len(comments) > 1 {
+		label += fmt.Sprintf(" and %d more", len(comments)-1)
+	}
+	if idx == m.current {
+		return Colorize(ColorMagenta, "▶ "+label+" (enter to open)")
+	}
+	return Colorize(ColorYellow, label)
+}
+
+// Init implements tea.Model
+func (m diffViewModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m diffViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// One line for the header, two for the gap and footer
+		height := max(1, msg.Height-3)
+		m.viewport.Width, m.viewport.Height = msg.Width, height
+		m.detail.Width, m.detail.Height = msg.Width, height
+		m.viewport.SetContent(m.render())
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.showDetail {
+			switch msg.String() {
+			case "q", "esc", "backspace":
+				m.showDetail = false
+				return m, nil
+			case "ctrl+c":
+				return m, tea.Quit
+			}
+			var cmd tea.Cmd
+			m.detail, cmd = m.detail.Update(msg)
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "}":
+			m.jumpToFile(1)
+			return m, nil
+		case "{":
+			m.jumpToFile(-1)
+			return m, nil
+		case "n":
+			m.selectMarker(1)
+			return m, nil
+		case "N":
+			m.selectMarker(-1)
+			return m, nil
+		case "enter":
+			m.openDetail()
+			return m, nil
+		case "g", "home":
+			m.viewport.GotoTop()
+			return m, nil
+		case "G", "end":
+			m.viewport.GotoBottom()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// jumpToFile scrolls to the next (dir 1) or previous (dir -1) file
+func (m *diffViewModel) jumpToFile(dir int) {
+	top := m.viewport.YOffset
+	if dir > 0 {
+		for _, start := range m.files {
+			if start > top {
+				m.viewport.SetYOffset(start)
+				return
+			}
+		}
+		return
+	}
+	for i := len(m.files) - 1; i >= 0; i-- {
+		if m.files[i] < top {
+			m.viewport.SetYOffset(m.files[i])
+			return
+		}
+	}
+}
+
+// selectMarker moves to the next (dir 1) or previous (dir -1) comment
+// marker, counting from the top of the screen when none is selected yet,
+// and scrolls it into view a few lines from the top
+func (m *diffViewModel) selectMarker(dir int) {
+	if len(m.markers) == 0 {
+		return
+	}
+	next := -1
+	if m.current >= 0 && m.markerVisible(m.current) {
+		next = m.current + dir
+	} else {
+		top := m.viewport.YOffset
+		for i, marker := range m.markers {
+			if dir > 0 && marker.line >= top {
+				next = i
+				break
+			}
+			if dir < 0 && marker.line < top {
+				next = i
+			}
+		}
+	}
+	if next < 0 || next >= len(m.markers) {
+		return
+	}
+	m.current = next
+	offset := m.viewport.YOffset
+	m.viewport.SetContent(m.render())
+	m.viewport.SetYOffset(offset)
+	if !m.markerVisible(next) {
+		m.viewport.SetYOffset(max(0, m.markers[next].line-m.viewport.Height/3))
+	}
+}
+
+// markerVisible reports whether the marker's line is on screen
+func (m *diffViewModel) markerVisible(idx int) bool {
+	line := m.markers[idx].line
+	return line >= m.viewport.YOffset && line < m.viewport.YOffset+m.viewport.Height
+}
+
+// openDetail shows the selected marker's comments, or the first marker on
+// screen when none is selected
+func (m *diffViewModel) openDetail() {
+	if m.opts.Preview == nil {
+		return
+	}
+	if m.current < 0 || !m.markerVisible(m.current) {
+		m.selectMarker(1)
+		if m.current < 0 || !m.markerVisible(m.current) {
+			return
+		}
+	}
+	var parts []string
+	for _, c := range m.markers[m.current].comments {
+		parts = append(parts, m.opts.Preview(c))
+	}
+	m.detail.SetContent(strings.Join(parts, "\n\n"))
+	m.detail.GotoTop()
+	m.showDetail = true
+}
+
+// currentFile is the path of the file at the top of the screen
+func (m *diffViewModel) currentFile() string {
+	if len(m.lines) == 0 {
+		return ""
+	}
+	return m.lines[min(m.viewport.YOffset, len(m.lines)-1)].path
+}
+
+// View implements tea.Model
+func (m diffViewModel) View() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	if m.showDetail {
+		marker := m.markers[m.current]
+		line := m.lines[marker.line]
+		location := fmt.Sprintf("%s:%d", line.path, max(line.newLine, line.oldLine))
+		return lipgloss.JoinVertical(lipgloss.Left,
+			titleStyle.Render("Comment")+"  "+helpStyle.Render(location),
+			m.detail.View(),
+			"",
+			helpStyle.Render("j/k:scroll | q/esc:back to diff"),
+		)
+	}
+
+	header := titleStyle.Render(m.opts.Title)
+	if file := m.currentFile(); file != "" {
+		header += "  " + helpStyle.Render(file)
+	}
+	actions := []string{"j/k:scroll", "h/l:pan", "{/}:file"}
+	if len(m.markers) > 0 {
+		actions = append(actions, fmt.Sprintf("n/N:comment (%d)", len(m.markers)))
+		if m.opts.Preview != nil {
+			actions = append(actions, "enter:open")
+		}
+	}
+	actions = append(actions, "q:quit")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		m.viewport.View(),
+		"",
+		helpStyle.Render(strings.Join(actions, " | ")),
+	)
+}
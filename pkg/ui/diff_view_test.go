@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/diffposition"
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+)
+
+const testPRDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -10,3 +10,4 @@ func main() {
+ 	a := 1
+-	b := 2
++	b := 3
++	c := 4
+ 	return
+diff --git a/util.go b/util.go
+new file mode 100644
+--- /dev/null
++++ b/util.go
+@@ -0,0 +1,2 @@
++package main
++// util
+`
+
+func TestParsePRDiff(t *testing.T) {
+	lines := parsePRDiff(testPRDiff)
+
+	find := func(text string) diffLine {
+		for _, line := range lines {
+			if line.text == text {
+				return line
+			}
+		}
+		t.Fatalf("line %q not found", text)
+		return diffLine{}
+	}
+	if got := find("-\tb := 2"); got.oldLine != 11 || got.newLine != 0 || got.path != "main.go" {
+		t.Errorf("removed line = %+v", got)
+	}
+	if got := find("+\tc := 4"); got.oldLine != 0 || got.newLine != 12 {
+		t.Errorf("added line = %+v", got)
+	}
+	if got := find(" \treturn"); got.oldLine != 12 || got.newLine != 13 {
+		t.Errorf("context line = %+v", got)
+	}
+	if got := find("+// util"); got.newLine != 2 || got.path != "util.go" {
+		t.Errorf("new file line = %+v", got)
+	}
+	// The next file's head isn't counted as context of the last hunk
+	if got := find("diff --git a/util.go b/util.go"); !got.fileHead || got.oldLine != 0 || got.path != "util.go" {
+		t.Errorf("file head = %+v", got)
+	}
+	if got := find("--- /dev/null"); got.oldLine != 0 || got.newLine != 0 {
+		t.Errorf("metadata line = %+v", got)
+	}
+}
+
+func TestPlaceComments(t *testing.T) {
+	lines := parsePRDiff(testPRDiff)
+	comments := []*github.ReviewComment{
+		{ID: 1, Path: "util.go", Line: 2, Author: "a"},
+		{ID: 2, Path: "main.go", Line: 11, DiffSide: diffposition.DiffSideLeft, Author: "b"},
+		{ID: 3, Path: "main.go", Line: 12, Author: "c"},
+		{ID: 4, Path: "main.go", Line: 12, Author: "d"},
+		{ID: 5, Path: "main.go", Line: 0, Author: "outdated"},
+		{ID: 6, Path: "main.go", Line: 99, Author: "outside"},
+	}
+
+	markers := placeComments(lines, comments)
+	if len(markers) != 3 {
+		t.Fatalf("expected 3 markers, got %d", len(markers))
+	}
+	if lines[markers[0].line].text != "-\tb := 2" || markers[0].comments[0].ID != 2 {
+		t.Errorf("expected the left-side comment on the removed line first, got %+v", markers[0])
+	}
+	if len(markers[1].comments) != 2 || lines[markers[1].line].text != "+\tc := 4" {
+		t.Errorf("expected both comments on line 12 in one marker, got %+v", markers[1])
+	}
+	if lines[markers[2].line].path != "util.go" {
+		t.Errorf("expected the util.go marker last, got %+v", markers[2])
+	}
+}
+
+func TestDiffViewNavigation(t *testing.T) {
+	var previewed []int64
+	m := newDiffViewModel(DiffViewOptions{
+		Title: "owner/repo #1",
+		Diff:  testPRDiff,
+		Comments: []*github.ReviewComment{
+			{ID: 1, Path: "main.go", Line: 12, Author: "alice"},
+			{ID: 2, Path: "util.go", Line: 1, Author: "bob"},
+		},
+		Preview: func(c *github.ReviewComment) string {
+			previewed = append(previewed, c.ID)
+			return "comment body"
+		},
+	})
+	press := func(model tea.Model, key string) tea.Model {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if key == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		updated, _ := model.Update(msg)
+		return updated
+	}
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 8})
+	if view := model.View(); !strings.Contains(view, "n/N:comment (2)") {
+		t.Errorf("expected the comment count, got:\n%s", view)
+	}
+
+	model = press(model, "}")
+	if got := model.(diffViewModel).viewport.YOffset; got != 10 {
+		t.Errorf("expected } to scroll to the second file, got offset %d", got)
+	}
+	model = press(model, "{")
+	if got := model.(diffViewModel).viewport.YOffset; got != 0 {
+		t.Errorf("expected { to scroll back to the first file, got offset %d", got)
+	}
+
+	model = press(model, "n")
+	model = press(model, "n")
+	if got := model.(diffViewModel).current; got != 1 {
+		t.Errorf("expected the second marker selected, got %d", got)
+	}
+	if view := model.View(); !strings.Contains(view, "@bob (enter to open)") || !strings.Contains(view, "util.go") {
+		t.Errorf("expected the selected marker scrolled into view and highlighted, got:\n%s", view)
+	}
+
+	model = press(model, "enter")
+	if !model.(diffViewModel).showDetail || len(previewed) != 1 || previewed[0] != 2 {
+		t.Fatalf("expected enter to open bob's comment, previewed %v", previewed)
+	}
+	if view := model.View(); !strings.Contains(view, "comment body") || !strings.Contains(view, "util.go:1") {
+		t.Errorf("expected the comment detail, got:\n%s", view)
+	}
+
+	model = press(model, "q")
+	if model.(diffViewModel).showDetail {
+		t.Error("expected q to go back to the diff")
+	}
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// defaultEditor is used when no editor is configured anywhere
+const defaultEditor = "vim"
+
+// configuredEditor comes from the config file; see SetConfiguredEditor
+var configuredEditor string
+
+// SetConfiguredEditor sets the editor used when $VISUAL and $EDITOR are unset
+func SetConfiguredEditor(editor string) {
+	configuredEditor = editor
+}
+
+// gitConfigEditor returns `git config core.editor`, or "" if unset. It is a
+// variable so tests don't depend on the user's git configuration.
+var gitConfigEditor = func() string {
+	out, err := exec.Command("git", "config", "core.editor").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// ResolveEditor returns the editor command split into program and arguments,
+// checking $VISUAL, $EDITOR, the configured editor, git's core.editor, then
+// falling back to vim.
+// Multi-word values such as "code --wait" are split on whitespace.
+func ResolveEditor() []string {
+	candidates := []func() string{
+		func() string { return os.Getenv("VISUAL") },
+		func() string { return os.Getenv("EDITOR") },
+		func() string { return configuredEditor },
+		gitConfigEditor,
+	}
+	for _, candidate := range candidates {
+		if parts := strings.Fields(candidate()); len(parts) > 0 {
+			return parts
+		}
+	}
+	return []string{defaultEditor}
+}
+
+// editorCommand builds a command that runs the resolved editor with args appended
+func editorCommand(args ...string) *exec.Cmd {
+	editor := ResolveEditor()
+	return exec.Command(editor[0], append(editor[1:], args...)...)
+}
+
+// editorCommandAtLine builds a command that opens file in the resolved editor
+// with the cursor on line (1-based); line <= 0 just opens the file
+func editorCommandAtLine(file string, line int) *exec.Cmd {
+	editor := ResolveEditor()
+	args := []string{file}
+	if line > 0 {
+		args = editorLineArgs(editor[0], file, line)
+	}
+	return exec.Command(editor[0], append(editor[1:], args...)...)
+}
+
+// editorLineArgs returns the arguments that open file at line for the given
+// editor program, keyed on its basename. Unknown editors get "+line file",
+// which vim, nano, and most terminal editors understand.
+func editorLineArgs(program, file string, line int) []string {
+	name := strings.TrimSuffix(filepath.Base(program), ".exe")
+	switch name {
+	case "code", "code-insiders", "codium", "cursor", "windsurf":
+		return []string{"--goto", fmt.Sprintf("%s:%d", file, line)}
+	case "subl", "sublime_text", "zed", "hx", "helix":
+		return []string{fmt.Sprintf("%s:%d", file, line)}
+	case "emacsclient":
+		return []string{fmt.Sprintf("+%d:1", line), file}
+	case "idea", "goland", "pycharm", "webstorm", "clion", "rubymine", "rider":
+		return []string{"--line", fmt.Sprintf("%d", line), file}
+	default:
+		return []string{fmt.Sprintf("+%d", line), file}
+	}
+}
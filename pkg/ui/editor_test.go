@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveEditorPrecedence(t *testing.T) {
+	original := gitConfigEditor
+	defer func() { gitConfigEditor = original }()
+
+	tests := []struct {
+		name      string
+		visual    string
+		editor    string
+		gitEditor string
+		expected  []string
+	}{
+		{"VISUAL wins", "code --wait", "nano", "emacs", []string{"code", "--wait"}},
+		{"EDITOR when VISUAL unset", "", "nano", "emacs", []string{"nano"}},
+		{"git core.editor when env unset", "", "", "subl -n -w", []string{"subl", "-n", "-w"}},
+		{"whitespace-only values are skipped", "  ", "", "emacs", []string{"emacs"}},
+		{"falls back to vim", "", "", "", []string{"vim"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VISUAL", tt.visual)
+			t.Setenv("EDITOR", tt.editor)
+			gitConfigEditor = func() string { return tt.gitEditor }
+
+			if got := ResolveEditor(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ResolveEditor() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEditorCommandAppendsArgs(t *testing.T) {
+	original := gitConfigEditor
+	defer func() { gitConfigEditor = original }()
+	gitConfigEditor = func() string { return "" }
+	t.Setenv("VISUAL", "code --wait")
+
+	c := editorCommand("+12", "main.go")
+	if want := []string{"code", "--wait", "+12", "main.go"}; !reflect.DeepEqual(c.Args, want) {
+		t.Errorf("editorCommand args = %v, want %v", c.Args, want)
+	}
+}
+
+func TestEditorLineArgs(t *testing.T) {
+	tests := []struct {
+		program  string
+		expected []string
+	}{
+		{"vim", []string{"+12", "main.go"}},
+		{"/usr/bin/nano", []string{"+12", "main.go"}},
+		{"code", []string{"--goto", "main.go:12"}},
+		{"/usr/local/bin/cursor", []string{"--goto", "main.go:12"}},
+		{"emacsclient", []string{"+12:1", "main.go"}},
+		{"subl", []string{"main.go:12"}},
+		{"goland", []string{"--line", "12", "main.go"}},
+		{"code.exe", []string{"--goto", "main.go:12"}},
+		{"unknown-editor", []string{"+12", "main.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.program, func(t *testing.T) {
+			if got := editorLineArgs(tt.program, "main.go", 12); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("editorLineArgs(%q) = %v, want %v", tt.program, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEditorCommandAtLine(t *testing.T) {
+	original := gitConfigEditor
+	defer func() { gitConfigEditor = original }()
+	gitConfigEditor = func() string { return "" }
+	t.Setenv("VISUAL", "code --wait")
+
+	c := editorCommandAtLine("main.go", 7)
+	if want := []string{"code", "--wait", "--goto", "main.go:7"}; !reflect.DeepEqual(c.Args, want) {
+		t.Errorf("editorCommandAtLine args = %v, want %v", c.Args, want)
+	}
+
+	c = editorCommandAtLine("main.go", 0)
+	if want := []string{"code", "--wait", "main.go"}; !reflect.DeepEqual(c.Args, want) {
+		t.Errorf("editorCommandAtLine without line = %v, want %v", c.Args, want)
+	}
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// hyperlinksEnvVar forces OSC8 hyperlinks on ("1") or off ("0")
+const hyperlinksEnvVar = "GH_RC_HYPERLINKS"
+
+// hyperlinkTermPrograms are TERM_PROGRAM values of terminals known to render OSC8
+var hyperlinkTermPrograms = map[string]bool{
+	"iTerm.app":    true,
+	"WezTerm":      true,
+	"vscode":       true,
+	"Hyper":        true,
+	"ghostty":      true,
+	"WarpTerminal": true,
+	"Tabby":        true,
+	"rio":          true,
+}
+
+// hyperlinksEnabled is detected from the environment once at startup
+var hyperlinksEnabled = detectHyperlinkSupport(os.Getenv)
+
+// SetHyperlinksEnabled overrides terminal detection for OSC8 hyperlinks.
+// Must be called before any rendering occurs (typically at startup).
+func SetHyperlinksEnabled(enabled bool) {
+	hyperlinksEnabled = enabled
+}
+
+// HyperlinksEnabled reports whether CreateHyperlink emits OSC8 sequences.
+func HyperlinksEnabled() bool {
+	return hyperlinksEnabled
+}
+
+// detectHyperlinkSupport guesses whether the terminal renders OSC8 links.
+// GH_RC_HYPERLINKS=0/1 wins; otherwise only terminals known to support them
+// get links, since unsupported ones print the escape sequence as garbage.
+func detectHyperlinkSupport(getenv func(string) string) bool {
+	if override := getenv(hyperlinksEnvVar); override != "" {
+		if enabled, err := strconv.ParseBool(override); err == nil {
+			return enabled
+		}
+	}
+
+	term := getenv("TERM")
+	if term == "dumb" {
+		return false
+	}
+	if hyperlinkTermPrograms[getenv("TERM_PROGRAM")] {
+		return true
+	}
+	if getenv("WT_SESSION") != "" || getenv("KITTY_WINDOW_ID") != "" || getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	// GNOME Terminal, Tilix, and other VTE terminals since 0.50
+	if vte, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+	for _, name := range []string{"kitty", "alacritty", "ghostty", "foot", "wezterm"} {
+		if strings.Contains(term, name) {
+			return true
+		}
+	}
+	return false
+}
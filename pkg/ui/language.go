@@ -5,6 +5,15 @@ import (
 	"strings"
 )
 
+// CodeFenceLanguage picks the language for a code fence: an explicit hint
+// (e.g. from a suggestion block's fence) wins over the path-based guess.
+func CodeFenceLanguage(hint, path string) string {
+	if hint != "" {
+		return hint
+	}
+	return CodeFenceLanguageFromPath(path)
+}
+
 // CodeFenceLanguageFromPath returns a glamour-friendly language identifier
 // derived from a file path extension. An empty string means "no preference".
 func CodeFenceLanguageFromPath(path string) string {
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// notifyTermPrograms are TERM_PROGRAM values of terminals that turn OSC 9
+// into a desktop notification
+var notifyTermPrograms = map[string]bool{
+	"iTerm.app": true,
+	"WezTerm":   true,
+	"ghostty":   true,
+}
+
+// notifyOut is where Notify writes; the terminal, not the TUI's output
+var notifyOut io.Writer = os.Stderr
+
+// notify is what the selector calls, replaceable in tests
+var notify = Notify
+
+// desktopNotifications is detected from the environment once at startup
+var desktopNotifications = detectDesktopNotifications(os.Getenv)
+
+// detectDesktopNotifications guesses whether the terminal shows OSC 9 as a
+// notification. Others may print it or misread it (ConEmu uses OSC 9 for
+// other things), so only known terminals get it.
+func detectDesktopNotifications(getenv func(string) string) bool {
+	if getenv("TERM") == "dumb" {
+		return false
+	}
+	return notifyTermPrograms[getenv("TERM_PROGRAM")]
+}
+
+// Notify rings the terminal bell and, where supported, posts message as an
+// OSC 9 desktop notification
+func Notify(message string) {
+	out := "\a"
+	if desktopNotifications {
+		// Control characters would end the sequence early
+		clean := strings.Map(func(r rune) rune {
+			if r < 0x20 || r == 0x7f {
+				return ' '
+			}
+			return r
+		}, message)
+		out = fmt.Sprintf("\x1b]9;%s\a", clean)
+	}
+	_, _ = io.WriteString(notifyOut, out)
+}
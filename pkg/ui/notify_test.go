@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNotify(t *testing.T) {
+	var buf bytes.Buffer
+	originalOut, originalDesktop := notifyOut, desktopNotifications
+	notifyOut = &buf
+	defer func() { notifyOut, desktopNotifications = originalOut, originalDesktop }()
+
+	desktopNotifications = false
+	Notify("2 new comments")
+	if got := buf.String(); got != "\a" {
+		t.Errorf("Notify() without desktop notifications wrote %q, want a bell", got)
+	}
+
+	buf.Reset()
+	desktopNotifications = true
+	Notify("2 new\ncomments")
+	if got, want := buf.String(), "\x1b]9;2 new comments\a"; got != want {
+		t.Errorf("Notify() wrote %q, want %q", got, want)
+	}
+}
+
+func TestDetectDesktopNotifications(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "iTerm2", env: map[string]string{"TERM_PROGRAM": "iTerm.app"}, want: true},
+		{name: "unknown terminal", env: map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, want: false},
+		{name: "dumb terminal", env: map[string]string{"TERM": "dumb", "TERM_PROGRAM": "WezTerm"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := detectDesktopNotifications(getenv); got != tt.want {
+				t.Errorf("detectDesktopNotifications() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
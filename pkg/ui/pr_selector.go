@@ -34,10 +34,15 @@ func (r *prItemRenderer) Description(pr *github.PullRequest) string {
 }
 
 func (r *prItemRenderer) Preview(pr *github.PullRequest) string {
-	return r.PreviewWithHighlight(pr, -1)
+	return r.PreviewWithHighlight(pr, -1, "")
 }
 
-func (r *prItemRenderer) PreviewWithHighlight(pr *github.PullRequest, highlightIdx int) string {
+func (r *prItemRenderer) PreviewWithHighlight(pr *github.PullRequest, highlightIdx int, query string) string {
+	return HighlightMatches(r.renderPreview(pr), query)
+}
+
+// renderPreview builds the detail view for a pull request before search highlighting
+func (r *prItemRenderer) renderPreview(pr *github.PullRequest) string {
 	var preview strings.Builder
 
 	// Header
@@ -48,6 +53,9 @@ func (r *prItemRenderer) PreviewWithHighlight(pr *github.PullRequest, highlightI
 	fmt.Fprintf(&preview, "Title: %s\n", pr.Title)
 	fmt.Fprintf(&preview, "Author: @%s\n", pr.Author)
 	fmt.Fprintf(&preview, "Branch: %s\n", pr.HeadRefName)
+	if len(pr.Assignees) > 0 {
+		fmt.Fprintf(&preview, "Assignees: @%s\n", strings.Join(pr.Assignees, ", @"))
+	}
 
 	if pr.IsDraft {
 		preview.WriteString(Colorize(ColorYellow, "\nStatus: Draft\n"))
@@ -1,9 +1,32 @@
 package ui
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/diffhunk"
+	"github.com/muesli/reflow/wordwrap"
 )
 
+// quoteWrapWidth is the column quoted bodies are wrapped to, "> " included
+const quoteWrapWidth = 80
+
+// quoteMarkerRe matches the blockquote markers a line already starts with
+var quoteMarkerRe = regexp.MustCompile(`^(?:> ?)+`)
+
+// mentionOnReply adds a bare @author line under quoted replies so the reply
+// notifies the quoted author
+var mentionOnReply bool
+
+// SetMentionOnReply controls whether FormatQuotedReply seeds the reply with
+// an @mention of the quoted author. Off by default since some teams find the
+// extra notifications noisy.
+func SetMentionOnReply(enabled bool) {
+	mentionOnReply = enabled
+}
+
 // FormatBlockquote formats text as a GitHub markdown blockquote.
 // Each line is prefixed with "> ".
 func FormatBlockquote(text string) string {
@@ -20,14 +43,17 @@ func FormatBlockquote(text string) string {
 
 // FormatQuotedReply formats a review comment as a blockquote for replying.
 // If includeContext is true, it includes the diff hunk as a quoted code block
-// above the author attribution, with the file path.
-func FormatQuotedReply(author, body, diffHunk, path string, includeContext bool) string {
+// above the author attribution, with the file path; contextLines, when
+// positive, keeps only that many of the hunk's last lines (those nearest the
+// commented line). If wrapQuotes is true, long lines of the body are wrapped
+// at 80 columns (see wrapQuoteBody).
+func FormatQuotedReply(author, body, diffHunk, path string, includeContext bool, contextLines int, wrapQuotes bool) string {
 	var parts []string
 
 	// Optionally add code context first (above the author line)
 	if includeContext && diffHunk != "" {
 		// Format the diff hunk with git-style headers
-		formattedDiff := FormatDiffWithHeaders(diffHunk, path)
+		formattedDiff := FormatDiffWithHeaders(truncateQuotedHunk(diffHunk, contextLines), path)
 		// Wrap in a quoted code fence
 		parts = append(parts, "> ```diff")
 		for _, line := range strings.Split(formattedDiff, "\n") {
@@ -44,12 +70,135 @@ func FormatQuotedReply(author, body, diffHunk, path string, includeContext bool)
 	// Add comment body (strip suggestion blocks for cleaner quoting)
 	cleanBody := StripSuggestionBlock(body)
 	if cleanBody != "" {
+		if wrapQuotes {
+			cleanBody = wrapQuoteBody(cleanBody, quoteWrapWidth-len("> "))
+		}
 		parts = append(parts, FormatBlockquote(cleanBody))
 	}
 
 	// Add empty lines for user's reply
 	parts = append(parts, "")
+	if mentionOnReply && author != "" {
+		parts = append(parts, "@"+author+" ")
+	}
 	parts = append(parts, "")
 
 	return strings.Join(parts, "\n")
 }
+
+// truncateQuotedHunk keeps the last maxLines lines of diffHunk under an @@
+// header recomputed for them, so the quote still says where the lines are
+func truncateQuotedHunk(diffHunk string, maxLines int) string {
+	header, rest, found := strings.Cut(diffHunk, "\n")
+	if !found || !strings.HasPrefix(header, "@@") {
+		return TruncateDiffTail(diffHunk, maxLines)
+	}
+	lines := strings.Split(rest, "\n")
+	if maxLines <= 0 || len(lines) <= maxLines {
+		return diffHunk
+	}
+	hunk, err := diffhunk.ParseDiffHunk(header)
+	if err != nil {
+		return header + "\n" + TruncateDiffTail(rest, maxLines)
+	}
+
+	dropped, kept := lines[:len(lines)-maxLines], lines[len(lines)-maxLines:]
+	oldStart, newStart := hunk.OldStart, hunk.NewStart
+	for _, line := range dropped {
+		oldLines, newLines := hunkLineCounts(line)
+		oldStart += oldLines
+		newStart += newLines
+	}
+	oldCount, newCount := 0, 0
+	for _, line := range kept {
+		oldLines, newLines := hunkLineCounts(line)
+		oldCount += oldLines
+		newCount += newLines
+	}
+
+	// Keep the section heading (e.g. the function name) after the ranges
+	_, section, _ := strings.Cut(header[len("@@"):], "@@")
+	header = fmt.Sprintf("@@ -%s +%s @@%s", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount), section)
+	return header + "\n...\n" + strings.Join(kept, "\n")
+}
+
+// hunkLineCounts returns how many old-side and new-side lines a diff line is
+func hunkLineCounts(line string) (oldLines, newLines int) {
+	switch {
+	case strings.HasPrefix(line, " "):
+		return 1, 1
+	case strings.HasPrefix(line, "-"):
+		return 1, 0
+	case strings.HasPrefix(line, "+"):
+		return 0, 1
+	}
+	return 0, 0
+}
+
+// hunkRange formats one side of an @@ header. A side with no lines names the
+// line before the hunk, as git does.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// wrapQuoteBody word-wraps each line of body to width, repeating any
+// blockquote markers the line starts with on its continuation lines. Lines
+// inside fenced code blocks are left alone, as are words longer than width
+// (e.g. URLs).
+func wrapQuoteBody(body string, width int) string {
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		marker := quoteMarkerRe.FindString(line)
+		content := line[len(marker):]
+		if fence := strings.TrimSpace(content); strings.HasPrefix(fence, "```") || strings.HasPrefix(fence, "~~~") {
+			inFence = !inFence
+			out = append(out, line)
+			continue
+		}
+		if inFence || len(line) <= width {
+			out = append(out, line)
+			continue
+		}
+		for _, segment := range strings.Split(wordwrap.String(content, max(width-len(marker), 1)), "\n") {
+			out = append(out, marker+segment)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// FormatSuggestionTemplate seeds a reply with a GitHub suggestion block
+// holding the commented line, taken from the end of the diff hunk (the line a
+// review comment is anchored to). Removed lines are skipped since suggestions
+// replace the new version of the code.
+func FormatSuggestionTemplate(diffHunk, path string) string {
+	var original string
+	for _, line := range strings.Split(strings.TrimRight(diffHunk, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"), strings.HasPrefix(line, "-"):
+			continue
+		case strings.HasPrefix(line, "+"), strings.HasPrefix(line, " "):
+			original = line[1:]
+		default:
+			original = line
+		}
+	}
+
+	parts := []string{
+		"```suggestion",
+		original,
+		"```",
+		"",
+		FormatEditorTemplate(
+			"Edit the suggested code for "+path+" above; add any explanation before the block.",
+			"Everything from the marker line down is ignored.",
+		),
+	}
+	return strings.Join(parts, "\n")
+}
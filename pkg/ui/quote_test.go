@@ -141,7 +141,7 @@ func TestFormatQuotedReply(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := FormatQuotedReply(tt.author, tt.body, tt.diffHunk, tt.path, tt.includeContext)
+			result := FormatQuotedReply(tt.author, tt.body, tt.diffHunk, tt.path, tt.includeContext, 0, false)
 
 			for _, expected := range tt.checkContains {
 				if !strings.Contains(result, expected) {
@@ -165,7 +165,7 @@ func TestFormatQuotedReply(t *testing.T) {
 
 func TestFormatQuotedReplyStructure(t *testing.T) {
 	// Test that context appears before author attribution
-	result := FormatQuotedReply("user", "body", "@@ -1 +1 @@\n+line", "file.go", true)
+	result := FormatQuotedReply("user", "body", "@@ -1 +1 @@\n+line", "file.go", true, 0, false)
 
 	contextIdx := strings.Index(result, "```diff")
 	authorIdx := strings.Index(result, "@user wrote:")
@@ -180,3 +180,119 @@ func TestFormatQuotedReplyStructure(t *testing.T) {
 		t.Errorf("Context should appear before author attribution, but context at %d, author at %d", contextIdx, authorIdx)
 	}
 }
+
+func TestFormatQuotedReplyContextLines(t *testing.T) {
+	hunk := "@@ -1,6 +1,6 @@\n one\n two\n three\n four\n five\n+six"
+
+	got := FormatQuotedReply("user", "body", hunk, "file.go", true, 2, false)
+	want := "> ```diff\n> --- a/file.go\n> +++ b/file.go\n> @@ -5 +5,2 @@\n> ...\n>  five\n> +six\n> ```"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("FormatQuotedReply() with 2 context lines =\n%s\nwant prefix\n%s", got, want)
+	}
+
+	if got := FormatQuotedReply("user", "body", hunk, "file.go", true, 0, false); !strings.Contains(got, ">  one") {
+		t.Errorf("Expected the whole hunk with no limit, got:\n%s", got)
+	}
+}
+
+func TestFormatQuotedReplyMention(t *testing.T) {
+	if got := FormatQuotedReply("user", "body", "", "", false, 0, false); strings.Contains(got, "\n@user ") {
+		t.Errorf("Expected no mention by default, got %q", got)
+	}
+
+	SetMentionOnReply(true)
+	t.Cleanup(func() { SetMentionOnReply(false) })
+
+	got := FormatQuotedReply("user", "body", "", "", false, 0, false)
+	if want := "> @user wrote:\n>\n> body\n\n@user \n"; got != want {
+		t.Errorf("FormatQuotedReply() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatQuotedReplyWrapQuotes(t *testing.T) {
+	long := strings.Repeat("word ", 30)
+	body := long + "\n> " + long + "\n```go\n" + long + "\n```\nhttps://example.com/" + strings.Repeat("x", 90)
+
+	if got := FormatQuotedReply("user", body, "", "", false, 0, false); !strings.Contains(got, "> "+long) {
+		t.Errorf("Expected long lines kept whole with wrapping off, got:\n%s", got)
+	}
+
+	got := FormatQuotedReply("user", body, "", "", false, 0, true)
+	lines := strings.Split(got, "\n")
+	inFence := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "> ```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || strings.Contains(line, "https://") {
+			continue
+		}
+		if len(line) > quoteWrapWidth {
+			t.Errorf("Expected quoted lines of at most %d columns, got %d: %q", quoteWrapWidth, len(line), line)
+		}
+	}
+	if !strings.Contains(got, "> ```go\n> "+long+"\n> ```") {
+		t.Errorf("Expected fenced code left unwrapped, got:\n%s", got)
+	}
+	if !strings.Contains(got, "> https://example.com/"+strings.Repeat("x", 90)) {
+		t.Errorf("Expected a long URL kept on one line, got:\n%s", got)
+	}
+	// Continuations of an already-quoted line keep its marker
+	nested := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "> > word") {
+			nested++
+		}
+	}
+	if nested < 2 {
+		t.Errorf("Expected the nested quote wrapped with its marker repeated, got:\n%s", got)
+	}
+}
+
+func TestFormatSuggestionTemplate(t *testing.T) {
+	hunk := "@@ -10,3 +10,3 @@ func main() {\n \tx := 1\n-\ty := 2\n+\ty := compute()"
+
+	got := FormatSuggestionTemplate(hunk, "main.go")
+	if !strings.HasPrefix(got, "```suggestion\n\ty := compute()\n```\n") {
+		t.Errorf("Expected a suggestion block with the commented line, got:\n%s", got)
+	}
+	if !strings.Contains(got, "main.go") {
+		t.Errorf("Expected the path in the instructions, got:\n%s", got)
+	}
+
+	// The instructions are dropped on submit but the suggestion fences survive
+	if want := "```suggestion\n\ty := compute()\n```"; SanitizeEditorContent(got) != want {
+		t.Errorf("SanitizeEditorContent() = %q, want %q", SanitizeEditorContent(got), want)
+	}
+}
+
+func TestFormatSuggestionTemplate_EmptyHunk(t *testing.T) {
+	got := FormatSuggestionTemplate("", "main.go")
+	if !strings.HasPrefix(got, "```suggestion\n\n```") {
+		t.Errorf("Expected an empty suggestion block, got:\n%s", got)
+	}
+}
+
+func TestTruncateQuotedHunkHeader(t *testing.T) {
+	hunk := "@@ -10,6 +10,4 @@ func main() {\n a\n-b\n+c\n-d\n-e\n f\n g"
+	tests := []struct {
+		maxLines int
+		want     string
+	}{
+		{0, hunk},
+		{7, hunk},
+		{2, "@@ -14,2 +12,2 @@ func main() {\n...\n f\n g"},
+		{4, "@@ -12,4 +12,2 @@ func main() {\n...\n-d\n-e\n f\n g"},
+		{5, "@@ -12,4 +11,3 @@ func main() {\n...\n+c\n-d\n-e\n f\n g"},
+	}
+	for _, tt := range tests {
+		if got := truncateQuotedHunk(hunk, tt.maxLines); got != tt.want {
+			t.Errorf("truncateQuotedHunk(%d) =\n%s\nwant\n%s", tt.maxLines, got, tt.want)
+		}
+	}
+
+	if got := truncateQuotedHunk("@@ -3 +3,3 @@\n x\n+y\n+z", 2); !strings.HasPrefix(got, "@@ -3,0 +4,2 @@\n") {
+		t.Errorf("expected an empty old side to name the line before, got:\n%s", got)
+	}
+}
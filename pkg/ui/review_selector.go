@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+)
+
+// reviewItemRenderer implements ItemRenderer for Review
+type reviewItemRenderer struct{}
+
+func (r *reviewItemRenderer) Title(review *github.Review) string {
+	// Format: "✓ Approved by @alice"
+	return fmt.Sprintf("%s by %s", formatReviewState(review.State), Colorize(ColorCyan, "@"+review.Author))
+}
+
+func (r *reviewItemRenderer) Description(review *github.Review) string {
+	// Format: "3 comments • 2 days ago • Looks good overall"
+	parts := []string{pluralize(review.CommentCount, "comment")}
+	if !review.SubmittedAt.IsZero() {
+		parts = append(parts, FormatRelativeTime(review.SubmittedAt))
+	}
+	if line, _, _ := strings.Cut(strings.TrimSpace(review.Body), "\n"); line != "" {
+		parts = append(parts, line)
+	}
+	return "  " + Colorize(ColorGray, strings.Join(parts, " • "))
+}
+
+func (r *reviewItemRenderer) Preview(review *github.Review) string {
+	return r.PreviewWithHighlight(review, -1, "")
+}
+
+func (r *reviewItemRenderer) PreviewWithHighlight(review *github.Review, highlightIdx int, query string) string {
+	return HighlightMatches(r.renderPreview(review), query)
+}
+
+// renderPreview builds the detail view for a review before search highlighting
+func (r *reviewItemRenderer) renderPreview(review *github.Review) string {
+	var preview strings.Builder
+
+	// Header
+	preview.WriteString(Colorize(ColorCyan, fmt.Sprintf("Review by @%s\n", review.Author)))
+	preview.WriteString(Colorize(ColorCyan, strings.Repeat("=", 50)))
+	preview.WriteString("\n\n")
+
+	fmt.Fprintf(&preview, "State: %s\n", formatReviewState(review.State))
+	if !review.SubmittedAt.IsZero() {
+		fmt.Fprintf(&preview, "Submitted: %s\n", FormatRelativeTime(review.SubmittedAt))
+	}
+	fmt.Fprintf(&preview, "Comments: %d\n", review.CommentCount)
+	if review.HTMLURL != "" {
+		fmt.Fprintf(&preview, "URL: %s\n", CreateHyperlink(review.HTMLURL, review.HTMLURL))
+	}
+
+	if body := strings.TrimSpace(review.Body); body != "" {
+		rendered, err := RenderMarkdown(body)
+		if err != nil {
+			rendered = body
+		}
+		preview.WriteString("\n" + strings.TrimRight(rendered, "\n") + "\n")
+	}
+
+	preview.WriteString("\n" + Colorize(ColorGray, "Press Enter to browse this review's comments"))
+
+	return preview.String()
+}
+
+func (r *reviewItemRenderer) FilterValue(review *github.Review) string {
+	// Allow filtering by author, state, or body
+	return fmt.Sprintf("%s %s %s", review.Author, review.State, review.Body)
+}
+
+func (r *reviewItemRenderer) EditPath(review *github.Review) string {
+	return "" // Not applicable for reviews
+}
+
+func (r *reviewItemRenderer) EditLine(review *github.Review) int {
+	return 0 // Not applicable for reviews
+}
+
+func (r *reviewItemRenderer) IsSkippable(review *github.Review) bool {
+	return false
+}
+
+func (r *reviewItemRenderer) ThreadCommentCount(review *github.Review) int {
+	return 0 // Not applicable for reviews
+}
+
+func (r *reviewItemRenderer) ThreadCommentPreview(review *github.Review, idx int) string {
+	return "" // Not applicable for reviews
+}
+
+func (r *reviewItemRenderer) WithSelectedComment(review *github.Review, idx int) *github.Review {
+	return review // No-op for reviews
+}
+
+// formatReviewState formats a review's state with appropriate color and emoji
+func formatReviewState(state string) string {
+	switch state {
+	case "APPROVED":
+		return Colorize(ColorGreen, EmojiText("✓ Approved", "Approved"))
+	case "CHANGES_REQUESTED":
+		return Colorize(ColorRed, EmojiText("✗ Changes requested", "Changes requested"))
+	case "COMMENTED":
+		return Colorize(ColorYellow, EmojiText("💬 Commented", "Commented"))
+	case "DISMISSED":
+		return Colorize(ColorGray, "Dismissed")
+	default:
+		return state
+	}
+}
+
+// pluralize formats n with noun, adding an s unless n is 1
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// SelectReview displays an interactive selector for choosing one of a pull
+// request's reviews
+func SelectReview(reviews []*github.Review) (*github.Review, error) {
+	renderer := &reviewItemRenderer{}
+	return SelectFromList(reviews, renderer)
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gh-tui-tools/gh-review-conductor/pkg/github"
+)
+
+func TestReviewItemRenderer(t *testing.T) {
+	originalEnabled := colorEnabled
+	defer func() { colorEnabled = originalEnabled }()
+	colorEnabled = false
+
+	r := &reviewItemRenderer{}
+	review := &github.Review{
+		State:        "CHANGES_REQUESTED",
+		Author:       "alice",
+		Body:         "Needs tests\n\nSee the inline comments.",
+		HTMLURL:      "https://github.com/owner/repo/pull/1#pullrequestreview-1",
+		SubmittedAt:  time.Now().Add(-2 * time.Hour),
+		CommentCount: 1,
+	}
+
+	if title := r.Title(review); !strings.Contains(title, "Changes requested by @alice") {
+		t.Errorf("unexpected title %q", title)
+	}
+	desc := r.Description(review)
+	for _, want := range []string{"1 comment •", "Needs tests"} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("expected %q in description %q", want, desc)
+		}
+	}
+	if strings.Contains(desc, "See the inline comments") {
+		t.Errorf("expected only the body's first line in description %q", desc)
+	}
+
+	preview := r.Preview(review)
+	for _, want := range []string{"Review by @alice", "Comments: 1", review.HTMLURL, "Press Enter to browse"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("expected %q in preview:\n%s", want, preview)
+		}
+	}
+}
+
+func TestFormatReviewState(t *testing.T) {
+	originalEnabled := colorEnabled
+	defer func() { colorEnabled = originalEnabled }()
+	colorEnabled = false
+
+	for state, want := range map[string]string{
+		"APPROVED":          "Approved",
+		"CHANGES_REQUESTED": "Changes requested",
+		"COMMENTED":         "Commented",
+		"DISMISSED":         "Dismissed",
+		"PENDING":           "PENDING",
+	} {
+		if got := formatReviewState(state); !strings.HasSuffix(got, want) {
+			t.Errorf("formatReviewState(%q) = %q, want it to end in %q", state, got, want)
+		}
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	for n, want := range map[int]string{0: "0 comments", 1: "1 comment", 3: "3 comments"} {
+		if got := pluralize(n, "comment"); got != want {
+			t.Errorf("pluralize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
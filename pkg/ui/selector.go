@@ -3,8 +3,12 @@ package ui
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -19,7 +23,8 @@ type ItemRenderer[T any] interface {
 	Preview(item T) string
 	// PreviewWithHighlight returns detailed preview text with a specific comment highlighted
 	// highlightIdx: 0 = main comment, 1+ = thread replies. -1 = no highlight.
-	PreviewWithHighlight(item T, highlightIdx int) string
+	// query is the active list filter; matches should be marked with HighlightMatches.
+	PreviewWithHighlight(item T, highlightIdx int, query string) string
 	// EditPath returns the file path to open in editor (optional)
 	EditPath(item T) string
 	// EditLine returns the line number to go to in editor (1-based, optional)
@@ -38,20 +43,90 @@ type ItemRenderer[T any] interface {
 	WithSelectedComment(item T, idx int) T
 }
 
+// PreviewWidthSetter is implemented by renderers whose inline previews (list
+// titles, thread comment previews) can adapt to the available width
+type PreviewWidthSetter interface {
+	// SetPreviewWidth sets the number of columns available to a list title
+	SetPreviewWidth(width int)
+}
+
+// ColumnMeasurer is implemented by renderers that line up columns across
+// list rows. The selector passes the items the list can show (those kept by
+// the h filter) whenever they change or the list is resized.
+type ColumnMeasurer[T any] interface {
+	// MeasureColumns sizes the renderer's columns to fit items
+	MeasureColumns(items []T)
+}
+
+// RawPreviewer is implemented by renderers that can show their detail
+// preview as unrendered markdown source, toggled with M in the detail view
+type RawPreviewer[T any] interface {
+	// PreviewRaw is PreviewWithHighlight with comment bodies left as source
+	PreviewRaw(item T, highlightIdx int, query string) string
+}
+
+// ReplyFolder is implemented by renderers that can fold the older replies of
+// a long thread in the detail view
+type ReplyFolder interface {
+	// SetVisibleReplies sets how many of the latest replies the next preview
+	// shows in full; a negative n shows them all
+	SetVisibleReplies(n int)
+}
+
+// DefaultVisibleReplies is how many of the latest replies a ReplyFolder
+// shows when a detail view opens
+const DefaultVisibleReplies = 3
+
+// PreviewWidthFit sizes previews to the list width
+const PreviewWidthFit = -1
+
 // CustomAction is a function that handles custom actions on items
 type CustomAction[T any] func(item T) (string, error)
 
 // EditorPreparer returns the initial content for the editor, or error to abort
 type EditorPreparer[T any] func(item T) (string, error)
 
-// EditorCompleter is called with the editor content to complete the action
-type EditorCompleter[T any] func(item T, editorContent string) (string, error)
+// EditorCompleter is called in the background with the editor content to
+// complete the action. Like a BackgroundAction, it leaves the item alone and
+// returns apply to record what it did.
+type EditorCompleter[T any] func(item T, editorContent string) (result string, apply func(), err error)
+
+// BackgroundAction runs off the UI goroutine, so it mustn't change the item:
+// View reads it meanwhile. It returns apply instead, which records the
+// outcome (e.g. a posted reply) on the UI goroutine. apply may be nil, and
+// runs even with an error so that partial work still shows.
+type BackgroundAction[T any] func(item T) (result string, apply func(), err error)
 
 // editorFinishedMsg is sent when an editor process completes
 type editorFinishedMsg struct {
 	err error
 }
 
+// BatchResult reports how a batch action went item by item, so the items
+// that failed can be retried on their own
+type BatchResult[T any] struct {
+	Summary    string            // Status text, e.g. "Replied to 2 of 3 threads"
+	Succeeded  []T               // Items the action completed for
+	Failed     []T               // Items it failed for, in the order tried
+	Errors     map[string]error  // Why each failed item failed, by ItemKey
+	FailedStep map[string]string // The step each failed item stopped at, by ItemKey, for a retry to resume from
+	Apply      func()            // Records what was done on the items; run on the UI goroutine
+}
+
+// batchCompleteMsg carries the BatchResult[T] of a batch action run in the
+// background, with the body it sent so failures can be retried
+type batchCompleteMsg struct {
+	result any // BatchResult[T]
+	body   string
+}
+
+// editorCompleteMsg carries the result of an EditorCompleter run in the background
+type editorCompleteMsg struct {
+	result string
+	apply  func()
+	err    error
+}
+
 // loadDetailMsg triggers the actual detail loading after showing loading state
 type loadDetailMsg struct{}
 
@@ -59,6 +134,39 @@ type loadDetailMsg struct{}
 type refreshFinishedMsg struct {
 	items any // will be []T
 	err   error
+	watch bool // true when triggered by the watch poll rather than the user
+}
+
+// preloadFinishedMsg carries the outcome of Preload, applied on the UI goroutine
+type preloadFinishedMsg struct {
+	apply func()
+	err   error
+}
+
+// watchTickMsg triggers a background refresh in watch mode
+type watchTickMsg struct{}
+
+// resolveFinishedMsg reports the outcome of an optimistic resolve
+type resolveFinishedMsg struct {
+	key    string // ItemKey of the item
+	status string
+	apply  func() // records what the call learned (e.g. a looked-up ID)
+	err    error
+	undo   func() // restores the item's state if the call failed
+}
+
+// acceptFinishedMsg carries the outcome of AcceptSuggestionAction
+type acceptFinishedMsg struct {
+	report string
+	apply  func()
+	err    error
+}
+
+// draftSubmitFinishedMsg carries the outcome of DraftSubmit
+type draftSubmitFinishedMsg struct {
+	status string
+	apply  func()
+	err    error
 }
 
 // agentFinishedMsg is sent when the coding agent process completes
@@ -69,6 +177,9 @@ type agentFinishedMsg struct {
 // ErrNoSelection is returned when no item was selected
 var ErrNoSelection = errors.New("no selection made")
 
+// ErrSwitchRequested is returned when the selector was left with SwitchKey
+var ErrSwitchRequested = errors.New("switch requested")
+
 // SelectorOptions configures the interactive selector.
 // Use this struct to configure all selector behavior in a readable way.
 type SelectorOptions[T any] struct {
@@ -81,14 +192,85 @@ type SelectorOptions[T any] struct {
 	OnOpen         CustomAction[T]     // Called when 'o' is pressed
 	FilterFunc     func(T, bool) bool  // Filter items based on state
 	FilterDefault  bool                // Initial filter state (true = filter active, e.g., hide resolved)
+	FilterModes    []string            // Optional: status labels for an h-key cycle of more than two filter states
+	FilterModeFunc func(T, int) bool   // Filter items by index into FilterModes; used instead of FilterFunc when set
 	IsItemResolved func(T) bool        // For dynamic key display (r vs u)
-	RefreshItems   func() ([]T, error) // Called when 'i' is pressed
+	RefreshItems   func() ([]T, error) // Called when 'i' is pressed, and on start when Items is empty
+	OnDetailOpen   func(T)             // Called when the detail view finishes loading
+	StatusWarning  func() string       // Optional warning shown at the start of the footer (e.g., rate limit)
+	Header         func([]T) string    // Optional list header computed from all items on each render (e.g., repo, PR, counts)
+	IsGroupHeader  func(T) bool        // Marks items '{' and '}' jump between (e.g., file headers)
+	IsToggle       func(T) bool        // Marks items Enter toggles in place: OnSelect runs and the list refilters instead of opening the detail view
+	InitialItem    func(T) bool        // Optional: preselects the first matching item, dropping the h filter if it hides it
+	Legend         []LegendEntry       // Optional: the renderer's colors and markers, explained in the help overlay
+
+	// Optional: run in the background on start (e.g., to look up the signed-in
+	// user). The func it returns records the result on the UI goroutine and
+	// the list refilters; an error is shown in the status bar.
+	Preload func() (func(), error)
+
+	// Action: g (jump to a comment by ID; replaces the list's g=go to top)
+	MatchesCommentID func(item T, id int64) bool // Reports whether the item holds the comment
+	RevealItem       func(item T)                // Optional: un-hides the item before jumping (e.g., expands its file)
+	JumpKey          string                      // e.g., "g go to comment"
+
+	// Action: ctrl+p (leave the list so the caller can switch to something
+	// else, e.g. another pull request; Select returns ErrSwitchRequested)
+	SwitchKey         string               // e.g., "ctrl+p switch PR"
+	OnSwitchRequested func(filterMode int) // Optional: called with the current filter mode, to carry it over
+	InitialFilterMode int                  // Optional: index into FilterModes to start on (e.g., one carried over), overriding FilterDefault
+
+	// Filter bar: a one-line summary of active filters above the list
+	ActiveFilters   func() []string // Labels for filters applied by FilterFunc/FilterModeFunc beyond the h state (e.g., "todo only")
+	ClearFilters    func()          // Resets the caller's filters; the selector resets its own on ClearFiltersKey
+	ClearFiltersKey string          // e.g., "X clear filters"
+
+	// Action: I (narrow the list to paths matching a glob typed in the
+	// footer; an empty glob shows every path again)
+	SetPathGlob func(glob string) error // Applied by FilterFunc/FilterModeFunc; errors on a bad pattern
+	PathGlob    func() string           // The active glob, "" for none
+	PathGlobKey string                  // e.g., "I path glob"
+
+	// Empty state: shown centered in place of the list when every item left
+	// is skippable (e.g., only file headers). Called with the active filter
+	// labels, none when there is simply nothing to show; "" keeps the list.
+	EmptyMessage func(filters []string) string
+
+	// Action: L (load the items left out by a cap such as browse --limit).
+	// These run on the UI goroutine, so they can keep the cap's state.
+	LimitItems  func([]T) []T // Optional: caps the items RefreshItems returns
+	LimitStatus func() string // e.g., "showing 50 of 312"; "" when nothing is left out
+	LoadAll     func() []T    // Lifts the cap and returns every item
+	LoadAllKey  string        // e.g., "L load all"
+
+	// PreviewWidth caps inline previews for renderers implementing
+	// PreviewWidthSetter. 0 keeps the renderer's defaults; PreviewWidthFit
+	// follows the list width and reflows on resize.
+	PreviewWidth int
+
+	// RowHeight is the number of lines per list row. With 2 or more, each row
+	// shows the title with the item's Description on the lines below it;
+	// 0 or 1 puts both on one line.
+	RowHeight int
+
+	// Watch mode: poll RefreshItems in the background
+	WatchInterval   time.Duration                 // Poll interval; 0 disables watch mode
+	ItemKey         func(T) string                // Identifies an item across refreshes so selection is kept
+	DescribeChanges func(old, updated []T) string // Status flashed after a refresh; "" for no change
+	Notify          bool                          // Ring the bell (and notify where supported) when DescribeChanges reports a change
 
 	// Action: r/u (resolve toggle)
 	ResolveAction CustomAction[T]
 	ResolveKey    string // e.g., "r resolve"
 	ResolveKeyAlt string // e.g., "u unresolve"
 
+	// Optimistic r/u (requires ItemKey): flips the item's displayed state at
+	// once and returns the API call to run in the background, plus an undo
+	// applied if that call fails. Like a BackgroundAction, the call leaves the
+	// item alone and returns apply to record anything else it learned. Used
+	// instead of ResolveAction when set.
+	ResolveStart func(item T) (call func() (string, func(), error), undo func(), err error)
+
 	// Action: R/U (resolve+comment via editor)
 	ResolveCommentPrepare  EditorPreparer[T]
 	ResolveCommentComplete EditorCompleter[T]
@@ -105,6 +287,35 @@ type SelectorOptions[T any] struct {
 	QuoteContextComplete EditorCompleter[T]
 	QuoteContextKey      string // e.g., "C quote+context"
 
+	// Action: G (reply with a suggestion block via editor)
+	SuggestPrepare  EditorPreparer[T]
+	SuggestComplete EditorCompleter[T]
+	SuggestKey      string // e.g., "G suggest"
+
+	// Action: W (won't fix: reply with a templated reason and resolve, via editor)
+	DismissPrepare  EditorPreparer[T]
+	DismissComplete EditorCompleter[T]
+	DismissKey      string // e.g., "W won't fix"
+
+	// Action: m (short reply typed in the selector instead of $EDITOR).
+	// ReplyPrepare returns the starting text, or an error if the item can't
+	// be replied to; ReplyComplete posts the sanitized text.
+	ReplyPrepare  EditorPreparer[T]
+	ReplyComplete EditorCompleter[T]
+	ReplyKey      string // e.g., "m reply"
+
+	// Marking: space marks items for batch actions (requires ItemKey)
+	Markable func(T) bool // Reports whether an item can be marked (e.g., comments but not file headers)
+
+	// Action: B (reply to every marked item with one editor body); after a
+	// batch with failures, F sends the same body to just the failed items,
+	// passing each one's FailedStep as resume so steps that worked aren't
+	// repeated. resume is nil for a fresh batch.
+	BatchReplyPrepare  func(items []T) (string, error)                                       // Returns the initial editor content
+	BatchReplyComplete func(items []T, body string, resume map[string]string) BatchResult[T] // Posts body to each item
+	BatchReplyKey      string                                                                // e.g., "B batch reply"
+	BatchRetryKey      string                                                                // e.g., "F retry failed"
+
 	// Action: a (launch agent)
 	AgentAction CustomAction[T]
 	AgentKey    string // e.g., "a agent"
@@ -117,6 +328,43 @@ type SelectorOptions[T any] struct {
 	ReactionAction   func(T) (int64, error)                                                      // Returns comment ID to react to
 	ReactionComplete func(item T, commentID int64, apiName, displayEmoji string) (string, error) // Applies reaction, returns confirmation message
 	ReactionKey      string                                                                      // e.g., "x react"
+	ReactionOrder    []string                                                                    // Optional: reaction names offered first (e.g., "rocket", "+1"); see ValidateReactionOrder
+
+	// Action: O (open file on GitHub, after confirming the URL)
+	OpenFilePreview CustomAction[T] // Returns the URL to confirm
+	OpenFileAction  CustomAction[T] // Opens the URL
+	OpenFileKey     string          // e.g., "O open file"
+
+	// Action: t/T (local triage tags)
+	TagAction       CustomAction[T] // Cycles the item's local tag
+	TagKey          string          // e.g., "t tag"
+	TagFilterToggle func() string   // Toggles a tag-based filter applied by FilterFunc; returns status text
+	TagFilterKey    string          // e.g., "T todo only"
+
+	// Action: H (hide outdated items)
+	OutdatedFilterToggle func() string // Toggles an outdated filter applied by FilterFunc; returns status text
+	OutdatedFilterKey    string        // e.g., "H hide outdated"
+
+	// Action: D (debug overlay with the item's raw fields; only with --debug)
+	DebugInfo func(T) string // Returns one "name: value" line per field
+
+	// Action: y (copy equivalent gh CLI command)
+	CopyCommand    func(T) (string, error) // Returns the command to copy to the clipboard
+	CopyCommandKey string                  // e.g., "y copy gh cmd"
+
+	// Action: p (copy a permalink to the code the item refers to)
+	CopyPermalink    func(T) (string, error) // Returns the URL to copy to the clipboard
+	CopyPermalinkKey string                  // e.g., "p copy permalink"
+
+	// Action: c/v (copy the item as a markdown quote, without/with context)
+	CopyQuote           func(T) (string, error) // Returns the quote to copy to the clipboard
+	CopyQuoteKey        string                  // e.g., "c copy quote"
+	CopyQuoteContext    func(T) (string, error) // Like CopyQuote, with surrounding context (e.g. the diff)
+	CopyQuoteContextKey string                  // e.g., "v copy quote+context"
+
+	// Action: Y (copy all items, e.g. as a markdown checklist; list view only)
+	CopyAll    func([]T) (string, error) // Formats every item, ignoring filters, for the clipboard
+	CopyAllKey string                    // e.g., "Y copy checklist"
 
 	// Action: s (apply suggestion)
 	ApplySuggestionPreview CustomAction[T] // Returns diff preview string
@@ -126,6 +374,26 @@ type SelectorOptions[T any] struct {
 	// Action: S (apply suggestion and resolve)
 	ApplySuggestionResolveAction CustomAction[T]
 	ApplySuggestionResolveKey    string // e.g., "S apply+resolve"
+
+	// Action: A (accept: apply the suggestion, reply and resolve, no preview)
+	AcceptSuggestionAction BackgroundAction[T] // Returns a report of each step
+	AcceptSuggestionKey    string              // e.g., "A accept"
+
+	// Draft mode: P makes replies buffer as drafts instead of posting, Z
+	// posts the buffered drafts together as one review. DraftSubmit takes
+	// the drafts on the UI goroutine and returns the call that posts them in
+	// the background, which returns status text and what to record.
+	DraftToggle    func() string                                  // Toggles draft mode; returns status text
+	DraftCount     func() int                                     // Number of buffered drafts, shown in the footer
+	DraftSubmit    func() (func() (string, func(), error), error) // Errors if the drafts can't be submitted now
+	DraftKey       string                                         // e.g., "P draft mode"
+	DraftSubmitKey string                                         // e.g., "Z submit review"
+}
+
+// LegendEntry explains one color or marker convention in the help overlay
+type LegendEntry struct {
+	Sample  string // Rendered example, styled the way it appears in the list
+	Meaning string
 }
 
 // SelectionModel is the tea.Model for interactive selection
@@ -133,29 +401,84 @@ type SelectionModel[T any] struct {
 	list       list.Model
 	items      []T
 	result     []T
+	switched   bool // left with SwitchKey
 	windowSize tea.WindowSizeMsg
 	viewport   viewport.Model
 	showDetail bool
 	showHelp   bool
+	showDebug  bool           // debug overlay (DebugInfo) shares helpView with help
+	showRaw    bool           // detail shows markdown source (RawPreviewer)
+	noWrap     bool           // detail lines run past the viewport instead of wrapping
+	helpView   viewport.Model // scrolls the help overlay on short terminals
+
+	// Reply folding in the detail view (ReplyFolder): replies shown beyond
+	// DefaultVisibleReplies (J/K), or all of them (E)
+	extraReplies int
+	allReplies   bool
+
+	// initialPending defers InitialItem until items loaded by RefreshItems arrive
+	initialPending bool
 
 	// Configuration (from SelectorOptions)
 	opts         SelectorOptions[T]
 	filterActive bool
+	filterMode   int // index into FilterModes when cycling filter states
 
 	// Runtime state for refresh
 	refreshing bool
 
+	// Spinner shown while an API call is in flight (refresh, initial load, editor completion)
+	spinner   spinner.Model
+	busyLabel string    // e.g. "Refreshing"; empty when idle
+	busySince time.Time // when the current busy operation started
+
 	// State for pending editor operation
 	pendingEditorItem    T
 	pendingEditorTmpFile string
-	pendingEditorAction  int // 2 = R/U, 3 = Q, 4 = C
+	pendingEditorAction  int // editorAction* constant for the open editor
+	pendingEditorBatch   []T // the marked items for B
+
+	// The items the last batch reply failed for, the step each stopped at,
+	// and the body it sent, for F
+	batchFailed     []T
+	batchFailedStep map[string]string
+	batchFailedBody string
+
+	// Items marked with space for batch actions, and items with an
+	// optimistic resolve in flight, both keyed by ItemKey
+	marked  map[string]bool
+	pending map[string]bool
 
 	// Confirmation message that persists until user dismisses it
 	confirmationMessage string
 
+	// The key (q or ctrl+p) waiting on y to leave with drafts unsubmitted
+	confirmLeave string
+
 	// Loading state for detail view
 	loadingDetail bool
 
+	// Find within the detail view (/, n, N)
+	detailContent   string          // unhighlighted content currently in the viewport
+	findInput       textinput.Model // input shown while typing a query
+	findInputActive bool            // true while the find input has focus
+	findQuery       string          // active search; "" when not searching
+	findMatches     []int           // line offset of each match in detailContent
+	findIdx         int             // index into findMatches of the current match
+
+	// Jump to comment by ID (g)
+	jumpInput       textinput.Model // input shown in the footer while typing an ID
+	jumpInputActive bool            // true while the jump input has focus
+
+	// Path glob filter (I)
+	globInput       textinput.Model // input shown in the footer while typing a glob
+	globInputActive bool            // true while the glob input has focus
+
+	// Inline reply composer (m)
+	composer       textarea.Model // multi-line input shown in a dialog
+	composerActive bool           // true while a reply is being typed
+	composerItem   T              // item the reply goes to
+
 	// Comment selection mode state (for cycling through thread comments)
 	commentSelectMode     bool        // true when cycling through comments
 	commentSelectAction   string      // "Q", "C", or "a" - which action triggered selection
@@ -163,6 +486,7 @@ type SelectionModel[T any] struct {
 	commentSelectItem     listItem[T] // the item being operated on
 	commentSelectStatus   string      // status message to display during selection
 	commentSelectInDetail bool        // true if selection was triggered from detail view
+	commentSelectSince    time.Time   // when selection started, to detect a double-press of x
 
 	// Reaction mode state (for cycling through emoji reactions)
 	reactionMode      bool        // true when cycling through reactions
@@ -170,6 +494,11 @@ type SelectionModel[T any] struct {
 	reactionCommentID int64       // comment ID to react to
 	reactionItem      listItem[T] // the item being reacted to
 
+	// Open file confirmation state
+	openFileConfirmMode bool        // true while confirming the URL
+	openFileURL         string      // URL shown for confirmation
+	openFileItem        listItem[T] // the item being opened
+
 	// Apply suggestion preview state
 	applyPreviewMode       bool        // true when showing apply preview
 	applyPreviewDiff       string      // the diff to show
@@ -195,16 +524,43 @@ func (i listItem[T]) Description() string {
 	return i.item.Description(i.value)
 }
 
-// SanitizeEditorContent strips trailing lines starting with # and trims whitespace.
-// This preserves Markdown headings in the body while removing the instruction
-// template that is appended at the end of editor content.
+// EditorTemplateSentinel marks where an editor template's instructions begin.
+// It is an HTML comment so it stays invisible if it ever reaches GitHub.
+const EditorTemplateSentinel = "<!-- gh-rc:template-below -->"
+
+// FormatEditorTemplate returns instruction lines for the end of an editor
+// buffer, preceded by EditorTemplateSentinel so SanitizeEditorContent drops
+// them exactly, whatever the user writes above.
+func FormatEditorTemplate(instructions ...string) string {
+	lines := []string{EditorTemplateSentinel}
+	for _, instruction := range instructions {
+		lines = append(lines, "# "+instruction)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// SanitizeEditorContent removes the instruction template appended at the end
+// of editor content and trims whitespace. When EditorTemplateSentinel is
+// present, everything from it on is dropped and all other lines are kept.
+// Otherwise trailing lines starting with # are stripped, which preserves
+// Markdown headings in the body. Lines inside a ``` or ~~~ fenced code block
+// are never treated as template, so shell and Python comments in code survive.
 func SanitizeEditorContent(raw string) string {
 	lines := strings.Split(raw, "\n")
-	// Remove trailing lines that are empty or start with #
+	inFence := fencedLines(lines)
+
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == EditorTemplateSentinel && !inFence[i] {
+			return strings.TrimSpace(strings.Join(lines[:i], "\n"))
+		}
+	}
+
+	// Remove trailing lines that are empty or start with # outside a fence
 	for len(lines) > 0 {
-		last := lines[len(lines)-1]
-		if strings.TrimSpace(last) == "" || strings.HasPrefix(last, "#") {
-			lines = lines[:len(lines)-1]
+		i := len(lines) - 1
+		last := lines[i]
+		if strings.TrimSpace(last) == "" || (strings.HasPrefix(last, "#") && !inFence[i]) {
+			lines = lines[:i]
 		} else {
 			break
 		}
@@ -212,6 +568,28 @@ func SanitizeEditorContent(raw string) string {
 	return strings.TrimSpace(strings.Join(lines, "\n"))
 }
 
+// fencedLines reports, per line, whether it is inside a fenced code block
+// (fence lines themselves included). An unclosed fence runs to the end.
+func fencedLines(lines []string) []bool {
+	inFence := make([]bool, len(lines))
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fence = trimmed[:3]
+				inFence[i] = true
+			}
+			continue
+		}
+		inFence[i] = true
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			fence = ""
+		}
+	}
+	return inFence
+}
+
 // splitActionKey splits an action key like "r resolve" into key and description
 func splitActionKey(actionKey string) (string, string) {
 	parts := strings.Fields(actionKey)
@@ -1,23 +1,38 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// reactionEmojis defines the available emoji reactions for GitHub comments
-var reactionEmojis = []struct {
+// reactionEmoji pairs a GitHub reaction with the glyph shown for it
+type reactionEmoji struct {
 	name    string // GitHub API name
 	display string // Actual emoji for display
-}{
+}
+
+// label is the glyph when the terminal shows emoji, otherwise the API name
+func (e reactionEmoji) label() string {
+	return EmojiText(e.display, e.name)
+}
+
+// reactionEmojis defines the available emoji reactions for GitHub comments
+var reactionEmojis = []reactionEmoji{
 	{"+1", "👍"},
 	{"-1", "👎"},
 	{"laugh", "😄"},
@@ -28,6 +43,58 @@ var reactionEmojis = []struct {
 	{"eyes", "👀"},
 }
 
+// ValidateReactionOrder checks that names are GitHub reaction names (as in
+// reactionEmojis), each listed at most once
+func ValidateReactionOrder(names []string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if err := ValidateReaction(name); err != nil {
+			return err
+		}
+		if seen[name] {
+			return fmt.Errorf("reaction %q is listed twice", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// ValidateReaction checks that name is one of GitHub's reaction names
+func ValidateReaction(name string) error {
+	var all []string
+	for _, emoji := range reactionEmojis {
+		if emoji.name == name {
+			return nil
+		}
+		all = append(all, emoji.name)
+	}
+	return fmt.Errorf("unknown reaction %q (valid: %s)", name, strings.Join(all, ", "))
+}
+
+// orderedReactions returns reactionEmojis with the named ones moved to the
+// front in the given order; unknown names are ignored
+func orderedReactions(order []string) []reactionEmoji {
+	if len(order) == 0 {
+		return reactionEmojis
+	}
+	ordered := make([]reactionEmoji, 0, len(reactionEmojis))
+	used := make(map[string]bool, len(reactionEmojis))
+	for _, name := range order {
+		for _, emoji := range reactionEmojis {
+			if emoji.name == name && !used[name] {
+				ordered = append(ordered, emoji)
+				used[name] = true
+			}
+		}
+	}
+	for _, emoji := range reactionEmojis {
+		if !used[emoji.name] {
+			ordered = append(ordered, emoji)
+		}
+	}
+	return ordered
+}
+
 // SelectFromList creates an interactive selector for a list of items.
 // For more options, use Select() with SelectorOptions.
 func SelectFromList[T any](items []T, renderer ItemRenderer[T]) (T, error) {
@@ -58,13 +125,65 @@ func SelectFromListWithAction[T any](items []T, renderer ItemRenderer[T], custom
 // Select creates an interactive selector with the given options.
 // This is the primary API for creating selectors.
 func Select[T any](opts SelectorOptions[T]) (T, error) {
+	if err := ValidateReactionOrder(opts.ReactionOrder); err != nil {
+		var zero T
+		return zero, err
+	}
+	m := newSelectionModel(opts)
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	finalModel, err := p.Run()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return finalModel.(SelectionModel[T]).Result()
+}
+
+// RunSelection drives a selector headlessly: each message is fed through
+// Update in order and the final model is returned. Commands returned by
+// Update are not run, so scripts supply any follow-up messages themselves
+// (e.g. a tea.WindowSizeMsg first, or the loadDetailMsg after Enter).
+func RunSelection[T any](opts SelectorOptions[T], msgs []tea.Msg) (SelectionModel[T], error) {
+	m := newSelectionModel(opts)
+	for i, msg := range msgs {
+		updated, _ := m.Update(msg)
+		switch next := updated.(type) {
+		case SelectionModel[T]:
+			m = next
+		case *SelectionModel[T]:
+			m = *next
+		default:
+			return m, fmt.Errorf("message %d (%T): unexpected model type %T", i, msg, updated)
+		}
+	}
+	return m, nil
+}
+
+// Result returns the item chosen in the selector, ErrSwitchRequested if it
+// was left with SwitchKey, or ErrNoSelection
+func (m SelectionModel[T]) Result() (T, error) {
+	if m.switched {
+		var zero T
+		return zero, ErrSwitchRequested
+	}
+	if len(m.result) == 0 {
+		var zero T
+		return zero, ErrNoSelection
+	}
+	return m.result[0], nil
+}
+
+// newSelectionModel builds the initial model for opts
+func newSelectionModel[T any](opts SelectorOptions[T]) SelectionModel[T] {
 	// Convert items to list items
 	listItems := make([]list.Item, len(opts.Items))
 	for i, item := range opts.Items {
 		listItems[i] = listItem[T]{value: item, item: opts.Renderer}
 	}
 
-	delegate := itemDelegate[T]{renderer: opts.Renderer}
+	delegate := itemDelegate[T]{renderer: opts.Renderer, height: opts.RowHeight}
 	l := list.New(listItems, delegate, 0, 0)
 	l.SetShowStatusBar(true)
 	l.SetFilteringEnabled(true)
@@ -74,36 +193,146 @@ func Select[T any](opts SelectorOptions[T]) (T, error) {
 	l.KeyMap.Quit.SetKeys()
 
 	m := SelectionModel[T]{
-		list:         l,
-		items:        opts.Items,
-		opts:         opts,
-		result:       nil,
-		filterActive: opts.FilterDefault,
+		list:    l,
+		items:   opts.Items,
+		opts:    opts,
+		result:  nil,
+		spinner: newBusySpinner(),
 	}
+	m.filterMode = initialFilterMode(opts)
+	m.filterActive = opts.FilterDefault || m.filterMode != 0
 
-	// Apply initial filter if FilterDefault is true
-	if opts.FilterDefault && opts.FilterFunc != nil {
+	// Apply initial filter if FilterDefault or InitialFilterMode set one
+	if m.filterActive && (opts.FilterFunc != nil || opts.hasFilterModes()) {
 		m.updateVisibleItems()
+	} else {
+		m.measureColumns()
 	}
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	finalModel, err := p.Run()
-	if err != nil {
-		var zero T
-		return zero, err
+	// With no items up front, load them asynchronously once the program starts
+	if len(opts.Items) == 0 && opts.RefreshItems != nil {
+		m.refreshing = true
+		m.startBusy("Loading")
+		m.initialPending = opts.InitialItem != nil
+	} else if opts.InitialItem != nil {
+		m.selectInitialItem()
 	}
+	return m
+}
 
-	final := finalModel.(SelectionModel[T])
-	if len(final.result) == 0 {
-		var zero T
-		return zero, ErrNoSelection
+// selectInitialItem moves the cursor to the first item matching InitialItem.
+// An item hidden by the h filter is revealed by going back to showing all
+// (after RevealItem, e.g. expanding its file). It reports whether one was selected.
+func (m *SelectionModel[T]) selectInitialItem() bool {
+	found := false
+	for _, item := range m.items {
+		if m.opts.InitialItem(item) {
+			found = true
+			if !m.keepItem(item) && m.opts.RevealItem != nil {
+				m.opts.RevealItem(item)
+				m.updateVisibleItems()
+			}
+			if !m.keepItem(item) {
+				m.filterMode = 0
+				m.filterActive = false
+				m.updateVisibleItems()
+			}
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	for i, listed := range m.list.Items() {
+		if m.opts.InitialItem(listed.(listItem[T]).value) {
+			m.list.Select(i)
+			return true
+		}
 	}
-	return final.result[0], nil
+	return false
 }
 
 // Init initializes the model
 func (m SelectionModel[T]) Init() tea.Cmd {
-	return nil
+	var cmds []tea.Cmd
+	if m.refreshing {
+		cmds = append(cmds, m.spinner.Tick, m.refreshCmd())
+	}
+	if m.opts.WatchInterval > 0 && m.opts.RefreshItems != nil {
+		cmds = append(cmds, m.watchTickCmd())
+	}
+	if preload := m.opts.Preload; preload != nil {
+		cmds = append(cmds, func() tea.Msg {
+			apply, err := preload()
+			return preloadFinishedMsg{apply: apply, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// newBusySpinner creates the spinner shown during API calls
+func newBusySpinner() spinner.Model {
+	s := spinner.New()
+	if ColorsEnabled() {
+		s.Spinner = spinner.Dot
+		s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	} else {
+		s.Spinner = spinner.Line
+	}
+	return s
+}
+
+// startBusy marks an API call as in flight and returns the command that animates the spinner
+func (m *SelectionModel[T]) startBusy(label string) tea.Cmd {
+	m.busyLabel = label
+	m.busySince = time.Now()
+	return m.spinner.Tick
+}
+
+// stopBusy clears the in-flight state so the spinner stops ticking
+func (m *SelectionModel[T]) stopBusy() {
+	m.busyLabel = ""
+}
+
+// applyPreviewWidth passes the configured preview width to the renderer
+func (m *SelectionModel[T]) applyPreviewWidth() {
+	setter, ok := m.opts.Renderer.(PreviewWidthSetter)
+	if !ok {
+		return
+	}
+	switch {
+	case m.opts.PreviewWidth > 0:
+		setter.SetPreviewWidth(m.opts.PreviewWidth)
+	case m.opts.PreviewWidth == PreviewWidthFit && m.list.Width() > 0:
+		// itemDelegate.Render cuts titles at the list width minus 4
+		setter.SetPreviewWidth(m.list.Width() - 4)
+	}
+}
+
+// filterQuery returns the text of the list filter, or "" when no filter is active
+func (m *SelectionModel[T]) filterQuery() string {
+	if m.list.FilterState() == list.Unfiltered {
+		return ""
+	}
+	return m.list.FilterValue()
+}
+
+// footerWarning returns the StatusWarning text styled for the footer, or "" if none
+func (m *SelectionModel[T]) footerWarning() string {
+	if m.opts.StatusWarning == nil {
+		return ""
+	}
+	warning := m.opts.StatusWarning()
+	if warning == "" {
+		return ""
+	}
+	return Colorize(ColorYellow, EmojiText("⚠️  ", "")+warning) + "  "
+}
+
+// busyStatus renders the spinner, label, and elapsed time for the footer
+func (m *SelectionModel[T]) busyStatus() string {
+	elapsed := time.Since(m.busySince).Truncate(time.Second)
+	return fmt.Sprintf("%s %s... %s", m.spinner.View(), m.busyLabel, elapsed)
 }
 
 // Update handles messages and updates the model
@@ -115,8 +344,20 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		footerHeight := 3
 		listHeight := msg.Height - headerHeight - footerHeight
 		m.list.SetSize(msg.Width, listHeight)
+		m.applyPreviewWidth()
+		m.measureColumns()
 		m.viewport = viewport.New(msg.Width, listHeight)
-		m.viewport.SetContent("")
+		m.setDetailContent("")
+		if m.showDetail {
+			// Rewrap the open detail to the new width
+			m.rerenderDetail()
+		}
+		if m.showHelp {
+			m.openHelp()
+		}
+		if m.showDebug {
+			m.openDebug()
+		}
 		return m, nil
 
 	case loadDetailMsg:
@@ -128,23 +369,45 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.commentSelectMode {
 				highlightIdx = m.commentSelectIdx
 			}
-			m.viewport.SetContent(m.opts.Renderer.PreviewWithHighlight(item.value, highlightIdx))
+			m.setDetailContent(m.detailPreview(item.value, highlightIdx))
 			m.viewport.GotoTop()
+			if m.opts.OnDetailOpen != nil {
+				m.opts.OnDetailOpen(item.value)
+			}
 		}
 		return m, nil
 
+	case spinner.TickMsg:
+		if m.busyLabel == "" {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case watchTickMsg:
+		return m.handleWatchTick()
+
 	case refreshFinishedMsg:
+		if msg.watch {
+			return m.handleWatchRefreshFinished(msg)
+		}
 		m.refreshing = false
+		m.stopBusy()
 		if msg.err != nil {
 			return m, m.list.NewStatusMessage(Colorize(ColorRed, fmt.Sprintf("Refresh failed: %v", msg.err)))
 		}
 		if items, ok := msg.items.([]T); ok {
+			items = m.limited(items)
+			change := m.describeChanges(items)
+			anchor, index := m.selectionAnchor()
 			m.items = items
-			listItems := make([]list.Item, len(items))
-			for i, item := range items {
-				listItems[i] = listItem[T]{value: item, item: m.opts.Renderer}
+			m.updateVisibleItems()
+			m.restoreSelection(anchor, index)
+			if m.initialPending {
+				m.initialPending = false
+				m.selectInitialItem()
 			}
-			cmd := m.list.SetItems(listItems)
 
 			// If in detail view, refresh the viewport content
 			if m.showDetail {
@@ -155,17 +418,73 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if m.commentSelectMode {
 						highlightIdx = m.commentSelectIdx
 					}
-					m.viewport.SetContent(m.opts.Renderer.PreviewWithHighlight(item.value, highlightIdx))
+					m.setDetailContent(m.detailPreview(item.value, highlightIdx))
 				}
 			}
 
-			return m, tea.Batch(cmd, m.list.NewStatusMessage(Colorize(ColorGreen, fmt.Sprintf("Refreshed: %d items", len(items)))))
+			status := fmt.Sprintf("Refreshed: %d items", len(items))
+			if change != "" {
+				status += " (" + change + ")"
+			}
+			return m, tea.Batch(m.notifyCmd(change), m.list.NewStatusMessage(Colorize(ColorGreen, status)))
+		}
+		return m, nil
+
+	case preloadFinishedMsg:
+		if msg.apply != nil {
+			// What was loaded may change what the filters keep
+			msg.apply()
+			anchor, index := m.selectionAnchor()
+			m.updateVisibleItems()
+			m.restoreSelection(anchor, index)
+		}
+		if msg.err != nil {
+			return m, m.list.NewStatusMessage(Colorize(ColorRed, msg.err.Error()))
 		}
 		return m, nil
 
 	case editorFinishedMsg:
 		return m.handleEditorFinished(msg)
 
+	case editorCompleteMsg:
+		return m.handleEditorComplete(msg)
+
+	case batchCompleteMsg:
+		return m.handleBatchComplete(msg)
+
+	case resolveFinishedMsg:
+		delete(m.pending, msg.key)
+		m.applyBackground(msg.apply)
+		if msg.err != nil {
+			if msg.undo != nil {
+				msg.undo()
+			}
+			return m, m.list.NewStatusMessage(Colorize(ColorRed, fmt.Sprintf("%v (reverted)", msg.err)))
+		}
+		if msg.status != "" {
+			return m, m.list.NewStatusMessage(msg.status)
+		}
+		return m, nil
+
+	case acceptFinishedMsg:
+		m.stopBusy()
+		m.applyBackground(msg.apply)
+		if msg.err != nil {
+			m.confirmationMessage = fmt.Sprintf("%s\n\nPress any key to continue...", Colorize(ColorRed, msg.err.Error()))
+			return m, nil
+		}
+		m.confirmationMessage = fmt.Sprintf("%s\n\nPress any key to continue...", msg.report)
+		return m, nil
+
+	case draftSubmitFinishedMsg:
+		m.stopBusy()
+		m.applyBackground(msg.apply)
+		if msg.err != nil {
+			m.confirmationMessage = fmt.Sprintf("%s\n\nPress any key to continue...", Colorize(ColorRed, msg.err.Error()))
+			return m, nil
+		}
+		return m, m.list.NewStatusMessage(Colorize(ColorGreen, msg.status))
+
 	case agentFinishedMsg:
 		if msg.err != nil {
 			return m, m.list.NewStatusMessage(Colorize(ColorRed, fmt.Sprintf("Agent error: %v", msg.err)))
@@ -173,33 +492,66 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.list.NewStatusMessage(Colorize(ColorGreen, "Agent completed"))
 
 	case tea.KeyMsg:
-		// If showing help overlay, any key dismisses it
-		if m.showHelp {
-			m.showHelp = false
+		// If showing the help or debug overlay, q/esc dismiss it and other
+		// keys scroll
+		if m.showHelp || m.showDebug {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "q", "esc", "?", "D":
+				m.showHelp = false
+				m.showDebug = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.helpView, cmd = m.helpView.Update(msg)
+			return m, cmd
+		}
+
+		// Leaving with unsubmitted drafts waits on y
+		if m.confirmLeave != "" {
+			key := m.confirmLeave
+			m.confirmLeave = ""
+			if msg.String() == "y" {
+				return m.leave(key)
+			}
 			return m, nil
 		}
 
 		// If showing confirmation, any key dismisses it
 		if m.confirmationMessage != "" {
+			// y copies the dialog's URL; any other key just dismisses it
+			url := confirmationURL(m.confirmationMessage)
 			m.confirmationMessage = ""
+			if msg.String() == "y" && url != "" {
+				if err := CopyToClipboard(url); err != nil {
+					return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+				}
+				return m, m.list.NewStatusMessage(Colorize(ColorGreen, "Copied "+url))
+			}
 			return m, nil
 		}
 
+		// The reply composer captures all keys while open
+		if m.composerActive {
+			return m.handleComposerKey(msg)
+		}
+
 		// Handle reaction mode
 		if m.reactionMode {
 			switch msg.String() {
 			case "enter":
 				// Add the reaction
-				emoji := reactionEmojis[m.reactionIdx]
+				emoji := m.reactionChoices()[m.reactionIdx]
 				m.reactionMode = false
 				if m.opts.ReactionComplete != nil {
-					msg, err := m.opts.ReactionComplete(m.reactionItem.value, m.reactionCommentID, emoji.name, emoji.display)
+					msg, err := m.opts.ReactionComplete(m.reactionItem.value, m.reactionCommentID, emoji.name, emoji.label())
 					if err != nil {
 						return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
 					}
 					// Refresh the viewport to show updated reactions
 					if m.showDetail {
-						m.viewport.SetContent(m.opts.Renderer.PreviewWithHighlight(m.reactionItem.value, -1))
+						m.setDetailContent(m.detailPreview(m.reactionItem.value, -1))
 					}
 					// Show confirmation dialog with the result
 					m.confirmationMessage = fmt.Sprintf("%s\n\nPress any key to continue...", msg)
@@ -211,7 +563,11 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.list.NewStatusMessage("Reaction cancelled")
 			case "x":
 				// Cycle to next emoji
-				m.reactionIdx = (m.reactionIdx + 1) % len(reactionEmojis)
+				m.reactionIdx = (m.reactionIdx + 1) % len(m.reactionChoices())
+				return m, m.showReactionStatus()
+			case "1", "2", "3", "4", "5", "6", "7", "8":
+				// Jump straight to an emoji by position; Enter still confirms
+				m.reactionIdx = int(msg.String()[0]-'1') % len(m.reactionChoices())
 				return m, m.showReactionStatus()
 			default:
 				// Any other key cancels reaction mode
@@ -220,6 +576,25 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Handle open file confirmation
+		if m.openFileConfirmMode {
+			m.openFileConfirmMode = false
+			if msg.String() != "y" && msg.String() != "Y" {
+				return m, m.list.NewStatusMessage("Open cancelled")
+			}
+			if m.opts.OpenFileAction == nil {
+				return m, nil
+			}
+			statusMsg, err := m.opts.OpenFileAction(m.openFileItem.value)
+			if err != nil {
+				return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+			}
+			if statusMsg != "" {
+				return m, m.list.NewStatusMessage(statusMsg)
+			}
+			return m, nil
+		}
+
 		// Handle apply suggestion preview mode
 		if m.applyPreviewMode {
 			switch msg.String() {
@@ -268,11 +643,15 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					selected := m.list.SelectedItem()
 					if selected != nil {
 						item := selected.(listItem[T])
-						m.viewport.SetContent(m.opts.Renderer.PreviewWithHighlight(item.value, -1))
+						m.setDetailContent(m.detailPreview(item.value, -1))
 					}
 				}
 				return m, m.list.NewStatusMessage("Selection cancelled")
 			case "Q", "C", "a", "x":
+				if msg.String() == "x" && m.isReactionDoublePress() {
+					// xx reacts to the thread head without cycling
+					return m.executeCommentAction()
+				}
 				if msg.String() == m.commentSelectAction {
 					m.cycleCommentSelection()
 					if m.commentSelectInDetail {
@@ -283,6 +662,12 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Different action - cancel current and start new
 				m.exitCommentSelectMode()
 				// Fall through to handle new action
+			case "o":
+				// Open the highlighted comment rather than the thread head
+				if m.opts.OnOpen != nil {
+					return m.openSelectedComment()
+				}
+				m.exitCommentSelectMode()
 			default:
 				// Any other key cancels selection mode
 				m.exitCommentSelectMode()
@@ -290,7 +675,7 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					selected := m.list.SelectedItem()
 					if selected != nil {
 						item := selected.(listItem[T])
-						m.viewport.SetContent(m.opts.Renderer.PreviewWithHighlight(item.value, -1))
+						m.setDetailContent(m.detailPreview(item.value, -1))
 					}
 				}
 				// Fall through to handle the key normally
@@ -304,12 +689,57 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// The jump input captures all keys while open
+		if m.jumpInputActive {
+			return m.handleJumpInputKey(msg)
+		}
+
+		// So does the path glob input
+		if m.globInputActive {
+			return m.handleGlobInputKey(msg)
+		}
+
+		// The detail find input captures all keys while open
+		if m.showDetail && m.findInputActive {
+			return m.handleFindInputKey(msg)
+		}
+
 		// If showing detail view, only handle specific keys
 		if m.showDetail {
 			switch msg.String() {
 			case "esc", "backspace", "left", "h", "q":
+				if msg.String() == "esc" && m.findQuery != "" {
+					// Esc closes an active search before leaving the detail view
+					m.clearDetailFind()
+					return m, nil
+				}
 				m.showDetail = false
 				return m, nil
+			case "/":
+				return m, m.openDetailFind()
+			case "n", "N":
+				if m.findQuery != "" {
+					m.stepDetailFind(msg.String() == "n")
+					return m, nil
+				}
+				var cmd tea.Cmd
+				m.viewport, cmd = m.viewport.Update(msg)
+				return m, cmd
+			case "J", "K", "E":
+				// Unfold (J) or fold (K) one older reply, or toggle all (E)
+				if _, ok := m.opts.Renderer.(ReplyFolder); ok {
+					return m, m.foldReplies(msg.String())
+				}
+				return m, nil
+			case "M":
+				// Toggle between rendered markdown and its source
+				if _, ok := m.opts.Renderer.(RawPreviewer[T]); ok {
+					return m, m.toggleRawPreview()
+				}
+				return m, nil
+			case "w":
+				// Toggle soft-wrapping long lines to the viewport width
+				return m, m.toggleDetailWrap()
 			case "ctrl+f":
 				// Page down in detail view
 				m.viewport.PageDown()
@@ -320,6 +750,10 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			case "r", "u":
 				// Execute resolve action from detail view (r=resolve, u=unresolve - both toggle)
+				if m.opts.ResolveStart != nil && m.opts.ItemKey != nil {
+					m.showDetail = false
+					return m, m.startOptimisticResolve()
+				}
 				if m.opts.ResolveAction != nil {
 					selected := m.list.SelectedItem()
 					if selected != nil {
@@ -342,7 +776,7 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if selected != nil {
 						item := selected.(listItem[T])
 						m.showDetail = false
-						return m, m.startEditorForAction(item.value, 2)
+						return m, m.startEditorForAction(item.value, editorActionResolveComment)
 					}
 				}
 				return m, nil
@@ -352,6 +786,19 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "C":
 				// Quote with context from detail view
 				return m.handleQuoteContextKey(true)
+			case "G":
+				// Suggestion reply from detail view
+				return m.handleSuggestKey(true)
+			case "W":
+				// Won't-fix reply and resolve from detail view
+				return m.handleDismissKey(true)
+			case "m":
+				// Inline reply from detail view
+				return m.openComposer(true)
+			case "D":
+				// Debug overlay from detail view
+				m.openDebug()
+				return m, nil
 			case "a":
 				// Launch agent from detail view
 				return m.handleAgentKey(true)
@@ -388,9 +835,38 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "S":
 				// Apply suggestion and resolve from detail view (with preview)
 				return m.startApplyPreview(true)
+			case "A":
+				// Accept suggestion from detail view
+				cmd := m.startAccept()
+				return m, cmd
+			case "P":
+				// Toggle draft mode from detail view
+				return m, m.toggleDraftMode()
+			case "Z":
+				// Submit drafts from detail view
+				cmd := m.startDraftSubmit()
+				return m, cmd
 			case "i":
 				// Refresh from detail view
 				return m.startRefresh()
+			case "O":
+				// Open file on GitHub from detail view
+				return m.startOpenFileConfirm()
+			case "y":
+				// Copy gh command from detail view
+				return m.copyFrom(m.opts.CopyCommand)
+			case "p":
+				// Copy permalink from detail view
+				return m.copyFrom(m.opts.CopyPermalink)
+			case "c":
+				// Copy quote from detail view
+				return m.copyFrom(m.opts.CopyQuote)
+			case "v":
+				// Copy quote with context from detail view
+				return m.copyFrom(m.opts.CopyQuoteContext)
+			case "t":
+				// Cycle local tag from detail view
+				return m.cycleTag()
 			case "o":
 				// Open in browser from detail view
 				if m.opts.OnOpen != nil {
@@ -420,11 +896,26 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			return m, tea.Quit
 		case "?":
-			m.showHelp = true
+			m.openHelp()
 			return m, nil
-		case "q":
-			m.result = nil
-			return m, tea.Quit
+		case "D":
+			// Show the selected item's raw fields (--debug only)
+			m.openDebug()
+			return m, nil
+		case "ctrl+d", "ctrl+u", "ctrl+f", "ctrl+b":
+			// Half-page (ctrl+d/u) and full-page (ctrl+f/b) scrolling
+			m.scrollList(msg.String())
+			return m, nil
+		case "q", "ctrl+p":
+			if msg.String() == "ctrl+p" && m.opts.SwitchKey == "" {
+				return m, nil
+			}
+			// Unsubmitted drafts are lost on leaving, so ask first
+			if m.draftCount() > 0 {
+				m.confirmLeave = msg.String()
+				return m, nil
+			}
+			return m.leave(msg.String())
 		case "enter", "right", "l":
 			selected := m.list.SelectedItem()
 			if selected != nil {
@@ -434,6 +925,15 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if err != nil {
 						return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
 					}
+					// Collapsible rows (e.g., file headers) change what's
+					// visible rather than having a detail view
+					if m.opts.IsToggle != nil && m.opts.IsToggle(item.value) {
+						m.updateVisibleItems()
+						if statusMsg != "" {
+							return m, m.list.NewStatusMessage(statusMsg)
+						}
+						return m, nil
+					}
 					if statusMsg != "" {
 						return m, m.list.NewStatusMessage(statusMsg)
 					}
@@ -441,7 +941,9 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Show detail view with loading state
 				m.showDetail = true
 				m.loadingDetail = true
-				m.viewport.SetContent("Loading...")
+				m.extraReplies, m.allReplies = 0, false
+				m.clearDetailFind()
+				m.setDetailContent("Loading...")
 				return m, func() tea.Msg { return loadDetailMsg{} }
 			}
 		case "o":
@@ -461,6 +963,9 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		case "h", "tab":
 			// Toggle filter (h = hide/show resolved, tab kept for compatibility)
+			if m.opts.hasFilterModes() {
+				return m, m.cycleFilterMode()
+			}
 			if m.opts.FilterFunc != nil {
 				m.filterActive = !m.filterActive
 				m.updateVisibleItems()
@@ -473,8 +978,97 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "i":
 			// Refresh
 			return m.startRefresh()
+		case "O":
+			// Open file on GitHub (after confirming the URL)
+			return m.startOpenFileConfirm()
+		case "y":
+			// Copy the equivalent gh command
+			return m.copyFrom(m.opts.CopyCommand)
+		case "p":
+			// Copy a permalink to the code
+			return m.copyFrom(m.opts.CopyPermalink)
+		case "c":
+			// Copy the comment as a markdown quote
+			return m.copyFrom(m.opts.CopyQuote)
+		case "v":
+			// Copy the quote with its context
+			return m.copyFrom(m.opts.CopyQuoteContext)
+		case "Y":
+			// Copy all items (e.g. unresolved comments as a checklist)
+			return m.copyAll()
+		case "L":
+			// Load the items left out by the cap
+			return m.loadAll()
+		case "X":
+			// Clear all filters
+			if m.opts.ClearFiltersKey != "" {
+				return m.clearFilters()
+			}
+			return m, nil
+		case " ":
+			// Mark the item for batch actions and move on to the next
+			if m.opts.Markable != nil && m.opts.ItemKey != nil {
+				m.toggleMark()
+				m.list.CursorDown()
+				return m, nil
+			}
+		case "B":
+			// Reply to every marked item at once
+			if m.opts.BatchReplyPrepare != nil {
+				return m, m.startBatchReply()
+			}
+			return m, nil
+		case "F":
+			// Retry the last batch reply on the items it failed for
+			if len(m.batchFailed) > 0 {
+				items, resume := m.batchFailed, m.batchFailedStep
+				m.batchFailed, m.batchFailedStep = nil, nil
+				return m.runBatchReply(items, m.batchFailedBody, resume)
+			}
+			return m, nil
+		case "g":
+			// Prompt for a comment ID to jump to
+			if m.opts.MatchesCommentID != nil {
+				return m, m.openJumpInput()
+			}
+		case "I":
+			// Prompt for a path glob to narrow the list to
+			if m.opts.SetPathGlob != nil {
+				return m, m.openGlobInput()
+			}
+			return m, nil
+		case "}":
+			// Jump to the next group header (e.g. file)
+			m.jumpToGroupHeader(1)
+			return m, nil
+		case "{":
+			// Jump to the previous group header
+			m.jumpToGroupHeader(-1)
+			return m, nil
+		case "t":
+			// Cycle local tag
+			return m.cycleTag()
+		case "T":
+			// Toggle tag filter
+			if m.opts.TagFilterToggle != nil {
+				status := m.opts.TagFilterToggle()
+				m.updateVisibleItems()
+				return m, m.list.NewStatusMessage(status)
+			}
+			return m, nil
+		case "H":
+			// Toggle hiding outdated items
+			if m.opts.OutdatedFilterToggle != nil {
+				status := m.opts.OutdatedFilterToggle()
+				m.updateVisibleItems()
+				return m, m.list.NewStatusMessage(status)
+			}
+			return m, nil
 		case "r", "u":
 			// Execute first custom action (r=resolve, u=unresolve - both trigger same action)
+			if m.opts.ResolveStart != nil && m.opts.ItemKey != nil {
+				return m, m.startOptimisticResolve()
+			}
 			if m.opts.ResolveAction != nil {
 				selected := m.list.SelectedItem()
 				if selected != nil {
@@ -497,7 +1091,7 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				selected := m.list.SelectedItem()
 				if selected != nil {
 					item := selected.(listItem[T])
-					return m, m.startEditorForAction(item.value, 2)
+					return m, m.startEditorForAction(item.value, editorActionResolveComment)
 				}
 			}
 			return m, nil
@@ -507,6 +1101,15 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "C":
 			// Execute quote with context action with editor
 			return m.handleQuoteContextKey(false)
+		case "G":
+			// Reply with a suggestion block via editor
+			return m.handleSuggestKey(false)
+		case "W":
+			// Reply with a won't-fix reason and resolve via editor
+			return m.handleDismissKey(false)
+		case "m":
+			// Type a short reply without leaving the selector
+			return m.openComposer(false)
 		case "a":
 			// Execute agent action
 			return m.handleAgentKey(false)
@@ -541,6 +1144,17 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "S":
 			// Apply suggestion and resolve (with preview)
 			return m.startApplyPreview(true)
+		case "A":
+			// Apply suggestion, reply and resolve in one go
+			cmd := m.startAccept()
+			return m, cmd
+		case "P":
+			// Toggle draft mode
+			return m, m.toggleDraftMode()
+		case "Z":
+			// Submit the buffered drafts as one review
+			cmd := m.startDraftSubmit()
+			return m, cmd
 		case "x":
 			// Add reaction
 			return m.handleReactionKey(false)
@@ -553,66 +1167,389 @@ func (m SelectionModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-// startApplyPreview initiates the apply suggestion preview mode
-func (m *SelectionModel[T]) startApplyPreview(withResolve bool) (tea.Model, tea.Cmd) {
-	if m.opts.ApplySuggestionPreview == nil {
-		// No preview configured, show error
-		m.confirmationMessage = fmt.Sprintf("%s\n\nPress any key to continue...", Colorize(ColorRed, "Apply preview not configured"))
+// startOpenFileConfirm asks the user to confirm the file URL before opening it
+func (m *SelectionModel[T]) startOpenFileConfirm() (tea.Model, tea.Cmd) {
+	if m.opts.OpenFilePreview == nil {
 		return m, nil
 	}
-
 	selected := m.list.SelectedItem()
 	if selected == nil {
 		return m, nil
 	}
-
 	item := selected.(listItem[T])
 
-	// Get the preview diff
-	diffPreview, err := m.opts.ApplySuggestionPreview(item.value)
+	url, err := m.opts.OpenFilePreview(item.value)
 	if err != nil {
-		m.confirmationMessage = fmt.Sprintf("%s\n\nPress any key to continue...", Colorize(ColorRed, err.Error()))
+		return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+	if url == "" {
 		return m, nil
 	}
 
-	// Enter preview mode
-	m.applyPreviewMode = true
-	m.applyPreviewDiff = diffPreview
-	m.applyPreviewItem = item
-	m.applyPreviewWithResolve = withResolve
-
+	m.openFileConfirmMode = true
+	m.openFileURL = url
+	m.openFileItem = item
 	return m, nil
 }
 
-// editInEditor opens the given file path in the user's editor at the specified line
-func (m *SelectionModel[T]) editInEditor(filePath string, line int) tea.Cmd {
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vim"
+// cycleTag advances the selected item's local tag and re-applies filters
+func (m *SelectionModel[T]) cycleTag() (tea.Model, tea.Cmd) {
+	if m.opts.TagAction == nil {
+		return m, nil
+	}
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return m, nil
 	}
 
-	// Format: editor +line filepath (most editors support this)
-	var c *exec.Cmd
-	if line > 0 {
-		c = exec.Command(editor, fmt.Sprintf("+%d", line), filePath)
-	} else {
-		c = exec.Command(editor, filePath)
+	statusMsg, err := m.opts.TagAction(selected.(listItem[T]).value)
+	if err != nil {
+		return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
 	}
-	return tea.ExecProcess(c, func(err error) tea.Msg {
-		return editorFinishedMsg{err: err}
-	})
+	if !m.showDetail {
+		m.updateVisibleItems()
+	}
+	if statusMsg != "" {
+		return m, m.list.NewStatusMessage(statusMsg)
+	}
+	return m, nil
 }
 
-// startEditorForAction prepares and launches the editor for the given action
-func (m *SelectionModel[T]) startEditorForAction(item T, action int) tea.Cmd {
-	var preparer EditorPreparer[T]
-	switch action {
-	case 2:
+// copyFrom copies the text source returns for the selected item (e.g. its gh
+// CLI command) to the clipboard
+func (m *SelectionModel[T]) copyFrom(source func(T) (string, error)) (tea.Model, tea.Cmd) {
+	if source == nil {
+		return m, nil
+	}
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return m, nil
+	}
+
+	text, err := source(selected.(listItem[T]).value)
+	if err != nil {
+		return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+	if text == "" {
+		return m, nil
+	}
+	if err := CopyToClipboard(text); err != nil {
+		return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+	if lines := strings.Count(text, "\n") + 1; lines > 1 {
+		return m, m.list.NewStatusMessage(Colorize(ColorGreen, fmt.Sprintf("Copied %d lines to clipboard", lines)))
+	}
+	return m, m.list.NewStatusMessage(Colorize(ColorGreen, "Copied: "+text))
+}
+
+// jumpToGroupHeader moves the selection to the next (dir > 0) or previous
+// visible item marked by IsGroupHeader, staying put when there is none
+func (m *SelectionModel[T]) jumpToGroupHeader(dir int) {
+	if m.opts.IsGroupHeader == nil {
+		return
+	}
+	visible := m.list.VisibleItems()
+	for i := m.list.Index() + dir; i >= 0 && i < len(visible); i += dir {
+		if m.opts.IsGroupHeader(visible[i].(listItem[T]).value) {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// openJumpInput shows the comment ID prompt in the list footer
+func (m *SelectionModel[T]) openJumpInput() tea.Cmd {
+	input := textinput.New()
+	input.Prompt = "Go to comment: "
+	input.Placeholder = "ID or URL"
+	m.jumpInput = input
+	m.jumpInputActive = true
+	return m.jumpInput.Focus()
+}
+
+// handleJumpInputKey routes keys to the jump input; enter jumps to the comment
+func (m SelectionModel[T]) handleJumpInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.jumpInputActive = false
+		m.jumpInput.Blur()
+		return m, nil
+	case "enter":
+		m.jumpInputActive = false
+		m.jumpInput.Blur()
+		id, ok := parseCommentID(m.jumpInput.Value())
+		if !ok {
+			return m, m.list.NewStatusMessage(Colorize(ColorRed, fmt.Sprintf("Not a comment ID: %q", m.jumpInput.Value())))
+		}
+		return m, m.jumpToComment(id)
+	}
+	var cmd tea.Cmd
+	m.jumpInput, cmd = m.jumpInput.Update(msg)
+	return m, cmd
+}
+
+// openGlobInput shows the path glob prompt in the list footer, holding the
+// active glob so it can be edited
+func (m *SelectionModel[T]) openGlobInput() tea.Cmd {
+	input := textinput.New()
+	input.Prompt = "Path glob: "
+	input.Placeholder = "e.g. pkg/ui/** (empty shows all)"
+	input.SetValue(m.pathGlob())
+	m.globInput = input
+	m.globInputActive = true
+	return m.globInput.Focus()
+}
+
+// handleGlobInputKey routes keys to the glob input; enter applies the glob
+func (m SelectionModel[T]) handleGlobInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.globInputActive = false
+		m.globInput.Blur()
+		return m, nil
+	case "enter":
+		m.globInputActive = false
+		m.globInput.Blur()
+		glob := strings.TrimSpace(m.globInput.Value())
+		if err := m.opts.SetPathGlob(glob); err != nil {
+			return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+		}
+		m.updateVisibleItems()
+		if glob == "" {
+			return m, m.list.NewStatusMessage("Showing all paths")
+		}
+		return m, m.list.NewStatusMessage(fmt.Sprintf("Showing paths matching %s", glob))
+	}
+	var cmd tea.Cmd
+	m.globInput, cmd = m.globInput.Update(msg)
+	return m, cmd
+}
+
+// pathGlob is the active path glob, "" for none
+func (m *SelectionModel[T]) pathGlob() string {
+	if m.opts.PathGlob == nil {
+		return ""
+	}
+	return m.opts.PathGlob()
+}
+
+// parseCommentID reads a comment ID typed as a number, "#123", or a comment
+// URL ending in "#discussion_r123" or "#issuecomment-123"
+func parseCommentID(s string) (int64, bool) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexAny(s, "#r-"); i >= 0 {
+		s = s[i+1:]
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	return id, err == nil && id > 0
+}
+
+// findItemByCommentID returns the index among the visible items of the item
+// holding comment id, or -1 if none is shown
+func (m *SelectionModel[T]) findItemByCommentID(id int64) int {
+	for i, listed := range m.list.VisibleItems() {
+		if m.opts.MatchesCommentID(listed.(listItem[T]).value, id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// jumpToComment selects the item holding comment id, revealing it first if
+// it exists but is hidden (e.g., in a collapsed file)
+func (m *SelectionModel[T]) jumpToComment(id int64) tea.Cmd {
+	idx := m.findItemByCommentID(id)
+	if idx < 0 {
+		known := false
+		for _, item := range m.items {
+			if m.opts.MatchesCommentID(item, id) {
+				known = true
+				if m.opts.RevealItem != nil {
+					m.opts.RevealItem(item)
+				}
+				break
+			}
+		}
+		if !known {
+			return m.list.NewStatusMessage(Colorize(ColorRed, fmt.Sprintf("Comment %d not found", id)))
+		}
+		m.updateVisibleItems()
+		idx = m.findItemByCommentID(id)
+		if idx < 0 {
+			return m.list.NewStatusMessage(Colorize(ColorRed, fmt.Sprintf("Comment %d is hidden by the current filters", id)))
+		}
+	}
+	m.list.Select(idx)
+	return m.list.NewStatusMessage(fmt.Sprintf("Jumped to comment %d", id))
+}
+
+// copyAll copies CopyAll's text for every item to the clipboard
+func (m *SelectionModel[T]) copyAll() (tea.Model, tea.Cmd) {
+	if m.opts.CopyAll == nil {
+		return m, nil
+	}
+
+	text, err := m.opts.CopyAll(m.items)
+	if err != nil {
+		return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+	if text == "" {
+		return m, nil
+	}
+	if err := CopyToClipboard(text); err != nil {
+		return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+	lines := strings.Count(strings.TrimRight(text, "\n"), "\n") + 1
+	return m, m.list.NewStatusMessage(Colorize(ColorGreen, fmt.Sprintf("Copied %d lines to clipboard", lines)))
+}
+
+// startApplyPreview initiates the apply suggestion preview mode
+func (m *SelectionModel[T]) startApplyPreview(withResolve bool) (tea.Model, tea.Cmd) {
+	if m.opts.ApplySuggestionPreview == nil {
+		// No preview configured, show error
+		m.confirmationMessage = fmt.Sprintf("%s\n\nPress any key to continue...", Colorize(ColorRed, "Apply preview not configured"))
+		return m, nil
+	}
+
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return m, nil
+	}
+
+	item := selected.(listItem[T])
+
+	// Get the preview diff
+	diffPreview, err := m.opts.ApplySuggestionPreview(item.value)
+	if err != nil {
+		m.confirmationMessage = fmt.Sprintf("%s\n\nPress any key to continue...", Colorize(ColorRed, err.Error()))
+		return m, nil
+	}
+
+	// Enter preview mode
+	m.applyPreviewMode = true
+	m.applyPreviewDiff = diffPreview
+	m.applyPreviewItem = item
+	m.applyPreviewWithResolve = withResolve
+
+	return m, nil
+}
+
+// startAccept runs AcceptSuggestionAction on the selected item in the
+// background; the report of each step is shown when it finishes
+func (m *SelectionModel[T]) startAccept() tea.Cmd {
+	action := m.opts.AcceptSuggestionAction
+	selected := m.list.SelectedItem()
+	if action == nil || selected == nil {
+		return nil
+	}
+
+	item := selected.(listItem[T]).value
+	tick := m.startBusy("Accepting")
+	return tea.Batch(tick, func() tea.Msg {
+		report, apply, err := action(item)
+		return acceptFinishedMsg{report: report, apply: apply, err: err}
+	})
+}
+
+// toggleDraftMode switches between posting replies and buffering them as drafts
+func (m *SelectionModel[T]) toggleDraftMode() tea.Cmd {
+	if m.opts.DraftToggle == nil {
+		return nil
+	}
+	return m.list.NewStatusMessage(m.opts.DraftToggle())
+}
+
+// startDraftSubmit posts the buffered drafts in the background
+func (m *SelectionModel[T]) startDraftSubmit() tea.Cmd {
+	submit := m.opts.DraftSubmit
+	if submit == nil {
+		return nil
+	}
+	if m.draftCount() == 0 {
+		return m.list.NewStatusMessage("No drafts to submit")
+	}
+
+	call, err := submit()
+	if err != nil {
+		return m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+	tick := m.startBusy("Submitting review")
+	return tea.Batch(tick, func() tea.Msg {
+		status, apply, err := call()
+		return draftSubmitFinishedMsg{status: status, apply: apply, err: err}
+	})
+}
+
+// leave quits for key: q leaves for good, ctrl+p so the caller can switch
+// (e.g. to another PR)
+func (m SelectionModel[T]) leave(key string) (tea.Model, tea.Cmd) {
+	if key == "ctrl+p" {
+		if m.opts.OnSwitchRequested != nil {
+			m.opts.OnSwitchRequested(m.filterMode)
+		}
+		m.switched = true
+		return m, tea.Quit
+	}
+	m.result = nil
+	return m, tea.Quit
+}
+
+// draftCount is the number of buffered drafts, 0 without draft support
+func (m *SelectionModel[T]) draftCount() int {
+	if m.opts.DraftCount == nil {
+		return 0
+	}
+	return m.opts.DraftCount()
+}
+
+// draftActions lists the footer entries for draft mode, with the draft count
+// once there is something to submit
+func (m *SelectionModel[T]) draftActions() []string {
+	if m.opts.DraftToggle == nil {
+		return nil
+	}
+	key, _ := splitActionKey(m.opts.DraftKey)
+	actions := []string{key + ":draft"}
+	if n := m.draftCount(); n > 0 && m.opts.DraftSubmit != nil {
+		key, _ := splitActionKey(m.opts.DraftSubmitKey)
+		actions = append(actions, fmt.Sprintf("%s:submit %s", key, pluralize(n, "draft")))
+	}
+	return actions
+}
+
+// editInEditor opens the given file path in the user's editor at the specified line
+func (m *SelectionModel[T]) editInEditor(filePath string, line int) tea.Cmd {
+	// Line-jump syntax depends on the editor (e.g. vim +line, code --goto file:line)
+	c := editorCommandAtLine(filePath, line)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
+
+// Editor actions, remembered in pendingEditorAction while the editor is open
+const (
+	editorActionResolveComment = iota + 2 // R/U
+	editorActionQuote                     // Q
+	editorActionQuoteContext              // C
+	editorActionSuggest                   // G
+	editorActionBatchReply                // B
+	editorActionDismiss                   // W
+)
+
+// startEditorForAction prepares and launches the editor for the given action
+func (m *SelectionModel[T]) startEditorForAction(item T, action int) tea.Cmd {
+	var preparer EditorPreparer[T]
+	switch action {
+	case editorActionResolveComment:
 		preparer = m.opts.ResolveCommentPrepare
-	case 3:
+	case editorActionQuote:
 		preparer = m.opts.QuotePrepare
-	case 4:
+	case editorActionQuoteContext:
 		preparer = m.opts.QuoteContextPrepare
+	case editorActionSuggest:
+		preparer = m.opts.SuggestPrepare
+	case editorActionDismiss:
+		preparer = m.opts.DismissPrepare
 	}
 
 	if preparer == nil {
@@ -624,6 +1561,29 @@ func (m *SelectionModel[T]) startEditorForAction(item T, action int) tea.Cmd {
 		return m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
 	}
 
+	m.pendingEditorItem = item
+	return m.openEditorWith(content, action)
+}
+
+// startBatchReply launches the editor for a reply posted to every marked item
+func (m *SelectionModel[T]) startBatchReply() tea.Cmd {
+	items := m.markedItems()
+	if len(items) == 0 {
+		return m.list.NewStatusMessage("Mark items with space first")
+	}
+
+	content, err := m.opts.BatchReplyPrepare(items)
+	if err != nil {
+		return m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+
+	m.pendingEditorBatch = items
+	return m.openEditorWith(content, editorActionBatchReply)
+}
+
+// openEditorWith writes content to a temp file and opens it in the editor;
+// handleEditorFinished picks up the result for action
+func (m *SelectionModel[T]) openEditorWith(content string, action int) tea.Cmd {
 	// Create temp file
 	tmpFile, err := os.CreateTemp("", "gh-review-conductor-*.md")
 	if err != nil {
@@ -635,15 +1595,10 @@ func (m *SelectionModel[T]) startEditorForAction(item T, action int) tea.Cmd {
 	}
 	_ = tmpFile.Close()
 
-	m.pendingEditorItem = item
 	m.pendingEditorTmpFile = tmpFile.Name()
 	m.pendingEditorAction = action
 
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vim"
-	}
-	c := exec.Command(editor, tmpFile.Name())
+	c := editorCommand(tmpFile.Name())
 	return tea.ExecProcess(c, func(err error) tea.Msg {
 		return editorFinishedMsg{err: err}
 	})
@@ -652,6 +1607,19 @@ func (m *SelectionModel[T]) startEditorForAction(item T, action int) tea.Cmd {
 // handleEditorFinished processes the editor result
 func (m SelectionModel[T]) handleEditorFinished(msg editorFinishedMsg) (tea.Model, tea.Cmd) {
 	if msg.err != nil {
+		if m.pendingEditorTmpFile != "" {
+			_ = os.Remove(m.pendingEditorTmpFile)
+			m.pendingEditorTmpFile = ""
+		}
+		// Exiting nonzero (e.g. vim's :cq) is how editors abort; only
+		// failing to run the editor at all is an error
+		var exitErr *exec.ExitError
+		if errors.As(msg.err, &exitErr) {
+			return m, m.list.NewStatusMessage(fmt.Sprintf("Cancelled (editor exited with status %d)", exitErr.ExitCode()))
+		}
+		if errors.Is(msg.err, exec.ErrNotFound) {
+			return m, m.list.NewStatusMessage(Colorize(ColorRed, fmt.Sprintf("Editor not found: %v (set $VISUAL or $EDITOR)", msg.err)))
+		}
 		return m, m.list.NewStatusMessage(Colorize(ColorRed, fmt.Sprintf("Editor error: %v", msg.err)))
 	}
 
@@ -673,22 +1641,196 @@ func (m SelectionModel[T]) handleEditorFinished(msg editorFinishedMsg) (tea.Mode
 		return m, m.list.NewStatusMessage("Cancelled (empty content)")
 	}
 
+	if m.pendingEditorAction == editorActionBatchReply {
+		return m.completeBatchReply(sanitized)
+	}
+
 	// Call the appropriate completer
 	var completer EditorCompleter[T]
 	switch m.pendingEditorAction {
-	case 2:
+	case editorActionResolveComment:
 		completer = m.opts.ResolveCommentComplete
-	case 3:
+	case editorActionQuote:
 		completer = m.opts.QuoteComplete
-	case 4:
+	case editorActionQuoteContext:
 		completer = m.opts.QuoteContextComplete
+	case editorActionSuggest:
+		completer = m.opts.SuggestComplete
+	case editorActionDismiss:
+		completer = m.opts.DismissComplete
 	}
 
 	if completer == nil {
 		return m, nil
 	}
 
-	result, err := completer(m.pendingEditorItem, sanitized)
+	// Run the API call in the background so the spinner can animate
+	item := m.pendingEditorItem
+	tick := m.startBusy("Sending")
+	return m, tea.Batch(tick, func() tea.Msg {
+		result, apply, err := completer(item, sanitized)
+		return editorCompleteMsg{result: result, apply: apply, err: err}
+	})
+}
+
+// completeBatchReply posts body to the marked items in the background and
+// clears the marks
+func (m SelectionModel[T]) completeBatchReply(body string) (tea.Model, tea.Cmd) {
+	items := m.pendingEditorBatch
+	m.pendingEditorBatch = nil
+	clear(m.marked)
+	return m.runBatchReply(items, body, nil)
+}
+
+// runBatchReply posts body to items in the background, resuming each item at
+// the step resume names when retrying
+func (m SelectionModel[T]) runBatchReply(items []T, body string, resume map[string]string) (tea.Model, tea.Cmd) {
+	complete := m.opts.BatchReplyComplete
+	if complete == nil {
+		return m, nil
+	}
+
+	tick := m.startBusy(fmt.Sprintf("Replying to %d", len(items)))
+	return m, tea.Batch(tick, func() tea.Msg {
+		return batchCompleteMsg{result: complete(items, body, resume), body: body}
+	})
+}
+
+// handleBatchComplete reports a finished batch reply, keeping the items it
+// failed for so F can retry just those
+func (m SelectionModel[T]) handleBatchComplete(msg batchCompleteMsg) (tea.Model, tea.Cmd) {
+	m.stopBusy()
+	result, ok := msg.result.(BatchResult[T])
+	if !ok {
+		return m, nil
+	}
+	m.applyBackground(result.Apply)
+	if len(result.Failed) == 0 {
+		m.batchFailed, m.batchFailedStep = nil, nil
+		return m, m.list.NewStatusMessage(result.Summary)
+	}
+
+	m.batchFailed = result.Failed
+	m.batchFailedStep = result.FailedStep
+	m.batchFailedBody = msg.body
+	var reasons []string
+	for _, item := range result.Failed {
+		if err := result.Errors[m.opts.ItemKey(item)]; err != nil {
+			reasons = append(reasons, err.Error())
+		}
+	}
+	status := result.Summary
+	if len(reasons) > 0 {
+		status += "; failed: " + strings.Join(reasons, ", ")
+	}
+	key, _ := splitActionKey(m.opts.BatchRetryKey)
+	status += fmt.Sprintf(" (%s retries %d)", key, len(result.Failed))
+	return m, m.list.NewStatusMessage(Colorize(ColorRed, status))
+}
+
+// startOptimisticResolve flips the selected item through ResolveStart and
+// runs the API call in the background, marking the item pending meanwhile
+func (m *SelectionModel[T]) startOptimisticResolve() tea.Cmd {
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return nil
+	}
+	item := selected.(listItem[T])
+	key := m.opts.ItemKey(item.value)
+	if m.pending[key] {
+		return m.list.NewStatusMessage("Still updating this thread")
+	}
+
+	call, undo, err := m.opts.ResolveStart(item.value)
+	if err != nil {
+		return m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+	if call == nil {
+		return nil
+	}
+
+	m.ensureRowMarkers()
+	m.pending[key] = true
+	m.list.SetItem(m.list.Index(), item)
+	return func() tea.Msg {
+		status, apply, err := call()
+		return resolveFinishedMsg{key: key, status: status, apply: apply, err: err, undo: undo}
+	}
+}
+
+// ensureRowMarkers creates the marked and pending sets and hands them to the
+// list delegate so rows can show them
+func (m *SelectionModel[T]) ensureRowMarkers() {
+	if m.marked != nil {
+		return
+	}
+	m.marked = make(map[string]bool)
+	m.pending = make(map[string]bool)
+	m.list.SetDelegate(itemDelegate[T]{
+		renderer: m.opts.Renderer,
+		height:   m.opts.RowHeight,
+		marked:   m.marked,
+		pending:  m.pending,
+		itemKey:  m.opts.ItemKey,
+	})
+}
+
+// scrollList moves the list cursor down (ctrl+d, ctrl+f) or up (ctrl+u,
+// ctrl+b) by half or a whole page, stopping at the first and last items
+func (m *SelectionModel[T]) scrollList(key string) {
+	count := len(m.list.VisibleItems())
+	if count == 0 {
+		return
+	}
+	step := max(m.list.Paginator.PerPage, 1)
+	if key == "ctrl+d" || key == "ctrl+u" {
+		step = max(step/2, 1)
+	}
+	if key == "ctrl+u" || key == "ctrl+b" {
+		step = -step
+	}
+	m.list.Select(min(max(m.list.Index()+step, 0), count-1))
+}
+
+// toggleMark marks or unmarks the selected item for batch actions
+func (m *SelectionModel[T]) toggleMark() {
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return
+	}
+	item := selected.(listItem[T]).value
+	if !m.opts.Markable(item) {
+		return
+	}
+	m.ensureRowMarkers()
+	key := m.opts.ItemKey(item)
+	if m.marked[key] {
+		delete(m.marked, key)
+	} else {
+		m.marked[key] = true
+	}
+}
+
+// markedItems returns the marked items in list order, including any the
+// current filters hide
+func (m *SelectionModel[T]) markedItems() []T {
+	if len(m.marked) == 0 {
+		return nil
+	}
+	var items []T
+	for _, item := range m.items {
+		if m.marked[m.opts.ItemKey(item)] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// handleEditorComplete shows the outcome of a background EditorCompleter call
+func (m SelectionModel[T]) handleEditorComplete(msg editorCompleteMsg) (tea.Model, tea.Cmd) {
+	m.stopBusy()
+	m.applyBackground(msg.apply)
+	result, err := msg.result, msg.err
 	if err != nil {
 		return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
 	}
@@ -708,43 +1850,284 @@ func (m SelectionModel[T]) handleEditorFinished(msg editorFinishedMsg) (tea.Mode
 	return m, nil
 }
 
+// defaultAgent is launched when GH_REVIEW_CONDUCTOR_AGENT is unset; see SetAgentCommand
+var defaultAgent = "claude"
+
+// SetAgentCommand changes the coding agent launched when
+// GH_REVIEW_CONDUCTOR_AGENT is unset. Empty keeps the built-in default.
+func SetAgentCommand(command string) {
+	if command != "" {
+		defaultAgent = command
+	}
+}
+
+// agentPTYEnv runs the agent on a pseudo-terminal when set to "1"
+const agentPTYEnv = "GH_REVIEW_CONDUCTOR_AGENT_PTY"
+
 // launchAgent starts the configured coding agent with the given prompt
 func (m *SelectionModel[T]) launchAgent(prompt string) tea.Cmd {
 	agent := os.Getenv("GH_REVIEW_CONDUCTOR_AGENT")
 	if agent == "" {
-		agent = "claude"
+		agent = defaultAgent
 	}
 	parts := strings.Fields(agent)
 	args := append(parts[1:], prompt)
 	c := exec.Command(parts[0], args...)
-	return tea.ExecProcess(c, func(err error) tea.Msg {
+	done := func(err error) tea.Msg {
 		return agentFinishedMsg{err: err}
-	})
+	}
+	// Some agents drop colors and progress output unless they see a terminal
+	if os.Getenv(agentPTYEnv) == "1" {
+		return ptyExec(c, done)
+	}
+	return tea.ExecProcess(c, done)
 }
 
 // updateVisibleItems applies filter and updates the list
 func (m *SelectionModel[T]) updateVisibleItems() {
 	listItems := make([]list.Item, 0, len(m.items))
 	for _, item := range m.items {
-		if m.opts.FilterFunc == nil || m.opts.FilterFunc(item, m.filterActive) {
+		if m.keepItem(item) {
 			listItems = append(listItems, listItem[T]{value: item, item: m.opts.Renderer})
 		}
 	}
 	m.list.SetItems(listItems)
+	m.measureColumns()
+}
+
+// measureColumns lets a ColumnMeasurer size its columns to the items kept by
+// the h filter (the / filter only narrows them, so columns stay put)
+func (m *SelectionModel[T]) measureColumns() {
+	measurer, ok := m.opts.Renderer.(ColumnMeasurer[T])
+	if !ok {
+		return
+	}
+	kept := make([]T, 0, len(m.items))
+	for _, item := range m.items {
+		if m.keepItem(item) {
+			kept = append(kept, item)
+		}
+	}
+	measurer.MeasureColumns(kept)
+}
+
+// keepItem reports whether item passes the current filter state
+func (m *SelectionModel[T]) keepItem(item T) bool {
+	if m.opts.hasFilterModes() {
+		return m.opts.FilterModeFunc(item, m.filterMode)
+	}
+	return m.opts.FilterFunc == nil || m.opts.FilterFunc(item, m.filterActive)
+}
+
+// canResolve reports whether r/u resolves items, optimistically or not
+func (opts SelectorOptions[T]) canResolve() bool {
+	return opts.ResolveAction != nil || (opts.ResolveStart != nil && opts.ItemKey != nil)
+}
+
+// hasFilterModes reports whether h cycles through FilterModes instead of toggling
+func (opts SelectorOptions[T]) hasFilterModes() bool {
+	return opts.FilterModeFunc != nil && len(opts.FilterModes) > 0
+}
+
+// initialFilterMode starts on InitialFilterMode if set, otherwise on the
+// second mode when FilterDefault is set, mirroring the two-state toggle where
+// FilterDefault means "filter active"
+func initialFilterMode[T any](opts SelectorOptions[T]) int {
+	if opts.InitialFilterMode > 0 && opts.InitialFilterMode < len(opts.FilterModes) {
+		return opts.InitialFilterMode
+	}
+	if opts.FilterDefault && len(opts.FilterModes) > 1 {
+		return 1
+	}
+	return 0
+}
+
+// cycleFilterMode advances to the next filter state and announces it
+func (m *SelectionModel[T]) cycleFilterMode() tea.Cmd {
+	m.filterMode = (m.filterMode + 1) % len(m.opts.FilterModes)
+	m.filterActive = m.filterMode != 0
+	m.updateVisibleItems()
+	return m.list.NewStatusMessage(m.opts.FilterModes[m.filterMode])
 }
 
 // startRefresh initiates an async refresh if RefreshItems is configured and not already refreshing
 func (m *SelectionModel[T]) startRefresh() (tea.Model, tea.Cmd) {
 	if m.opts.RefreshItems != nil && !m.refreshing {
 		m.refreshing = true
-		return m, func() tea.Msg {
-			items, err := m.opts.RefreshItems()
-			return refreshFinishedMsg{items: items, err: err}
-		}
+		return m, tea.Batch(m.startBusy("Refreshing"), m.refreshCmd())
 	}
 	return m, nil
 }
 
+// loadAll lifts the cap via LoadAll while items are left out
+func (m *SelectionModel[T]) loadAll() (tea.Model, tea.Cmd) {
+	if m.limitStatus() == "" || m.refreshing {
+		return m, nil
+	}
+	items := m.opts.LoadAll()
+	anchor, index := m.selectionAnchor()
+	m.items = items
+	m.updateVisibleItems()
+	m.restoreSelection(anchor, index)
+	return m, m.list.NewStatusMessage(Colorize(ColorGreen, fmt.Sprintf("Loaded all %d items", len(items))))
+}
+
+// limited caps refreshed items with LimitItems, if set
+func (m *SelectionModel[T]) limited(items []T) []T {
+	if m.opts.LimitItems == nil {
+		return items
+	}
+	return m.opts.LimitItems(items)
+}
+
+// limitStatus returns LimitStatus while LoadAll can lift the cap, or ""
+func (m *SelectionModel[T]) limitStatus() string {
+	if m.opts.LimitStatus == nil || m.opts.LoadAll == nil {
+		return ""
+	}
+	return m.opts.LimitStatus()
+}
+
+// refreshCmd returns a command that fetches items via RefreshItems
+func (m *SelectionModel[T]) refreshCmd() tea.Cmd {
+	refresh := m.opts.RefreshItems
+	return func() tea.Msg {
+		items, err := refresh()
+		return refreshFinishedMsg{items: items, err: err}
+	}
+}
+
+// watchTickCmd schedules the next watch-mode poll
+func (m *SelectionModel[T]) watchTickCmd() tea.Cmd {
+	return tea.Tick(m.opts.WatchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{}
+	})
+}
+
+// handleWatchTick starts a background refresh unless one is already running or
+// the user is in the middle of an interaction that a refresh would disrupt
+func (m SelectionModel[T]) handleWatchTick() (tea.Model, tea.Cmd) {
+	busy := m.refreshing || m.commentSelectMode || m.reactionMode || m.applyPreviewMode || m.pendingEditorTmpFile != ""
+	if busy {
+		return m, m.watchTickCmd()
+	}
+
+	m.refreshing = true
+	refresh := m.opts.RefreshItems
+	return m, func() tea.Msg {
+		items, err := refresh()
+		return refreshFinishedMsg{items: items, err: err, watch: true}
+	}
+}
+
+// handleWatchRefreshFinished merges polled items into the list, keeping the
+// selected item and detail scroll position, then schedules the next poll
+func (m SelectionModel[T]) handleWatchRefreshFinished(msg refreshFinishedMsg) (tea.Model, tea.Cmd) {
+	m.refreshing = false
+	next := m.watchTickCmd()
+	if msg.err != nil {
+		return m, tea.Batch(next, m.list.NewStatusMessage(Colorize(ColorRed, fmt.Sprintf("Watch refresh failed: %v", msg.err))))
+	}
+	items, ok := msg.items.([]T)
+	if !ok {
+		return m, next
+	}
+	items = m.limited(items)
+
+	change := m.describeChanges(items)
+
+	anchor, index := m.selectionAnchor()
+	m.items = items
+	m.updateVisibleItems()
+	m.restoreSelection(anchor, index)
+
+	if m.showDetail {
+		if selected := m.list.SelectedItem(); selected != nil {
+			item := selected.(listItem[T])
+			highlightIdx := -1
+			if m.commentSelectMode {
+				highlightIdx = m.commentSelectIdx
+			}
+			offset := m.viewport.YOffset
+			m.setDetailContent(m.detailPreview(item.value, highlightIdx))
+			m.viewport.SetYOffset(offset)
+		}
+	}
+
+	if change == "" {
+		return m, next
+	}
+	return m, tea.Batch(next, m.notifyCmd(change), m.list.NewStatusMessage(Colorize(ColorGreen, change)))
+}
+
+// describeChanges summarizes what a refresh to items would change, or ""
+func (m *SelectionModel[T]) describeChanges(items []T) string {
+	if m.opts.DescribeChanges == nil {
+		return ""
+	}
+	return m.opts.DescribeChanges(m.items, items)
+}
+
+// notifyCmd signals change to the user when Notify is set; nil otherwise
+func (m *SelectionModel[T]) notifyCmd(change string) tea.Cmd {
+	if !m.opts.Notify || change == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		notify(change)
+		return nil
+	}
+}
+
+// selectionAnchor returns ItemKeys to look for when restoring the selection
+// after the items change: the selected item, then the items after it, then
+// those before it nearest first. It also returns the selected index as a
+// fallback for when ItemKey isn't configured.
+func (m *SelectionModel[T]) selectionAnchor() ([]string, int) {
+	index := m.list.Index()
+	if m.opts.ItemKey == nil {
+		return nil, index
+	}
+	visible := m.list.VisibleItems()
+	if index >= len(visible) {
+		return nil, index
+	}
+	anchor := make([]string, 0, len(visible))
+	for i := index; i < len(visible); i++ {
+		anchor = append(anchor, m.opts.ItemKey(visible[i].(listItem[T]).value))
+	}
+	for i := index - 1; i >= 0; i-- {
+		anchor = append(anchor, m.opts.ItemKey(visible[i].(listItem[T]).value))
+	}
+	return anchor, index
+}
+
+// restoreSelection selects the first anchor key still visible, so the cursor
+// stays on the same item or moves to the next one if it was filtered out.
+// Without a match the previous index is kept, clamped to the list.
+func (m *SelectionModel[T]) restoreSelection(anchor []string, index int) {
+	visible := m.list.VisibleItems()
+	if len(visible) == 0 {
+		return
+	}
+	if len(anchor) > 0 {
+		positions := make(map[string]int, len(visible))
+		for i, listed := range visible {
+			key := m.opts.ItemKey(listed.(listItem[T]).value)
+			if _, seen := positions[key]; !seen {
+				positions[key] = i
+			}
+		}
+		for _, key := range anchor {
+			if i, ok := positions[key]; ok {
+				m.list.Select(i)
+				return
+			}
+		}
+	}
+	m.list.Select(min(index, len(visible)-1))
+}
+
 // isSelectedResolved returns whether the currently selected item is resolved
 func (m *SelectionModel[T]) isSelectedResolved() bool {
 	if m.opts.IsItemResolved == nil {
@@ -776,14 +2159,27 @@ func (m *SelectionModel[T]) getResolveActionKeySecond() string {
 
 // View renders the current model state
 func (m SelectionModel[T]) View() string {
-	if m.showHelp {
+	if m.showHelp || m.showDebug {
 		return m.renderHelpOverlay()
 	}
 
+	if m.confirmLeave != "" {
+		return m.renderDialog(fmt.Sprintf("Leave with %s unsubmitted? They will be discarded.\n\nPress y to leave, any other key to stay...",
+			pluralize(m.draftCount(), "draft")))
+	}
+
 	if m.confirmationMessage != "" {
 		return m.renderConfirmation()
 	}
 
+	if m.composerActive {
+		return m.renderComposer()
+	}
+
+	if m.openFileConfirmMode {
+		return m.renderDialog(fmt.Sprintf("Open file on GitHub?\n\n%s\n\nPress 'y' to open, any other key to cancel", m.openFileURL))
+	}
+
 	if m.applyPreviewMode {
 		return m.renderApplyPreview()
 	}
@@ -795,7 +2191,7 @@ func (m SelectionModel[T]) View() string {
 		// Build action hints for the sticky footer
 		var actions []string
 		actions = append(actions, "q/esc:back")
-		if m.opts.ResolveAction != nil {
+		if m.opts.canResolve() {
 			key, _ := splitActionKey(m.getResolveActionKey())
 			actions = append(actions, key+":resolve")
 		}
@@ -811,6 +2207,18 @@ func (m SelectionModel[T]) View() string {
 			key, _ := splitActionKey(m.opts.QuoteContextKey)
 			actions = append(actions, key+":quote+context")
 		}
+		if m.opts.SuggestPrepare != nil {
+			key, _ := splitActionKey(m.opts.SuggestKey)
+			actions = append(actions, key+":suggest")
+		}
+		if m.opts.DismissPrepare != nil {
+			key, desc := splitActionKey(m.opts.DismissKey)
+			actions = append(actions, key+":"+desc)
+		}
+		if m.opts.ReplyPrepare != nil {
+			key, desc := splitActionKey(m.opts.ReplyKey)
+			actions = append(actions, key+":"+desc)
+		}
 		if m.opts.AgentAction != nil {
 			key, _ := splitActionKey(m.opts.AgentKey)
 			actions = append(actions, key+":agent")
@@ -831,21 +2239,63 @@ func (m SelectionModel[T]) View() string {
 			key, _ := splitActionKey(m.opts.ApplySuggestionResolveKey)
 			actions = append(actions, key+":apply+resolve")
 		}
+		if m.opts.AcceptSuggestionAction != nil {
+			key, _ := splitActionKey(m.opts.AcceptSuggestionKey)
+			actions = append(actions, key+":accept")
+		}
+		actions = append(actions, m.draftActions()...)
 		if m.opts.OnOpen != nil {
 			actions = append(actions, "o:open")
 		}
+		if m.opts.OpenFilePreview != nil {
+			key, _ := splitActionKey(m.opts.OpenFileKey)
+			actions = append(actions, key+":open file")
+		}
+		if m.opts.CopyCommand != nil {
+			key, _ := splitActionKey(m.opts.CopyCommandKey)
+			actions = append(actions, key+":copy cmd")
+		}
+		if m.opts.CopyPermalink != nil {
+			key, _ := splitActionKey(m.opts.CopyPermalinkKey)
+			actions = append(actions, key+":permalink")
+		}
+		if m.opts.CopyQuote != nil {
+			key, _ := splitActionKey(m.opts.CopyQuoteKey)
+			actions = append(actions, key+":copy quote")
+		}
+		if m.opts.TagAction != nil {
+			key, _ := splitActionKey(m.opts.TagKey)
+			actions = append(actions, key+":tag")
+		}
 		if m.opts.RefreshItems != nil {
 			actions = append(actions, "i:refresh")
 		}
+		if _, ok := m.opts.Renderer.(ReplyFolder); ok {
+			actions = append(actions, "J/K:replies")
+		}
+		if _, ok := m.opts.Renderer.(RawPreviewer[T]); ok {
+			if m.showRaw {
+				actions = append(actions, "M:rendered")
+			} else {
+				actions = append(actions, "M:raw")
+			}
+		}
+		if m.noWrap {
+			actions = append(actions, "w:wrap")
+		} else {
+			actions = append(actions, "w:nowrap")
+		}
+		actions = append(actions, "/:find")
 		actions = append(actions, "ctrl+f/b:scroll")
 
 		// Show comment selection or reaction mode status if active
 		var header string
-		if m.reactionMode {
-			emoji := reactionEmojis[m.reactionIdx]
-			reactionStatus := fmt.Sprintf("React: [%d/%d] %s (x=next, Enter=add, Esc=cancel)",
-				m.reactionIdx+1, len(reactionEmojis), emoji.display)
-			header = titleStyle.Render("Detail View") + "  " + helpStyle.Render(reactionStatus)
+		if m.findInputActive {
+			header = titleStyle.Render("Detail View") + "  " + m.findInput.View()
+		} else if m.findQuery != "" {
+			header = titleStyle.Render("Detail View") + "  " + helpStyle.Render(m.findStatus())
+		} else if m.reactionMode {
+			header = titleStyle.Render("Detail View") + "  " + helpStyle.Render(m.reactionStatus())
 		} else if m.commentSelectMode && m.commentSelectInDetail {
 			header = titleStyle.Render("Detail View") + "  " + helpStyle.Render(m.commentSelectStatus)
 		} else {
@@ -853,10 +2303,10 @@ func (m SelectionModel[T]) View() string {
 		}
 
 		var footer string
-		if m.refreshing {
-			footer = helpStyle.Render("Refreshing...")
+		if m.busyLabel != "" {
+			footer = helpStyle.Render(m.busyStatus())
 		} else {
-			footer = helpStyle.Render(strings.Join(actions, " | "))
+			footer = m.footerWarning() + helpStyle.Render(strings.Join(actions, " | "))
 		}
 
 		// Calculate available height for viewport
@@ -883,7 +2333,7 @@ func (m SelectionModel[T]) View() string {
 	// Build sticky footer with action hints
 	var actions []string
 	actions = append(actions, "enter:view")
-	if m.opts.ResolveAction != nil {
+	if m.opts.canResolve() {
 		key, _ := splitActionKey(m.getResolveActionKey())
 		actions = append(actions, key+":resolve")
 	}
@@ -899,6 +2349,18 @@ func (m SelectionModel[T]) View() string {
 		key, _ := splitActionKey(m.opts.QuoteContextKey)
 		actions = append(actions, key+":quote+context")
 	}
+	if m.opts.SuggestPrepare != nil {
+		key, _ := splitActionKey(m.opts.SuggestKey)
+		actions = append(actions, key+":suggest")
+	}
+	if m.opts.DismissPrepare != nil {
+		key, desc := splitActionKey(m.opts.DismissKey)
+		actions = append(actions, key+":"+desc)
+	}
+	if m.opts.ReplyPrepare != nil {
+		key, desc := splitActionKey(m.opts.ReplyKey)
+		actions = append(actions, key+":"+desc)
+	}
 	if m.opts.AgentAction != nil {
 		key, _ := splitActionKey(m.opts.AgentKey)
 		actions = append(actions, key+":agent")
@@ -919,40 +2381,221 @@ func (m SelectionModel[T]) View() string {
 		key, _ := splitActionKey(m.opts.ApplySuggestionResolveKey)
 		actions = append(actions, key+":apply+resolve")
 	}
+	if m.opts.AcceptSuggestionAction != nil {
+		key, _ := splitActionKey(m.opts.AcceptSuggestionKey)
+		actions = append(actions, key+":accept")
+	}
+	actions = append(actions, m.draftActions()...)
 	if m.opts.OnOpen != nil {
 		actions = append(actions, "o:open")
 	}
+	if m.opts.OpenFilePreview != nil {
+		key, _ := splitActionKey(m.opts.OpenFileKey)
+		actions = append(actions, key+":open file")
+	}
+	if m.opts.CopyCommand != nil {
+		key, _ := splitActionKey(m.opts.CopyCommandKey)
+		actions = append(actions, key+":copy cmd")
+	}
+	if m.opts.CopyPermalink != nil {
+		key, _ := splitActionKey(m.opts.CopyPermalinkKey)
+		actions = append(actions, key+":permalink")
+	}
+	if m.opts.CopyQuote != nil {
+		key, _ := splitActionKey(m.opts.CopyQuoteKey)
+		actions = append(actions, key+":copy quote")
+	}
+	if m.opts.CopyAll != nil {
+		key, _ := splitActionKey(m.opts.CopyAllKey)
+		actions = append(actions, key+":copy all")
+	}
+	if m.opts.TagAction != nil {
+		key, _ := splitActionKey(m.opts.TagKey)
+		actions = append(actions, key+":tag")
+	}
+	if m.opts.TagFilterToggle != nil {
+		key, _ := splitActionKey(m.opts.TagFilterKey)
+		actions = append(actions, key+":todo only")
+	}
+	if m.opts.OutdatedFilterToggle != nil {
+		key, _ := splitActionKey(m.opts.OutdatedFilterKey)
+		actions = append(actions, key+":outdated")
+	}
+	if m.opts.SetPathGlob != nil {
+		key, _ := splitActionKey(m.opts.PathGlobKey)
+		if glob := m.pathGlob(); glob != "" {
+			actions = append(actions, key+":path "+glob)
+		} else {
+			actions = append(actions, key+":path")
+		}
+	}
+	if m.opts.Markable != nil && m.opts.ItemKey != nil {
+		actions = append(actions, "space:mark")
+	}
+	if m.opts.BatchReplyPrepare != nil && len(m.marked) > 0 {
+		key, _ := splitActionKey(m.opts.BatchReplyKey)
+		actions = append(actions, fmt.Sprintf("%s:reply to %d marked", key, len(m.marked)))
+	}
+	if len(m.batchFailed) > 0 {
+		key, _ := splitActionKey(m.opts.BatchRetryKey)
+		actions = append(actions, fmt.Sprintf("%s:retry %d failed", key, len(m.batchFailed)))
+	}
 	if m.opts.RefreshItems != nil {
 		actions = append(actions, "i:refresh")
 	}
-	if m.opts.FilterFunc != nil {
+	if m.opts.hasFilterModes() {
+		actions = append(actions, "h:cycle filter")
+	} else if m.opts.FilterFunc != nil {
 		actions = append(actions, "h:hide resolved")
 	}
+	if m.opts.SwitchKey != "" {
+		key, desc := splitActionKey(m.opts.SwitchKey)
+		actions = append(actions, key+":"+desc)
+	}
 	actions = append(actions, "?:help")
 	actions = append(actions, "q:quit")
 
+	// Lead with the cap so it isn't lost when the footer is cut off
+	if status := m.limitStatus(); status != "" {
+		key, desc := splitActionKey(m.opts.LoadAllKey)
+		actions = append([]string{fmt.Sprintf("%s (%s:%s)", status, key, desc)}, actions...)
+	}
+
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 
 	// Show comment selection or reaction status if active
 	var footer string
-	if m.reactionMode {
-		emoji := reactionEmojis[m.reactionIdx]
-		reactionStatus := fmt.Sprintf("React: [%d/%d] %s (x=next, Enter=add, Esc=cancel)",
-			m.reactionIdx+1, len(reactionEmojis), emoji.display)
-		footer = helpStyle.Render(reactionStatus)
+	if m.jumpInputActive {
+		footer = m.jumpInput.View()
+	} else if m.globInputActive {
+		footer = m.globInput.View()
+	} else if m.reactionMode {
+		footer = helpStyle.Render(m.reactionStatus())
 	} else if m.commentSelectMode && !m.commentSelectInDetail {
 		footer = helpStyle.Render(m.commentSelectStatus)
-	} else if m.refreshing {
-		footer = helpStyle.Render("Refreshing...")
+	} else if m.busyLabel != "" {
+		footer = helpStyle.Render(m.busyStatus())
 	} else {
-		footer = helpStyle.Render(strings.Join(actions, " | "))
+		footer = m.footerWarning() + helpStyle.Render(strings.Join(actions, " | "))
+	}
+
+	// Recompute the header from all items so resolves and refreshes are reflected
+	if m.opts.Header != nil {
+		m.list.Title = m.opts.Header(m.items)
+	}
+
+	listView := m.emptyStateView()
+	if listView == "" {
+		listView = m.list.View()
+	}
+
+	// The filter bar uses one of the rows reserved for the header
+	if summary := m.filterSummary(); summary != "" {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			helpStyle.Render(summary),
+			listView,
+			"",
+			footer,
+		)
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left,
-		m.list.View(),
-		"",
-		footer,
-	)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		listView,
+		"",
+		footer,
+	)
+}
+
+// emptyStateView renders EmptyMessage centered under the list title when
+// no items but skippable ones are left; "" when the list should be drawn.
+// A / query leaves it to the list's own "No items".
+func (m *SelectionModel[T]) emptyStateView() string {
+	if m.opts.EmptyMessage == nil || m.list.SettingFilter() || m.filterQuery() != "" {
+		return ""
+	}
+	for _, listed := range m.list.Items() {
+		if !m.opts.Renderer.IsSkippable(listed.(listItem[T]).value) {
+			return ""
+		}
+	}
+	message := m.opts.EmptyMessage(m.activeFilterLabels())
+	if message == "" {
+		return ""
+	}
+
+	title := m.list.Styles.TitleBar.Render(m.list.Styles.Title.Render(m.list.Title))
+	height := max(m.list.Height()-lipgloss.Height(title), 1)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		lipgloss.Place(m.list.Width(), height, lipgloss.Center, lipgloss.Center, message),
+	)
+}
+
+// activeFilterLabels lists the filters currently narrowing the list
+func (m *SelectionModel[T]) activeFilterLabels() []string {
+	var labels []string
+	if m.opts.hasFilterModes() {
+		if m.filterMode != 0 {
+			labels = append(labels, strings.ToLower(m.opts.FilterModes[m.filterMode]))
+		}
+	} else if m.opts.FilterFunc != nil && m.filterActive {
+		labels = append(labels, "hiding resolved")
+	}
+	if m.opts.ActiveFilters != nil {
+		labels = append(labels, m.opts.ActiveFilters()...)
+	}
+	if query := m.filterQuery(); query != "" {
+		labels = append(labels, fmt.Sprintf("%q", query))
+	}
+	return labels
+}
+
+// filterSummary renders the filter bar, e.g. "Filters: hiding resolved | todo only"
+func (m *SelectionModel[T]) filterSummary() string {
+	labels := m.activeFilterLabels()
+	if len(labels) == 0 {
+		return ""
+	}
+	summary := "Filters: " + strings.Join(labels, " | ")
+	// Count matches as the query is typed, before it is applied
+	if m.list.SettingFilter() && m.filterQuery() != "" {
+		summary += "  " + m.matchCount()
+	}
+	if m.opts.ClearFiltersKey != "" {
+		key, _ := splitActionKey(m.opts.ClearFiltersKey)
+		summary += fmt.Sprintf("  (%s to clear)", key)
+	}
+	return summary
+}
+
+// matchCount describes how many items the list filter matches, e.g.
+// "12 matches"; skippable items such as file headers aren't counted
+func (m *SelectionModel[T]) matchCount() string {
+	n := 0
+	for _, listed := range m.list.VisibleItems() {
+		if !m.opts.Renderer.IsSkippable(listed.(listItem[T]).value) {
+			n++
+		}
+	}
+	if n == 1 {
+		return "1 match"
+	}
+	return fmt.Sprintf("%d matches", n)
+}
+
+// clearFilters resets every filter so all items are shown
+func (m *SelectionModel[T]) clearFilters() (tea.Model, tea.Cmd) {
+	if len(m.activeFilterLabels()) == 0 {
+		return m, m.list.NewStatusMessage("No filters to clear")
+	}
+	m.filterMode = 0
+	m.filterActive = false
+	if m.opts.ClearFilters != nil {
+		m.opts.ClearFilters()
+	}
+	m.list.ResetFilter()
+	m.updateVisibleItems()
+	return m, m.list.NewStatusMessage("Cleared filters")
 }
 
 func (m SelectionModel[T]) renderApplyPreview() string {
@@ -1004,6 +2647,36 @@ func (m SelectionModel[T]) renderApplyPreview() string {
 
 // renderConfirmation renders a centered confirmation dialog
 func (m SelectionModel[T]) renderConfirmation() string {
+	message := m.confirmationMessage
+	if confirmationURL(message) != "" {
+		const dismiss = "Press any key to continue..."
+		const copyOrDismiss = "Press y to copy the URL, any other key to continue..."
+		if strings.Contains(message, dismiss) {
+			message = strings.Replace(message, dismiss, copyOrDismiss, 1)
+		} else {
+			message += "\n\n" + copyOrDismiss
+		}
+	}
+	return m.renderDialog(message)
+}
+
+// renderComposer renders the inline reply composer as a dialog
+func (m SelectionModel[T]) renderComposer() string {
+	return m.renderDialog("Reply\n\n" + m.composer.View() + "\n\nctrl+d send | esc cancel")
+}
+
+// confirmationURLRe matches a URL in a dialog message. It stops at the
+// escape sequence ending an OSC 8 hyperlink's target and at the closing
+// parenthesis of the "text (url)" fallback.
+var confirmationURLRe = regexp.MustCompile(`https://[^\s\x1b\x07()<>"]+`)
+
+// confirmationURL returns the first URL in message, or ""
+func confirmationURL(message string) string {
+	return strings.TrimRight(confirmationURLRe.FindString(message), ".,;:")
+}
+
+// renderDialog renders message in a centered box
+func (m SelectionModel[T]) renderDialog(message string) string {
 	width := m.windowSize.Width
 	height := m.windowSize.Height
 
@@ -1021,7 +2694,7 @@ func (m SelectionModel[T]) renderConfirmation() string {
 		Padding(1, 2).
 		Width(60)
 
-	box := boxStyle.Render(m.confirmationMessage)
+	box := boxStyle.Render(message)
 
 	// Center the box
 	boxHeight := lipgloss.Height(box)
@@ -1050,8 +2723,15 @@ func (m SelectionModel[T]) renderConfirmation() string {
 	return strings.Join(lines, "\n")
 }
 
-// renderHelpOverlay renders a help overlay
-func (m SelectionModel[T]) renderHelpOverlay() string {
+// helpBoxChromeWidth and helpBoxChromeHeight are the room taken by the help
+// box's border and padding, plus a row of margin above and below
+const (
+	helpBoxChromeWidth  = 6
+	helpBoxChromeHeight = 6
+)
+
+// helpWindowSize returns the window size, defaulting before the first resize
+func (m SelectionModel[T]) helpWindowSize() (int, int) {
 	width := m.windowSize.Width
 	height := m.windowSize.Height
 
@@ -1061,21 +2741,97 @@ func (m SelectionModel[T]) renderHelpOverlay() string {
 	if height == 0 {
 		height = 24
 	}
+	return width, height
+}
+
+// openHelp shows the help overlay, sizing its viewport to fit the window
+func (m *SelectionModel[T]) openHelp() {
+	m.setOverlayContent(m.helpText(), m.showHelp)
+	m.showHelp = true
+}
+
+// openDebug shows the selected item's raw fields in an overlay drawn like
+// the help. It does nothing unless debug output is on and the caller set
+// DebugInfo.
+func (m *SelectionModel[T]) openDebug() {
+	if m.opts.DebugInfo == nil || !uiDebug.Load() {
+		return
+	}
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return
+	}
+
+	info := strings.TrimRight(m.opts.DebugInfo(selected.(listItem[T]).value), "\n")
+	text := "Debug: selected item\n\n" + info + "\n\n↑/↓ to scroll, q or esc to close"
+	m.setOverlayContent(text, m.showDebug)
+	m.showDebug = true
+}
+
+// setOverlayContent loads text into the help overlay's viewport, sized to fit
+// the window. keepOffset preserves the scroll position when the overlay is
+// already open and only the window changed.
+func (m *SelectionModel[T]) setOverlayContent(helpText string, keepOffset bool) {
+	width, height := m.helpWindowSize()
+
+	viewWidth := lipgloss.Width(helpText)
+	if maxWidth := width - helpBoxChromeWidth; viewWidth > maxWidth {
+		viewWidth = max(maxWidth, 1)
+	}
+	viewHeight := lipgloss.Height(helpText)
+	if maxHeight := height - helpBoxChromeHeight; viewHeight > maxHeight {
+		viewHeight = max(maxHeight, 1)
+	}
+
+	offset := m.helpView.YOffset
+	m.helpView = viewport.New(viewWidth, viewHeight)
+	m.helpView.SetContent(helpText)
+	if keepOffset {
+		m.helpView.SetYOffset(offset) // keep the scroll position across resizes
+	}
+}
 
+// helpText builds the help overlay contents for the configured actions
+func (m SelectionModel[T]) helpText() string {
 	helpText := `Keyboard Shortcuts
 
 Navigation:
   ↑/↓, j/k     Move up/down
+  ctrl+d/u     Half page down/up (list)
+  ctrl+f/b     Page down/up
   enter, l, →  View detail / select
   ←, esc       Go back (from detail)
   q            Quit (list) / Back (detail)
   /            Filter items
-  h            Toggle hide resolved (list)
+  h            Toggle hide resolved / cycle filter (list)`
 
-Actions:`
+	if m.opts.IsGroupHeader != nil {
+		helpText += fmt.Sprintf("\n  %-12s %s", "{/}", "Previous/next file (list)")
+	}
+	if m.opts.ClearFiltersKey != "" {
+		key, desc := splitActionKey(m.opts.ClearFiltersKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (list)")
+	}
+	if m.opts.MatchesCommentID != nil {
+		key, desc := splitActionKey(m.opts.JumpKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (list)")
+	}
+	if m.opts.SetPathGlob != nil {
+		key, desc := splitActionKey(m.opts.PathGlobKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (list; ** spans directories, empty shows all)")
+	}
+	if m.opts.LoadAll != nil {
+		key, desc := splitActionKey(m.opts.LoadAllKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (list)")
+	}
+	if m.opts.SwitchKey != "" {
+		key, desc := splitActionKey(m.opts.SwitchKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (list)")
+	}
+	helpText += "\n\nActions:"
 
 	// Add dynamic action help
-	if m.opts.ResolveAction != nil {
+	if m.opts.canResolve() {
 		key, desc := splitActionKey(m.getResolveActionKey())
 		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
 	}
@@ -1091,6 +2847,18 @@ Actions:`
 		key, desc := splitActionKey(m.opts.QuoteContextKey)
 		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
 	}
+	if m.opts.SuggestPrepare != nil {
+		key, desc := splitActionKey(m.opts.SuggestKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
+	}
+	if m.opts.DismissPrepare != nil {
+		key, desc := splitActionKey(m.opts.DismissKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (reply with a reason and resolve)")
+	}
+	if m.opts.ReplyPrepare != nil {
+		key, desc := splitActionKey(m.opts.ReplyKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (type a short reply inline, ctrl+d to send)")
+	}
 	if m.opts.AgentAction != nil {
 		key, desc := splitActionKey(m.opts.AgentKey)
 		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
@@ -1111,21 +2879,135 @@ Actions:`
 		key, desc := splitActionKey(m.opts.ApplySuggestionResolveKey)
 		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
 	}
+	if m.opts.AcceptSuggestionAction != nil {
+		key, desc := splitActionKey(m.opts.AcceptSuggestionKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (apply without preview, reply and resolve)")
+	}
+	if m.opts.DraftToggle != nil {
+		key, desc := splitActionKey(m.opts.DraftKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (toggle; replies are saved as drafts instead of posted)")
+	}
+	if m.opts.DraftSubmit != nil {
+		key, desc := splitActionKey(m.opts.DraftSubmitKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (post the drafts together as one review)")
+	}
 	if m.opts.OnOpen != nil {
 		helpText += fmt.Sprintf("\n  %-12s %s", "o", "open in browser")
 	}
+	if m.opts.OpenFilePreview != nil {
+		key, desc := splitActionKey(m.opts.OpenFileKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
+	}
+	if m.opts.CopyCommand != nil {
+		key, desc := splitActionKey(m.opts.CopyCommandKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
+	}
+	if m.opts.CopyPermalink != nil {
+		key, desc := splitActionKey(m.opts.CopyPermalinkKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
+	}
+	if m.opts.CopyQuote != nil {
+		key, desc := splitActionKey(m.opts.CopyQuoteKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (to paste elsewhere; nothing is posted)")
+	}
+	if m.opts.CopyQuoteContext != nil {
+		key, desc := splitActionKey(m.opts.CopyQuoteContextKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
+	}
+	if m.opts.CopyAll != nil {
+		key, desc := splitActionKey(m.opts.CopyAllKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
+	}
+	if m.opts.TagAction != nil {
+		key, desc := splitActionKey(m.opts.TagKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
+	}
+	if m.opts.TagFilterToggle != nil {
+		key, desc := splitActionKey(m.opts.TagFilterKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc)
+	}
+	if m.opts.OutdatedFilterToggle != nil {
+		key, desc := splitActionKey(m.opts.OutdatedFilterKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (toggle)")
+	}
+	if m.opts.Markable != nil && m.opts.ItemKey != nil {
+		helpText += fmt.Sprintf("\n  %-12s %s", "space", "mark for batch actions (list)")
+	}
+	if m.opts.BatchReplyPrepare != nil {
+		key, desc := splitActionKey(m.opts.BatchReplyKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (list)")
+		key, desc = splitActionKey(m.opts.BatchRetryKey)
+		helpText += fmt.Sprintf("\n  %-12s %s", key, desc+" (resend the last batch reply to the threads it failed for)")
+	}
 	if m.opts.RefreshItems != nil {
 		helpText += fmt.Sprintf("\n  %-12s %s", "i", "refresh")
 	}
+	if m.opts.DebugInfo != nil && uiDebug.Load() {
+		helpText += fmt.Sprintf("\n  %-12s %s", "D", "show the item's raw fields (debug)")
+	}
+
+	helpText += m.legendText()
 
 	helpText += `
 
 Detail View:
   i            Refresh content
+  /            Find in detail
+  n/N          Next/previous match`
+	if _, ok := m.opts.Renderer.(ReplyFolder); ok {
+		helpText += fmt.Sprintf("\n  %-12s %s", "J/K", "Show one more/fewer older reply")
+		helpText += fmt.Sprintf("\n  %-12s %s", "E", "Show all replies / latest only")
+	}
+	if _, ok := m.opts.Renderer.(RawPreviewer[T]); ok {
+		helpText += fmt.Sprintf("\n  %-12s %s", "M", "Toggle raw markdown")
+	}
+	helpText += `
+  w            Toggle line wrapping
   ctrl+f       Page down
   ctrl+b       Page up
 
-Press any key to close this help...`
+↑/↓ to scroll, q or esc to close this help`
+
+	return helpText
+}
+
+// legendText builds the help overlay's Legend section from the styles the
+// list is drawn with, followed by the caller's entries
+func (m SelectionModel[T]) legendText() string {
+	entries := []LegendEntry{{Sample: cursorStyle.Render(">"), Meaning: "current row"}}
+	if m.opts.Markable != nil && m.opts.ItemKey != nil {
+		entries = append(entries, LegendEntry{Sample: "*", Meaning: "marked for batch actions"})
+	}
+	if m.opts.ResolveStart != nil && m.opts.ItemKey != nil {
+		entries = append(entries, LegendEntry{Sample: "…", Meaning: "resolve in progress"})
+	}
+	if m.hasSkippableItems() {
+		entries = append(entries, LegendEntry{Sample: skippableStyle.Render("crossed out"), Meaning: "can't be selected"})
+	}
+	entries = append(entries, m.opts.Legend...)
+
+	text := "\n\nLegend:"
+	for _, entry := range entries {
+		// Pad by display width so styled samples line up
+		pad := max(12-lipgloss.Width(entry.Sample), 0)
+		text += "\n  " + entry.Sample + strings.Repeat(" ", pad) + " " + entry.Meaning
+	}
+	return text
+}
+
+// hasSkippableItems reports whether any item renders crossed out
+func (m SelectionModel[T]) hasSkippableItems() bool {
+	for _, item := range m.items {
+		if m.opts.Renderer.IsSkippable(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderHelpOverlay renders the help (or debug) overlay centered in the window
+func (m SelectionModel[T]) renderHelpOverlay() string {
+	width, height := m.helpWindowSize()
 
 	// Create styled box
 	boxStyle := lipgloss.NewStyle().
@@ -1133,7 +3015,7 @@ Press any key to close this help...`
 		BorderForeground(lipgloss.Color("205")).
 		Padding(1, 2)
 
-	box := boxStyle.Render(helpText)
+	box := boxStyle.Render(m.helpView.View())
 
 	// Center the box
 	boxHeight := lipgloss.Height(box)
@@ -1162,13 +3044,23 @@ Press any key to close this help...`
 	return strings.Join(lines, "\n")
 }
 
+// Row styles, shared by the list and the help overlay's legend
+var (
+	cursorStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	skippableStyle = lipgloss.NewStyle().Strikethrough(true).Foreground(lipgloss.Color("241"))
+)
+
 // itemDelegate renders individual list items
 type itemDelegate[T any] struct {
 	renderer ItemRenderer[T]
+	height   int             // lines per row; see SelectorOptions.RowHeight
+	marked   map[string]bool // items marked for batch actions, by itemKey
+	pending  map[string]bool // items with an optimistic resolve in flight, by itemKey
+	itemKey  func(T) string
 }
 
 func (d itemDelegate[T]) Height() int {
-	return 1
+	return max(d.height, 1)
 }
 
 func (d itemDelegate[T]) Spacing() int {
@@ -1194,39 +3086,67 @@ func (d itemDelegate[T]) Render(w io.Writer, m list.Model, index int, item list.
 	// Truncate if needed
 	maxWidth := m.Width() - 4
 	if maxWidth > 0 {
-		if len(title) > maxWidth {
-			title = title[:maxWidth-3] + "..."
-		}
-		if len(desc) > maxWidth {
-			desc = desc[:maxWidth-3] + "..."
-		}
+		title = truncateDisplay(title, maxWidth)
+		desc = truncateDisplay(desc, maxWidth)
 	}
 
-	var line string
-	if desc != "" {
+	// Multi-line rows put the description under the title instead of after it
+	var below []string
+	line := title
+	if d.Height() > 1 {
+		below = descriptionLines(i.Description(), d.Height()-1, maxWidth)
+	} else if desc != "" {
 		line = fmt.Sprintf("%s - %s", title, desc)
-	} else {
-		line = title
+	}
+
+	// Marked items get a * after the cursor column, pending ones a …
+	mark := " "
+	if d.itemKey != nil {
+		key := d.itemKey(i.value)
+		if d.pending[key] {
+			mark = "…"
+		} else if d.marked[key] {
+			mark = "*"
+		}
 	}
 
 	// Style based on selection and skippable state
 	if index == m.Index() {
-		style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+		style := cursorStyle
 		if isSkippable {
-			style = style.Strikethrough(true).Foreground(lipgloss.Color("241"))
+			style = style.Strikethrough(true).Foreground(skippableStyle.GetForeground())
 		}
-		line = style.Render("> " + line)
+		line = style.Render(">" + mark + line)
 	} else {
 		if isSkippable {
-			line = lipgloss.NewStyle().Strikethrough(true).Foreground(lipgloss.Color("241")).Render("  " + line)
+			line = skippableStyle.Render(" " + mark + line)
 		} else {
-			line = "  " + line
+			line = " " + mark + line
 		}
 	}
+	for _, extra := range below {
+		line += "\n  " + extra
+	}
 
 	_, _ = fmt.Fprint(w, line)
 }
 
+// descriptionLines splits desc into exactly n lines cut to maxWidth columns,
+// padding with blank lines so every row keeps the delegate's height
+func descriptionLines(desc string, n, maxWidth int) []string {
+	lines := make([]string, n)
+	if desc == "" {
+		return lines
+	}
+	for i, text := range strings.SplitN(desc, "\n", n) {
+		if maxWidth > 0 {
+			text = truncateDisplay(strings.ReplaceAll(text, "\n", " "), maxWidth)
+		}
+		lines[i] = text
+	}
+	return lines
+}
+
 // enterCommentSelectMode starts comment selection for the given action
 func (m *SelectionModel[T]) enterCommentSelectMode(action string, item listItem[T]) {
 	m.commentSelectMode = true
@@ -1234,11 +3154,35 @@ func (m *SelectionModel[T]) enterCommentSelectMode(action string, item listItem[
 	m.commentSelectIdx = 0
 	m.commentSelectItem = item
 	m.commentSelectInDetail = false
+	m.commentSelectSince = time.Now()
 
 	// Build initial status
 	count := m.opts.Renderer.ThreadCommentCount(item.value)
 	preview := m.opts.Renderer.ThreadCommentPreview(item.value, 0)
-	m.commentSelectStatus = fmt.Sprintf("[1/%d] %s (%s=next, Enter=select, Esc=cancel)", count, preview, action)
+	m.commentSelectStatus = commentSelectStatus(0, count, preview, action)
+}
+
+// commentSelectStatus is the status line shown while choosing comment idx
+// (0-based) of a thread for action
+func commentSelectStatus(idx, count int, preview, action string) string {
+	return fmt.Sprintf("[%d/%d] %s (%s=next, Enter=select, Esc=cancel)", idx+1, count, preview, action)
+}
+
+// CommentSelectChromeWidth is how many columns the comment-selection status
+// line takes around a ThreadCommentPreview, for a thread of count comments
+func CommentSelectChromeWidth(count int) int {
+	return DisplayWidth(commentSelectStatus(count-1, count, "", "x"))
+}
+
+// reactionDoublePressWindow is how soon a second x must follow the first to
+// react to the thread head instead of cycling to the first reply
+const reactionDoublePressWindow = 400 * time.Millisecond
+
+// isReactionDoublePress reports whether x was pressed again right after it
+// started comment selection, which picks the head comment directly
+func (m *SelectionModel[T]) isReactionDoublePress() bool {
+	return m.commentSelectAction == "x" && m.commentSelectIdx == 0 &&
+		time.Since(m.commentSelectSince) < reactionDoublePressWindow
 }
 
 // cycleCommentSelection advances to the next comment in the thread
@@ -1248,8 +3192,7 @@ func (m *SelectionModel[T]) cycleCommentSelection() {
 
 	// Update status
 	preview := m.opts.Renderer.ThreadCommentPreview(m.commentSelectItem.value, m.commentSelectIdx)
-	m.commentSelectStatus = fmt.Sprintf("[%d/%d] %s (%s=next, Enter=select, Esc=cancel)",
-		m.commentSelectIdx+1, count, preview, m.commentSelectAction)
+	m.commentSelectStatus = commentSelectStatus(m.commentSelectIdx, count, preview, m.commentSelectAction)
 }
 
 // showCommentSelectStatus returns a command to display the current selection status
@@ -1291,7 +3234,115 @@ func (m *SelectionModel[T]) handleQuoteKey(inDetailView bool) (tea.Model, tea.Cm
 	if inDetailView {
 		m.showDetail = false
 	}
-	return m, m.startEditorForAction(item.value, 3)
+	return m, m.startEditorForAction(item.value, editorActionQuote)
+}
+
+// handleDismissKey handles the 'W' key for a won't-fix reply, used by both
+// list and detail views. The reply resolves the whole thread, so there is no
+// comment selection step.
+func (m *SelectionModel[T]) handleDismissKey(inDetailView bool) (tea.Model, tea.Cmd) {
+	if m.opts.DismissPrepare == nil {
+		return m, nil
+	}
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return m, nil
+	}
+
+	if inDetailView {
+		m.showDetail = false
+	}
+	return m, m.startEditorForAction(selected.(listItem[T]).value, editorActionDismiss)
+}
+
+// openComposer handles the 'm' key, opening the inline reply composer for
+// the selected item. Used by both list and detail views.
+func (m *SelectionModel[T]) openComposer(inDetailView bool) (tea.Model, tea.Cmd) {
+	if m.opts.ReplyPrepare == nil {
+		return m, nil
+	}
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return m, nil
+	}
+
+	item := selected.(listItem[T]).value
+	content, err := m.opts.ReplyPrepare(item)
+	if err != nil {
+		return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+
+	composer := textarea.New()
+	composer.ShowLineNumbers = false
+	composer.CharLimit = 0
+	composer.Placeholder = "Reply..."
+	composer.SetWidth(composerWidth)
+	composer.SetHeight(composerHeight)
+	composer.SetValue(content)
+
+	if inDetailView {
+		m.showDetail = false
+	}
+	m.composer = composer
+	m.composerItem = item
+	m.composerActive = true
+	return m, m.composer.Focus()
+}
+
+// composerWidth and composerHeight size the reply composer's text area
+const (
+	composerWidth  = 56
+	composerHeight = 5
+)
+
+// handleComposerKey handles keys while the reply composer is open: ctrl+d
+// sends, esc cancels, and everything else edits the text
+func (m SelectionModel[T]) handleComposerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.composerActive = false
+		m.composer.Blur()
+		return m, m.list.NewStatusMessage("Reply cancelled")
+	case "ctrl+d":
+		m.composerActive = false
+		m.composer.Blur()
+		body := SanitizeEditorContent(m.composer.Value())
+		if body == "" {
+			return m, m.list.NewStatusMessage("Cancelled (empty content)")
+		}
+		completer := m.opts.ReplyComplete
+		if completer == nil {
+			return m, nil
+		}
+		item := m.composerItem
+		tick := m.startBusy("Sending")
+		return m, tea.Batch(tick, func() tea.Msg {
+			result, apply, err := completer(item, body)
+			return editorCompleteMsg{result: result, apply: apply, err: err}
+		})
+	}
+
+	var cmd tea.Cmd
+	m.composer, cmd = m.composer.Update(msg)
+	return m, cmd
+}
+
+// handleSuggestKey handles the 'G' key for a suggestion reply, used by both
+// list and detail views. Suggestions target the code, so there is no comment
+// selection step.
+func (m *SelectionModel[T]) handleSuggestKey(inDetailView bool) (tea.Model, tea.Cmd) {
+	if m.opts.SuggestPrepare == nil {
+		return m, nil
+	}
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return m, nil
+	}
+
+	if inDetailView {
+		m.showDetail = false
+	}
+	return m, m.startEditorForAction(selected.(listItem[T]).value, editorActionSuggest)
 }
 
 // handleQuoteContextKey handles the 'C' key for quote with context, used by both list and detail views
@@ -1319,7 +3370,7 @@ func (m *SelectionModel[T]) handleQuoteContextKey(inDetailView bool) (tea.Model,
 	if inDetailView {
 		m.showDetail = false
 	}
-	return m, m.startEditorForAction(item.value, 4)
+	return m, m.startEditorForAction(item.value, editorActionQuoteContext)
 }
 
 // handleAgentKey handles the 'a' key for agent action, used by both list and detail views
@@ -1401,10 +3452,250 @@ func (m *SelectionModel[T]) enterReactionMode(commentID int64, item listItem[T])
 
 // showReactionStatus returns a command to display the current reaction selection status
 func (m *SelectionModel[T]) showReactionStatus() tea.Cmd {
-	emoji := reactionEmojis[m.reactionIdx]
-	msg := fmt.Sprintf("React: [%d/%d] %s (x=next, Enter=add, Esc=cancel)",
-		m.reactionIdx+1, len(reactionEmojis), emoji.display)
-	return m.list.NewStatusMessage(msg)
+	return m.list.NewStatusMessage(m.reactionStatus())
+}
+
+// reactionStatus describes the emoji currently picked in reaction mode
+func (m *SelectionModel[T]) reactionStatus() string {
+	choices := m.reactionChoices()
+	emoji := choices[m.reactionIdx]
+	return fmt.Sprintf("React: [%d/%d] %s (x=next, 1-%d=pick, Enter=add, Esc=cancel)",
+		m.reactionIdx+1, len(choices), emoji.label(), len(choices))
+}
+
+// reactionChoices is the reaction picker's cycle, in ReactionOrder
+func (m *SelectionModel[T]) reactionChoices() []reactionEmoji {
+	return orderedReactions(m.opts.ReactionOrder)
+}
+
+// detailPreview renders item for the detail view, as markdown source when
+// the raw toggle is on and the renderer supports it
+func (m SelectionModel[T]) detailPreview(item T, highlightIdx int) string {
+	if folder, ok := m.opts.Renderer.(ReplyFolder); ok {
+		folder.SetVisibleReplies(m.visibleReplies())
+	}
+	if raw, ok := m.opts.Renderer.(RawPreviewer[T]); ok && m.showRaw {
+		return raw.PreviewRaw(item, highlightIdx, m.filterQuery())
+	}
+	return m.opts.Renderer.PreviewWithHighlight(item, highlightIdx, m.filterQuery())
+}
+
+// toggleRawPreview switches the detail view between rendered and raw
+// markdown, keeping the scroll position and any comment highlight
+func (m *SelectionModel[T]) toggleRawPreview() tea.Cmd {
+	m.showRaw = !m.showRaw
+	m.rerenderDetail()
+	if m.showRaw {
+		return m.list.NewStatusMessage("Showing raw markdown")
+	}
+	return m.list.NewStatusMessage("Showing rendered markdown")
+}
+
+// toggleDetailWrap switches the detail view between soft-wrapped lines and
+// lines cut off at the right edge, keeping the scroll position
+func (m *SelectionModel[T]) toggleDetailWrap() tea.Cmd {
+	m.noWrap = !m.noWrap
+	m.rerenderDetail()
+	if m.noWrap {
+		return m.list.NewStatusMessage("Line wrapping off")
+	}
+	return m.list.NewStatusMessage("Line wrapping on")
+}
+
+// visibleReplies is how many of the latest replies the detail view shows;
+// -1 for all
+func (m SelectionModel[T]) visibleReplies() int {
+	if m.allReplies {
+		return -1
+	}
+	return max(DefaultVisibleReplies+m.extraReplies, 0)
+}
+
+// foldReplies handles J (show one more older reply), K (fold one) and E
+// (show all or go back to the latest few)
+func (m *SelectionModel[T]) foldReplies(key string) tea.Cmd {
+	replies := m.opts.Renderer.ThreadCommentCount(m.selectedValue()) - 1
+	if m.allReplies && key != "E" {
+		// Step from everything being shown
+		m.allReplies = false
+		m.extraReplies = replies - DefaultVisibleReplies
+	}
+
+	switch key {
+	case "J":
+		if m.visibleReplies() >= replies {
+			return m.list.NewStatusMessage("All replies shown")
+		}
+		m.extraReplies++
+	case "K":
+		if m.visibleReplies() == 0 {
+			return m.list.NewStatusMessage("All replies folded")
+		}
+		m.extraReplies = min(m.visibleReplies(), replies) - 1 - DefaultVisibleReplies
+	case "E":
+		m.allReplies = !m.allReplies
+		m.extraReplies = 0
+	}
+	m.rerenderDetail()
+	return nil
+}
+
+// selectedValue returns the selected item's value, or the zero value
+func (m *SelectionModel[T]) selectedValue() T {
+	if selected := m.list.SelectedItem(); selected != nil {
+		return selected.(listItem[T]).value
+	}
+	var zero T
+	return zero
+}
+
+// applyBackground runs a background action's apply on the UI goroutine,
+// redrawing the detail view since it may show what changed
+func (m *SelectionModel[T]) applyBackground(apply func()) {
+	if apply == nil {
+		return
+	}
+	apply()
+	if m.showDetail {
+		m.rerenderDetail()
+	}
+}
+
+// rerenderDetail re-renders the selected item in the detail view, keeping
+// the scroll position and any comment highlight
+func (m *SelectionModel[T]) rerenderDetail() {
+	selected := m.list.SelectedItem()
+	if selected == nil {
+		return
+	}
+	highlightIdx := -1
+	if m.commentSelectMode {
+		highlightIdx = m.commentSelectIdx
+	}
+	offset := m.viewport.YOffset
+	m.setDetailContent(m.detailPreview(selected.(listItem[T]).value, highlightIdx))
+	m.viewport.SetYOffset(offset)
+}
+
+// setDetailContent sets the detail viewport content, re-running any active find
+func (m *SelectionModel[T]) setDetailContent(content string) {
+	if !m.noWrap && m.viewport.Width > 0 {
+		content = WrapText(content, m.viewport.Width)
+	}
+	m.detailContent = content
+	if m.findQuery == "" {
+		m.viewport.SetContent(content)
+		return
+	}
+	m.findMatches = findMatchLines(content, m.findQuery)
+	if m.findIdx >= len(m.findMatches) {
+		m.findIdx = 0
+	}
+	m.viewport.SetContent(HighlightMatches(content, m.findQuery))
+}
+
+// openDetailFind shows the find input in the detail view
+func (m *SelectionModel[T]) openDetailFind() tea.Cmd {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "find"
+	input.SetValue(m.findQuery)
+	input.CursorEnd()
+	m.findInput = input
+	m.findInputActive = true
+	return m.findInput.Focus()
+}
+
+// handleFindInputKey routes keys to the find input; enter runs the search
+func (m SelectionModel[T]) handleFindInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.findInputActive = false
+		m.findInput.Blur()
+		return m, nil
+	case "enter":
+		m.findInputActive = false
+		m.findInput.Blur()
+		m.runDetailFind(m.findInput.Value())
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.findInput, cmd = m.findInput.Update(msg)
+	return m, cmd
+}
+
+// runDetailFind searches the detail content for query and scrolls to the first match
+func (m *SelectionModel[T]) runDetailFind(query string) {
+	offset := m.viewport.YOffset
+	m.findQuery = query
+	m.findIdx = 0
+	m.setDetailContent(m.detailContent)
+	m.viewport.SetYOffset(offset)
+	if len(m.findMatches) > 0 {
+		m.scrollDetailToLine(m.findMatches[0])
+	}
+}
+
+// stepDetailFind moves to the next (or previous) match, wrapping around
+func (m *SelectionModel[T]) stepDetailFind(forward bool) {
+	if len(m.findMatches) == 0 {
+		return
+	}
+	if forward {
+		m.findIdx = (m.findIdx + 1) % len(m.findMatches)
+	} else {
+		m.findIdx = (m.findIdx - 1 + len(m.findMatches)) % len(m.findMatches)
+	}
+	m.scrollDetailToLine(m.findMatches[m.findIdx])
+}
+
+// clearDetailFind removes the active search and its highlighting
+func (m *SelectionModel[T]) clearDetailFind() {
+	hadQuery := m.findQuery != ""
+	m.findInputActive = false
+	m.findQuery = ""
+	m.findMatches = nil
+	m.findIdx = 0
+	if hadQuery {
+		offset := m.viewport.YOffset
+		m.viewport.SetContent(m.detailContent)
+		m.viewport.SetYOffset(offset)
+	}
+}
+
+// findStatus describes the active search for the detail header
+func (m *SelectionModel[T]) findStatus() string {
+	if len(m.findMatches) == 0 {
+		return fmt.Sprintf("/%s: no matches (esc=clear)", m.findQuery)
+	}
+	return fmt.Sprintf("/%s: match %d/%d (n=next, N=prev, esc=clear)", m.findQuery, m.findIdx+1, len(m.findMatches))
+}
+
+// findMatchLines returns the line index of every case-insensitive occurrence
+// of query in content, ignoring terminal escape sequences
+func findMatchLines(content, query string) []int {
+	if query == "" {
+		return nil
+	}
+	needle := strings.ToLower(query)
+	var matches []int
+	for i, line := range strings.Split(content, "\n") {
+		plain := strings.ToLower(terminalEscapeRe.ReplaceAllString(line, ""))
+		for n := strings.Count(plain, needle); n > 0; n-- {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// scrollDetailToLine scrolls the detail view so line is near the top, with a
+// little context above it
+func (m *SelectionModel[T]) scrollDetailToLine(line int) {
+	offset := line - 2
+	if offset < 0 {
+		offset = 0
+	}
+	m.viewport.SetYOffset(offset)
 }
 
 // updateDetailViewWithHighlight updates the detail view to highlight the currently selected comment
@@ -1412,26 +3703,41 @@ func (m *SelectionModel[T]) updateDetailViewWithHighlight() {
 	if !m.showDetail {
 		return
 	}
-	content := m.opts.Renderer.PreviewWithHighlight(m.commentSelectItem.value, m.commentSelectIdx)
-	m.viewport.SetContent(content)
+	content := m.detailPreview(m.commentSelectItem.value, m.commentSelectIdx)
+	m.setDetailContent(content)
 
 	// Scroll to make the highlighted section visible
 	// Look for the highlight marker and scroll to it
 	lines := strings.Split(content, "\n")
 	for i, line := range lines {
 		if strings.Contains(line, "SELECTED") {
-			// Set Y offset so the highlighted line is near the top of the viewport
-			// Leave a few lines of context above if possible
-			offset := i - 2
-			if offset < 0 {
-				offset = 0
-			}
-			m.viewport.SetYOffset(offset)
+			// Keep the highlighted line near the top of the viewport
+			m.scrollDetailToLine(i)
 			break
 		}
 	}
 }
 
+// openSelectedComment opens the comment highlighted in comment-select mode,
+// staying in the view the selection was started from
+func (m *SelectionModel[T]) openSelectedComment() (tea.Model, tea.Cmd) {
+	value := m.opts.Renderer.WithSelectedComment(m.commentSelectItem.value, m.commentSelectIdx)
+	wasInDetail := m.commentSelectInDetail
+	m.exitCommentSelectMode()
+	if wasInDetail {
+		m.setDetailContent(m.detailPreview(m.commentSelectItem.value, -1))
+	}
+
+	statusMsg, err := m.opts.OnOpen(value)
+	if err != nil {
+		return m, m.list.NewStatusMessage(Colorize(ColorRed, err.Error()))
+	}
+	if statusMsg != "" {
+		return m, m.list.NewStatusMessage(statusMsg)
+	}
+	return m, nil
+}
+
 // executeCommentAction runs the pending action with the selected comment
 func (m *SelectionModel[T]) executeCommentAction() (tea.Model, tea.Cmd) {
 	// Update item with selected comment index
@@ -1447,12 +3753,12 @@ func (m *SelectionModel[T]) executeCommentAction() (tea.Model, tea.Cmd) {
 		if wasInDetail {
 			m.showDetail = false
 		}
-		return m, m.startEditorForAction(itemWithSelection, 3)
+		return m, m.startEditorForAction(itemWithSelection, editorActionQuote)
 	case "C":
 		if wasInDetail {
 			m.showDetail = false
 		}
-		return m, m.startEditorForAction(itemWithSelection, 4)
+		return m, m.startEditorForAction(itemWithSelection, editorActionQuoteContext)
 	case "a":
 		if m.opts.AgentAction != nil {
 			result, err := m.opts.AgentAction(itemWithSelection)
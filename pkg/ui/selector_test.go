@@ -1,13 +1,20 @@
 package ui
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestSanitizeEditorContent(t *testing.T) {
@@ -76,6 +83,36 @@ func TestSanitizeEditorContent(t *testing.T) {
 			input:    "# Heading\nContent\n# Template comment",
 			expected: "# Heading\nContent",
 		},
+		{
+			name:     "bash comments in a fenced block preserved",
+			input:    "Try this:\n```bash\n# install deps\nmake deps\n# run tests\n```\n# Write your comment above. Lines starting with # are ignored.\n",
+			expected: "Try this:\n```bash\n# install deps\nmake deps\n# run tests\n```",
+		},
+		{
+			name:     "comments ending an unclosed fence preserved",
+			input:    "Try this:\n```sh\nmake deps\n# then run tests",
+			expected: "Try this:\n```sh\nmake deps\n# then run tests",
+		},
+		{
+			name:     "tilde fence with python comments preserved",
+			input:    "~~~python\nx = 1\n# done\n~~~\n\n# template",
+			expected: "~~~python\nx = 1\n# done\n~~~",
+		},
+		{
+			name:     "sentinel keeps trailing headings",
+			input:    "Intro\n# Next steps\n\n" + FormatEditorTemplate("Write your comment above."),
+			expected: "Intro\n# Next steps",
+		},
+		{
+			name:     "sentinel drops non-# template lines",
+			input:    "Reply\n" + EditorTemplateSentinel + "\nplain instructions\n",
+			expected: "Reply",
+		},
+		{
+			name:     "sentinel inside a fence is content",
+			input:    "```html\n" + EditorTemplateSentinel + "\n```\n# template",
+			expected: "```html\n" + EditorTemplateSentinel + "\n```",
+		},
 	}
 
 	for _, tt := range tests {
@@ -106,11 +143,11 @@ func TestEditorPreparerType(t *testing.T) {
 
 func TestEditorCompleterType(t *testing.T) {
 	// This test verifies the EditorCompleter type works as expected
-	completer := EditorCompleter[string](func(item string, content string) (string, error) {
-		return "completed: " + item + " with " + content, nil
+	completer := EditorCompleter[string](func(item string, content string) (string, func(), error) {
+		return "completed: " + item + " with " + content, nil, nil
 	})
 
-	result, err := completer("item", "content")
+	result, _, err := completer("item", "content")
 	if err != nil {
 		t.Errorf("EditorCompleter returned unexpected error: %v", err)
 	}
@@ -387,6 +424,24 @@ func TestReactionEmojis(t *testing.T) {
 	}
 }
 
+// TestReactionEmojiLabel verifies glyphs fall back to API names without emoji support
+func TestReactionEmojiLabel(t *testing.T) {
+	originalEnabled := colorEnabled
+	defer func() { colorEnabled = originalEnabled }()
+
+	heart := reactionEmoji{name: "heart", display: "❤️"}
+
+	colorEnabled = true
+	if got := heart.label(); got != "❤️" {
+		t.Errorf("label() with emoji = %q, want %q", got, "❤️")
+	}
+
+	colorEnabled = false
+	if got := heart.label(); got != "heart" {
+		t.Errorf("label() without emoji = %q, want %q", got, "heart")
+	}
+}
+
 // TestReactionActionType verifies the ReactionAction callback type works correctly
 func TestReactionActionType(t *testing.T) {
 	// Test a successful reaction action
@@ -533,7 +588,7 @@ func TestReactionStatusMessageFormat(t *testing.T) {
 
 	for _, tt := range tests {
 		emoji := reactionEmojis[tt.idx]
-		msg := fmt.Sprintf("React: [%d/%d] %s (x=next, Enter=add, Esc=cancel)",
+		msg := fmt.Sprintf("React: [%d/%d] %s (x=next, 1-8=pick, Enter=add, Esc=cancel)",
 			tt.idx+1, len(reactionEmojis), emoji.display)
 
 		if !strings.Contains(msg, tt.wantContain) {
@@ -638,7 +693,7 @@ func TestReactionIndexCyclingWrapAround(t *testing.T) {
 // TestReactionStatusMessageAllEmojis verifies status messages for all emojis
 func TestReactionStatusMessageAllEmojis(t *testing.T) {
 	for idx, emoji := range reactionEmojis {
-		msg := fmt.Sprintf("React: [%d/%d] %s (x=next, Enter=add, Esc=cancel)",
+		msg := fmt.Sprintf("React: [%d/%d] %s (x=next, 1-8=pick, Enter=add, Esc=cancel)",
 			idx+1, len(reactionEmojis), emoji.display)
 
 		// Each message should contain the emoji display
@@ -1084,7 +1139,9 @@ type mockRenderer struct {
 func (r mockRenderer) Title(item string) string                           { return item }
 func (r mockRenderer) Description(item string) string                     { return "desc" }
 func (r mockRenderer) Preview(item string) string                         { return r.previewContent }
-func (r mockRenderer) PreviewWithHighlight(item string, idx int) string   { return r.previewContent + "-" + item }
+func (r mockRenderer) PreviewWithHighlight(item string, idx int, query string) string {
+	return r.previewContent + "-" + item
+}
 func (r mockRenderer) EditPath(item string) string                        { return "" }
 func (r mockRenderer) EditLine(item string) int                           { return 0 }
 func (r mockRenderer) FilterValue(item string) string                     { return item }
@@ -1112,6 +1169,61 @@ func newTestModel(items []string, opts SelectorOptions[string]) SelectionModel[s
 		opts:         opts,
 		result:       nil,
 		filterActive: opts.FilterDefault,
+		filterMode:   initialFilterMode(opts),
+	}
+}
+
+// runCmd executes cmd, expanding batches, and returns the non-batch messages produced
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, runCmd(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func TestPreload(t *testing.T) {
+	login := ""
+	opts := SelectorOptions[string]{
+		Items:    []string{"mine", "theirs"},
+		Renderer: mockRenderer{previewContent: "preview"},
+		FilterFunc: func(item string, active bool) bool {
+			return !active || item == login
+		},
+		FilterDefault: true,
+		Preload: func() (func(), error) {
+			return func() { login = "mine" }, nil
+		},
+	}
+	m := newSelectionModel(opts)
+	if len(m.list.Items()) != 0 {
+		t.Fatalf("Expected nothing to pass the filter before the preload, got %d items", len(m.list.Items()))
+	}
+
+	msgs := runCmd(m.Init())
+	if len(msgs) != 1 {
+		t.Fatalf("Expected Init to run the preload, got %v", msgs)
+	}
+	if login != "" {
+		t.Error("Expected the preload result to wait for Update")
+	}
+	updated, _ := m.Update(msgs[0])
+	if got := len(updated.(SelectionModel[string]).list.Items()); got != 1 {
+		t.Errorf("Expected the list refiltered after the preload, got %d items", got)
+	}
+
+	// A failed preload is reported in the status bar
+	opts.Preload = func() (func(), error) { return nil, errors.New("lookup failed") }
+	m = newSelectionModel(opts)
+	if _, cmd := m.Update(runCmd(m.Init())[0]); cmd == nil {
+		t.Error("Expected a status message for a failed preload")
 	}
 }
 
@@ -1144,7 +1256,7 @@ func TestRefreshKeyTriggersRefresh(t *testing.T) {
 
 		// Execute the command to trigger the callback
 		if cmd != nil {
-			cmd()
+			runCmd(cmd)
 			if !refreshCalled {
 				t.Error("Expected RefreshItems callback to be called")
 			}
@@ -1203,7 +1315,7 @@ func TestRefreshKeyTriggersRefresh(t *testing.T) {
 
 		// Execute if a command was returned (it shouldn't be)
 		if cmd != nil {
-			cmd()
+			runCmd(cmd)
 		}
 		if callCount > 0 {
 			t.Errorf("Expected RefreshItems not to be called while already refreshing, got %d calls", callCount)
@@ -1211,6 +1323,133 @@ func TestRefreshKeyTriggersRefresh(t *testing.T) {
 	})
 }
 
+func TestInitialItem(t *testing.T) {
+	items := []string{"item1", "item2", "resolved3", "item4"}
+	hideResolved := func(item string, active bool) bool {
+		return !active || !strings.HasPrefix(item, "resolved")
+	}
+
+	t.Run("selects_the_matching_item", func(t *testing.T) {
+		m := newSelectionModel(SelectorOptions[string]{
+			Items:       items,
+			Renderer:    mockRenderer{},
+			InitialItem: func(item string) bool { return item == "item4" },
+		})
+		if m.list.Index() != 3 {
+			t.Errorf("expected index 3, got %d", m.list.Index())
+		}
+	})
+
+	t.Run("drops_the_filter_hiding_it", func(t *testing.T) {
+		m := newSelectionModel(SelectorOptions[string]{
+			Items:         items,
+			Renderer:      mockRenderer{},
+			FilterFunc:    hideResolved,
+			FilterDefault: true,
+			InitialItem:   func(item string) bool { return item == "resolved3" },
+		})
+		if m.filterActive {
+			t.Error("expected the filter to be turned off")
+		}
+		if got := m.list.SelectedItem().(listItem[string]).value; got != "resolved3" {
+			t.Errorf("expected resolved3 selected, got %q", got)
+		}
+	})
+
+	t.Run("keeps_the_filter_when_visible", func(t *testing.T) {
+		m := newSelectionModel(SelectorOptions[string]{
+			Items:         items,
+			Renderer:      mockRenderer{},
+			FilterFunc:    hideResolved,
+			FilterDefault: true,
+			InitialItem:   func(item string) bool { return item == "item4" },
+		})
+		if !m.filterActive {
+			t.Error("expected the filter to stay on")
+		}
+		if got := m.list.SelectedItem().(listItem[string]).value; got != "item4" {
+			t.Errorf("expected item4 selected, got %q", got)
+		}
+	})
+
+	t.Run("applies_after_the_initial_load", func(t *testing.T) {
+		m := newSelectionModel(SelectorOptions[string]{
+			Renderer:     mockRenderer{},
+			RefreshItems: func() ([]string, error) { return items, nil },
+			InitialItem:  func(item string) bool { return item == "item2" },
+		})
+		updated, _ := m.Update(refreshFinishedMsg{items: items})
+		if got := updated.(SelectionModel[string]).list.Index(); got != 1 {
+			t.Errorf("expected index 1 after loading, got %d", got)
+		}
+	})
+}
+
+func TestLoadAllKey(t *testing.T) {
+	items := []string{"item1", "item2"}
+	lifted := false
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		LimitStatus: func() string {
+			if lifted {
+				return ""
+			}
+			return "showing 2 of 3"
+		},
+		LoadAll: func() []string {
+			lifted = true
+			return []string{"item1", "item2", "item3"}
+		},
+		LoadAllKey: "L load all",
+	})
+
+	if view := m.View(); !strings.Contains(view, "showing 2 of 3 (L:load all)") {
+		t.Errorf("expected the cap in the footer, got:\n%s", view)
+	}
+
+	// Lifting the cap needs no fetch, so L applies it at once
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	loaded := *updated.(*SelectionModel[string])
+	if len(loaded.items) != 3 {
+		t.Errorf("expected all 3 items after L, got %d", len(loaded.items))
+	}
+	view := loaded.View()
+	if strings.Contains(view, "showing 2 of 3") {
+		t.Error("expected the cap to leave the footer once lifted")
+	}
+	if !strings.Contains(view, "Loaded all 3 items") {
+		t.Errorf("expected a status message, got:\n%s", view)
+	}
+
+	// Nothing left out: L does nothing
+	if _, cmd := loaded.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}}); cmd != nil {
+		t.Error("expected no command once everything is loaded")
+	}
+}
+
+func TestLimitItemsCapsRefresh(t *testing.T) {
+	items := []string{"item1"}
+	var limited [][]string
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		LimitItems: func(all []string) []string {
+			limited = append(limited, all)
+			return all[:1]
+		},
+	})
+
+	updated, _ := m.Update(refreshFinishedMsg{items: []string{"item1", "item2"}})
+	if got := updated.(SelectionModel[string]).items; len(got) != 1 || len(limited) != 1 {
+		t.Errorf("expected the refresh capped by LimitItems, got %v", got)
+	}
+	updated, _ = m.Update(refreshFinishedMsg{items: []string{"item1", "item2"}, watch: true})
+	if got := updated.(SelectionModel[string]).items; len(got) != 1 || len(limited) != 2 {
+		t.Errorf("expected a watch refresh capped too, got %v", got)
+	}
+}
+
 func TestRefreshKeyInDetailView(t *testing.T) {
 	t.Run("pressing_i_in_detail_view_triggers_refresh", func(t *testing.T) {
 		refreshCalled := false
@@ -1241,7 +1480,7 @@ func TestRefreshKeyInDetailView(t *testing.T) {
 		}
 
 		if cmd != nil {
-			cmd()
+			runCmd(cmd)
 			if !refreshCalled {
 				t.Error("Expected RefreshItems callback to be called from detail view")
 			}
@@ -1386,7 +1625,7 @@ func TestRefreshingStateBlocksMultipleRefreshes(t *testing.T) {
 
 	// Execute the first command
 	if cmd1 != nil {
-		cmd1()
+		runCmd(cmd1)
 	}
 
 	// Only one call should have been made
@@ -1394,3 +1633,2004 @@ func TestRefreshingStateBlocksMultipleRefreshes(t *testing.T) {
 		t.Errorf("Expected exactly 1 RefreshItems call, got %d", callCount)
 	}
 }
+
+func TestLoadDetailMsgCallsOnDetailOpen(t *testing.T) {
+	items := []string{"item1", "item2"}
+	var opened []string
+
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		OnDetailOpen: func(item string) {
+			opened = append(opened, item)
+		},
+	})
+	m.showDetail = true
+	m.loadingDetail = true
+
+	updated, _ := m.Update(loadDetailMsg{})
+	result := updated.(SelectionModel[string])
+
+	if result.loadingDetail {
+		t.Error("Expected loadingDetail to be cleared")
+	}
+	if len(opened) != 1 || opened[0] != "item1" {
+		t.Errorf("Expected OnDetailOpen to be called once with item1, got %v", opened)
+	}
+}
+
+func TestRefreshShowsSpinnerUntilFinished(t *testing.T) {
+	items := []string{"item1"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		RefreshItems: func() ([]string, error) {
+			return []string{"item1", "item2"}, nil
+		},
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 80, Height: 24}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	busy := updated.(*SelectionModel[string])
+	if busy.busyLabel == "" {
+		t.Fatal("Expected busy state while refreshing")
+	}
+	if !strings.Contains(busy.View(), "Refreshing...") {
+		t.Error("Expected footer to show refresh progress")
+	}
+
+	var finished tea.Msg
+	for _, msg := range runCmd(cmd) {
+		if _, ok := msg.(refreshFinishedMsg); ok {
+			finished = msg
+		}
+	}
+	if finished == nil {
+		t.Fatal("Expected refresh command to produce refreshFinishedMsg")
+	}
+
+	done, _ := busy.Update(finished)
+	result := done.(SelectionModel[string])
+	if result.busyLabel != "" {
+		t.Error("Expected busy state to clear after refresh finished")
+	}
+
+	// Ticks after the operation finished must not keep the spinner running
+	if _, tickCmd := result.Update(result.spinner.Tick()); tickCmd != nil {
+		t.Error("Expected spinner ticks to stop once idle")
+	}
+}
+
+func TestInitLoadsItemsWhenEmpty(t *testing.T) {
+	m := newTestModel(nil, SelectorOptions[string]{
+		Renderer: mockRenderer{previewContent: "preview"},
+		RefreshItems: func() ([]string, error) {
+			return []string{"loaded"}, nil
+		},
+	})
+	if m.Init() != nil {
+		t.Fatal("Expected no init command unless an initial load is pending")
+	}
+
+	m.refreshing = true
+	m.startBusy("Loading")
+	var loaded bool
+	for _, msg := range runCmd(m.Init()) {
+		if finished, ok := msg.(refreshFinishedMsg); ok {
+			items, _ := finished.items.([]string)
+			loaded = len(items) == 1 && items[0] == "loaded"
+		}
+	}
+	if !loaded {
+		t.Error("Expected Init to load items via RefreshItems")
+	}
+}
+
+func TestEditorCompleteRunsInBackground(t *testing.T) {
+	items := []string{"item1"}
+	var completed string
+	applied := false
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		QuoteComplete: func(item string, content string) (string, func(), error) {
+			completed = content
+			return "Replied", func() { applied = true }, nil
+		},
+	})
+
+	tmpFile, err := os.CreateTemp(t.TempDir(), "reply-*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = tmpFile.WriteString("Thanks!\n# instructions")
+	_ = tmpFile.Close()
+	m.pendingEditorItem = "item1"
+	m.pendingEditorTmpFile = tmpFile.Name()
+	m.pendingEditorAction = editorActionQuote
+
+	updated, cmd := m.Update(editorFinishedMsg{})
+	busy := updated.(SelectionModel[string])
+	if busy.busyLabel == "" {
+		t.Error("Expected busy state while the reply is sent")
+	}
+	if completed != "" {
+		t.Error("Expected completer to run in the returned command, not inline")
+	}
+
+	var complete tea.Msg
+	for _, msg := range runCmd(cmd) {
+		if _, ok := msg.(editorCompleteMsg); ok {
+			complete = msg
+		}
+	}
+	if completed != "Thanks!" || complete == nil {
+		t.Fatalf("Expected completer to be called with sanitized content, got %q", completed)
+	}
+	if applied {
+		t.Error("Expected the completer's changes to wait for Update")
+	}
+
+	done, _ := busy.Update(complete)
+	if done.(SelectionModel[string]).busyLabel != "" {
+		t.Error("Expected busy state to clear after completion")
+	}
+	if !applied {
+		t.Error("Expected the completer's changes applied in Update")
+	}
+}
+
+func TestStatusWarningShownInFooter(t *testing.T) {
+	items := []string{"item1"}
+	warning := ""
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:         items,
+		Renderer:      mockRenderer{previewContent: "preview"},
+		StatusWarning: func() string { return warning },
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 200, Height: 24}
+
+	if strings.Contains(m.View(), "API requests left") {
+		t.Error("Expected no warning when StatusWarning returns empty")
+	}
+
+	warning = "42 API requests left"
+	if !strings.Contains(m.View(), "42 API requests left") {
+		t.Error("Expected warning in list footer")
+	}
+
+	m.showDetail = true
+	if !strings.Contains(m.View(), "42 API requests left") {
+		t.Error("Expected warning in detail footer")
+	}
+}
+
+func TestWatchRefreshKeepsSelection(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:         items,
+		Renderer:      mockRenderer{previewContent: "preview"},
+		RefreshItems:  func() ([]string, error) { return items, nil },
+		WatchInterval: time.Minute,
+		ItemKey:       func(s string) string { return s },
+		DescribeChanges: func(old, updated []string) string {
+			if len(updated) > len(old) {
+				return fmt.Sprintf("%d new", len(updated)-len(old))
+			}
+			return ""
+		},
+	})
+	m.list.Select(1) // "b"
+
+	updated, cmd := m.Update(watchTickMsg{})
+	polling := updated.(SelectionModel[string])
+	if !polling.refreshing {
+		t.Fatal("Expected watch tick to start a background refresh")
+	}
+	if polling.busyLabel != "" {
+		t.Error("Expected watch refresh not to show the busy spinner")
+	}
+	msgs := runCmd(cmd)
+	if len(msgs) != 1 || !msgs[0].(refreshFinishedMsg).watch {
+		t.Fatalf("Expected a watch refreshFinishedMsg, got %v", msgs)
+	}
+
+	done, next := polling.Update(refreshFinishedMsg{items: []string{"new", "a", "b", "c"}, watch: true})
+	result := done.(SelectionModel[string])
+	if result.refreshing {
+		t.Error("Expected refreshing to clear")
+	}
+	if got := result.list.SelectedItem().(listItem[string]).value; got != "b" {
+		t.Errorf("Expected selection to stay on %q, got %q", "b", got)
+	}
+	if next == nil {
+		t.Error("Expected the next watch poll to be scheduled")
+	}
+}
+
+func TestManualRefreshKeepsPosition(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		ItemKey:  func(s string) string { return s },
+	})
+	m.list.Select(2) // "c"
+
+	updated, _ := m.Update(refreshFinishedMsg{items: []string{"new", "a", "b", "c", "d"}})
+	result := updated.(SelectionModel[string])
+	if got := result.list.SelectedItem().(listItem[string]).value; got != "c" {
+		t.Errorf("Expected selection to stay on %q, got %q", "c", got)
+	}
+
+	updated, _ = result.Update(refreshFinishedMsg{items: []string{"new", "a", "b", "d"}})
+	result = updated.(SelectionModel[string])
+	if got := result.list.SelectedItem().(listItem[string]).value; got != "d" {
+		t.Errorf("Expected selection to move to the next item %q, got %q", "d", got)
+	}
+
+	updated, _ = result.Update(refreshFinishedMsg{items: []string{"new", "a"}})
+	result = updated.(SelectionModel[string])
+	if got := result.list.SelectedItem().(listItem[string]).value; got != "a" {
+		t.Errorf("Expected selection to fall back to the nearest earlier item %q, got %q", "a", got)
+	}
+}
+
+func TestWatchTickSkippedWhileRefreshing(t *testing.T) {
+	items := []string{"a"}
+	refreshed := false
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:         items,
+		Renderer:      mockRenderer{previewContent: "preview"},
+		RefreshItems:  func() ([]string, error) { refreshed = true; return items, nil },
+		WatchInterval: time.Minute,
+	})
+	m.refreshing = true
+
+	updated, cmd := m.Update(watchTickMsg{})
+	if cmd == nil {
+		t.Fatal("Expected the watch poll to be rescheduled")
+	}
+	if !updated.(SelectionModel[string]).refreshing || refreshed {
+		t.Error("Expected no second refresh while one is in flight")
+	}
+}
+
+func TestFindMatchLines(t *testing.T) {
+	content := "first line\n" + ColorRed + "TODO" + ColorReset + " and todo\nnothing here"
+	matches := findMatchLines(content, "todo")
+	if len(matches) != 2 || matches[0] != 1 || matches[1] != 1 {
+		t.Errorf("Expected two matches on line 1, got %v", matches)
+	}
+	if got := findMatchLines(content, "31m"); len(got) != 0 {
+		t.Errorf("Expected escape sequences to be ignored, got %v", got)
+	}
+}
+
+func TestDetailFindNavigation(t *testing.T) {
+	items := []string{"item1"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 80, Height: 10}
+	m.viewport = viewport.New(80, 5)
+	m.showDetail = true
+
+	lines := make([]string, 40)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	lines[10] = "match here"
+	lines[30] = "another MATCH"
+	m.setDetailContent(strings.Join(lines, "\n"))
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(SelectionModel[string])
+	if !m.findInputActive {
+		t.Fatal("Expected / to open the find input in detail view")
+	}
+	for _, r := range "match" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(SelectionModel[string])
+	}
+	if !m.showDetail {
+		t.Fatal("Expected typing in the find input not to leave the detail view")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(SelectionModel[string])
+
+	if len(m.findMatches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(m.findMatches))
+	}
+	if m.viewport.YOffset != 8 {
+		t.Errorf("Expected viewport to scroll to first match, got offset %d", m.viewport.YOffset)
+	}
+	if !strings.Contains(m.View(), "match 1/2") {
+		t.Error("Expected header to show match position")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(SelectionModel[string])
+	if m.findIdx != 1 || m.viewport.YOffset != 28 {
+		t.Errorf("Expected n to move to second match, got idx %d offset %d", m.findIdx, m.viewport.YOffset)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(SelectionModel[string])
+	if m.findIdx != 0 {
+		t.Errorf("Expected n to wrap to first match, got idx %d", m.findIdx)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(SelectionModel[string])
+	if m.findQuery != "" || !m.showDetail {
+		t.Error("Expected esc to clear the search and stay in detail view")
+	}
+}
+
+// widthRenderer records the preview width passed by the selector
+type widthRenderer struct {
+	mockRenderer
+	width *int
+}
+
+func (r widthRenderer) SetPreviewWidth(width int) { *r.width = width }
+
+func TestWindowResizeUpdatesPreviewWidth(t *testing.T) {
+	items := []string{"item1"}
+	width := 0
+	renderer := widthRenderer{mockRenderer: mockRenderer{previewContent: "preview"}, width: &width}
+
+	m := newTestModel(items, SelectorOptions[string]{Items: items, Renderer: renderer})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 24})
+	if width != 0 {
+		t.Errorf("Expected renderer defaults when PreviewWidth is unset, got %d", width)
+	}
+
+	m = newTestModel(items, SelectorOptions[string]{Items: items, Renderer: renderer, PreviewWidth: PreviewWidthFit})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 24})
+	if width != 116 {
+		t.Errorf("Expected preview width to follow the list, got %d", width)
+	}
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 24})
+	if width != 196 {
+		t.Errorf("Expected preview width to reflow on resize, got %d", width)
+	}
+
+	m = newTestModel(items, SelectorOptions[string]{Items: items, Renderer: renderer, PreviewWidth: 60})
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 24})
+	if width != 60 {
+		t.Errorf("Expected fixed preview width, got %d", width)
+	}
+}
+
+func TestOpenFileRequiresConfirmation(t *testing.T) {
+	items := []string{"item1"}
+	opened := ""
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		OpenFilePreview: func(item string) (string, error) {
+			return "https://github.com/owner/repo/blob/abc/" + item + "#L1", nil
+		},
+		OpenFileAction: func(item string) (string, error) {
+			opened = item
+			return "Opened", nil
+		},
+		OpenFileKey: "O open file",
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 120, Height: 24}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+	confirming := updated.(*SelectionModel[string])
+	if !strings.Contains(confirming.View(), "blob/abc/item1#L1") {
+		t.Error("Expected the URL to be shown for confirmation")
+	}
+	if opened != "" {
+		t.Fatal("Expected nothing to open before confirmation")
+	}
+
+	cancelled, _ := confirming.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if opened != "" || cancelled.(SelectionModel[string]).openFileConfirmMode {
+		t.Error("Expected any key other than y to cancel")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+	updated.(*SelectionModel[string]).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if opened != "item1" {
+		t.Errorf("Expected y to open the file, got %q", opened)
+	}
+}
+
+func TestCopyCommandCopiesToClipboard(t *testing.T) {
+	var copied string
+	original := clipboardWriteAll
+	clipboardWriteAll = func(text string) error {
+		copied = text
+		return nil
+	}
+	defer func() { clipboardWriteAll = original }()
+
+	items := []string{"item1"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		CopyCommand: func(item string) (string, error) {
+			return "gh api repos/owner/repo/pulls/comments/" + item, nil
+		},
+		CopyCommandKey: "y copy gh cmd",
+	})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if copied != "gh api repos/owner/repo/pulls/comments/item1" {
+		t.Errorf("Expected command to be copied, got %q", copied)
+	}
+}
+
+func TestTagFilterToggleReappliesFilter(t *testing.T) {
+	items := []string{"todo-1", "other", "todo-2"}
+	todoOnly := false
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		FilterFunc: func(item string, hideResolved bool) bool {
+			return !todoOnly || strings.HasPrefix(item, "todo")
+		},
+		TagFilterToggle: func() string {
+			todoOnly = !todoOnly
+			return "toggled"
+		},
+		TagFilterKey: "T todo only",
+	})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	if got := len(updated.(SelectionModel[string]).list.Items()); got != 2 {
+		t.Errorf("Expected 2 visible items with the tag filter on, got %d", got)
+	}
+}
+
+func TestOutdatedFilterToggle(t *testing.T) {
+	items := []string{"current", "outdated-1", "outdated-2"}
+	hideOutdated := false
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		FilterFunc: func(item string, hideResolved bool) bool {
+			return !hideOutdated || !strings.HasPrefix(item, "outdated")
+		},
+		OutdatedFilterToggle: func() string {
+			hideOutdated = !hideOutdated
+			return "Hiding outdated"
+		},
+		OutdatedFilterKey: "H hide outdated",
+	})
+
+	if got := len(m.list.Items()); got != 3 {
+		t.Fatalf("Expected outdated items to show by default, got %d items", got)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'H'}})
+	if got := len(updated.(SelectionModel[string]).list.Items()); got != 1 {
+		t.Errorf("Expected 1 visible item with outdated hidden, got %d", got)
+	}
+}
+
+func TestListPageScrolling(t *testing.T) {
+	items := make([]string, 50)
+	for i := range items {
+		items[i] = fmt.Sprintf("item-%d", i)
+	}
+	var model tea.Model = newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+	})
+	perPage := model.(SelectionModel[string]).list.Paginator.PerPage
+	if perPage < 2 {
+		t.Fatalf("Expected several items per page, got %d", perPage)
+	}
+
+	steps := []struct {
+		key  tea.KeyType
+		want int
+	}{
+		{tea.KeyCtrlD, perPage / 2},
+		{tea.KeyCtrlF, perPage/2 + perPage},
+		{tea.KeyCtrlU, perPage},
+		{tea.KeyCtrlB, 0},
+		{tea.KeyCtrlB, 0}, // stops at the first item
+	}
+	for _, step := range steps {
+		model, _ = model.Update(tea.KeyMsg{Type: step.key})
+		if got := model.(SelectionModel[string]).list.Index(); got != step.want {
+			t.Errorf("After %s: expected index %d, got %d", tea.KeyMsg{Type: step.key}, step.want, got)
+		}
+	}
+
+	for range 10 {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	}
+	if got := model.(SelectionModel[string]).list.Index(); got != len(items)-1 {
+		t.Errorf("Expected paging down to stop at the last item, got %d", got)
+	}
+}
+
+func TestAcceptSuggestionKey(t *testing.T) {
+	items := []string{"suggestion"}
+	var accepted string
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		AcceptSuggestionAction: func(item string) (string, func(), error) {
+			accepted = item
+			return "✓ Applied\n✓ Replied\n✓ Resolved thread", nil, nil
+		},
+		AcceptSuggestionKey: "A accept",
+	})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	var finished tea.Msg
+	for _, msg := range runCmd(cmd) {
+		if _, ok := msg.(acceptFinishedMsg); ok {
+			finished = msg
+		}
+	}
+	if accepted != "suggestion" || finished == nil {
+		t.Fatalf("Expected A to run the accept action in the background (accepted %q)", accepted)
+	}
+
+	updated, _ := m.Update(finished)
+	if got := updated.(SelectionModel[string]).confirmationMessage; !strings.Contains(got, "Resolved thread") {
+		t.Errorf("Expected the step report in a dialog, got %q", got)
+	}
+}
+
+func TestSwitchKey(t *testing.T) {
+	items := []string{"open", "resolved"}
+	carried := -1
+	opts := SelectorOptions[string]{
+		Items:          items,
+		Renderer:       mockRenderer{previewContent: "preview"},
+		FilterModes:    []string{"Showing all", "Hiding resolved", "Only resolved"},
+		FilterModeFunc: func(item string, mode int) bool { return mode != 2 || item == "resolved" },
+		SwitchKey:      "ctrl+p switch PR",
+		OnSwitchRequested: func(mode int) {
+			carried = mode
+		},
+		InitialFilterMode: 2,
+	}
+	m := newSelectionModel(opts)
+	if m.filterMode != 2 || len(m.list.Items()) != 1 {
+		t.Fatalf("Expected to start on InitialFilterMode, got mode %d with %d items", m.filterMode, len(m.list.Items()))
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if cmd == nil {
+		t.Fatal("Expected ctrl+p to quit the selector")
+	}
+	if _, err := updated.(SelectionModel[string]).Result(); !errors.Is(err, ErrSwitchRequested) {
+		t.Errorf("Expected ErrSwitchRequested, got %v", err)
+	}
+	if carried != 2 {
+		t.Errorf("Expected the filter mode handed over, got %d", carried)
+	}
+
+	// Without SwitchKey, ctrl+p does nothing
+	opts.SwitchKey = ""
+	m = newSelectionModel(opts)
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlP}); cmd != nil {
+		t.Error("Expected ctrl+p to be ignored without SwitchKey")
+	}
+}
+
+func TestLeaveWithDraftsAsksFirst(t *testing.T) {
+	items := []string{"comment"}
+	switched := false
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:             items,
+		Renderer:          mockRenderer{previewContent: "preview"},
+		DraftToggle:       func() string { return "Draft mode" },
+		DraftCount:        func() int { return 2 },
+		SwitchKey:         "ctrl+p switch PR",
+		OnSwitchRequested: func(int) { switched = true },
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 200, Height: 24}
+	m.list.SetSize(200, 20)
+
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune{'q'}}, {Type: tea.KeyCtrlP}} {
+		updated, cmd := m.Update(key)
+		asking := updated.(SelectionModel[string])
+		if cmd != nil {
+			t.Fatalf("Expected %s to wait for confirmation with drafts pending", key)
+		}
+		if view := asking.View(); !strings.Contains(view, "Leave with 2 drafts unsubmitted?") {
+			t.Errorf("Expected the drafts named in the prompt, got:\n%s", view)
+		}
+
+		// Any other key stays
+		updated, cmd = asking.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		if cmd != nil || updated.(SelectionModel[string]).confirmLeave != "" {
+			t.Errorf("Expected n to stay after %s", key)
+		}
+
+		// y leaves
+		if _, cmd = asking.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}); cmd == nil {
+			t.Errorf("Expected y to leave after %s", key)
+		}
+	}
+	if !switched {
+		t.Error("Expected y after ctrl+p to switch")
+	}
+}
+
+func TestIsToggle(t *testing.T) {
+	collapsed := false
+	opts := SelectorOptions[string]{
+		Items:    []string{"header", "child"},
+		Renderer: mockRenderer{previewContent: "preview"},
+		FilterFunc: func(item string, active bool) bool {
+			return item != "child" || !collapsed
+		},
+		OnSelect: func(item string) (string, error) {
+			if item == "header" {
+				collapsed = !collapsed
+			}
+			return "", nil
+		},
+		IsToggle: func(item string) bool { return item == "header" },
+	}
+	m := newSelectionModel(opts)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(SelectionModel[string])
+	if m.showDetail {
+		t.Error("Expected enter on a toggle row not to open the detail view")
+	}
+	if !collapsed || len(m.list.Items()) != 1 {
+		t.Fatalf("Expected the child hidden in place, got collapsed=%v with %d items", collapsed, len(m.list.Items()))
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(SelectionModel[string])
+	if collapsed || len(m.list.Items()) != 2 {
+		t.Fatalf("Expected the child shown again, got collapsed=%v with %d items", collapsed, len(m.list.Items()))
+	}
+
+	// Other rows still open the detail view
+	m.list.Select(1)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !updated.(SelectionModel[string]).showDetail {
+		t.Error("Expected enter on a regular row to open the detail view")
+	}
+}
+
+func TestPathGlobInput(t *testing.T) {
+	items := []string{"pkg/ui/colors.go", "cmd/browse.go"}
+	glob := ""
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		FilterFunc: func(item string, _ bool) bool {
+			return glob == "" || strings.HasPrefix(item, strings.TrimSuffix(glob, "**"))
+		},
+		SetPathGlob: func(g string) error {
+			if strings.Contains(g, "[") {
+				return errors.New("bad path glob")
+			}
+			glob = g
+			return nil
+		},
+		PathGlob:    func() string { return glob },
+		PathGlobKey: "I path glob",
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 400, Height: 24}
+	m.list.SetSize(400, 20)
+
+	typeGlob := func(m SelectionModel[string], text string) SelectionModel[string] {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+		m = updated.(SelectionModel[string])
+		if !m.globInputActive {
+			t.Fatal("Expected I to open the glob input")
+		}
+		m.globInput.SetValue(text)
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		return updated.(SelectionModel[string])
+	}
+
+	m = typeGlob(m, "pkg/ui/**")
+	if len(m.list.Items()) != 1 || glob != "pkg/ui/**" {
+		t.Fatalf("Expected only the matching path listed, got %d items", len(m.list.Items()))
+	}
+	if view := m.View(); !strings.Contains(view, "I:path pkg/ui/**") {
+		t.Errorf("Expected the active glob in the footer, got:\n%s", view)
+	}
+
+	// A bad pattern is reported and the glob kept
+	m = typeGlob(m, "pkg/[ui")
+	if glob != "pkg/ui/**" {
+		t.Errorf("Expected a bad pattern to leave the glob alone, got %q", glob)
+	}
+
+	// An empty glob shows everything again
+	m = typeGlob(m, "")
+	if len(m.list.Items()) != 2 {
+		t.Errorf("Expected an empty glob to clear the path filter, got %d items", len(m.list.Items()))
+	}
+}
+
+func TestDraftModeKeys(t *testing.T) {
+	items := []string{"comment"}
+	drafting, drafts, submitted := false, 0, 0
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		DraftToggle: func() string {
+			drafting = !drafting
+			return "Draft mode"
+		},
+		DraftCount: func() int { return drafts },
+		DraftSubmit: func() (func() (string, func(), error), error) {
+			return func() (string, func(), error) {
+				submitted++
+				return "Submitted a review with 2 replies.", func() { drafts = 0 }, nil
+			}, nil
+		},
+		DraftKey:       "P draft mode",
+		DraftSubmitKey: "Z submit review",
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 400, Height: 24}
+	m.list.SetSize(400, 20)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	m = updated.(SelectionModel[string])
+	if !drafting {
+		t.Fatal("Expected P to toggle draft mode")
+	}
+
+	// Nothing to submit yet
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Z'}})
+	runCmd(cmd)
+	if submitted != 0 {
+		t.Error("Expected Z to do nothing without drafts")
+	}
+
+	drafts = 2
+	if view := m.View(); !strings.Contains(view, "Z:submit 2 drafts") {
+		t.Errorf("Expected the draft count in the footer, got:\n%s", view)
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Z'}})
+	var finished tea.Msg
+	for _, msg := range runCmd(cmd) {
+		if _, ok := msg.(draftSubmitFinishedMsg); ok {
+			finished = msg
+		}
+	}
+	if submitted != 1 || finished == nil {
+		t.Fatalf("Expected Z to submit the drafts in the background (submitted %d times)", submitted)
+	}
+	updated, _ = m.Update(finished)
+	if view := updated.(SelectionModel[string]).View(); strings.Contains(view, "Z:submit") {
+		t.Errorf("Expected the submit entry gone once the drafts are posted, got:\n%s", view)
+	}
+}
+
+func TestEmptyStateMessage(t *testing.T) {
+	items := []string{"done-1", "done-2"}
+	var gotFilters []string
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		FilterFunc: func(item string, hideResolved bool) bool {
+			return !hideResolved || !strings.HasPrefix(item, "done")
+		},
+		EmptyMessage: func(filters []string) string {
+			gotFilters = filters
+			return "No unresolved comments — press h to show all"
+		},
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 80, Height: 24}
+
+	if view := m.View(); strings.Contains(view, "No unresolved comments") {
+		t.Errorf("Expected the list while items are visible, got:\n%s", view)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	view := updated.(SelectionModel[string]).View()
+	if !strings.Contains(view, "No unresolved comments — press h to show all") {
+		t.Errorf("Expected the empty state once everything is filtered out, got:\n%s", view)
+	}
+	if len(gotFilters) != 1 || gotFilters[0] != "hiding resolved" {
+		t.Errorf("Expected the active filters to be passed, got %v", gotFilters)
+	}
+}
+
+func TestDebugOverlay(t *testing.T) {
+	originalDebug := uiDebug.Load()
+	defer func() { uiDebug.Store(originalDebug) }()
+
+	items := []string{"first", "second"}
+	opts := SelectorOptions[string]{
+		Items:     items,
+		Renderer:  mockRenderer{previewContent: "preview"},
+		DebugInfo: func(item string) string { return "thread ID: (empty) for " + item },
+	}
+	press := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}}
+
+	uiDebug.Store(false)
+	updated, _ := newTestModel(items, opts).Update(press)
+	if updated.(SelectionModel[string]).showDebug {
+		t.Error("Expected D to do nothing without --debug")
+	}
+
+	uiDebug.Store(true)
+	updated, _ = newTestModel(items, opts).Update(press)
+	m := updated.(SelectionModel[string])
+	if !m.showDebug {
+		t.Fatal("Expected D to open the debug overlay with --debug")
+	}
+	if view := m.View(); !strings.Contains(view, "thread ID: (empty) for first") {
+		t.Errorf("Expected the selected item's fields in the overlay, got:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(SelectionModel[string]).showDebug {
+		t.Error("Expected esc to close the debug overlay")
+	}
+}
+
+func TestHeaderReflectsCurrentItems(t *testing.T) {
+	items := []string{"open", "done"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		Header: func(items []string) string {
+			return fmt.Sprintf("owner/repo #1 — %d total", len(items))
+		},
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 120, Height: 24}
+
+	if !strings.Contains(m.View(), "owner/repo #1 — 2 total") {
+		t.Error("Expected header in list view")
+	}
+
+	updated, _ := m.Update(refreshFinishedMsg{items: []string{"a", "b", "c"}})
+	if !strings.Contains(updated.(SelectionModel[string]).View(), "owner/repo #1 — 3 total") {
+		t.Error("Expected header to update after refresh")
+	}
+}
+
+func TestHKeyCyclesFilterModes(t *testing.T) {
+	items := []string{"open", "resolved-by-me", "resolved-by-other"}
+	modes := []string{"Showing all", "Hiding resolved", "Only resolved by me"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:       items,
+		Renderer:    mockRenderer{previewContent: "preview"},
+		FilterModes: modes,
+		FilterModeFunc: func(item string, mode int) bool {
+			switch mode {
+			case 1:
+				return item == "open"
+			case 2:
+				return item == "resolved-by-me"
+			}
+			return true
+		},
+		FilterDefault: true,
+	})
+	m.updateVisibleItems()
+
+	if m.filterMode != 1 || len(m.list.Items()) != 1 {
+		t.Fatalf("Expected FilterDefault to start on mode 1 with 1 item, got mode %d with %d items", m.filterMode, len(m.list.Items()))
+	}
+
+	want := []struct {
+		mode    int
+		visible int
+	}{{2, 1}, {0, 3}, {1, 1}}
+	var model tea.Model = m
+	for _, w := range want {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+		got := model.(SelectionModel[string])
+		if got.filterMode != w.mode {
+			t.Errorf("Expected mode %d (%s), got %d", w.mode, modes[w.mode], got.filterMode)
+		}
+		if n := len(got.list.Items()); n != w.visible {
+			t.Errorf("Mode %d: expected %d visible items, got %d", w.mode, w.visible, n)
+		}
+	}
+}
+
+func TestHelpOverlayScrollsOnShortTerminals(t *testing.T) {
+	items := []string{"item1"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 80, Height: 12}
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	got := model.(SelectionModel[string])
+	if !got.showHelp {
+		t.Fatal("Expected '?' to open the help overlay")
+	}
+	if h := lipgloss.Height(got.View()); h > 12 {
+		t.Errorf("Expected help overlay to fit 12 rows, got %d", h)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	got = model.(SelectionModel[string])
+	if !got.showHelp {
+		t.Fatal("Expected down arrow to scroll, not close, the help overlay")
+	}
+	if got.helpView.YOffset != 1 {
+		t.Errorf("Expected help to scroll by one line, got offset %d", got.helpView.YOffset)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.(SelectionModel[string]).showHelp {
+		t.Error("Expected esc to close the help overlay")
+	}
+}
+
+func TestItemDelegateTruncatesColoredTitlesOnVisibleWidth(t *testing.T) {
+	title := Colorize(ColorCyan, "@reviewer") + " Line 42 " + Colorize(ColorGray, "a long body preview that overflows")
+	renderer := mockRenderer{previewContent: "preview"}
+	l := list.New([]list.Item{listItem[string]{value: title, item: renderer}}, itemDelegate[string]{renderer: renderer}, 24, 10)
+
+	var buf bytes.Buffer
+	itemDelegate[string]{renderer: renderer}.Render(&buf, l, 1, listItem[string]{value: title, item: renderer})
+	out := buf.String()
+
+	if !strings.Contains(out, "@reviewer") {
+		t.Errorf("Expected the visible start of the title to survive, got %q", out)
+	}
+	if !strings.Contains(out, "..."+ColorReset) {
+		t.Errorf("Expected the cut title to end with the indicator and a color reset, got %q", out)
+	}
+	if strings.Contains(terminalEscapeRe.ReplaceAllString(out, ""), "\x1b") {
+		t.Errorf("Expected no partial escape sequences, got %q", out)
+	}
+}
+
+func TestItemDelegateRowHeightPutsDescriptionBelow(t *testing.T) {
+	renderer := mockRenderer{previewContent: "preview"}
+	item := listItem[string]{value: "title", item: renderer}
+	l := list.New([]list.Item{item}, itemDelegate[string]{renderer: renderer}, 40, 10)
+
+	var single bytes.Buffer
+	itemDelegate[string]{renderer: renderer}.Render(&single, l, 1, item)
+	if single.String() != "  title - desc" {
+		t.Errorf("Expected a one-line row by default, got %q", single.String())
+	}
+
+	twoLine := itemDelegate[string]{renderer: renderer, height: 2}
+	if twoLine.Height() != 2 {
+		t.Errorf("Height() = %d, want 2", twoLine.Height())
+	}
+	var buf bytes.Buffer
+	twoLine.Render(&buf, l, 1, item)
+	if buf.String() != "  title\n  desc" {
+		t.Errorf("Expected the description on the second line, got %q", buf.String())
+	}
+
+	// Short descriptions are padded so every row keeps the same height
+	if got := descriptionLines("", 2, 10); len(got) != 2 {
+		t.Errorf("descriptionLines padded to %d lines, want 2", len(got))
+	}
+}
+
+func TestCopyAllCopiesEveryItem(t *testing.T) {
+	var copied string
+	original := clipboardWriteAll
+	clipboardWriteAll = func(text string) error {
+		copied = text
+		return nil
+	}
+	defer func() { clipboardWriteAll = original }()
+
+	items := []string{"open", "hidden"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:      items,
+		Renderer:   mockRenderer{previewContent: "preview"},
+		FilterFunc: func(item string, active bool) bool { return !active || item == "open" },
+		CopyAll: func(items []string) (string, error) {
+			return strings.Join(items, "\n") + "\n", nil
+		},
+		CopyAllKey:    "Y copy checklist",
+		FilterDefault: true,
+	})
+	m.updateVisibleItems()
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Y'}})
+	if copied != "open\nhidden\n" {
+		t.Errorf("Expected all items to be copied regardless of filter, got %q", copied)
+	}
+}
+
+func TestBraceKeysJumpBetweenGroupHeaders(t *testing.T) {
+	items := []string{"file:a.go", "a1", "a2", "file:b.go", "b1", "file:c.go"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:         items,
+		Renderer:      mockRenderer{previewContent: "preview"},
+		IsGroupHeader: func(item string) bool { return strings.HasPrefix(item, "file:") },
+	})
+	m.list.Select(1)
+
+	press := func(model tea.Model, r rune) SelectionModel[string] {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		return updated.(SelectionModel[string])
+	}
+
+	m = press(m, '}')
+	if got := m.list.Index(); got != 3 {
+		t.Errorf("Expected '}' to jump to the next file header at 3, got %d", got)
+	}
+	m = press(m, '}')
+	if got := m.list.Index(); got != 5 {
+		t.Errorf("Expected '}' to jump to the last file header at 5, got %d", got)
+	}
+	m = press(m, '}')
+	if got := m.list.Index(); got != 5 {
+		t.Errorf("Expected '}' to stay on the last header, got %d", got)
+	}
+	m = press(m, '{')
+	m = press(m, '{')
+	if got := m.list.Index(); got != 0 {
+		t.Errorf("Expected '{' twice to reach the first file header, got %d", got)
+	}
+}
+
+func TestSuggestKeySeedsEditorAndPostsSuggestion(t *testing.T) {
+	items := []string{"item1"}
+	var posted string
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		SuggestPrepare: func(item string) (string, error) {
+			return FormatSuggestionTemplate("@@ -1 +1 @@\n+old()", "main.go"), nil
+		},
+		SuggestComplete: func(item string, content string) (string, func(), error) {
+			posted = content
+			return "Replied", nil, nil
+		},
+		SuggestKey: "G suggest",
+	})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	pending := updated.(*SelectionModel[string])
+	if pending.pendingEditorAction != editorActionSuggest || pending.pendingEditorTmpFile == "" {
+		t.Fatalf("Expected G to open the editor for a suggestion, got action %d", pending.pendingEditorAction)
+	}
+	seeded, err := os.ReadFile(pending.pendingEditorTmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(seeded), "```suggestion\nold()\n```") {
+		t.Errorf("Expected the editor to be seeded with a suggestion block, got %q", seeded)
+	}
+
+	// Simulate the user editing the suggested line
+	if err := os.WriteFile(pending.pendingEditorTmpFile, []byte("```suggestion\nnew()\n```\n# ignored"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, cmd := pending.Update(editorFinishedMsg{})
+	runCmd(cmd)
+	if posted != "```suggestion\nnew()\n```" {
+		t.Errorf("Expected the suggestion block to be posted intact, got %q", posted)
+	}
+}
+
+func TestDismissKeyOpensEditorAndCompletes(t *testing.T) {
+	items := []string{"item1"}
+	var posted string
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		DismissPrepare: func(item string) (string, error) {
+			return "Won't fix: \n", nil
+		},
+		DismissComplete: func(item string, content string) (string, func(), error) {
+			posted = content
+			return "Posted and resolved", nil, nil
+		},
+		DismissKey: "W won't fix",
+	})
+	if view := m.View(); !strings.Contains(view, "W:won't fix") {
+		t.Errorf("Expected W in the footer, got:\n%s", view)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}})
+	pending := updated.(*SelectionModel[string])
+	if pending.pendingEditorAction != editorActionDismiss || pending.pendingEditorTmpFile == "" {
+		t.Fatalf("Expected W to open the editor, got action %d", pending.pendingEditorAction)
+	}
+	if err := os.WriteFile(pending.pendingEditorTmpFile, []byte("Won't fix: out of scope\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, cmd := pending.Update(editorFinishedMsg{})
+	runCmd(cmd)
+	if posted != "Won't fix: out of scope" {
+		t.Errorf("Expected the reason to be posted, got %q", posted)
+	}
+}
+
+func TestFilterBarShowsAndClearsActiveFilters(t *testing.T) {
+	items := []string{"open", "resolved", "todo-open"}
+	todoOnly := true
+	cleared := false
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:       items,
+		Renderer:    mockRenderer{previewContent: "preview"},
+		FilterModes: []string{"Showing all", "Hiding resolved"},
+		FilterModeFunc: func(item string, mode int) bool {
+			if todoOnly && !strings.HasPrefix(item, "todo") {
+				return false
+			}
+			return mode == 0 || item != "resolved"
+		},
+		FilterDefault: true,
+		ActiveFilters: func() []string {
+			if todoOnly {
+				return []string{"todo only"}
+			}
+			return nil
+		},
+		ClearFilters: func() {
+			todoOnly = false
+			cleared = true
+		},
+		ClearFiltersKey: "X clear filters",
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 120, Height: 24}
+	m.updateVisibleItems()
+
+	if !strings.Contains(m.View(), "Filters: hiding resolved | todo only  (X to clear)") {
+		t.Errorf("Expected filter bar listing active filters, got:\n%s", m.View())
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'X'}})
+	got := updated.(*SelectionModel[string])
+	if !cleared || got.filterMode != 0 {
+		t.Errorf("Expected X to reset the h state and call ClearFilters (mode %d, cleared %v)", got.filterMode, cleared)
+	}
+	if n := len(got.list.Items()); n != 3 {
+		t.Errorf("Expected all 3 items visible after clearing, got %d", n)
+	}
+	if strings.Contains(got.View(), "Filters:") {
+		t.Error("Expected no filter bar once filters are cleared")
+	}
+}
+
+// threadMockRenderer reports three comments per item and tags the selected one
+type threadMockRenderer struct {
+	mockRenderer
+}
+
+func (r threadMockRenderer) ThreadCommentCount(item string) int { return 3 }
+func (r threadMockRenderer) WithSelectedComment(item string, idx int) string {
+	return fmt.Sprintf("%s#%d", item, idx)
+}
+
+func TestOpenInCommentSelectModeUsesSelectedComment(t *testing.T) {
+	items := []string{"a"}
+	var opened string
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: threadMockRenderer{mockRenderer{previewContent: "preview"}},
+		OnOpen: func(item string) (string, error) {
+			opened = item
+			return "opened", nil
+		},
+	})
+	m.enterCommentSelectMode("Q", m.list.SelectedItem().(listItem[string]))
+	m.cycleCommentSelection()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	result := updated.(*SelectionModel[string])
+	if opened != "a#1" {
+		t.Errorf("Expected the reply to be opened, got %q", opened)
+	}
+	if result.commentSelectMode {
+		t.Error("Expected comment-select mode to end after opening")
+	}
+}
+
+func TestRunSelectionScriptsMessages(t *testing.T) {
+	var opened string
+	opts := SelectorOptions[string]{
+		Items:        []string{"a", "b", "c"},
+		Renderer:     threadMockRenderer{mockRenderer{previewContent: "preview"}},
+		QuotePrepare: func(item string) (string, error) { return "", nil },
+		OnOpen: func(item string) (string, error) {
+			opened = item
+			return "", nil
+		},
+	}
+	key := func(s string) tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	m, err := RunSelection(opts, []tea.Msg{
+		tea.WindowSizeMsg{Width: 80, Height: 24},
+		tea.KeyMsg{Type: tea.KeyDown},
+		key("Q"), // enter comment-select mode on "b"
+		key("Q"), // cycle to the first reply
+	})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	if !m.commentSelectMode || m.commentSelectIdx != 1 {
+		t.Fatalf("Expected comment-select mode on reply 1, got mode=%v idx=%d", m.commentSelectMode, m.commentSelectIdx)
+	}
+
+	m, err = RunSelection(opts, []tea.Msg{
+		tea.WindowSizeMsg{Width: 80, Height: 24},
+		tea.KeyMsg{Type: tea.KeyDown},
+		key("Q"),
+		key("Q"),
+		key("o"),
+	})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	if opened != "b#1" {
+		t.Errorf("Expected reply 1 of %q to be opened, got %q", "b", opened)
+	}
+	if _, err := m.Result(); err != ErrNoSelection {
+		t.Errorf("Expected ErrNoSelection, got %v", err)
+	}
+}
+
+func TestReactionModeDigitPicksEmoji(t *testing.T) {
+	var added string
+	key := func(s string) tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	m, err := RunSelection(SelectorOptions[string]{
+		Items:          []string{"a"},
+		Renderer:       mockRenderer{previewContent: "preview"},
+		ReactionAction: func(item string) (int64, error) { return 42, nil },
+		ReactionComplete: func(item string, commentID int64, apiName, display string) (string, error) {
+			added = apiName
+			return "added", nil
+		},
+	}, []tea.Msg{
+		tea.WindowSizeMsg{Width: 80, Height: 24},
+		key("x"),
+		key("5"),
+	})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	if !m.reactionMode || m.reactionIdx != 4 {
+		t.Fatalf("Expected reaction mode on emoji 5, got mode=%v idx=%d", m.reactionMode, m.reactionIdx)
+	}
+	if !strings.Contains(m.reactionStatus(), "1-8=pick") {
+		t.Errorf("Expected the status to mention digit selection, got %q", m.reactionStatus())
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if added != "heart" {
+		t.Errorf("Expected the heart reaction to be added, got %q", added)
+	}
+	if updated.(SelectionModel[string]).reactionMode {
+		t.Error("Expected reaction mode to end after Enter")
+	}
+}
+
+func TestReactionDoublePressPicksThreadHead(t *testing.T) {
+	var reactedTo string
+	opts := SelectorOptions[string]{
+		Items:    []string{"a"},
+		Renderer: threadMockRenderer{mockRenderer{previewContent: "preview"}},
+		ReactionAction: func(item string) (int64, error) {
+			reactedTo = item
+			return 1, nil
+		},
+	}
+	key := func(s string) tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	m, err := RunSelection(opts, []tea.Msg{tea.WindowSizeMsg{Width: 80, Height: 24}, key("x"), key("x")})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	if !m.reactionMode || m.commentSelectMode || reactedTo != "a#0" {
+		t.Errorf("Expected xx to react to the head comment, got reactionMode=%v selectMode=%v item=%q",
+			m.reactionMode, m.commentSelectMode, reactedTo)
+	}
+
+	// After the window, x cycles through the thread as before
+	m, err = RunSelection(opts, []tea.Msg{tea.WindowSizeMsg{Width: 80, Height: 24}, key("x")})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	m.commentSelectSince = time.Now().Add(-time.Second)
+	updated, _ := m.Update(key("x"))
+	result := updated.(SelectionModel[string])
+	if !result.commentSelectMode || result.commentSelectIdx != 1 {
+		t.Errorf("Expected a slow second x to cycle to reply 1, got mode=%v idx=%d", result.commentSelectMode, result.commentSelectIdx)
+	}
+}
+
+func TestJumpToCommentRevealsHiddenItem(t *testing.T) {
+	hidden := map[string]bool{"30": true}
+	opts := SelectorOptions[string]{
+		Items:         []string{"10", "20", "30"},
+		Renderer:      mockRenderer{},
+		FilterFunc:    func(item string, active bool) bool { return !hidden[item] },
+		FilterDefault: true,
+		MatchesCommentID: func(item string, id int64) bool {
+			return item == strconv.FormatInt(id, 10)
+		},
+		RevealItem: func(item string) { delete(hidden, item) },
+		JumpKey:    "g go to comment",
+	}
+	key := func(s string) tea.Msg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	m, err := RunSelection(opts, []tea.Msg{
+		tea.WindowSizeMsg{Width: 80, Height: 24},
+		key("g"), key("3"), key("0"), enter,
+	})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	if m.jumpInputActive {
+		t.Error("Expected the jump input to close after enter")
+	}
+	if got := m.list.SelectedItem().(listItem[string]).value; got != "30" {
+		t.Errorf("Expected comment 30 to be selected, got %q", got)
+	}
+	if hidden["30"] {
+		t.Error("Expected RevealItem to un-hide comment 30")
+	}
+
+	m, _ = RunSelection(opts, []tea.Msg{
+		tea.WindowSizeMsg{Width: 80, Height: 24},
+		tea.KeyMsg{Type: tea.KeyDown},
+		key("g"), key("9"), key("9"), enter,
+	})
+	if got := m.list.SelectedItem().(listItem[string]).value; got != "20" {
+		t.Errorf("Expected selection to stay on 20 for an unknown ID, got %q", got)
+	}
+}
+
+func TestParseCommentID(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   int64
+		wantOK bool
+	}{
+		{"123", 123, true},
+		{" #123 ", 123, true},
+		{"https://github.com/o/r/pull/1#discussion_r456", 456, true},
+		{"https://github.com/o/r/pull/1#issuecomment-789", 789, true},
+		{"", 0, false},
+		{"abc", 0, false},
+		{"0", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseCommentID(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseCommentID(%q) = %d, %v; want %d, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestBatchReplyToMarkedItems(t *testing.T) {
+	var gotItems []string
+	var gotBody string
+	applied := false
+	opts := SelectorOptions[string]{
+		Items:    []string{"header", "a", "b", "c"},
+		Renderer: mockRenderer{},
+		ItemKey:  func(item string) string { return item },
+		Markable: func(item string) bool { return item != "header" },
+		BatchReplyPrepare: func(items []string) (string, error) {
+			return "", nil
+		},
+		BatchReplyComplete: func(items []string, body string, resume map[string]string) BatchResult[string] {
+			gotItems, gotBody = items, body
+			return BatchResult[string]{Summary: "Replied to 2 of 2 threads", Succeeded: items, Apply: func() { applied = true }}
+		},
+		BatchReplyKey: "B batch reply",
+	}
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	// Space on the header does nothing but move on; a and c are marked
+	m, err := RunSelection(opts, []tea.Msg{
+		tea.WindowSizeMsg{Width: 80, Height: 24},
+		space, space, tea.KeyMsg{Type: tea.KeyDown}, space,
+	})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	marked := m.markedItems()
+	if strings.Join(marked, ",") != "a,c" {
+		t.Fatalf("Expected a and c to be marked, got %v", marked)
+	}
+	if view := m.View(); !strings.Contains(view, " *a") || !strings.Contains(view, "B:reply to 2 marked") {
+		t.Errorf("Expected marked rows and the batch hint in the view, got:\n%s", view)
+	}
+
+	m.pendingEditorBatch = marked
+	updated, cmd := m.completeBatchReply("fixed")
+	for _, c := range cmd().(tea.BatchMsg) {
+		msg, ok := c().(batchCompleteMsg)
+		if !ok {
+			continue
+		}
+		if msg.result.(BatchResult[string]).Summary != "Replied to 2 of 2 threads" {
+			t.Errorf("Unexpected completion message: %+v", msg)
+		}
+		if applied {
+			t.Error("Expected the batch's changes to wait for Update")
+		}
+		if updated, _ = updated.(SelectionModel[string]).handleBatchComplete(msg); !applied {
+			t.Error("Expected the batch's changes applied in Update")
+		}
+	}
+	if strings.Join(gotItems, ",") != "a,c" || gotBody != "fixed" {
+		t.Errorf("Expected the body posted to a and c, got %v %q", gotItems, gotBody)
+	}
+	after := updated.(SelectionModel[string])
+	if left := after.markedItems(); len(left) != 0 {
+		t.Errorf("Expected marks to be cleared, got %v", left)
+	}
+}
+
+func TestBatchReplyRetriesFailedOnly(t *testing.T) {
+	var calls [][]string
+	var resumes []map[string]string
+	opts := SelectorOptions[string]{
+		Items:             []string{"a", "b", "c"},
+		Renderer:          mockRenderer{},
+		ItemKey:           func(item string) string { return item },
+		Markable:          func(item string) bool { return true },
+		BatchReplyPrepare: func(items []string) (string, error) { return "", nil },
+		BatchReplyComplete: func(items []string, body string, resume map[string]string) BatchResult[string] {
+			calls = append(calls, items)
+			resumes = append(resumes, resume)
+			result := BatchResult[string]{Summary: "done", Errors: map[string]error{}, FailedStep: map[string]string{}}
+			for _, item := range items {
+				if item == "b" && len(calls) == 1 {
+					result.Failed = append(result.Failed, item)
+					result.Errors[item] = errors.New("b (boom)")
+					result.FailedStep[item] = "resolve"
+					continue
+				}
+				result.Succeeded = append(result.Succeeded, item)
+			}
+			return result
+		},
+		BatchReplyKey: "B batch reply",
+		BatchRetryKey: "F retry failed",
+	}
+	m := newTestModel(opts.Items, opts)
+
+	run := func(m SelectionModel[string], cmd tea.Cmd) SelectionModel[string] {
+		for _, c := range cmd().(tea.BatchMsg) {
+			if msg, ok := c().(batchCompleteMsg); ok {
+				updated, _ := m.Update(msg)
+				return updated.(SelectionModel[string])
+			}
+		}
+		t.Fatal("Expected the batch to complete")
+		return m
+	}
+
+	updated, cmd := m.runBatchReply([]string{"a", "b", "c"}, "fixed", nil)
+	m = run(updated.(SelectionModel[string]), cmd)
+	if strings.Join(m.batchFailed, ",") != "b" || m.batchFailedBody != "fixed" {
+		t.Fatalf("Expected b kept for retry with its body, got %v %q", m.batchFailed, m.batchFailedBody)
+	}
+	if view := m.View(); !strings.Contains(view, "b (boom)") || !strings.Contains(view, "F:retry 1 failed") {
+		t.Errorf("Expected the failure and the retry hint, got:\n%s", view)
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m = run(updated.(SelectionModel[string]), cmd)
+	if len(calls) != 2 || strings.Join(calls[1], ",") != "b" {
+		t.Errorf("Expected F to retry only b, got %v", calls)
+	}
+	if resumes[0] != nil || resumes[1]["b"] != "resolve" {
+		t.Errorf("Expected F to resume b at the step it failed, got %v", resumes)
+	}
+	if len(m.batchFailed) != 0 {
+		t.Errorf("Expected nothing left to retry, got %v", m.batchFailed)
+	}
+}
+
+func TestOptimisticResolveRevertsOnFailure(t *testing.T) {
+	resolved := map[string]bool{}
+	opts := SelectorOptions[string]{
+		Items:    []string{"a", "b"},
+		Renderer: mockRenderer{},
+		ItemKey:  func(item string) string { return item },
+		ResolveStart: func(item string) (func() (string, func(), error), func(), error) {
+			resolved[item] = true
+			call := func() (string, func(), error) { return "", nil, errors.New("forbidden") }
+			return call, func() { resolved[item] = false }, nil
+		},
+	}
+
+	m, err := RunSelection(opts, []tea.Msg{
+		tea.WindowSizeMsg{Width: 80, Height: 24},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}},
+	})
+	if err != nil {
+		t.Fatalf("RunSelection returned error: %v", err)
+	}
+	if !resolved["a"] || !m.pending["a"] {
+		t.Fatalf("Expected a to be flipped and pending, got resolved=%v pending=%v", resolved, m.pending)
+	}
+	if view := m.View(); !strings.Contains(view, ">…a") {
+		t.Errorf("Expected a pending marker on the row, got:\n%s", view)
+	}
+
+	// A second r while in flight doesn't start another call
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(SelectionModel[string])
+
+	updated, _ = m.Update(resolveFinishedMsg{key: "a", err: errors.New("forbidden"), undo: func() { resolved["a"] = false }})
+	m = updated.(SelectionModel[string])
+	if resolved["a"] || m.pending["a"] {
+		t.Errorf("Expected the flip undone and nothing pending, got resolved=%v pending=%v", resolved, m.pending)
+	}
+}
+
+func TestHelpTextLegend(t *testing.T) {
+	items := []string{"item1"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{},
+		Legend:   []LegendEntry{{Sample: "@bot", Meaning: "bot author"}},
+		Markable: func(string) bool { return true },
+		ItemKey:  func(s string) string { return s },
+	})
+
+	help := m.helpText()
+	if !strings.Contains(help, "Legend:") {
+		t.Fatalf("Expected a Legend section, got:\n%s", help)
+	}
+	for _, want := range []string{"current row", "marked for batch actions", "@bot", "bot author"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("Expected legend to mention %q", want)
+		}
+	}
+	for _, unwanted := range []string{"resolve in progress", "can't be selected"} {
+		if strings.Contains(help, unwanted) {
+			t.Errorf("Expected no %q entry without the matching feature", unwanted)
+		}
+	}
+}
+
+func TestRefreshNotifiesOnChanges(t *testing.T) {
+	var notified []string
+	original := notify
+	notify = func(message string) { notified = append(notified, message) }
+	defer func() { notify = original }()
+
+	items := []string{"a", "b"}
+	opts := SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+		DescribeChanges: func(old, updated []string) string {
+			if len(updated) > len(old) {
+				return fmt.Sprintf("%d new", len(updated)-len(old))
+			}
+			return ""
+		},
+	}
+
+	quiet := newTestModel(items, opts)
+	_, cmd := quiet.Update(refreshFinishedMsg{items: []string{"a", "b", "c"}})
+	runCmd(cmd)
+	if len(notified) != 0 {
+		t.Errorf("Expected no notification without Notify, got %v", notified)
+	}
+
+	opts.Notify = true
+	m := newTestModel(items, opts)
+	updated, cmd := m.Update(refreshFinishedMsg{items: []string{"a", "b", "c"}})
+	runCmd(cmd)
+	if len(notified) != 1 || notified[0] != "1 new" {
+		t.Errorf("Expected one notification for the new item, got %v", notified)
+	}
+	if view := updated.(SelectionModel[string]).View(); !strings.Contains(view, "Refreshed: 3 items (1 new)") {
+		t.Errorf("Expected the change in the refresh status, got:\n%s", view)
+	}
+
+	_, cmd = updated.Update(refreshFinishedMsg{items: []string{"a", "b", "c"}})
+	runCmd(cmd)
+	if len(notified) != 1 {
+		t.Errorf("Expected no notification when nothing changed, got %v", notified)
+	}
+}
+
+func TestValidateReactionOrder(t *testing.T) {
+	if err := ValidateReactionOrder(nil); err != nil {
+		t.Errorf("ValidateReactionOrder(nil) = %v, want nil", err)
+	}
+	if err := ValidateReactionOrder([]string{"rocket", "+1"}); err != nil {
+		t.Errorf("ValidateReactionOrder(valid) = %v, want nil", err)
+	}
+	if err := ValidateReactionOrder([]string{"thumbsup"}); err == nil || !strings.Contains(err.Error(), "thumbsup") {
+		t.Errorf("Expected an error naming the unknown reaction, got %v", err)
+	}
+	if err := ValidateReactionOrder([]string{"eyes", "eyes"}); err == nil {
+		t.Error("Expected an error for a duplicate reaction")
+	}
+}
+
+func TestReactionOrderReordersPicker(t *testing.T) {
+	got := orderedReactions([]string{"rocket", "+1"})
+	var names []string
+	for _, emoji := range got {
+		names = append(names, emoji.name)
+	}
+	want := "rocket +1 -1 laugh confused heart hooray eyes"
+	if strings.Join(names, " ") != want {
+		t.Errorf("orderedReactions() = %v, want %s", names, want)
+	}
+
+	items := []string{"item1"}
+	var reacted string
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:         items,
+		Renderer:      mockRenderer{},
+		ReactionOrder: []string{"rocket", "+1"},
+		ReactionComplete: func(item string, commentID int64, apiName, displayEmoji string) (string, error) {
+			reacted = apiName
+			return "Reacted", nil
+		},
+	})
+	m.enterReactionMode(1, listItem[string]{value: "item1", item: mockRenderer{}})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if reacted != "+1" {
+		t.Errorf("Expected the second configured reaction, got %q", reacted)
+	}
+}
+
+// rawMockRenderer is a mockRenderer that can also show raw previews
+type rawMockRenderer struct {
+	mockRenderer
+}
+
+func (r rawMockRenderer) PreviewRaw(item string, idx int, query string) string {
+	return fmt.Sprintf("raw-%s-%d", item, idx)
+}
+
+func TestToggleRawPreview(t *testing.T) {
+	items := []string{"item1"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: rawMockRenderer{mockRenderer{previewContent: "preview"}},
+	})
+	m.showDetail = true
+	m.setDetailContent(m.detailPreview("item1", -1))
+	if !strings.Contains(m.View(), "M:raw") {
+		t.Error("Expected the raw toggle in the detail footer")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	result := updated.(SelectionModel[string])
+	if !result.showRaw || result.detailContent != "raw-item1--1" {
+		t.Errorf("Expected M to show the raw preview, got %q", result.detailContent)
+	}
+
+	// The selected comment stays highlighted across toggles
+	result.commentSelectMode = true
+	result.commentSelectIdx = 2
+	result.toggleRawPreview()
+	result.toggleRawPreview()
+	if result.detailContent != "raw-item1-2" {
+		t.Errorf("Expected the raw preview to keep the highlight, got %q", result.detailContent)
+	}
+
+	plain := newTestModel(items, SelectorOptions[string]{Items: items, Renderer: mockRenderer{previewContent: "preview"}})
+	plain.showDetail = true
+	if strings.Contains(plain.View(), "M:raw") {
+		t.Error("Expected no raw toggle for renderers without PreviewRaw")
+	}
+}
+
+// foldingMockRenderer is a mockRenderer for a thread with replies that
+// records the reply folding it was asked for
+type foldingMockRenderer struct {
+	mockRenderer
+	replies int
+	visible *int
+}
+
+func (r foldingMockRenderer) SetVisibleReplies(n int)            { *r.visible = n }
+func (r foldingMockRenderer) ThreadCommentCount(item string) int { return r.replies + 1 }
+
+func TestFoldRepliesInDetail(t *testing.T) {
+	items := []string{"item1"}
+	visible := 0
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: foldingMockRenderer{mockRenderer: mockRenderer{previewContent: "preview"}, replies: 5, visible: &visible},
+	})
+	m.showDetail = true
+	m.rerenderDetail()
+	if visible != DefaultVisibleReplies {
+		t.Fatalf("Expected the latest %d replies by default, got %d", DefaultVisibleReplies, visible)
+	}
+
+	press := func(key rune) {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		m = updated.(SelectionModel[string])
+	}
+
+	press('J')
+	press('J')
+	press('J') // only 5 replies
+	if visible != 5 {
+		t.Errorf("Expected J to unfold up to every reply, got %d", visible)
+	}
+	press('K')
+	if visible != 4 {
+		t.Errorf("Expected K to fold one reply, got %d", visible)
+	}
+	press('E')
+	if visible != -1 {
+		t.Errorf("Expected E to show all replies, got %d", visible)
+	}
+	press('K')
+	if visible != 4 {
+		t.Errorf("Expected K after E to fold one of all 5 replies, got %d", visible)
+	}
+	if !strings.Contains(m.View(), "J/K:replies") {
+		t.Error("Expected the reply folding keys in the detail footer")
+	}
+}
+
+func TestEditorExitNonzeroCancels(t *testing.T) {
+	items := []string{"item1"}
+	newModel := func() (SelectionModel[string], string) {
+		m := newTestModel(items, SelectorOptions[string]{Items: items, Renderer: mockRenderer{}})
+		tmp, err := os.CreateTemp(t.TempDir(), "edit-*.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = tmp.Close()
+		m.pendingEditorTmpFile = tmp.Name()
+		m.pendingEditorAction = editorActionQuote
+		return m, tmp.Name()
+	}
+
+	aborted := exec.Command("sh", "-c", "exit 1").Run()
+	missing := exec.Command("gh-review-conductor-no-such-editor").Run()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus string
+	}{
+		{name: "nonzero exit", err: aborted, wantStatus: "Cancelled (editor exited with status 1)"},
+		{name: "editor not found", err: missing, wantStatus: "Editor not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, tmpFile := newModel()
+			updated, _ := m.Update(editorFinishedMsg{err: tt.err})
+			result := updated.(SelectionModel[string])
+			if result.pendingEditorTmpFile != "" {
+				t.Error("Expected the pending editor state to be cleared")
+			}
+			if _, err := os.Stat(tmpFile); !os.IsNotExist(err) {
+				t.Errorf("Expected the temp file to be removed, stat error: %v", err)
+			}
+			if got := result.View(); !strings.Contains(got, tt.wantStatus) {
+				t.Errorf("Expected status containing %q, got:\n%s", tt.wantStatus, got)
+			}
+		})
+	}
+}
+
+type measuringMockRenderer struct {
+	mockRenderer
+	measured *[]string
+}
+
+func (r measuringMockRenderer) MeasureColumns(items []string) { *r.measured = items }
+
+func TestMeasureColumnsFollowsFilter(t *testing.T) {
+	items := []string{"item1", "resolved2", "item3"}
+	var measured []string
+	m := newSelectionModel(SelectorOptions[string]{
+		Items:    items,
+		Renderer: measuringMockRenderer{mockRenderer{}, &measured},
+		FilterFunc: func(item string, active bool) bool {
+			return !active || !strings.HasPrefix(item, "resolved")
+		},
+		FilterDefault: true,
+	})
+	if len(measured) != 2 {
+		t.Fatalf("expected the 2 unfiltered items measured, got %v", measured)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	if len(measured) != 3 {
+		t.Errorf("expected all 3 items measured after h, got %v", measured)
+	}
+
+	measured = nil
+	updated.(SelectionModel[string]).Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	if len(measured) != 3 {
+		t.Errorf("expected a resize to remeasure, got %v", measured)
+	}
+}
+
+func TestFilterBarCountsMatchesWhileTyping(t *testing.T) {
+	items := []string{"apple", "apricot", "banana"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+	})
+	m.windowSize = tea.WindowSizeMsg{Width: 120, Height: 24}
+
+	m.list.SetFilterText("ap")
+	m.list.SetFilterState(list.Filtering)
+	if view := m.View(); !strings.Contains(view, `Filters: "ap"  2 matches`) {
+		t.Errorf("expected the match count while typing, got:\n%s", view)
+	}
+
+	m.list.SetFilterText("ban")
+	m.list.SetFilterState(list.Filtering)
+	if view := m.View(); !strings.Contains(view, "1 match") || strings.Contains(view, "1 matches") {
+		t.Errorf("expected a singular match count, got:\n%s", view)
+	}
+
+	// Once applied, the list's status bar takes over
+	m.list.SetFilterText("ap")
+	if strings.Contains(m.View(), "matches") {
+		t.Error("expected no match count once the filter is applied")
+	}
+}
+
+func TestConfirmationURL(t *testing.T) {
+	url := "https://github.com/owner/repo/pull/1#discussion_r42"
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"hyperlink", "Reply posted: \x1b]8;;" + url + "\x1b\\view\x1b]8;;\x1b\\\n\nPress any key to continue...", url},
+		{"fallback", "Reply posted: view (" + url + ")", url},
+		{"trailing period", "See " + url + ".", url},
+		{"no url", "Reply posted\n\nPress any key to continue...", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := confirmationURL(tt.message); got != tt.want {
+				t.Errorf("confirmationURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmationCopiesURLOnY(t *testing.T) {
+	var copied string
+	original := clipboardWriteAll
+	clipboardWriteAll = func(text string) error {
+		copied = text
+		return nil
+	}
+	defer func() { clipboardWriteAll = original }()
+
+	url := "https://github.com/owner/repo/pull/1#discussion_r42"
+	items := []string{"item1"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: "preview"},
+	})
+	m.confirmationMessage = "Reply posted: " + url + "\n\nPress any key to continue..."
+	if view := m.View(); !strings.Contains(view, "Press y to copy the URL") {
+		t.Errorf("Expected dialog to offer copying the URL, got:\n%s", view)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if copied != url {
+		t.Errorf("Expected y to copy %q, got %q", url, copied)
+	}
+	m2 := updated.(SelectionModel[string])
+	if m2.confirmationMessage != "" {
+		t.Error("Expected y to dismiss the dialog")
+	}
+	if view := m2.View(); !strings.Contains(view, "Copied "+url) {
+		t.Errorf("Expected copied flash, got:\n%s", view)
+	}
+
+	copied = ""
+	m2.confirmationMessage = "Reply posted: " + url + "\n\nPress any key to continue..."
+	updated, _ = m2.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if copied != "" {
+		t.Errorf("Expected other keys not to copy, got %q", copied)
+	}
+	if updated.(SelectionModel[string]).confirmationMessage != "" {
+		t.Error("Expected any other key to dismiss the dialog")
+	}
+}
+
+func TestToggleDetailWrap(t *testing.T) {
+	long := strings.Repeat("frame ", 30)
+	items := []string{"item1"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:    items,
+		Renderer: mockRenderer{previewContent: long},
+	})
+	m.viewport = viewport.New(80, 20)
+	m.showDetail = true
+	m.setDetailContent(m.detailPreview("item1", -1))
+	for _, line := range strings.Split(m.detailContent, "\n") {
+		if lipgloss.Width(line) > m.viewport.Width {
+			t.Fatalf("Expected lines wrapped to %d columns by default, got %q", m.viewport.Width, line)
+		}
+	}
+	if !strings.Contains(m.View(), "w:nowrap") {
+		t.Error("Expected the wrap toggle in the detail footer")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	result := updated.(SelectionModel[string])
+	if !result.noWrap || result.detailContent != long+"-item1" {
+		t.Errorf("Expected w to turn wrapping off, got %q", result.detailContent)
+	}
+
+	updated, _ = result.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if updated.(SelectionModel[string]).noWrap {
+		t.Error("Expected a second w to turn wrapping back on")
+	}
+}
+
+func TestInlineReplyComposer(t *testing.T) {
+	var gotItem, gotBody string
+	items := []string{"item1"}
+	m := newTestModel(items, SelectorOptions[string]{
+		Items:        items,
+		Renderer:     mockRenderer{previewContent: "preview"},
+		ReplyPrepare: func(item string) (string, error) { return "", nil },
+		ReplyComplete: func(item, body string) (string, func(), error) {
+			gotItem, gotBody = item, body
+			return "Posted", nil, nil
+		},
+		ReplyKey: "m reply",
+	})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	result := updated.(*SelectionModel[string])
+	if !result.composerActive {
+		t.Fatal("Expected m to open the composer")
+	}
+	if view := result.View(); !strings.Contains(view, "ctrl+d send") {
+		t.Errorf("Expected the composer dialog, got:\n%s", view)
+	}
+
+	updated, _ = result.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("done ")})
+	updated, cmd := updated.(SelectionModel[string]).Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	sent := updated.(SelectionModel[string])
+	if sent.composerActive {
+		t.Error("Expected ctrl+d to close the composer")
+	}
+	if cmd == nil {
+		t.Fatal("Expected ctrl+d to send the reply")
+	}
+	for _, msg := range cmd().(tea.BatchMsg) {
+		if msg == nil {
+			continue
+		}
+		if _, ok := msg().(editorCompleteMsg); ok {
+			break
+		}
+	}
+	if gotItem != "item1" || gotBody != "done" {
+		t.Errorf("Expected the sanitized reply for item1, got %q for %q", gotBody, gotItem)
+	}
+
+	updated, _ = sent.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	updated, _ = updated.(*SelectionModel[string]).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(SelectionModel[string]).composerActive {
+		t.Error("Expected esc to cancel the composer")
+	}
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// truncationIndicator marks text cut by truncateDisplay
+var truncationIndicator = "..."
+
+// SetTruncationIndicator changes the marker appended to truncated list rows
+// and previews (e.g. "…" to save columns). Call before rendering starts.
+func SetTruncationIndicator(indicator string) {
+	truncationIndicator = indicator
+}
+
+// TruncateDisplay is truncateDisplay for callers outside the package.
+func TruncateDisplay(s string, max int) string {
+	return truncateDisplay(s, max)
+}
+
+// DisplayWidth returns the terminal columns s occupies, ignoring escape sequences.
+func DisplayWidth(s string) int {
+	return runewidth.StringWidth(terminalEscapeRe.ReplaceAllString(s, ""))
+}
+
+// truncateDisplay cuts s to at most max terminal columns, ending in the
+// truncation indicator when cut. Wide characters count as two columns, runes
+// are never split, and escape sequences are kept whole; styling and
+// hyperlinks left open by the cut are closed after the indicator.
+func truncateDisplay(s string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+	if DisplayWidth(s) <= max {
+		return s
+	}
+
+	tail := truncationIndicator
+	if runewidth.StringWidth(tail) >= max {
+		return runewidth.Truncate(tail, max, "")
+	}
+	budget := max - runewidth.StringWidth(tail)
+
+	var b strings.Builder
+	width := 0
+	styled, inLink := false, false
+
+	// writePlain copies runes of escape-free text until the budget runs out
+	writePlain := func(text string) bool {
+		for _, r := range text {
+			w := runewidth.RuneWidth(r)
+			if width+w > budget {
+				return false
+			}
+			b.WriteRune(r)
+			width += w
+		}
+		return true
+	}
+
+	pos := 0
+	complete := true
+	for _, loc := range terminalEscapeRe.FindAllStringIndex(s, -1) {
+		if !writePlain(s[pos:loc[0]]) {
+			complete = false
+			break
+		}
+		seq := s[loc[0]:loc[1]]
+		b.WriteString(seq)
+		if strings.HasPrefix(seq, "\x1b]8;") {
+			// OSC 8 with an empty URL closes the link: ESC ] 8 ; params ; URL ST
+			fields := strings.SplitN(strings.TrimRight(seq, "\x07\x1b\\"), ";", 3)
+			inLink = len(fields) == 3 && fields[2] != ""
+		} else {
+			styled = true
+		}
+		pos = loc[1]
+	}
+	if complete {
+		writePlain(s[pos:])
+	}
+
+	b.WriteString(tail)
+	if inLink {
+		b.WriteString("\x1b]8;;\x1b\\")
+	}
+	if styled {
+		b.WriteString(ColorReset)
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateDisplay(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		max  int
+		want string
+	}{
+		{"fits unchanged", "hello", 10, "hello"},
+		{"ascii cut", "hello world", 8, "hello..."},
+		{"multibyte runes are not split", "héllo wörld", 8, "héllo..."},
+		{"wide CJK counts two columns", "日本語のテキスト", 9, "日本語..."},
+		{"wide char that would overflow is dropped", "日本語テ", 6, "日..."},
+		{"emoji", "🚀🚀🚀🚀", 7, "🚀🚀..."},
+		{"max smaller than indicator", "hello world", 2, ".."},
+		{"zero width", "hello", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateDisplay(tt.s, tt.max)
+			if got != tt.want {
+				t.Errorf("truncateDisplay(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+			}
+			if w := DisplayWidth(got); w > tt.max {
+				t.Errorf("truncateDisplay(%q, %d) is %d columns wide", tt.s, tt.max, w)
+			}
+		})
+	}
+}
+
+func TestTruncateDisplay_ANSI(t *testing.T) {
+	t.Run("escape sequences are kept whole and styling is reset", func(t *testing.T) {
+		s := ColorRed + "error" + ColorReset + " in a long message"
+		got := truncateDisplay(s, 10)
+		want := ColorRed + "error" + ColorReset + " i..." + ColorReset
+		if got != want {
+			t.Errorf("truncateDisplay() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("cut inside a color closes it", func(t *testing.T) {
+		got := truncateDisplay(ColorCyan+"@a-very-long-author-name"+ColorReset, 8)
+		if got != ColorCyan+"@a-ve..."+ColorReset {
+			t.Errorf("truncateDisplay() = %q", got)
+		}
+	})
+
+	t.Run("escapes don't count toward width", func(t *testing.T) {
+		s := ColorGreen + "ok" + ColorReset
+		if got := truncateDisplay(s, 2); got != s {
+			t.Errorf("truncateDisplay() = %q, want %q unchanged", got, s)
+		}
+	})
+
+	t.Run("cut inside a hyperlink closes it", func(t *testing.T) {
+		link := "\033]8;;https://example.com\033\\a long link text\033]8;;\033\\"
+		got := truncateDisplay(link, 6)
+		if !strings.HasPrefix(got, "\033]8;;https://example.com\033\\a l...") {
+			t.Errorf("expected the link to be cut after 3 columns, got %q", got)
+		}
+		if !strings.Contains(got, "...\033]8;;\033\\") {
+			t.Errorf("expected the hyperlink to be closed, got %q", got)
+		}
+	})
+}
+
+func TestSetTruncationIndicator(t *testing.T) {
+	original := truncationIndicator
+	defer SetTruncationIndicator(original)
+
+	SetTruncationIndicator("…")
+	if got := truncateDisplay("hello world", 6); got != "hello…" {
+		t.Errorf("truncateDisplay() with custom indicator = %q, want %q", got, "hello…")
+	}
+}